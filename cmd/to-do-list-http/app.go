@@ -1,38 +1,104 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"to-do-list/internal/config"
+	list_handler_http "to-do-list/internal/handler/http/list"
+	share_handler_http "to-do-list/internal/handler/http/share"
+	tag_handler_http "to-do-list/internal/handler/http/tag"
 	handler_http "to-do-list/internal/handler/http/task"
-	repo "to-do-list/internal/repo/task"
+	user_handler_http "to-do-list/internal/handler/http/user"
+	workspace_handler_http "to-do-list/internal/handler/http/workspace"
+	list_usecase "to-do-list/internal/usecase/list"
+	share_usecase "to-do-list/internal/usecase/share"
+	tag_usecase "to-do-list/internal/usecase/tag"
 	usecase "to-do-list/internal/usecase/task"
-	redis_client "to-do-list/pkg/redis"
-
-	_ "github.com/lib/pq"
+	user_usecase "to-do-list/internal/usecase/user"
+	workspace_usecase "to-do-list/internal/usecase/workspace"
+	"to-do-list/pkg/migrate"
+	"to-do-list/schema"
 )
 
 func startApp(cfg *config.Config) error {
 
-	db_credential := fmt.Sprintf(cfg.Database.Credential, cfg.Database.Host, cfg.Database.Port, cfg.Database.User, cfg.Database.Password, cfg.Database.DbName)
+	if cfg.Auth.Secret == "" {
+		return errors.New("auth.secret is required to sign access tokens")
+	}
 
-	db, err := sql.Open(cfg.Database.Driver, db_credential)
+	store, err := openStorage(cfg)
 
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	defer db.Close()
+	defer store.close()
+
+	taskUseCase := usecase.NewUseCase(store.tasks,
+		usecase.WithBlockOpenSubtasks(cfg.Task.BlockOpenSubtasks),
+		usecase.WithValidator(handler_http.Validate),
+		usecase.WithEvents(store.tasks),
+		usecase.WithSharing(store.lists),
+	)
+
+	go startPurger(context.Background(), taskUseCase, cfg.Trash)
+	go startRebalancer(context.Background(), taskUseCase, cfg.Task.RebalanceInterval)
+
+	taskHandler := handler_http.NewHandler(taskUseCase,
+		handler_http.WithIdempotency(store.idempotency),
+	)
+
+	tagUseCase := tag_usecase.NewUseCase(store.tags)
+
+	tagHandler := tag_handler_http.NewHandler(tagUseCase)
+
+	listUseCase := list_usecase.NewUseCase(store.lists, taskUseCase)
+
+	listHandler := list_handler_http.NewHandler(listUseCase)
+
+	shareUseCase := share_usecase.NewUseCase(store.shares, store.lists, listUseCase, taskUseCase, []byte(cfg.Auth.Secret))
+
+	shareHandler := share_handler_http.NewHandler(shareUseCase)
 
-	redis := redis_client.NewRedisClient(cfg.Redis.Host, cfg.Redis.Password)
+	userUseCase := user_usecase.NewUseCase(store.users, []byte(cfg.Auth.Secret), userOptions(cfg.Auth)...)
 
-	taskRepo := repo.NewTaskRepository(db, redis)
+	userHandler := user_handler_http.NewHandler(userUseCase)
 
-	taskUseCase := usecase.NewUseCase(taskRepo)
+	workspaceUseCase := workspace_usecase.NewUseCase(store.workspaces)
 
-	taskHandler := handler_http.NewHandler(taskUseCase)
+	workspaceHandler := workspace_handler_http.NewHandler(workspaceUseCase, cfg.Workspace.Domain)
 
-	router := newRoutes(taskHandler)
+	router := newRoutes(taskHandler, tagHandler, listHandler, shareHandler, userHandler, workspaceHandler)
 
 	return startServer(router, cfg)
 }
+
+// migrateUp applies the pending migrations before anything reads the
+// database.
+func migrateUp(ctx context.Context, db *sql.DB) error {
+	migrator, err := migrate.New(db, schema.FS)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		fmt.Printf("[Migrate] Applied : %d_%s\n", m.Version, m.Name)
+	}
+	return err
+}
+
+// userOptions keeps the defaults of the user usecase for the lifetimes left
+// out of the config.
+func userOptions(cfg config.Auth) []user_usecase.Option {
+	var opts []user_usecase.Option
+	if cfg.AccessTTL > 0 {
+		opts = append(opts, user_usecase.WithAccessTTL(cfg.AccessTTL))
+	}
+	if cfg.RefreshTTL > 0 {
+		opts = append(opts, user_usecase.WithRefreshTTL(cfg.RefreshTTL))
+	}
+	return opts
+}
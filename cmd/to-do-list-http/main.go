@@ -14,6 +14,10 @@ package main
 import (
 	"log"
 	"to-do-list/internal/config"
+
+	// due windows are computed in the client's timezone, do not depend on
+	// the host having zoneinfo installed
+	_ "time/tzdata"
 )
 
 const repoName = "to-do-list"
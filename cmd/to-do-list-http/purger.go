@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"to-do-list/internal/config"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval  = time.Hour
+)
+
+type trashPurger interface {
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// startPurger removes the trash older than the configured retention once at
+// start and then every purge interval, until ctx is done.
+func startPurger(ctx context.Context, purger trashPurger, cfg config.Trash) {
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultTrashRetention
+	}
+	if cfg.PurgeInterval <= 0 {
+		cfg.PurgeInterval = defaultPurgeInterval
+	}
+
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := purger.PurgeTrash(ctx, cfg.Retention)
+		if err != nil {
+			fmt.Println("[Purger] Purge error :", err)
+		} else if purged > 0 {
+			fmt.Println("[Purger] Purged tasks :", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
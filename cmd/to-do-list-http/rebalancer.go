@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const defaultRebalanceInterval = time.Hour
+
+type rankRebalancer interface {
+	RebalanceRanks(ctx context.Context) (int64, error)
+}
+
+// startRebalancer spreads the keys of the manual order again once at start
+// and then every interval when moves made them too long, until ctx is done.
+func startRebalancer(ctx context.Context, rebalancer rankRebalancer, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultRebalanceInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		rebalanced, err := rebalancer.RebalanceRanks(ctx)
+		if err != nil {
+			fmt.Println("[Rebalancer] Rebalance error :", err)
+		} else if rebalanced > 0 {
+			fmt.Println("[Rebalancer] Rebalanced tasks :", rebalanced)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -2,18 +2,49 @@ package main
 
 import (
 	"net/http"
+	"to-do-list/internal/handler/http/list"
+	"to-do-list/internal/handler/http/share"
+	"to-do-list/internal/handler/http/tag"
 	"to-do-list/internal/handler/http/task"
+	"to-do-list/internal/handler/http/user"
+	"to-do-list/internal/handler/http/workspace"
+	"to-do-list/pkg/requestinfo"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-openapi/runtime/middleware"
 )
 
-func newRoutes(task *task.Handler) *chi.Mux {
+func newRoutes(task *task.Handler, tag *tag.Handler, list *list.Handler, share *share.Handler, user *user.Handler, workspace *workspace.Handler) *chi.Mux {
 	myRouter := chi.NewRouter()
-	myRouter.Get("/api/tasks", task.GetAll)
-	myRouter.Post("/api/task", task.Create)
-	myRouter.Put("/api/task/{id}", task.Update)
-	myRouter.Delete("/api/task/{id}", task.Delete)
+	myRouter.Use(requestinfo.Middleware)
+
+	myRouter.Post("/api/auth/register", user.Register)
+	myRouter.Post("/api/auth/login", user.Login)
+	myRouter.Post("/api/auth/refresh", user.Refresh)
+	myRouter.Post("/api/auth/logout", user.Logout)
+
+	// share links are public, their routes only read and only what the
+	// link shows
+	myRouter.Get("/api/shared/{token}", share.Shared)
+	myRouter.Get("/api/shared/{token}/tasks", share.SharedTasks)
+
+	// everything else belongs to the signed in user in one of their
+	// workspaces, read only API keys only get through to reads
+	myRouter.Group(func(myRouter chi.Router) {
+		myRouter.Use(user.Middleware, user.Scope, workspace.Middleware)
+		myRouter.Get("/api/workspaces", workspace.GetAll)
+		myRouter.Post("/api/workspaces", workspace.Create)
+		myRouter.Get("/api/workspaces/{id}/members", workspace.Members)
+		myRouter.Post("/api/workspaces/{id}/members", workspace.AddMember)
+		myRouter.Delete("/api/workspaces/{id}/members/{user_id}", workspace.RemoveMember)
+		myRouter.Get("/api/keys", user.GetAPIKeys)
+		myRouter.Post("/api/keys", user.CreateAPIKey)
+		myRouter.Delete("/api/keys/{id}", user.RevokeAPIKey)
+		myRouter.Get("/api/links", share.GetAll)
+		myRouter.Post("/api/links", share.Create)
+		myRouter.Delete("/api/links/{id}", share.Revoke)
+		routeResources(myRouter, task, tag, list)
+	})
 
 	myRouter.Handle("/docs.yaml", http.FileServer(http.Dir("./docs")))
 	opts := middleware.SwaggerUIOpts{SpecURL: "docs.yaml"}
@@ -22,3 +53,47 @@ func newRoutes(task *task.Handler) *chi.Mux {
 	return myRouter
 
 }
+
+func routeResources(myRouter chi.Router, task *task.Handler, tag *tag.Handler, list *list.Handler) {
+	myRouter.Get("/api/tasks", task.GetAll)
+	myRouter.Post("/api/tasks/batch", task.Batch)
+	myRouter.Get("/api/tasks/plan", task.Plan)
+	myRouter.Get("/api/task/{id}", task.GetByID)
+	myRouter.Post("/api/task", task.Create)
+	myRouter.Put("/api/task/{id}", task.Update)
+	myRouter.Patch("/api/task/{id}", task.Patch)
+	myRouter.Delete("/api/task/{id}", task.Delete)
+	myRouter.Put("/api/task/{id}/list", task.Move)
+	myRouter.Post("/api/task/{id}/move", task.Reposition)
+	myRouter.Put("/api/task/{id}/done", task.Done)
+	myRouter.Get("/api/task/{id}/subtasks", task.Subtasks)
+	myRouter.Post("/api/task/{id}/subtasks", task.CreateSubtask)
+	myRouter.Put("/api/task/{id}/subtasks/order", task.ReorderSubtasks)
+	myRouter.Post("/api/task/{id}/restore", task.Restore)
+	myRouter.Get("/api/trash", task.Trash)
+	myRouter.Get("/api/task/{id}/history", task.History)
+	myRouter.Get("/api/audit", task.Audit)
+	myRouter.Post("/api/undo", task.Undo)
+	myRouter.Post("/api/task/{id}/revert", task.Revert)
+	myRouter.Get("/api/task/{id}/dependencies", task.Dependencies)
+	myRouter.Post("/api/task/{id}/dependencies", task.AddDependency)
+	myRouter.Delete("/api/task/{id}/dependencies/{blocked_by}", task.RemoveDependency)
+
+	myRouter.Get("/api/tags", tag.GetAll)
+	myRouter.Post("/api/tag", tag.Create)
+	myRouter.Put("/api/tag/{id}", tag.Update)
+	myRouter.Delete("/api/tag/{id}", tag.Delete)
+	myRouter.Post("/api/tag/{id}/merge", tag.Merge)
+
+	myRouter.Get("/api/lists", list.GetAll)
+	myRouter.Post("/api/lists", list.Create)
+	myRouter.Get("/api/lists/{id}", list.GetByID)
+	myRouter.Put("/api/lists/{id}", list.Update)
+	myRouter.Delete("/api/lists/{id}", list.Delete)
+	myRouter.Get("/api/lists/{id}/tasks", list.Tasks)
+	myRouter.Get("/api/lists/{id}/members", list.Members)
+	myRouter.Post("/api/lists/{id}/members", list.Invite)
+	myRouter.Delete("/api/lists/{id}/members/{user_id}", list.RemoveMember)
+	myRouter.Post("/api/lists/{id}/accept", list.Accept)
+	myRouter.Get("/api/invitations", list.Invitations)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"to-do-list/internal/config"
+	handler_http "to-do-list/internal/handler/http/task"
+	list_repo "to-do-list/internal/repo/list"
+	"to-do-list/internal/repo/memory"
+	share_repo "to-do-list/internal/repo/share"
+	"to-do-list/internal/repo/sqlite"
+	tag_repo "to-do-list/internal/repo/tag"
+	repo "to-do-list/internal/repo/task"
+	user_repo "to-do-list/internal/repo/user"
+	workspace_repo "to-do-list/internal/repo/workspace"
+	list_usecase "to-do-list/internal/usecase/list"
+	share_usecase "to-do-list/internal/usecase/share"
+	tag_usecase "to-do-list/internal/usecase/tag"
+	usecase "to-do-list/internal/usecase/task"
+	user_usecase "to-do-list/internal/usecase/user"
+	workspace_usecase "to-do-list/internal/usecase/workspace"
+	redis_client "to-do-list/pkg/redis"
+
+	_ "github.com/lib/pq"
+)
+
+const postgresDriver = "postgres"
+
+// taskRepo is the task repo of the usecase with its audit log.
+type taskRepo interface {
+	usecase.Repo
+	usecase.EventRepo
+}
+
+// listRepo is the list repo of the usecase, which also tells the task and
+// share usecases who may reach a task.
+type listRepo interface {
+	list_usecase.Repo
+	usecase.Sharing
+}
+
+// storage holds the repos of the configured backend.
+type storage struct {
+	tasks       taskRepo
+	lists       listRepo
+	tags        tag_usecase.Repo
+	shares      share_usecase.Repo
+	users       user_usecase.Repo
+	workspaces  workspace_usecase.Repo
+	idempotency handler_http.IdempotencyStore
+	close       func() error
+}
+
+// openStorage builds the repos of database.driver: postgres with redis,
+// sqlite to keep everything in one file on a single machine, or memory to
+// run without any external service, losing everything on exit.
+func openStorage(cfg *config.Config) (*storage, error) {
+	switch cfg.Database.Driver {
+	case memory.Driver:
+		return openMemory(cfg), nil
+	case sqlite.Driver:
+		return openSQLite(cfg)
+	case postgresDriver:
+		return openPostgres(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q, use %s, %s or %s", cfg.Database.Driver, postgresDriver, sqlite.Driver, memory.Driver)
+	}
+}
+
+func openPostgres(cfg *config.Config) (*storage, error) {
+	db, err := sql.Open(cfg.Database.Driver, cfg.Database.DataSource())
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database.Migrate {
+		if err := migrateUp(context.Background(), db); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	redis := redis_client.NewRedisClient(cfg.Redis.Host, cfg.Redis.Password)
+
+	return &storage{
+		tasks:       repo.NewTaskRepository(db, redis),
+		lists:       list_repo.NewListRepository(db, redis),
+		tags:        tag_repo.NewTagRepository(db, redis),
+		shares:      share_repo.NewShareRepository(db),
+		users:       user_repo.NewUserRepository(db),
+		workspaces:  workspace_repo.NewWorkspaceRepository(db),
+		idempotency: redis_client.NewIdempotencyStore(redis, cfg.Task.IdempotencyWindow),
+		close:       db.Close,
+	}, nil
+}
+
+func openMemory(cfg *config.Config) *storage {
+	store := memory.NewStore()
+
+	return &storage{
+		tasks:       memory.NewTaskRepository(store),
+		lists:       memory.NewListRepository(store),
+		tags:        memory.NewTagRepository(store),
+		shares:      memory.NewShareRepository(store),
+		users:       memory.NewUserRepository(store),
+		workspaces:  memory.NewWorkspaceRepository(store),
+		idempotency: memory.NewIdempotencyStore(cfg.Task.IdempotencyWindow),
+		close:       func() error { return nil },
+	}
+}
+
+// openSQLite keeps the idempotency keys in the process like openMemory, a
+// retry after a restart runs again.
+func openSQLite(cfg *config.Config) (*storage, error) {
+	if cfg.Database.Path == "" {
+		return nil, fmt.Errorf("database driver %s needs database.path", sqlite.Driver)
+	}
+	store, err := sqlite.Open(cfg.Database.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage{
+		tasks:       sqlite.NewTaskRepository(store),
+		lists:       sqlite.NewListRepository(store),
+		tags:        sqlite.NewTagRepository(store),
+		shares:      sqlite.NewShareRepository(store),
+		users:       sqlite.NewUserRepository(store),
+		workspaces:  sqlite.NewWorkspaceRepository(store),
+		idempotency: memory.NewIdempotencyStore(cfg.Task.IdempotencyWindow),
+		close:       store.Close,
+	}, nil
+}
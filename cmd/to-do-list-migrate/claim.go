@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// claimSteps give the rows created before users existed to one user. Lists
+// go before tasks, a task only goes in a list of its owner. An orphan tag
+// named like a tag of the user is merged into it. claim takes the events
+// last, around their append only trigger.
+var claimSteps = []struct {
+	table string
+	query string
+}{
+	{"task_tags", `INSERT INTO task_tags (task_id, tag_id)
+	SELECT task_tags.task_id, own.id FROM task_tags
+	JOIN tags orphan ON orphan.id = task_tags.tag_id
+	JOIN tags own ON own.name = orphan.name AND own.owner_id = $1
+	WHERE orphan.owner_id IS NULL
+	ON CONFLICT DO NOTHING`},
+	{"merged tags", `DELETE FROM tags orphan USING tags own
+	WHERE orphan.owner_id IS NULL AND own.owner_id = $1 AND own.name = orphan.name`},
+	{"tags", `UPDATE tags SET owner_id = $1 WHERE owner_id IS NULL`},
+	{"lists", `UPDATE lists SET owner_id = $1 WHERE owner_id IS NULL`},
+	{"tasks", `UPDATE tasks SET owner_id = $1 WHERE owner_id IS NULL`},
+}
+
+const claimEventsQuery = `UPDATE task_events SET owner_id = $1 WHERE owner_id IS NULL`
+
+const fetchUserID = `SELECT id FROM users WHERE email=$1`
+
+// claim makes the user of email the owner of the tasks, lists, tags and
+// events that have none, all of them or none if a step fails.
+func claim(ctx context.Context, db *sql.DB, email string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var userID int64
+	err = tx.QueryRowContext(ctx, fetchUserID, strings.ToLower(strings.TrimSpace(email))).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("no user with the email %q, register first", email)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, step := range claimSteps {
+		if err := claimRows(ctx, tx, step.table, step.query, userID); err != nil {
+			return err
+		}
+	}
+
+	// task_events is append only, its trigger is off for the update and
+	// back on before the commit, like in schema/18_event_users
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE task_events DISABLE TRIGGER task_events_append_only`); err != nil {
+		return fmt.Errorf("disable the task_events trigger: %w", err)
+	}
+	if err := claimRows(ctx, tx, "task_events", claimEventsQuery, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE task_events ENABLE TRIGGER task_events_append_only`); err != nil {
+		return fmt.Errorf("enable the task_events trigger: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// claimRows runs the query of a claim step and prints how many rows of table
+// it gave to the user.
+func claimRows(ctx context.Context, tx *sql.Tx, table, query string, userID int64) error {
+	result, err := tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("claim %s: %w", table, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d\n", table, n)
+	return nil
+}
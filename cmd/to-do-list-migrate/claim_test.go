@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestClaim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "case 1 -> every row without an owner goes to the user",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM users WHERE email=(.*)`).WithArgs("ann@example.com").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectExec(`INSERT INTO task_tags (.*) ON CONFLICT DO NOTHING`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM tags orphan USING tags own`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE tags SET owner_id = (.*) WHERE owner_id IS NULL`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`UPDATE lists SET owner_id = (.*) WHERE owner_id IS NULL`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE tasks SET owner_id = (.*) WHERE owner_id IS NULL`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 5))
+				mock.ExpectExec(`ALTER TABLE task_events DISABLE TRIGGER task_events_append_only`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`UPDATE task_events SET owner_id = (.*) WHERE owner_id IS NULL`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 9))
+				mock.ExpectExec(`ALTER TABLE task_events ENABLE TRIGGER task_events_append_only`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "case 2 -> unknown email",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM users WHERE email=(.*)`).WithArgs("ann@example.com").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "case 3 -> a failed step claims nothing",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM users WHERE email=(.*)`).WithArgs("ann@example.com").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectExec(`INSERT INTO task_tags`).WithArgs(7).WillReturnError(errors.New("connection reset"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "case 4 -> the events are not claimed when their trigger stays on",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM users WHERE email=(.*)`).WithArgs("ann@example.com").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectExec(`INSERT INTO task_tags (.*) ON CONFLICT DO NOTHING`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM tags orphan USING tags own`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`UPDATE tags SET owner_id = (.*) WHERE owner_id IS NULL`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`UPDATE lists SET owner_id = (.*) WHERE owner_id IS NULL`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`UPDATE tasks SET owner_id = (.*) WHERE owner_id IS NULL`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`ALTER TABLE task_events DISABLE TRIGGER task_events_append_only`).WillReturnError(errors.New("must be owner of table task_events"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err := claim(context.Background(), db, " Ann@Example.com ")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Command to-do-list-migrate applies the migrations of schema/ to the
+// database of the config, the same ones the http app applies at start when
+// database.migrate is set.
+//
+//	to-do-list-migrate up            apply every pending migration
+//	to-do-list-migrate down [n]      undo the last n migrations, 1 by default
+//	to-do-list-migrate status        list the migrations and when they were applied
+//	to-do-list-migrate baseline [v]  record the migrations up to v, the last one by
+//	                                 default, as applied without running them, for a
+//	                                 database created from schema/tasks.sql
+//	to-do-list-migrate claim <email> make the user of email the owner of the
+//	                                 tasks, lists, tags and events created
+//	                                 before users existed, which no one sees
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"to-do-list/internal/config"
+	"to-do-list/pkg/migrate"
+	"to-do-list/schema"
+
+	_ "github.com/lib/pq"
+)
+
+const repoName = "to-do-list"
+
+const usage = "usage: to-do-list-migrate up | down [n] | status | baseline [version] | claim <email>"
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	cfg, err := config.New(repoName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := sql.Open(cfg.Database.Driver, cfg.Database.DataSource())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	if os.Args[1] == "claim" {
+		if len(os.Args) != 3 {
+			db.Close()
+			log.Fatal(usage)
+		}
+		if err := claim(context.Background(), db, os.Args[2]); err != nil {
+			db.Close()
+			log.Fatal(err)
+		}
+		return
+	}
+
+	migrator, err := migrate.New(db, schema.FS)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := run(context.Background(), migrator, os.Args[1], os.Args[2:]); err != nil {
+		db.Close()
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, migrator *migrate.Migrator, command string, args []string) error {
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %d_%s\n", m.Version, m.Name)
+		}
+		return err
+
+	case "down":
+		n, err := argInt(args, 1)
+		if err != nil {
+			return err
+		}
+		undone, err := migrator.Down(ctx, n)
+		for _, m := range undone {
+			fmt.Printf("undone %d_%s\n", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range states {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%02d_%-20s %s\n", s.Version, s.Name, applied)
+		}
+		return nil
+
+	case "baseline":
+		version, err := argInt(args, migrator.Latest())
+		if err != nil {
+			return err
+		}
+		return migrator.Baseline(ctx, version)
+	}
+
+	return fmt.Errorf("unknown command %q, %s", command, usage)
+}
+
+func argInt(args []string, fallback int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a positive number, %s", args[0], usage)
+	}
+	return n, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 	"to-do-list/pkg/env"
 
 	"gopkg.in/yaml.v3"
@@ -32,9 +33,13 @@ func newWithFile(filename string) (*Config, error) {
 }
 
 type Config struct {
-	Server   Server   `yaml:"server"`
-	Database Database `yaml:"database"`
-	Redis    Redis    `yaml:"redis"`
+	Server    Server    `yaml:"server"`
+	Database  Database  `yaml:"database"`
+	Redis     Redis     `yaml:"redis"`
+	Task      Task      `yaml:"task"`
+	Trash     Trash     `yaml:"trash"`
+	Auth      Auth      `yaml:"auth"`
+	Workspace Workspace `yaml:"workspace"`
 }
 
 type Server struct {
@@ -46,13 +51,26 @@ type HTTPServer struct {
 }
 
 type Database struct {
+	// postgres, sqlite to keep everything in the file at path, created at
+	// the first start, or memory to keep everything in the process and lose
+	// it on exit. Only postgres reads the rest of the database config and
+	// redis.
 	Driver     string `yaml:"driver"`
+	Path       string `yaml:"path"`
 	Host       string `yaml:"host"`
 	Port       int    `yaml:"port"`
 	User       string `yaml:"user"`
 	Password   string `yaml:"password"`
 	DbName     string `yaml:"dbname"`
 	Credential string `yaml:"credential"`
+	// apply the pending migrations of schema/ at start, see
+	// cmd/to-do-list-migrate to run them by hand
+	Migrate bool `yaml:"migrate"`
+}
+
+// DataSource fills the credential with the rest of the database config.
+func (d Database) DataSource() string {
+	return fmt.Sprintf(d.Credential, d.Host, d.Port, d.User, d.Password, d.DbName)
 }
 
 type Redis struct {
@@ -60,6 +78,39 @@ type Redis struct {
 	Password string `yaml:"password"`
 }
 
+type Task struct {
+	// refuse to complete a task while some of its subtasks are open
+	BlockOpenSubtasks bool `yaml:"block_open_subtasks"`
+	// how long the response to an Idempotency-Key is replayed, e.g. 24h
+	IdempotencyWindow time.Duration `yaml:"idempotency_window"`
+	// how often the keys of the manual order are checked for length, e.g. 1h
+	RebalanceInterval time.Duration `yaml:"rebalance_interval"`
+}
+
+type Trash struct {
+	// how long deleted tasks stay in the trash before they are purged, e.g. 720h
+	Retention time.Duration `yaml:"retention"`
+	// how often the purger looks for expired trash, e.g. 1h
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+type Auth struct {
+	// key that signs access tokens, required, keep it out of version control
+	// outside development
+	Secret string `yaml:"secret"`
+	// how long an access token is accepted, e.g. 15m
+	AccessTTL time.Duration `yaml:"access_ttl"`
+	// how long a refresh token can be traded for new tokens, e.g. 720h
+	RefreshTTL time.Duration `yaml:"refresh_ttl"`
+}
+
+type Workspace struct {
+	// domain whose subdomains name workspaces, acme.<domain> works in the
+	// workspace acme, e.g. todo.example.com. Empty leaves only the
+	// X-Workspace header
+	Domain string `yaml:"domain"`
+}
+
 func getConfigFile(repoName, env string) string {
 	var (
 		filename = fmt.Sprintf("%s.%s.yaml", repoName, env)
@@ -0,0 +1,261 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	taskhandler "to-do-list/internal/handler/http/task"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	useCase ListUsecase
+}
+
+func NewHandler(useCase ListUsecase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+type ListUsecase interface {
+	GetAllList(ctx context.Context) ([]model.ListModel, error)
+	GetList(ctx context.Context, id int64) (model.ListModel, error)
+	GetListTasks(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error)
+	CreateList(ctx context.Context, r model.ListModel) (model.ListModel, error)
+	UpdateList(ctx context.Context, r model.ListModel) (model.ListModel, error)
+	DeleteList(ctx context.Context, id int64, opts model.DeleteOptions) error
+	GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error)
+	InviteMember(ctx context.Context, id int64, r model.InviteRequest) (model.MemberModel, error)
+	AcceptInvitation(ctx context.Context, id int64) (model.MemberModel, error)
+	RemoveMember(ctx context.Context, id int64, userID int64) error
+	GetInvitations(ctx context.Context) ([]model.MemberModel, error)
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	data, err := h.useCase.GetAllList(ctx)
+
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := util.ResponseStandard{
+		Message: "Lists",
+		Data:    data,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Get All List] Response error")
+	}
+}
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.useCase.GetList(ctx, id)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := util.ResponseStandard{
+		Message: "List Found",
+		Data:    data,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Get List] Response error")
+	}
+}
+
+// Tasks pages through the tasks of a list with the query parameters of the
+// task list.
+func (h *Handler) Tasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, errorFields := taskhandler.ParseFilter(r.URL.Query())
+	if errorFields != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   errorFields,
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	page, err := h.useCase.GetListTasks(ctx, id, filter)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if taskhandler.NotModified(w, r, taskhandler.PageETag(page)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	responses := taskhandler.ResponsePage{
+		Message:    "Task List",
+		Data:       page.Tasks,
+		NextCursor: page.NextCursor,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[List Tasks] Response error")
+	}
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.ListModel{}
+		status  = http.StatusCreated
+	)
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.CreateList(ctx, request)
+
+	responses := util.ResponseStandard{
+		Message: "List Created",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Create List] Response error")
+	}
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.ListModel{}
+		status  = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	request.ID = id
+	data, err := h.useCase.UpdateList(ctx, request)
+
+	responses := util.ResponseStandard{
+		Message: "List Updated",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Update List] Response error")
+	}
+}
+
+// Delete removes the list. The mode query parameter says whether its tasks
+// go to the trash (cascade) or move to the list in the to parameter, or to
+// no list without it (reassign).
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	opts, errorFields := ParseDeleteOptions(r.URL.Query())
+	if errorFields != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   errorFields,
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	status := http.StatusOK
+	responses := util.ResponseStandard{
+		Message: "List Deleted",
+		Data:    util.StatusRespose{Success: true},
+	}
+
+	err := h.useCase.DeleteList(ctx, id, opts)
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Delete List] Response error")
+	}
+}
+
+func urlID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "List not found"}, http.StatusNotFound, w)
+		return 0, false
+	}
+	return id, true
+}
+
+// decode reads and validates the request body, answering 422 itself when
+// the body is unusable.
+func decode(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	if err := json.Unmarshal(reqBody, request); err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []taskmodel.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	if validate := Validate(request); validate != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   validate,
+		}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	return true
+}
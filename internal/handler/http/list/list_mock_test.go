@@ -0,0 +1,66 @@
+package list
+
+import (
+	"context"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+)
+
+type ListUsecaseMock struct {
+	GetAllListFunc   func(ctx context.Context) ([]model.ListModel, error)
+	GetListFunc      func(ctx context.Context, id int64) (model.ListModel, error)
+	GetListTasksFunc func(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error)
+	CreateListFunc   func(ctx context.Context, r model.ListModel) (model.ListModel, error)
+	UpdateListFunc   func(ctx context.Context, r model.ListModel) (model.ListModel, error)
+	DeleteListFunc   func(ctx context.Context, id int64, opts model.DeleteOptions) error
+
+	GetMembersFunc       func(ctx context.Context, id int64) ([]model.MemberModel, error)
+	InviteMemberFunc     func(ctx context.Context, id int64, r model.InviteRequest) (model.MemberModel, error)
+	AcceptInvitationFunc func(ctx context.Context, id int64) (model.MemberModel, error)
+	RemoveMemberFunc     func(ctx context.Context, id int64, userID int64) error
+	GetInvitationsFunc   func(ctx context.Context) ([]model.MemberModel, error)
+}
+
+func (m *ListUsecaseMock) GetAllList(ctx context.Context) ([]model.ListModel, error) {
+	return m.GetAllListFunc(ctx)
+}
+
+func (m *ListUsecaseMock) GetList(ctx context.Context, id int64) (model.ListModel, error) {
+	return m.GetListFunc(ctx, id)
+}
+
+func (m *ListUsecaseMock) GetListTasks(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+	return m.GetListTasksFunc(ctx, id, filter)
+}
+
+func (m *ListUsecaseMock) CreateList(ctx context.Context, r model.ListModel) (model.ListModel, error) {
+	return m.CreateListFunc(ctx, r)
+}
+
+func (m *ListUsecaseMock) UpdateList(ctx context.Context, r model.ListModel) (model.ListModel, error) {
+	return m.UpdateListFunc(ctx, r)
+}
+
+func (m *ListUsecaseMock) DeleteList(ctx context.Context, id int64, opts model.DeleteOptions) error {
+	return m.DeleteListFunc(ctx, id, opts)
+}
+
+func (m *ListUsecaseMock) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	return m.GetMembersFunc(ctx, id)
+}
+
+func (m *ListUsecaseMock) InviteMember(ctx context.Context, id int64, r model.InviteRequest) (model.MemberModel, error) {
+	return m.InviteMemberFunc(ctx, id, r)
+}
+
+func (m *ListUsecaseMock) AcceptInvitation(ctx context.Context, id int64) (model.MemberModel, error) {
+	return m.AcceptInvitationFunc(ctx, id)
+}
+
+func (m *ListUsecaseMock) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	return m.RemoveMemberFunc(ctx, id, userID)
+}
+
+func (m *ListUsecaseMock) GetInvitations(ctx context.Context) ([]model.MemberModel, error) {
+	return m.GetInvitationsFunc(ctx)
+}
@@ -0,0 +1,204 @@
+package list
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	taskhandler "to-do-list/internal/handler/http/task"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Create(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *ListUsecaseMock
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when create list",
+			useCase: &ListUsecaseMock{
+				CreateListFunc: func(ctx context.Context, r model.ListModel) (model.ListModel, error) {
+					r.ID = 1
+					return r, nil
+				},
+			},
+			body:     `{"name":"Work"}`,
+			wantCode: http.StatusCreated,
+			wantResponse: util.ResponseStandard{
+				Message: "List Created",
+				Data:    model.ListModel{ID: 1, Name: "Work"},
+			},
+		},
+		{
+			name:     "case 2 -> fail when name is empty",
+			useCase:  &ListUsecaseMock{},
+			body:     `{}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []taskmodel.ErrorField{
+				{FieldName: "Name", Message: "Name is required"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Post("/api/lists", h.Create)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/lists", bytes.NewBufferString(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_Tasks(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *ListUsecaseMock
+		url          string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when list tasks of a list",
+			useCase: &ListUsecaseMock{
+				GetListTasksFunc: func(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+					return taskmodel.TaskPage{
+						Tasks:      []taskmodel.TaskModel{{ID: 1, TaskName: "task 1"}},
+						NextCursor: "next",
+					}, nil
+				},
+			},
+			url:      "/api/lists/2/tasks?limit=1",
+			wantCode: http.StatusOK,
+			wantResponse: taskhandler.ResponsePage{
+				Message:    "Task List",
+				Data:       []taskmodel.TaskModel{{ID: 1, TaskName: "task 1"}},
+				NextCursor: "next",
+			},
+		},
+		{
+			name: "case 2 -> fail when list not found",
+			useCase: &ListUsecaseMock{
+				GetListTasksFunc: func(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+					return taskmodel.TaskPage{}, apperror.New(apperror.ErrNotFound, "list not found")
+				},
+			},
+			url:          "/api/lists/9/tasks",
+			wantCode:     http.StatusNotFound,
+			wantResponse: util.ErrorResponse{Message: "list not found"},
+		},
+		{
+			name:     "case 3 -> fail when filter is invalid",
+			useCase:  &ListUsecaseMock{},
+			url:      "/api/lists/2/tasks?is_done=maybe",
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []taskmodel.ErrorField{
+				{FieldName: "is_done", Message: "is_done must be true or false"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Get("/api/lists/{id}/tasks", h.Tasks)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", tt.url, nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *ListUsecaseMock
+		url          string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when delete list and reassign its tasks",
+			useCase: &ListUsecaseMock{
+				DeleteListFunc: func(ctx context.Context, id int64, opts model.DeleteOptions) error {
+					if id != 1 || opts.Mode != model.DeleteReassign || opts.To == nil || *opts.To != 2 {
+						return apperror.New(apperror.ErrValidation, "unexpected options")
+					}
+					return nil
+				},
+			},
+			url:      "/api/lists/1?mode=reassign&to=2",
+			wantCode: http.StatusOK,
+			wantResponse: util.ResponseStandard{
+				Message: "List Deleted",
+				Data:    util.StatusRespose{Success: true},
+			},
+		},
+		{
+			name:     "case 2 -> fail when target list is not an id",
+			useCase:  &ListUsecaseMock{},
+			url:      "/api/lists/1?mode=reassign&to=work",
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []taskmodel.ErrorField{
+				{FieldName: "to", Message: "to must be a list id"},
+			}},
+		},
+		{
+			name: "case 3 -> fail when mode is missing",
+			useCase: &ListUsecaseMock{
+				DeleteListFunc: func(ctx context.Context, id int64, opts model.DeleteOptions) error {
+					return apperror.New(apperror.ErrValidation, "mode must be cascade or reassign")
+				},
+			},
+			url:      "/api/lists/1",
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ResponseStandard{
+				Message: "mode must be cascade or reassign",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Delete("/api/lists/{id}", h.Delete)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("DELETE", tt.url, nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
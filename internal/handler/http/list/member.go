@@ -0,0 +1,142 @@
+package list
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	model "to-do-list/internal/model/list"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) Members(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.useCase.GetMembers(ctx, id)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if err := util.ResponseJSON(data, http.StatusOK, w); err != nil {
+		fmt.Println("[Get Members] Response error")
+	}
+}
+
+// Invite shares the list with a user by email, inviting a member again
+// changes their role.
+func (h *Handler) Invite(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.InviteRequest{}
+		status  = http.StatusCreated
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.InviteMember(ctx, id, request)
+
+	responses := util.ResponseStandard{
+		Message: "Member Invited",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Invite Member] Response error")
+	}
+}
+
+func (h *Handler) Accept(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		status = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.useCase.AcceptInvitation(ctx, id)
+
+	responses := util.ResponseStandard{
+		Message: "Invitation Accepted",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Accept Invitation] Response error")
+	}
+}
+
+// RemoveMember revokes a membership or an invitation. Members remove
+// themselves to leave a list or decline an invitation.
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.ParseInt(chi.URLParam(r, "user_id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Member not found"}, http.StatusNotFound, w)
+		return
+	}
+
+	status := http.StatusOK
+	responses := util.ResponseStandard{
+		Message: "Member Removed",
+		Data:    util.StatusRespose{Success: true},
+	}
+
+	if err := h.useCase.RemoveMember(ctx, id, userID); err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Remove Member] Response error")
+	}
+}
+
+func (h *Handler) Invitations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	data, err := h.useCase.GetInvitations(ctx)
+
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if err := util.ResponseJSON(data, http.StatusOK, w); err != nil {
+		fmt.Println("[Get Invitations] Response error")
+	}
+}
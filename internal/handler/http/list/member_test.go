@@ -0,0 +1,136 @@
+package list
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Invite(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *ListUsecaseMock
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when invite a member",
+			useCase: &ListUsecaseMock{
+				InviteMemberFunc: func(ctx context.Context, id int64, r model.InviteRequest) (model.MemberModel, error) {
+					return model.MemberModel{ListID: id, UserID: 2, Email: r.Email, Role: r.Role}, nil
+				},
+			},
+			body:     `{"email":"bob@example.com","role":"editor"}`,
+			wantCode: http.StatusCreated,
+			wantResponse: util.ResponseStandard{
+				Message: "Member Invited",
+				Data:    model.MemberModel{ListID: 1, UserID: 2, Email: "bob@example.com", Role: model.RoleEditor},
+			},
+		},
+		{
+			name:     "case 2 -> fail when role is unknown",
+			useCase:  &ListUsecaseMock{},
+			body:     `{"email":"bob@example.com","role":"admin"}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []taskmodel.ErrorField{
+				{FieldName: "Role", Message: "Role is oneof"},
+			}},
+		},
+		{
+			name: "case 3 -> fail when the caller is not an owner of the list",
+			useCase: &ListUsecaseMock{
+				InviteMemberFunc: func(ctx context.Context, id int64, r model.InviteRequest) (model.MemberModel, error) {
+					return model.MemberModel{}, apperror.New(apperror.ErrForbidden, "the owner role on the list is needed, you are editor")
+				},
+			},
+			body:     `{"email":"bob@example.com","role":"viewer"}`,
+			wantCode: http.StatusForbidden,
+			wantResponse: util.ResponseStandard{
+				Message: "the owner role on the list is needed, you are editor",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Post("/api/lists/{id}/members", h.Invite)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/lists/1/members", bytes.NewBufferString(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_RemoveMember(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *ListUsecaseMock
+		url          string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when remove a member",
+			useCase: &ListUsecaseMock{
+				RemoveMemberFunc: func(ctx context.Context, id int64, userID int64) error {
+					if id != 1 || userID != 2 {
+						return apperror.New(apperror.ErrNotFound, "member not found")
+					}
+					return nil
+				},
+			},
+			url:      "/api/lists/1/members/2",
+			wantCode: http.StatusOK,
+			wantResponse: util.ResponseStandard{
+				Message: "Member Removed",
+				Data:    util.StatusRespose{Success: true},
+			},
+		},
+		{
+			name:     "case 2 -> fail when member is not an id",
+			useCase:  &ListUsecaseMock{},
+			url:      "/api/lists/1/members/bob",
+			wantCode: http.StatusNotFound,
+			wantResponse: util.ErrorResponse{
+				Message: "Member not found",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Delete("/api/lists/{id}/members/{user_id}", h.RemoveMember)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("DELETE", tt.url, nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
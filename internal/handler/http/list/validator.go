@@ -0,0 +1,47 @@
+package list
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+func Validate(request interface{}) []taskmodel.ErrorField {
+	validate := validator.New()
+	err := validate.Struct(request)
+
+	if err != nil {
+		var (
+			arrErrorField = []taskmodel.ErrorField{}
+			errorField    = taskmodel.ErrorField{}
+		)
+		for _, err := range err.(validator.ValidationErrors) {
+			errorField.FieldName = err.Field()
+			errorField.Message = fmt.Sprintf("%v is %v", err.Field(), err.ActualTag())
+			arrErrorField = append(arrErrorField, errorField)
+		}
+
+		return arrErrorField
+	}
+	return nil
+}
+
+// ParseDeleteOptions reads the mode and to query parameters of a list
+// deletion. The usecase checks the mode.
+func ParseDeleteOptions(query url.Values) (model.DeleteOptions, []taskmodel.ErrorField) {
+	opts := model.DeleteOptions{Mode: query.Get("mode")}
+
+	if to := query.Get("to"); to != "" {
+		id, err := strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			return opts, []taskmodel.ErrorField{{FieldName: "to", Message: "to must be a list id"}}
+		}
+		opts.To = &id
+	}
+
+	return opts, nil
+}
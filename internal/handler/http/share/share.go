@@ -0,0 +1,168 @@
+package share
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	taskhandler "to-do-list/internal/handler/http/task"
+	model "to-do-list/internal/model/share"
+	taskmodel "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	useCase ShareUsecase
+}
+
+func NewHandler(useCase ShareUsecase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+type ShareUsecase interface {
+	CreateLink(ctx context.Context, r model.LinkRequest) (model.LinkModel, error)
+	GetLinks(ctx context.Context) ([]model.LinkModel, error)
+	RevokeLink(ctx context.Context, id int64) error
+	GetShared(ctx context.Context, token string) (model.SharedModel, error)
+	GetSharedTasks(ctx context.Context, token string, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.LinkRequest{}
+		status  = http.StatusCreated
+	)
+
+	reqBody, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(reqBody, &request)
+	}
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []taskmodel.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	data, err := h.useCase.CreateLink(ctx, request)
+
+	responses := util.ResponseStandard{
+		Message: "Link Created",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Create Link] Response error")
+	}
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	responses, err := h.useCase.GetLinks(ctx)
+
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Get Links] Response error")
+	}
+}
+
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Share link not found"}, http.StatusNotFound, w)
+		return
+	}
+
+	status := http.StatusOK
+	responses := util.ResponseStandard{
+		Message: "Link Revoked",
+		Data:    util.StatusRespose{Success: true},
+	}
+
+	if err := h.useCase.RevokeLink(ctx, id); err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Revoke Link] Response error")
+	}
+}
+
+// Shared is the public page of a link, for visitors who are not signed in.
+func (h *Handler) Shared(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	public(w)
+
+	data, err := h.useCase.GetShared(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if err := util.ResponseJSON(data, http.StatusOK, w); err != nil {
+		fmt.Println("[Get Shared] Response error")
+	}
+}
+
+// SharedTasks pages through the tasks of a shared list with the query
+// parameters of the task list.
+func (h *Handler) SharedTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	public(w)
+
+	filter, errorFields := taskhandler.ParseFilter(r.URL.Query())
+	if errorFields != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   errorFields,
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	page, err := h.useCase.GetSharedTasks(ctx, chi.URLParam(r, "token"), filter)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if taskhandler.NotModified(w, r, taskhandler.PageETag(page)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	responses := taskhandler.ResponsePage{
+		Message:    "Task List",
+		Data:       page.Tasks,
+		NextCursor: page.NextCursor,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Get Shared Tasks] Response error")
+	}
+}
+
+// public keeps the token in the URL out of the Referer of links followed
+// from the page.
+func public(w http.ResponseWriter) {
+	w.Header().Set("Referrer-Policy", "no-referrer")
+}
@@ -0,0 +1,35 @@
+package share
+
+import (
+	"context"
+	model "to-do-list/internal/model/share"
+	taskmodel "to-do-list/internal/model/task"
+)
+
+type ShareUsecaseMock struct {
+	CreateLinkFunc     func(ctx context.Context, r model.LinkRequest) (model.LinkModel, error)
+	GetLinksFunc       func(ctx context.Context) ([]model.LinkModel, error)
+	RevokeLinkFunc     func(ctx context.Context, id int64) error
+	GetSharedFunc      func(ctx context.Context, token string) (model.SharedModel, error)
+	GetSharedTasksFunc func(ctx context.Context, token string, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error)
+}
+
+func (m *ShareUsecaseMock) CreateLink(ctx context.Context, r model.LinkRequest) (model.LinkModel, error) {
+	return m.CreateLinkFunc(ctx, r)
+}
+
+func (m *ShareUsecaseMock) GetLinks(ctx context.Context) ([]model.LinkModel, error) {
+	return m.GetLinksFunc(ctx)
+}
+
+func (m *ShareUsecaseMock) RevokeLink(ctx context.Context, id int64) error {
+	return m.RevokeLinkFunc(ctx, id)
+}
+
+func (m *ShareUsecaseMock) GetShared(ctx context.Context, token string) (model.SharedModel, error) {
+	return m.GetSharedFunc(ctx, token)
+}
+
+func (m *ShareUsecaseMock) GetSharedTasks(ctx context.Context, token string, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+	return m.GetSharedTasksFunc(ctx, token, filter)
+}
@@ -0,0 +1,125 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/share"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Create(t *testing.T) {
+	listID := int64(4)
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		useCase      *ShareUsecaseMock
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when create a link to a list",
+			useCase: &ShareUsecaseMock{
+				CreateLinkFunc: func(ctx context.Context, r model.LinkRequest) (model.LinkModel, error) {
+					return model.LinkModel{ID: 3, ListID: r.ListID, CreatedAt: createdAt, URL: "/api/shared/3.sig"}, nil
+				},
+			},
+			body:     `{"list_id":4}`,
+			wantCode: http.StatusCreated,
+			wantResponse: util.ResponseStandard{
+				Message: "Link Created",
+				Data:    model.LinkModel{ID: 3, ListID: &listID, CreatedAt: createdAt, URL: "/api/shared/3.sig"},
+			},
+		},
+		{
+			name: "case 2 -> fail when the caller does not own the list",
+			useCase: &ShareUsecaseMock{
+				CreateLinkFunc: func(ctx context.Context, r model.LinkRequest) (model.LinkModel, error) {
+					return model.LinkModel{}, apperror.New(apperror.ErrForbidden, "the owner role is needed to share, you are viewer")
+				},
+			},
+			body:     `{"list_id":4}`,
+			wantCode: http.StatusForbidden,
+			wantResponse: util.ResponseStandard{
+				Message: "the owner role is needed to share, you are viewer",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Post("/api/links", h.Create)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/links", bytes.NewBufferString(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_Shared(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *ShareUsecaseMock
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when the link is valid",
+			useCase: &ShareUsecaseMock{
+				GetSharedFunc: func(ctx context.Context, token string) (model.SharedModel, error) {
+					return model.SharedModel{List: &listmodel.ListModel{ID: 4, Name: "Work"}}, nil
+				},
+			},
+			wantCode:     http.StatusOK,
+			wantResponse: model.SharedModel{List: &listmodel.ListModel{ID: 4, Name: "Work"}},
+		},
+		{
+			name: "case 2 -> fail when the link is revoked",
+			useCase: &ShareUsecaseMock{
+				GetSharedFunc: func(ctx context.Context, token string) (model.SharedModel, error) {
+					return model.SharedModel{}, apperror.New(apperror.ErrNotFound, "share link not found")
+				},
+			},
+			wantCode:     http.StatusNotFound,
+			wantResponse: util.ErrorResponse{Message: "share link not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Get("/api/shared/{token}", h.Shared)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", "/api/shared/3.sig", nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+			assert.Equal(t, "no-referrer", recorder.Header().Get("Referrer-Policy"))
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package tag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	model "to-do-list/internal/model/tag"
+	taskmodel "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	useCase TagUsecase
+}
+
+func NewHandler(useCase TagUsecase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+type TagUsecase interface {
+	GetAllTag(ctx context.Context) ([]model.TagModel, error)
+	CreateTag(ctx context.Context, r model.TagModel) (model.TagModel, error)
+	RenameTag(ctx context.Context, r model.TagModel) (model.TagModel, error)
+	MergeTag(ctx context.Context, from int64, into int64) (model.TagModel, error)
+	DeleteTag(ctx context.Context, r model.TagModel) error
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	responses, err := h.useCase.GetAllTag(ctx)
+
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Get All Tag] Response error")
+	}
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.TagModel{}
+		status  = http.StatusCreated
+	)
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.CreateTag(ctx, request)
+
+	responses := util.ResponseStandard{
+		Message: "Tag Created",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Create Tag] Response error")
+	}
+}
+
+// Update renames the tag. Renaming to the name of another tag is a conflict,
+// use Merge to combine them.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.TagModel{}
+		status  = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	request.ID = id
+	data, err := h.useCase.RenameTag(ctx, request)
+
+	responses := util.ResponseStandard{
+		Message: "Tag Renamed",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Rename Tag] Response error")
+	}
+}
+
+func (h *Handler) Merge(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.MergeRequest{}
+		status  = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.MergeTag(ctx, id, request.Into)
+
+	responses := util.ResponseStandard{
+		Message: "Tag Merged",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Merge Tag] Response error")
+	}
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	status := http.StatusOK
+	responses := util.ResponseStandard{
+		Message: "Tag Deleted",
+		Data:    util.StatusRespose{Success: true},
+	}
+
+	err := h.useCase.DeleteTag(ctx, model.TagModel{ID: id})
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Delete Tag] Response error")
+	}
+}
+
+func urlID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Tag not found"}, http.StatusNotFound, w)
+		return 0, false
+	}
+	return id, true
+}
+
+// decode reads and validates the request body, answering 422 itself when
+// the body is unusable.
+func decode(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	if err := json.Unmarshal(reqBody, request); err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []taskmodel.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	if validate := Validate(request); validate != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   validate,
+		}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,34 @@
+package tag
+
+import (
+	"context"
+	model "to-do-list/internal/model/tag"
+)
+
+type TagUsecaseMock struct {
+	GetAllTagFunc func(ctx context.Context) ([]model.TagModel, error)
+	CreateTagFunc func(ctx context.Context, r model.TagModel) (model.TagModel, error)
+	RenameTagFunc func(ctx context.Context, r model.TagModel) (model.TagModel, error)
+	MergeTagFunc  func(ctx context.Context, from int64, into int64) (model.TagModel, error)
+	DeleteTagFunc func(ctx context.Context, r model.TagModel) error
+}
+
+func (m *TagUsecaseMock) GetAllTag(ctx context.Context) ([]model.TagModel, error) {
+	return m.GetAllTagFunc(ctx)
+}
+
+func (m *TagUsecaseMock) CreateTag(ctx context.Context, r model.TagModel) (model.TagModel, error) {
+	return m.CreateTagFunc(ctx, r)
+}
+
+func (m *TagUsecaseMock) RenameTag(ctx context.Context, r model.TagModel) (model.TagModel, error) {
+	return m.RenameTagFunc(ctx, r)
+}
+
+func (m *TagUsecaseMock) MergeTag(ctx context.Context, from int64, into int64) (model.TagModel, error) {
+	return m.MergeTagFunc(ctx, from, into)
+}
+
+func (m *TagUsecaseMock) DeleteTag(ctx context.Context, r model.TagModel) error {
+	return m.DeleteTagFunc(ctx, r)
+}
@@ -0,0 +1,168 @@
+package tag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	model "to-do-list/internal/model/tag"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Create(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *TagUsecaseMock
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when create tag",
+			useCase: &TagUsecaseMock{
+				CreateTagFunc: func(ctx context.Context, r model.TagModel) (model.TagModel, error) {
+					r.ID = 1
+					return r, nil
+				},
+			},
+			body:     `{"name":"home"}`,
+			wantCode: http.StatusCreated,
+			wantResponse: util.ResponseStandard{
+				Message: "Tag Created",
+				Data:    model.TagModel{ID: 1, Name: "home"},
+			},
+		},
+		{
+			name:     "case 2 -> fail when name is empty",
+			useCase:  &TagUsecaseMock{},
+			body:     `{"name":""}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []taskmodel.ErrorField{
+				{FieldName: "Name", Message: "Name is required"},
+			}},
+		},
+		{
+			name: "case 3 -> fail when tag already exists",
+			useCase: &TagUsecaseMock{
+				CreateTagFunc: func(ctx context.Context, r model.TagModel) (model.TagModel, error) {
+					return r, apperror.New(apperror.ErrConflict, "tag already exists")
+				},
+			},
+			body:     `{"name":"home"}`,
+			wantCode: http.StatusConflict,
+			wantResponse: util.ResponseStandard{
+				Message: "tag already exists",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Post("/api/tag", h.Create)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/tag", bytes.NewBufferString(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_Merge(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *TagUsecaseMock
+		url          string
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when merge tag",
+			useCase: &TagUsecaseMock{
+				MergeTagFunc: func(ctx context.Context, from int64, into int64) (model.TagModel, error) {
+					return model.TagModel{ID: into, Name: "house", TaskCount: 3}, nil
+				},
+			},
+			url:      "/api/tag/1/merge",
+			body:     `{"into":2}`,
+			wantCode: http.StatusOK,
+			wantResponse: util.ResponseStandard{
+				Message: "Tag Merged",
+				Data:    model.TagModel{ID: 2, Name: "house", TaskCount: 3},
+			},
+		},
+		{
+			name:         "case 2 -> fail when id is not a number",
+			useCase:      &TagUsecaseMock{},
+			url:          "/api/tag/abc/merge",
+			body:         `{"into":2}`,
+			wantCode:     http.StatusNotFound,
+			wantResponse: util.ErrorResponse{Message: "Tag not found"},
+		},
+		{
+			name: "case 3 -> fail when tag does not exist",
+			useCase: &TagUsecaseMock{
+				MergeTagFunc: func(ctx context.Context, from int64, into int64) (model.TagModel, error) {
+					return model.TagModel{}, apperror.New(apperror.ErrNotFound, "tag not found")
+				},
+			},
+			url:      "/api/tag/1/merge",
+			body:     `{"into":9}`,
+			wantCode: http.StatusNotFound,
+			wantResponse: util.ResponseStandard{
+				Message: "tag not found",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Post("/api/tag/{id}/merge", h.Merge)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", tt.url, bytes.NewBufferString(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	h := NewHandler(&TagUsecaseMock{
+		DeleteTagFunc: func(ctx context.Context, r model.TagModel) error {
+			return nil
+		},
+	})
+
+	router := chi.NewRouter()
+	router.Delete("/api/tag/{id}", h.Delete)
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("DELETE", "/api/tag/1", nil)
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code, "error code")
+
+	jsonExpect, _ := json.Marshal(util.ResponseStandard{Message: "Tag Deleted", Data: util.StatusRespose{Success: true}})
+	assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+}
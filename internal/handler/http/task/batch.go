@@ -0,0 +1,82 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	model "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+)
+
+// BatchItem is the outcome of one batch operation, with the status and
+// message the single task endpoint would have answered. Operations undone
+// with their batch have status 424.
+type BatchItem struct {
+	Op      string           `json:"op"`
+	ID      int64            `json:"id,omitempty"`
+	Status  int              `json:"status"`
+	Message string           `json:"message,omitempty"`
+	Error   interface{}      `json:"error,omitempty"`
+	Data    *model.TaskModel `json:"data,omitempty"`
+}
+
+// Batch runs create, update, delete and complete operations in one
+// transaction. An all or nothing batch with a failed operation answers with
+// the status of that operation, any other batch with 200.
+func (h *Handler) Batch(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.BatchRequest{}
+		status  = http.StatusOK
+	)
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	results, err := h.useCase.Batch(ctx, request)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := ResponseStandard{
+		Message: "Batch Done",
+	}
+
+	items := make([]BatchItem, len(results))
+	for i, result := range results {
+		items[i] = batchItem(result)
+		if result.Err != nil && status == http.StatusOK && request.Mode != model.BatchBestEffort {
+			status = items[i].Status
+			responses.Message = "Batch Rolled Back"
+		}
+	}
+	responses.Data = items
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Batch] Response error")
+	}
+}
+
+func batchItem(result model.BatchResult) BatchItem {
+	item := BatchItem{Op: result.Op, ID: result.ID, Status: http.StatusOK, Data: result.Task}
+	if result.Op == model.BatchCreate {
+		item.Status = http.StatusCreated
+	}
+
+	if result.RolledBack {
+		item.Status = http.StatusFailedDependency
+		item.Message = "rolled back with the batch"
+	}
+	if result.Err != nil {
+		item.Status = util.StatusFromError(result.Err)
+		item.Message = util.MessageFromError(result.Err)
+
+		var fields model.FieldErrors
+		if errors.As(result.Err, &fields) {
+			item.Error = []model.ErrorField(fields)
+		}
+	}
+	return item
+}
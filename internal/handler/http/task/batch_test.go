@@ -0,0 +1,113 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Batch(t *testing.T) {
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> every operation applied",
+			taskUseCase: &TaskUsecaseMock{BatchFunc: func(ctx context.Context, r model.BatchRequest) ([]model.BatchResult, error) {
+				return []model.BatchResult{
+					{Op: model.BatchCreate, ID: 4, Task: &model.TaskModel{ID: 4, TaskName: "task 4", Version: 1}},
+					{Op: model.BatchDelete, ID: 3},
+				}, nil
+			}},
+			body:     `{"operations":[{"op":"create","task":{"task_name":"task 4"}},{"op":"delete","id":3}]}`,
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Batch Done",
+				Data: []BatchItem{
+					{Op: model.BatchCreate, ID: 4, Status: http.StatusCreated, Data: &model.TaskModel{ID: 4, TaskName: "task 4", Version: 1}},
+					{Op: model.BatchDelete, ID: 3, Status: http.StatusOK},
+				},
+			},
+		},
+		{
+			name: "case 2 -> all or nothing batch rolled back",
+			taskUseCase: &TaskUsecaseMock{BatchFunc: func(ctx context.Context, r model.BatchRequest) ([]model.BatchResult, error) {
+				return []model.BatchResult{
+					{Op: model.BatchComplete, ID: 2, RolledBack: true},
+					{Op: model.BatchCreate, Err: apperror.Wrap(apperror.ErrValidation, "Invalid Request Data", model.FieldErrors{
+						{FieldName: "TaskName", Message: "TaskName is required"},
+					})},
+				}, nil
+			}},
+			body:     `{"operations":[{"op":"complete","id":2},{"op":"create","task":{}}]}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: ResponseStandard{
+				Message: "Batch Rolled Back",
+				Data: []BatchItem{
+					{Op: model.BatchComplete, ID: 2, Status: http.StatusFailedDependency, Message: "rolled back with the batch"},
+					{Op: model.BatchCreate, Status: http.StatusUnprocessableEntity, Message: "Invalid Request Data", Error: []model.ErrorField{
+						{FieldName: "TaskName", Message: "TaskName is required"},
+					}},
+				},
+			},
+		},
+		{
+			name: "case 3 -> best effort batch with a failed operation",
+			taskUseCase: &TaskUsecaseMock{BatchFunc: func(ctx context.Context, r model.BatchRequest) ([]model.BatchResult, error) {
+				return []model.BatchResult{
+					{Op: model.BatchComplete, ID: 2, Task: &model.TaskModel{ID: 2, TaskName: "task 2", IsDone: true, Version: 2}},
+					{Op: model.BatchDelete, ID: 9, Err: apperror.New(apperror.ErrNotFound, "task not found")},
+				}, nil
+			}},
+			body:     `{"mode":"best_effort","operations":[{"op":"complete","id":2},{"op":"delete","id":9}]}`,
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Batch Done",
+				Data: []BatchItem{
+					{Op: model.BatchComplete, ID: 2, Status: http.StatusOK, Data: &model.TaskModel{ID: 2, TaskName: "task 2", IsDone: true, Version: 2}},
+					{Op: model.BatchDelete, ID: 9, Status: http.StatusNotFound, Message: "task not found"},
+				},
+			},
+		},
+		{
+			name: "case 4 -> invalid batch",
+			taskUseCase: &TaskUsecaseMock{BatchFunc: func(ctx context.Context, r model.BatchRequest) ([]model.BatchResult, error) {
+				return nil, apperror.New(apperror.ErrValidation, "operations is required")
+			}},
+			body:         `{"operations":[]}`,
+			wantCode:     http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "operations is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Post("/api/tasks/batch", h.Batch)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/tasks/batch", bytes.NewBufferString(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
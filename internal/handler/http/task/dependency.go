@@ -0,0 +1,134 @@
+package task
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	model "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Dependencies lists the tasks the task waits for.
+func (h *Handler) Dependencies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.useCase.GetBlockers(ctx, id)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := ResponseStandard{
+		Message: "Task Dependencies",
+		Data:    data,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Dependencies] Response error")
+	}
+}
+
+func (h *Handler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.DependencyRequest{}
+		status  = http.StatusCreated
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	if request.BlockedBy < 1 {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []model.ErrorField{{FieldName: "blocked_by", Message: "blocked_by must be the id of a task"}},
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	dependency := model.Dependency{TaskID: id, BlockedBy: request.BlockedBy}
+	err := h.useCase.AddDependency(ctx, dependency)
+
+	responses := ResponseStandard{
+		Message: "Dependency Added",
+		Data:    dependency,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Add Dependency] Response error")
+	}
+}
+
+func (h *Handler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		status = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	blockedBy, err := strconv.ParseInt(chi.URLParam(r, "blocked_by"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Dependency not found"}, http.StatusNotFound, w)
+		return
+	}
+
+	err = h.useCase.RemoveDependency(ctx, model.Dependency{TaskID: id, BlockedBy: blockedBy})
+
+	responses := ResponseStandard{
+		Message: "Dependency Removed",
+		Data:    StatusRespose{Success: true},
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Remove Dependency] Response error")
+	}
+}
+
+// Plan lists the open tasks in an order that respects their dependencies.
+func (h *Handler) Plan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := h.useCase.Plan(ctx)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := ResponseStandard{
+		Message: "Task Plan",
+		Data:    data,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Plan] Response error")
+	}
+}
@@ -0,0 +1,154 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Dependencies(t *testing.T) {
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		method       string
+		url          string
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when list dependencies",
+			taskUseCase: &TaskUsecaseMock{
+				GetBlockersFunc: func(ctx context.Context, id int64) ([]model.TaskModel, error) {
+					return []model.TaskModel{{ID: 1, TaskName: "task 1", Version: 1}}, nil
+				},
+			},
+			method:   "GET",
+			url:      "/api/task/2/dependencies",
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Task Dependencies",
+				Data:    []model.TaskModel{{ID: 1, TaskName: "task 1", Version: 1}},
+			},
+		},
+		{
+			name: "case 2 -> success when add dependency",
+			taskUseCase: &TaskUsecaseMock{
+				AddDependencyFunc: func(ctx context.Context, dependency model.Dependency) error {
+					return nil
+				},
+			},
+			method:   "POST",
+			url:      "/api/task/2/dependencies",
+			body:     `{"blocked_by": 1}`,
+			wantCode: http.StatusCreated,
+			wantResponse: ResponseStandard{
+				Message: "Dependency Added",
+				Data:    model.Dependency{TaskID: 2, BlockedBy: 1},
+			},
+		},
+		{
+			name:     "case 3 -> add dependency without blocker",
+			method:   "POST",
+			url:      "/api/task/2/dependencies",
+			body:     `{}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{
+				Message: "Invalid Request Data",
+				Error:   []model.ErrorField{{FieldName: "blocked_by", Message: "blocked_by must be the id of a task"}},
+			},
+		},
+		{
+			name: "case 4 -> add dependency creating a cycle",
+			taskUseCase: &TaskUsecaseMock{
+				AddDependencyFunc: func(ctx context.Context, dependency model.Dependency) error {
+					return apperror.New(apperror.ErrConflict, "dependency would create a cycle: 1 -> 2 -> 1")
+				},
+			},
+			method:   "POST",
+			url:      "/api/task/1/dependencies",
+			body:     `{"blocked_by": 2}`,
+			wantCode: http.StatusConflict,
+			wantResponse: ResponseStandard{
+				Message: "dependency would create a cycle: 1 -> 2 -> 1",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+		{
+			name: "case 5 -> success when remove dependency",
+			taskUseCase: &TaskUsecaseMock{
+				RemoveDependencyFunc: func(ctx context.Context, dependency model.Dependency) error {
+					return nil
+				},
+			},
+			method:   "DELETE",
+			url:      "/api/task/2/dependencies/1",
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Dependency Removed",
+				Data:    StatusRespose{Success: true},
+			},
+		},
+		{
+			name: "case 6 -> remove missing dependency",
+			taskUseCase: &TaskUsecaseMock{
+				RemoveDependencyFunc: func(ctx context.Context, dependency model.Dependency) error {
+					return apperror.New(apperror.ErrNotFound, "dependency not found")
+				},
+			},
+			method:   "DELETE",
+			url:      "/api/task/2/dependencies/3",
+			wantCode: http.StatusNotFound,
+			wantResponse: ResponseStandard{
+				Message: "dependency not found",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+		{
+			name: "case 7 -> success when plan tasks",
+			taskUseCase: &TaskUsecaseMock{
+				PlanFunc: func(ctx context.Context) ([]model.TaskModel, error) {
+					return []model.TaskModel{{ID: 1, TaskName: "task 1", Version: 1}, {ID: 2, TaskName: "task 2", Version: 1, Blocked: true}}, nil
+				},
+			},
+			method:   "GET",
+			url:      "/api/tasks/plan",
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Task Plan",
+				Data:    []model.TaskModel{{ID: 1, TaskName: "task 1", Version: 1}, {ID: 2, TaskName: "task 2", Version: 1, Blocked: true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Get("/api/task/{id}/dependencies", h.Dependencies)
+			router.Post("/api/task/{id}/dependencies", h.AddDependency)
+			router.Delete("/api/task/{id}/dependencies/{blocked_by}", h.RemoveDependency)
+			router.Get("/api/tasks/plan", h.Plan)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package task
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	model "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+)
+
+// ETag is the entity tag of a task at a version.
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// PageETag changes whenever a task of the page changes, or the page holds
+// other tasks. A task gets blocked, and its rank spread again, without a new
+// version, so both are part of the tag too.
+func PageETag(page model.TaskPage) string {
+	hash := sha1.New()
+	for _, task := range page.Tasks {
+		fmt.Fprintf(hash, "%d:%d:%s:%t,", task.ID, task.Version, task.Rank, task.Blocked)
+	}
+	hash.Write([]byte(page.NextCursor))
+	return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`
+}
+
+// NotModified sets the ETag header and reports whether the If-None-Match
+// header of the request matches it. Weak tags match their strong form.
+func NotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	for _, tag := range etagList(r.Header.Get("If-None-Match")) {
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedVersion turns the If-Match header into the version a write has to
+// find, 0 when there is no header. It answers the request itself and
+// returns false when the header does not match the task.
+func (h *Handler) expectedVersion(w http.ResponseWriter, r *http.Request, id int64) (int64, bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return 0, true
+	}
+
+	current, err := h.useCase.GetTask(r.Context(), id)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return 0, false
+	}
+
+	etag := ETag(current.Version)
+	for _, tag := range etagList(header) {
+		if tag == "*" || tag == etag {
+			return current.Version, true
+		}
+	}
+
+	w.Header().Set("ETag", etag)
+	util.ResponseErrorJSON(&util.ErrorResponse{Message: "Precondition Failed"}, http.StatusPreconditionFailed, w)
+	return 0, false
+}
+
+func etagList(header string) []string {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
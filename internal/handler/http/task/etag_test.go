@@ -0,0 +1,179 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ETag(t *testing.T) {
+	getTask := func(ctx context.Context, id int64) (model.TaskModel, error) {
+		return model.TaskModel{ID: id, TaskName: "task 1", Version: 3}, nil
+	}
+	page := model.TaskPage{Tasks: []model.TaskModel{{ID: 1, TaskName: "task 1", Version: 3}}}
+
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		method       string
+		url          string
+		header       map[string]string
+		body         string
+		wantCode     int
+		wantETag     string
+		wantResponse interface{}
+	}{
+		{
+			name:        "case 1 -> get task returns its etag",
+			taskUseCase: &TaskUsecaseMock{GetTaskFunc: getTask},
+			method:      "GET",
+			url:         "/api/task/1",
+			wantCode:    http.StatusOK,
+			wantETag:    `"3"`,
+			wantResponse: ResponseStandard{
+				Message: "Task Found",
+				Data:    model.TaskModel{ID: 1, TaskName: "task 1", Version: 3},
+			},
+		},
+		{
+			name:        "case 2 -> get task not modified",
+			taskUseCase: &TaskUsecaseMock{GetTaskFunc: getTask},
+			method:      "GET",
+			url:         "/api/task/1",
+			header:      map[string]string{"If-None-Match": `"2", W/"3"`},
+			wantCode:    http.StatusNotModified,
+			wantETag:    `"3"`,
+		},
+		{
+			name: "case 3 -> task list not modified",
+			taskUseCase: &TaskUsecaseMock{GetAllTaskFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+				return page, nil
+			}},
+			method:   "GET",
+			url:      "/api/tasks",
+			header:   map[string]string{"If-None-Match": PageETag(page)},
+			wantCode: http.StatusNotModified,
+			wantETag: PageETag(page),
+		},
+		{
+			name: "case 4 -> update with matching if-match",
+			taskUseCase: &TaskUsecaseMock{
+				GetTaskFunc: getTask,
+				UpdateTaskFunc: func(ctx context.Context, r model.TaskModel) (model.TaskModel, error) {
+					if r.Version != 3 {
+						return r, apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+					}
+					r.Version++
+					return r, nil
+				},
+			},
+			method:   "PUT",
+			url:      "/api/task/1",
+			header:   map[string]string{"If-Match": `"3"`},
+			body:     `{"task_name":"renamed","version":1}`,
+			wantCode: http.StatusOK,
+			wantETag: `"4"`,
+			wantResponse: ResponseStandard{
+				Message: "Task Updated",
+				Data:    model.TaskModel{ID: 1, TaskName: "renamed", Version: 4},
+			},
+		},
+		{
+			name:         "case 5 -> update with stale if-match",
+			taskUseCase:  &TaskUsecaseMock{GetTaskFunc: getTask},
+			method:       "PUT",
+			url:          "/api/task/1",
+			header:       map[string]string{"If-Match": `"2"`},
+			body:         `{"task_name":"renamed"}`,
+			wantCode:     http.StatusPreconditionFailed,
+			wantETag:     `"3"`,
+			wantResponse: util.ErrorResponse{Message: "Precondition Failed"},
+		},
+		{
+			name: "case 6 -> task changed between the check and the write",
+			taskUseCase: &TaskUsecaseMock{
+				GetTaskFunc: getTask,
+				DeleteTaskFunc: func(ctx context.Context, r model.TaskModel) error {
+					return apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+				},
+			},
+			method:   "DELETE",
+			url:      "/api/task/1",
+			header:   map[string]string{"If-Match": `*`},
+			wantCode: http.StatusPreconditionFailed,
+			wantResponse: ResponseStandard{
+				Message: "task has been changed",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+		{
+			name: "case 7 -> patch passes the if-match version",
+			taskUseCase: &TaskUsecaseMock{
+				GetTaskFunc: getTask,
+				PatchTaskFunc: func(ctx context.Context, id int64, version int64, patch model.TaskPatch) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1", IsDone: true, Version: version + 1}, nil
+				},
+			},
+			method:   "PATCH",
+			url:      "/api/task/1",
+			header:   map[string]string{"If-Match": `"1", "3"`},
+			body:     `{"is_done":true}`,
+			wantCode: http.StatusOK,
+			wantETag: `"4"`,
+			wantResponse: ResponseStandard{
+				Message: "Task Updated",
+				Data:    model.TaskModel{ID: 1, TaskName: "task 1", IsDone: true, Version: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Get("/api/tasks", h.GetAll)
+			router.Get("/api/task/{id}", h.GetByID)
+			router.Put("/api/task/{id}", h.Update)
+			router.Patch("/api/task/{id}", h.Patch)
+			router.Delete("/api/task/{id}", h.Delete)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			for key, value := range tt.header {
+				request.Header.Set(key, value)
+			}
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+			assert.Equal(t, tt.wantETag, recorder.Header().Get("ETag"), "etag")
+
+			if tt.wantResponse == nil {
+				assert.Empty(t, recorder.Body.Bytes(), "handler response")
+				return
+			}
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestPageETag(t *testing.T) {
+	page := model.TaskPage{Tasks: []model.TaskModel{{ID: 1, Version: 2}, {ID: 2, Version: 1}}}
+	blocked := model.TaskPage{Tasks: []model.TaskModel{{ID: 1, Version: 2}, {ID: 2, Version: 1, Blocked: true}}}
+	spread := model.TaskPage{Tasks: []model.TaskModel{{ID: 1, Version: 2}, {ID: 2, Version: 1, Rank: "V"}}}
+
+	assert.Equal(t, PageETag(page), PageETag(model.TaskPage{Tasks: []model.TaskModel{{ID: 1, Version: 2}, {ID: 2, Version: 1}}}))
+	assert.NotEqual(t, PageETag(page), PageETag(blocked))
+	assert.NotEqual(t, PageETag(page), PageETag(spread))
+}
@@ -0,0 +1,74 @@
+package task
+
+import (
+	"fmt"
+	"net/http"
+	util "to-do-list/pkg/response"
+)
+
+// History lists the changes of one task, newest first. It is kept after the
+// task is deleted.
+func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, errorFields := ParseEventFilter(r.URL.Query())
+	if errorFields != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   errorFields,
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	page, err := h.useCase.GetHistory(ctx, id, filter)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := ResponsePage{
+		Message:    "Task History",
+		Data:       page.Events,
+		NextCursor: page.NextCursor,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[History] Response error")
+	}
+}
+
+// Audit lists the changes of every task, newest first, filtered by task,
+// actor, action and time range.
+func (h *Handler) Audit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, errorFields := ParseEventFilter(r.URL.Query())
+	if errorFields != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   errorFields,
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	page, err := h.useCase.GetAudit(ctx, filter)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := ResponsePage{
+		Message:    "Audit Log",
+		Data:       page.Events,
+		NextCursor: page.NextCursor,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Audit] Response error")
+	}
+}
@@ -0,0 +1,107 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Events(t *testing.T) {
+	at := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	taskID := int64(1)
+	events := []model.TaskEvent{
+		{ID: 5, TaskID: 1, Action: model.EventDelete, Before: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 2}, Actor: "alice", RequestID: "req-2", CreatedAt: at},
+	}
+
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		url          string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when get task history",
+			taskUseCase: &TaskUsecaseMock{
+				GetHistoryFunc: func(ctx context.Context, id int64, filter model.EventFilter) (model.EventPage, error) {
+					assert.Equal(t, int64(1), id)
+					assert.Equal(t, model.EventFilter{Limit: 1}, filter)
+					return model.EventPage{Events: events, NextCursor: "5"}, nil
+				},
+			},
+			url:      "/api/task/1/history?limit=1",
+			wantCode: http.StatusOK,
+			wantResponse: ResponsePage{
+				Message:    "Task History",
+				Data:       events,
+				NextCursor: "5",
+			},
+		},
+		{
+			name: "case 2 -> success when get audit of a time range",
+			taskUseCase: &TaskUsecaseMock{
+				GetAuditFunc: func(ctx context.Context, filter model.EventFilter) (model.EventPage, error) {
+					assert.Equal(t, model.EventFilter{TaskID: &taskID, Actor: "alice", Action: model.EventDelete, From: &from, To: &to}, filter)
+					return model.EventPage{Events: events}, nil
+				},
+			},
+			url:      "/api/audit?task_id=1&actor=alice&action=delete&from=2026-03-09T00:00:00Z&to=2026-03-10T00:00:00Z",
+			wantCode: http.StatusOK,
+			wantResponse: ResponsePage{
+				Message: "Audit Log",
+				Data:    events,
+			},
+		},
+		{
+			name:     "case 3 -> invalid audit filter",
+			url:      "/api/audit?from=yesterday&to=2026-03-10T00:00:00Z&action=read",
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{
+				Message: "Invalid Request Data",
+				Error: []model.ErrorField{
+					{FieldName: "action", Message: "action must be one of create, update, delete, restore"},
+					{FieldName: "from", Message: "from must be an RFC 3339 time"},
+				},
+			},
+		},
+		{
+			name:     "case 4 -> empty time range",
+			url:      "/api/audit?from=2026-03-10T00:00:00Z&to=2026-03-09T00:00:00Z",
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{
+				Message: "Invalid Request Data",
+				Error:   []model.ErrorField{{FieldName: "to", Message: "to must be after from"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Get("/api/task/{id}/history", h.History)
+			router.Get("/api/audit", h.Audit)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", tt.url, nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
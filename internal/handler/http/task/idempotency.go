@@ -0,0 +1,109 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	redis_client "to-do-list/pkg/redis"
+	"to-do-list/pkg/requestinfo"
+	util "to-do-list/pkg/response"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	maxIdempotencyKey = 255
+)
+
+type IdempotencyStore interface {
+	Begin(ctx context.Context, key string, fingerprint string) (*redis_client.IdempotentResponse, error)
+	Complete(ctx context.Context, key string, fingerprint string, response redis_client.IdempotentResponse) error
+	Release(ctx context.Context, key string) error
+}
+
+// idempotent runs next once per Idempotency-Key, user and workspace and
+// answers the retries with the first response. Requests without the header always run.
+func (h *Handler) idempotent(w http.ResponseWriter, r *http.Request, scope string, next http.HandlerFunc) {
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" || h.idempotency == nil {
+		next(w, r)
+		return
+	}
+	if len(key) > maxIdempotencyKey {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Idempotency-Key is too long"}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	sum := sha256.Sum256(body)
+	fingerprint := hex.EncodeToString(sum[:])
+	ctx := r.Context()
+	key = fmt.Sprintf("%s:%d:%d:%s", scope, requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx), key)
+
+	stored, err := h.idempotency.Begin(ctx, key, fingerprint)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+	if stored != nil {
+		for name, value := range stored.Header {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(stored.Status)
+		_, _ = w.Write(stored.Body)
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	next(recorder, r)
+
+	// server errors may pass on a retry, so they are not remembered
+	if recorder.status >= http.StatusInternalServerError {
+		if err := h.idempotency.Release(context.Background(), key); err != nil {
+			fmt.Println(err)
+		}
+		return
+	}
+
+	response := redis_client.IdempotentResponse{
+		Status: recorder.status,
+		Header: map[string]string{},
+		Body:   recorder.body.Bytes(),
+	}
+	for _, name := range []string{"Content-Type", "ETag"} {
+		if value := w.Header().Get(name); value != "" {
+			response.Header[name] = value
+		}
+	}
+	if err := h.idempotency.Complete(context.Background(), key, fingerprint, response); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// responseRecorder copies what a handler writes.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
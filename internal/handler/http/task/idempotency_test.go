@@ -0,0 +1,117 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	redis_client "to-do-list/pkg/redis"
+	"to-do-list/pkg/requestinfo"
+	util "to-do-list/pkg/response"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_CreateIdempotent(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+	}
+	defer mr.Close()
+
+	created := 0
+	h := NewHandler(&TaskUsecaseMock{
+		CreateTaskFunc: func(ctx context.Context, r model.TaskModel) (model.TaskModel, error) {
+			created++
+			r.ID = int64(created)
+			r.Version = 1
+			return r, nil
+		},
+	}, WithIdempotency(redis_client.NewIdempotencyStore(redis_client.NewRedisClient(mr.Addr(), ""), time.Hour)))
+
+	router := chi.NewRouter()
+	router.Post("/api/task", h.Create)
+
+	first, _ := json.Marshal(ResponseStandard{Message: "Task Created", Data: model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}})
+
+	tests := []struct {
+		name         string
+		key          string
+		workspace    int64
+		body         string
+		wantCode     int
+		wantCreated  int
+		wantReplayed string
+		wantResponse []byte
+	}{
+		{
+			name:         "case 1 -> first request creates the task",
+			key:          "key-1",
+			body:         `{"task_name":"task 1"}`,
+			wantCode:     http.StatusCreated,
+			wantCreated:  1,
+			wantResponse: first,
+		},
+		{
+			name:         "case 2 -> retry replays the first response",
+			key:          "key-1",
+			body:         `{"task_name":"task 1"}`,
+			wantCode:     http.StatusCreated,
+			wantCreated:  1,
+			wantReplayed: "true",
+			wantResponse: first,
+		},
+		{
+			name:         "case 3 -> key reused with another body",
+			key:          "key-1",
+			body:         `{"task_name":"task 2"}`,
+			wantCode:     http.StatusUnprocessableEntity,
+			wantCreated:  1,
+			wantResponse: mustMarshal(util.ErrorResponse{Message: "Idempotency-Key was already used for another request"}),
+		},
+		{
+			name:         "case 4 -> no key creates every time",
+			body:         `{"task_name":"task 1"}`,
+			wantCode:     http.StatusCreated,
+			wantCreated:  2,
+			wantResponse: mustMarshal(ResponseStandard{Message: "Task Created", Data: model.TaskModel{ID: 2, TaskName: "task 1", Version: 1}}),
+		},
+		{
+			name:         "case 5 -> same key in another workspace creates again",
+			key:          "key-1",
+			workspace:    2,
+			body:         `{"task_name":"task 1"}`,
+			wantCode:     http.StatusCreated,
+			wantCreated:  3,
+			wantResponse: mustMarshal(ResponseStandard{Message: "Task Created", Data: model.TaskModel{ID: 3, TaskName: "task 1", Version: 1}}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/task", bytes.NewBufferString(tt.body))
+			request = request.WithContext(requestinfo.WithWorkspaceID(request.Context(), tt.workspace))
+			if tt.key != "" {
+				request.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			router.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+			assert.Equal(t, tt.wantCreated, created, "created tasks")
+			assert.Equal(t, tt.wantReplayed, recorder.Header().Get("Idempotent-Replayed"))
+			assert.Equal(t, tt.wantResponse, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
@@ -0,0 +1,114 @@
+package task
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	model "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+)
+
+const (
+	mergePatchType = "application/merge-patch+json"
+	jsonPatchType  = "application/json-patch+json"
+)
+
+// Patch updates the fields present in the body. It takes an RFC 7396 merge
+// patch, also when sent as plain application/json, or an RFC 6902 JSON
+// patch.
+func (h *Handler) Patch(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		status = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	patch, err := parsePatch(r.Header.Get("Content-Type"), reqBody)
+	if errors.Is(err, errUnsupportedPatch) {
+		w.Header().Set("Accept-Patch", mergePatchType+", "+jsonPatchType)
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Unsupported Media Type"}, http.StatusUnsupportedMediaType, w)
+		return
+	}
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []model.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	version, ok := h.expectedVersion(w, r, id)
+	if !ok {
+		return
+	}
+
+	data, err := h.useCase.PatchTask(ctx, id, version, patch)
+
+	var fields model.FieldErrors
+	if errors.As(err, &fields) {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []model.ErrorField(fields),
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	responses := ResponseStandard{
+		Message: "Task Updated",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	} else {
+		w.Header().Set("ETag", ETag(data.Version))
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Patch] Response error")
+	}
+}
+
+var errUnsupportedPatch = errors.New("unsupported patch media type")
+
+func parsePatch(contentType string, body []byte) (model.TaskPatch, error) {
+	mediaType := mergePatchType
+	if contentType != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return nil, errUnsupportedPatch
+		}
+	}
+
+	switch mediaType {
+	case mergePatchType, "application/json":
+		if trimmed := bytes.TrimSpace(body); len(trimmed) == 0 || trimmed[0] != '{' || !json.Valid(trimmed) {
+			return nil, errors.New("a merge patch must be a JSON object")
+		}
+		return model.MergePatch(body), nil
+	case jsonPatchType:
+		var ops model.JSONPatch
+		if err := json.Unmarshal(body, &ops); err != nil {
+			return nil, err
+		}
+		return ops, nil
+	default:
+		return nil, errUnsupportedPatch
+	}
+}
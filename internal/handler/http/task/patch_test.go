@@ -0,0 +1,127 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Patch(t *testing.T) {
+	// the mock applies the patch to a stored task like the usecase does
+	patchTask := func(ctx context.Context, id int64, version int64, patch model.TaskPatch) (model.TaskModel, error) {
+		doc, _ := json.Marshal(model.TaskModel{ID: id, TaskName: "task 1"})
+		patched, err := patch.Apply(doc)
+		if err != nil {
+			return model.TaskModel{}, apperror.Wrap(apperror.ErrValidation, "invalid patch", err)
+		}
+		var task model.TaskModel
+		err = json.Unmarshal(patched, &task)
+		return task, err
+	}
+
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		contentType  string
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name:        "case 1 -> success with merge patch",
+			taskUseCase: &TaskUsecaseMock{PatchTaskFunc: patchTask},
+			contentType: "application/merge-patch+json",
+			body:        `{"is_done":true}`,
+			wantCode:    http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Task Updated",
+				Data:    model.TaskModel{ID: 1, TaskName: "task 1", IsDone: true},
+			},
+		},
+		{
+			name:        "case 2 -> plain json is a merge patch",
+			taskUseCase: &TaskUsecaseMock{PatchTaskFunc: patchTask},
+			contentType: "application/json; charset=utf-8",
+			body:        `{"priority":"high"}`,
+			wantCode:    http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Task Updated",
+				Data:    model.TaskModel{ID: 1, TaskName: "task 1", Priority: model.PriorityHigh},
+			},
+		},
+		{
+			name:        "case 3 -> success with json patch",
+			taskUseCase: &TaskUsecaseMock{PatchTaskFunc: patchTask},
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"replace","path":"/task_name","value":"renamed"}]`,
+			wantCode:    http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Task Updated",
+				Data:    model.TaskModel{ID: 1, TaskName: "renamed"},
+			},
+		},
+		{
+			name:         "case 4 -> unsupported media type",
+			taskUseCase:  &TaskUsecaseMock{},
+			contentType:  "text/plain",
+			body:         `is_done=true`,
+			wantCode:     http.StatusUnsupportedMediaType,
+			wantResponse: util.ErrorResponse{Message: "Unsupported Media Type"},
+		},
+		{
+			name:        "case 5 -> merge patch must be an object",
+			taskUseCase: &TaskUsecaseMock{},
+			contentType: "application/merge-patch+json",
+			body:        `[1]`,
+			wantCode:    http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []model.ErrorField{
+				{FieldName: "body", Message: "a merge patch must be a JSON object"},
+			}},
+		},
+		{
+			name: "case 6 -> patched task fails validation",
+			taskUseCase: &TaskUsecaseMock{
+				PatchTaskFunc: func(ctx context.Context, id int64, version int64, patch model.TaskPatch) (model.TaskModel, error) {
+					return model.TaskModel{}, apperror.Wrap(apperror.ErrValidation, "Invalid Request Data", model.FieldErrors{
+						{FieldName: "TaskName", Message: "TaskName is required"},
+					})
+				},
+			},
+			contentType: "application/merge-patch+json",
+			body:        `{"task_name":null}`,
+			wantCode:    http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []model.ErrorField{
+				{FieldName: "TaskName", Message: "TaskName is required"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Patch("/api/task/{id}", h.Patch)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("PATCH", "/api/task/1", bytes.NewBufferString(tt.body))
+			request.Header.Set("Content-Type", tt.contentType)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package task
+
+import (
+	"fmt"
+	"net/http"
+	model "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+)
+
+// Reposition moves a task right before or right after another one in the
+// manual order.
+func (h *Handler) Reposition(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.RepositionRequest{}
+		status  = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.RepositionTask(ctx, id, request)
+
+	responses := ResponseStandard{
+		Message: "Task Repositioned",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	} else {
+		w.Header().Set("ETag", ETag(data.Version))
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Reposition] Response error")
+	}
+}
@@ -0,0 +1,79 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Reposition(t *testing.T) {
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		body         string
+		wantCode     int
+		wantETag     string
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when move before a task",
+			taskUseCase: &TaskUsecaseMock{
+				RepositionTaskFunc: func(ctx context.Context, id int64, place model.RepositionRequest) (model.TaskModel, error) {
+					if place.Before == nil || *place.Before != 3 {
+						return model.TaskModel{}, apperror.New(apperror.ErrValidation, "exactly one of before and after is required")
+					}
+					return model.TaskModel{ID: id, TaskName: "task 1", Rank: "U", Version: 2}, nil
+				},
+			},
+			body:     `{"before": 3}`,
+			wantCode: http.StatusOK,
+			wantETag: `"2"`,
+			wantResponse: ResponseStandard{
+				Message: "Task Repositioned",
+				Data:    model.TaskModel{ID: 1, TaskName: "task 1", Rank: "U", Version: 2},
+			},
+		},
+		{
+			name: "case 2 -> neither before nor after",
+			taskUseCase: &TaskUsecaseMock{
+				RepositionTaskFunc: func(ctx context.Context, id int64, place model.RepositionRequest) (model.TaskModel, error) {
+					return model.TaskModel{}, apperror.New(apperror.ErrValidation, "exactly one of before and after is required")
+				},
+			},
+			body:     `{}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: ResponseStandard{
+				Message: "exactly one of before and after is required",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Post("/api/task/{id}/move", h.Reposition)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/task/1/move", strings.NewReader(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+			assert.Equal(t, tt.wantETag, recorder.Header().Get("ETag"))
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	model "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) Subtasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.useCase.GetSubtasks(ctx, id)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := ResponseStandard{
+		Message: "Subtask List",
+		Data:    data,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Subtasks] Response error")
+	}
+}
+
+func (h *Handler) CreateSubtask(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.TaskModel{}
+		status  = http.StatusCreated
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	if validate := Validate(request); validate != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   validate,
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	data, err := h.useCase.CreateSubtask(ctx, id, request)
+
+	responses := ResponseStandard{
+		Message: "Subtask Created",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Create Subtask] Response error")
+	}
+}
+
+func (h *Handler) ReorderSubtasks(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.ReorderRequest{}
+		status  = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.ReorderSubtasks(ctx, id, request.IDs)
+
+	responses := ResponseStandard{
+		Message: "Subtasks Reordered",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Reorder Subtasks] Response error")
+	}
+}
+
+// Done completes or reopens a task, rolling the change up to its parent.
+func (h *Handler) Done(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.DoneRequest{}
+		status  = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.SetTaskDone(ctx, id, request.IsDone)
+
+	responses := ResponseStandard{
+		Message: "Task Updated",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Done] Response error")
+	}
+}
+
+func urlID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "To do list not found"}, http.StatusNotFound, w)
+		return 0, false
+	}
+	return id, true
+}
+
+// decode reads the JSON body, answering 422 itself when it is unusable.
+func decode(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	if err := json.Unmarshal(reqBody, request); err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []model.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	return true
+}
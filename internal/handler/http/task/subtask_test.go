@@ -0,0 +1,145 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Subtasks(t *testing.T) {
+	parentID := int64(1)
+
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		method       string
+		url          string
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when list subtasks",
+			taskUseCase: &TaskUsecaseMock{
+				GetSubtasksFunc: func(ctx context.Context, id int64) ([]model.TaskModel, error) {
+					return []model.TaskModel{{ID: 2, TaskName: "step 1", ParentID: &parentID}}, nil
+				},
+			},
+			method:   "GET",
+			url:      "/api/task/1/subtasks",
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Subtask List",
+				Data:    []model.TaskModel{{ID: 2, TaskName: "step 1", ParentID: &parentID}},
+			},
+		},
+		{
+			name: "case 2 -> success when create subtask",
+			taskUseCase: &TaskUsecaseMock{
+				CreateSubtaskFunc: func(ctx context.Context, id int64, r model.TaskModel) (model.TaskModel, error) {
+					r.ID = 3
+					r.ParentID = &id
+					r.Position = 1
+					return r, nil
+				},
+			},
+			method:   "POST",
+			url:      "/api/task/1/subtasks",
+			body:     `{"task_name":"step 2"}`,
+			wantCode: http.StatusCreated,
+			wantResponse: ResponseStandard{
+				Message: "Subtask Created",
+				Data:    model.TaskModel{ID: 3, TaskName: "step 2", ParentID: &parentID, Position: 1},
+			},
+		},
+		{
+			name:     "case 3 -> fail when create subtask without name",
+			method:   "POST",
+			url:      "/api/task/1/subtasks",
+			body:     `{}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []model.ErrorField{
+				{FieldName: "TaskName", Message: "TaskName is required"},
+			}},
+		},
+		{
+			name: "case 4 -> fail when reorder does not list every subtask",
+			taskUseCase: &TaskUsecaseMock{
+				ReorderSubtasksFunc: func(ctx context.Context, id int64, ids []int64) ([]model.TaskModel, error) {
+					return nil, apperror.New(apperror.ErrValidation, "ids must list every subtask once")
+				},
+			},
+			method:   "PUT",
+			url:      "/api/task/1/subtasks/order",
+			body:     `{"ids":[3]}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: ResponseStandard{
+				Message: "ids must list every subtask once",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+		{
+			name: "case 5 -> fail when completing a parent with open subtasks",
+			taskUseCase: &TaskUsecaseMock{
+				SetTaskDoneFunc: func(ctx context.Context, id int64, done bool) (model.TaskModel, error) {
+					return model.TaskModel{}, apperror.New(apperror.ErrConflict, "task has open subtasks")
+				},
+			},
+			method:   "PUT",
+			url:      "/api/task/1/done",
+			body:     `{"is_done":true}`,
+			wantCode: http.StatusConflict,
+			wantResponse: ResponseStandard{
+				Message: "task has open subtasks",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+		{
+			name: "case 6 -> success when complete a subtask",
+			taskUseCase: &TaskUsecaseMock{
+				SetTaskDoneFunc: func(ctx context.Context, id int64, done bool) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "step 1", IsDone: done, ParentID: &parentID}, nil
+				},
+			},
+			method:   "PUT",
+			url:      "/api/task/2/done",
+			body:     `{"is_done":true}`,
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Task Updated",
+				Data:    model.TaskModel{ID: 2, TaskName: "step 1", IsDone: true, ParentID: &parentID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Get("/api/task/{id}/subtasks", h.Subtasks)
+			router.Post("/api/task/{id}/subtasks", h.CreateSubtask)
+			router.Put("/api/task/{id}/subtasks/order", h.ReorderSubtasks)
+			router.Put("/api/task/{id}/done", h.Done)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
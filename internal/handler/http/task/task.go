@@ -15,35 +15,91 @@ import (
 )
 
 type Handler struct {
-	useCase TaskUsecase
+	useCase     TaskUsecase
+	idempotency IdempotencyStore
 }
 
-type ResponseStandard struct {
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
+type Option func(*Handler)
+
+// WithIdempotency makes Create honor the Idempotency-Key header.
+func WithIdempotency(store IdempotencyStore) Option {
+	return func(h *Handler) {
+		h.idempotency = store
+	}
 }
 
-type StatusRespose struct {
-	Success bool `json:"status"`
+type ResponseStandard = util.ResponseStandard
+
+type ResponsePage struct {
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor,omitempty"`
 }
 
-func NewHandler(useCase TaskUsecase) *Handler {
-	return &Handler{useCase: useCase}
+type StatusRespose = util.StatusRespose
+
+func NewHandler(useCase TaskUsecase, opts ...Option) *Handler {
+	h := &Handler{useCase: useCase}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 type TaskUsecase interface {
-	GetAllTask(ctx context.Context) ([]model.TaskModel, error)
+	GetAllTask(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error)
+	GetTask(ctx context.Context, id int64) (model.TaskModel, error)
 	CreateTask(ctx context.Context, r model.TaskModel) (model.TaskModel, error)
 	UpdateTask(ctx context.Context, r model.TaskModel) (model.TaskModel, error)
+	MoveTask(ctx context.Context, id int64, listID *int64) (model.TaskModel, error)
 	DeleteTask(ctx context.Context, r model.TaskModel) error
+	GetSubtasks(ctx context.Context, id int64) ([]model.TaskModel, error)
+	CreateSubtask(ctx context.Context, parentID int64, r model.TaskModel) (model.TaskModel, error)
+	ReorderSubtasks(ctx context.Context, id int64, ids []int64) ([]model.TaskModel, error)
+	SetTaskDone(ctx context.Context, id int64, done bool) (model.TaskModel, error)
+	PatchTask(ctx context.Context, id int64, version int64, patch model.TaskPatch) (model.TaskModel, error)
+	Batch(ctx context.Context, r model.BatchRequest) ([]model.BatchResult, error)
+	GetTrash(ctx context.Context) ([]model.TaskModel, error)
+	RestoreTask(ctx context.Context, id int64) (model.TaskModel, error)
+	GetHistory(ctx context.Context, id int64, filter model.EventFilter) (model.EventPage, error)
+	GetAudit(ctx context.Context, filter model.EventFilter) (model.EventPage, error)
+	Undo(ctx context.Context) ([]model.TaskModel, error)
+	RevertTask(ctx context.Context, id, to, version int64) (model.TaskModel, error)
+	GetBlockers(ctx context.Context, id int64) ([]model.TaskModel, error)
+	AddDependency(ctx context.Context, dependency model.Dependency) error
+	RemoveDependency(ctx context.Context, dependency model.Dependency) error
+	Plan(ctx context.Context) ([]model.TaskModel, error)
+	RepositionTask(ctx context.Context, id int64, place model.RepositionRequest) (model.TaskModel, error)
 }
 
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	responses, err := h.useCase.GetAllTask(ctx)
+
+	filter, errorFields := ParseFilter(r.URL.Query())
+	if errorFields != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   errorFields,
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	page, err := h.useCase.GetAllTask(ctx, filter)
 
 	if err != nil {
-		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Internal Server Error"}, http.StatusInternalServerError, w)
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if NotModified(w, r, PageETag(page)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	responses := ResponsePage{
+		Message:    "Task List",
+		Data:       page.Tasks,
+		NextCursor: page.NextCursor,
 	}
 
 	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
@@ -52,7 +108,42 @@ func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
 
 }
 
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "To do list not found"}, http.StatusNotFound, w)
+		return
+	}
+
+	data, err := h.useCase.GetTask(ctx, id)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if NotModified(w, r, ETag(data.Version)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	responses := ResponseStandard{
+		Message: "Task Found",
+		Data:    data,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Get By ID] Response error")
+	}
+}
+
+// Create inserts a task once per Idempotency-Key, retries get the response
+// of the first request.
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	h.idempotent(w, r, "task:create", h.create)
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx     = r.Context()
 		request = model.TaskModel{}
@@ -64,7 +155,13 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
 		return
 	}
-	json.Unmarshal(reqBody, &request)
+	if err := json.Unmarshal(reqBody, &request); err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []model.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
 
 	validate := Validate(request)
 	if validate != nil {
@@ -83,9 +180,11 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		status = http.StatusInternalServerError
-		responses.Message = err.Error()
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
 		responses.Data = StatusRespose{Success: false}
+	} else {
+		w.Header().Set("ETag", ETag(data.Version))
 	}
 
 	if err := util.ResponseJSON(responses, status, w); err != nil {
@@ -97,6 +196,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx     = r.Context()
 		request = model.TaskModel{}
+		status  = http.StatusOK
 	)
 
 	reqBody, err := ioutil.ReadAll(r.Body)
@@ -104,7 +204,13 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
 		return
 	}
-	json.Unmarshal(reqBody, &request)
+	if err := json.Unmarshal(reqBody, &request); err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []model.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
 
 	validate := Validate(request)
 	if validate != nil {
@@ -121,7 +227,13 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, ok := h.expectedVersion(w, r, id)
+	if !ok {
+		return
+	}
+
 	request.ID = id
+	request.Version = version
 	data, err := h.useCase.UpdateTask(ctx, request)
 
 	responses := ResponseStandard{
@@ -130,15 +242,64 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		responses.Message = err.Error()
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
 		responses.Data = StatusRespose{Success: false}
+	} else {
+		w.Header().Set("ETag", ETag(data.Version))
 	}
 
-	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+	if err := util.ResponseJSON(responses, status, w); err != nil {
 		fmt.Println("[Update] Response error")
 	}
 }
 
+// Move puts the task in the list of the body, or takes it out of its list
+// when list_id is null.
+func (h *Handler) Move(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.MoveRequest{}
+		status  = http.StatusOK
+	)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "To do list not found"}, http.StatusNotFound, w)
+		return
+	}
+
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
+		return
+	}
+	if err := json.Unmarshal(reqBody, &request); err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []model.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	data, err := h.useCase.MoveTask(ctx, id, request.ListID)
+
+	responses := ResponseStandard{
+		Message: "Task Moved",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Move] Response error")
+	}
+}
+
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -147,6 +308,12 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, ok := h.expectedVersion(w, r, id)
+	if !ok {
+		return
+	}
+
+	status := http.StatusOK
 	status_response := StatusRespose{Success: true}
 
 	responses := ResponseStandard{
@@ -154,14 +321,15 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 		Data:    status_response,
 	}
 
-	request := model.TaskModel{ID: id}
+	request := model.TaskModel{ID: id, Version: version}
 	err = h.useCase.DeleteTask(ctx, request)
 	if err != nil {
-		responses.Message = err.Error()
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
 		responses.Data = StatusRespose{Success: false}
 	}
 
-	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
-		fmt.Println("[Update] Response error")
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Delete] Response error")
 	}
 }
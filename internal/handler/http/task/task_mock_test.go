@@ -8,14 +8,41 @@ import (
 var TaskUseCase = &TaskUsecaseMock{}
 
 type TaskUsecaseMock struct {
-	GetAllTaskFunc func(ctx context.Context) ([]model.TaskModel, error)
+	GetAllTaskFunc func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error)
+	GetTaskFunc    func(ctx context.Context, id int64) (model.TaskModel, error)
 	CreateTaskFunc func(ctx context.Context, r model.TaskModel) (model.TaskModel, error)
 	UpdateTaskFunc func(ctx context.Context, r model.TaskModel) (model.TaskModel, error)
+	MoveTaskFunc   func(ctx context.Context, id int64, listID *int64) (model.TaskModel, error)
 	DeleteTaskFunc func(ctx context.Context, r model.TaskModel) error
+
+	GetSubtasksFunc     func(ctx context.Context, id int64) ([]model.TaskModel, error)
+	CreateSubtaskFunc   func(ctx context.Context, parentID int64, r model.TaskModel) (model.TaskModel, error)
+	ReorderSubtasksFunc func(ctx context.Context, id int64, ids []int64) ([]model.TaskModel, error)
+	SetTaskDoneFunc     func(ctx context.Context, id int64, done bool) (model.TaskModel, error)
+	PatchTaskFunc       func(ctx context.Context, id int64, version int64, patch model.TaskPatch) (model.TaskModel, error)
+	BatchFunc           func(ctx context.Context, r model.BatchRequest) ([]model.BatchResult, error)
+
+	GetTrashFunc    func(ctx context.Context) ([]model.TaskModel, error)
+	RestoreTaskFunc func(ctx context.Context, id int64) (model.TaskModel, error)
+	GetHistoryFunc  func(ctx context.Context, id int64, filter model.EventFilter) (model.EventPage, error)
+	GetAuditFunc    func(ctx context.Context, filter model.EventFilter) (model.EventPage, error)
+	UndoFunc        func(ctx context.Context) ([]model.TaskModel, error)
+	RevertTaskFunc  func(ctx context.Context, id, to, version int64) (model.TaskModel, error)
+
+	GetBlockersFunc      func(ctx context.Context, id int64) ([]model.TaskModel, error)
+	AddDependencyFunc    func(ctx context.Context, dependency model.Dependency) error
+	RemoveDependencyFunc func(ctx context.Context, dependency model.Dependency) error
+	PlanFunc             func(ctx context.Context) ([]model.TaskModel, error)
+
+	RepositionTaskFunc func(ctx context.Context, id int64, place model.RepositionRequest) (model.TaskModel, error)
+}
+
+func (mock *TaskUsecaseMock) GetAllTask(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+	return mock.GetAllTaskFunc(ctx, filter)
 }
 
-func (mock *TaskUsecaseMock) GetAllTask(ctx context.Context) ([]model.TaskModel, error) {
-	return mock.GetAllTaskFunc(ctx)
+func (mock *TaskUsecaseMock) GetTask(ctx context.Context, id int64) (model.TaskModel, error) {
+	return mock.GetTaskFunc(ctx, id)
 }
 
 func (mock *TaskUsecaseMock) CreateTask(ctx context.Context, task model.TaskModel) (model.TaskModel, error) {
@@ -26,6 +53,78 @@ func (mock *TaskUsecaseMock) UpdateTask(ctx context.Context, task model.TaskMode
 	return mock.UpdateTaskFunc(ctx, task)
 }
 
+func (mock *TaskUsecaseMock) MoveTask(ctx context.Context, id int64, listID *int64) (model.TaskModel, error) {
+	return mock.MoveTaskFunc(ctx, id, listID)
+}
+
 func (mock *TaskUsecaseMock) DeleteTask(ctx context.Context, task model.TaskModel) error {
 	return mock.DeleteTaskFunc(ctx, task)
 }
+
+func (mock *TaskUsecaseMock) GetSubtasks(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	return mock.GetSubtasksFunc(ctx, id)
+}
+
+func (mock *TaskUsecaseMock) CreateSubtask(ctx context.Context, parentID int64, task model.TaskModel) (model.TaskModel, error) {
+	return mock.CreateSubtaskFunc(ctx, parentID, task)
+}
+
+func (mock *TaskUsecaseMock) ReorderSubtasks(ctx context.Context, id int64, ids []int64) ([]model.TaskModel, error) {
+	return mock.ReorderSubtasksFunc(ctx, id, ids)
+}
+
+func (mock *TaskUsecaseMock) SetTaskDone(ctx context.Context, id int64, done bool) (model.TaskModel, error) {
+	return mock.SetTaskDoneFunc(ctx, id, done)
+}
+
+func (mock *TaskUsecaseMock) PatchTask(ctx context.Context, id int64, version int64, patch model.TaskPatch) (model.TaskModel, error) {
+	return mock.PatchTaskFunc(ctx, id, version, patch)
+}
+
+func (mock *TaskUsecaseMock) Batch(ctx context.Context, r model.BatchRequest) ([]model.BatchResult, error) {
+	return mock.BatchFunc(ctx, r)
+}
+
+func (mock *TaskUsecaseMock) GetTrash(ctx context.Context) ([]model.TaskModel, error) {
+	return mock.GetTrashFunc(ctx)
+}
+
+func (mock *TaskUsecaseMock) RestoreTask(ctx context.Context, id int64) (model.TaskModel, error) {
+	return mock.RestoreTaskFunc(ctx, id)
+}
+
+func (mock *TaskUsecaseMock) GetHistory(ctx context.Context, id int64, filter model.EventFilter) (model.EventPage, error) {
+	return mock.GetHistoryFunc(ctx, id, filter)
+}
+
+func (mock *TaskUsecaseMock) GetAudit(ctx context.Context, filter model.EventFilter) (model.EventPage, error) {
+	return mock.GetAuditFunc(ctx, filter)
+}
+
+func (mock *TaskUsecaseMock) Undo(ctx context.Context) ([]model.TaskModel, error) {
+	return mock.UndoFunc(ctx)
+}
+
+func (mock *TaskUsecaseMock) RevertTask(ctx context.Context, id, to, version int64) (model.TaskModel, error) {
+	return mock.RevertTaskFunc(ctx, id, to, version)
+}
+
+func (mock *TaskUsecaseMock) GetBlockers(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	return mock.GetBlockersFunc(ctx, id)
+}
+
+func (mock *TaskUsecaseMock) AddDependency(ctx context.Context, dependency model.Dependency) error {
+	return mock.AddDependencyFunc(ctx, dependency)
+}
+
+func (mock *TaskUsecaseMock) RemoveDependency(ctx context.Context, dependency model.Dependency) error {
+	return mock.RemoveDependencyFunc(ctx, dependency)
+}
+
+func (mock *TaskUsecaseMock) Plan(ctx context.Context) ([]model.TaskModel, error) {
+	return mock.PlanFunc(ctx)
+}
+
+func (mock *TaskUsecaseMock) RepositionTask(ctx context.Context, id int64, place model.RepositionRequest) (model.TaskModel, error) {
+	return mock.RepositionTaskFunc(ctx, id, place)
+}
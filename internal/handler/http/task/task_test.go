@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"testing"
 	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
 	util "to-do-list/pkg/response"
 
 	"github.com/go-chi/chi/v5"
@@ -52,49 +53,61 @@ func TestHandler_GetAll(t *testing.T) {
 		taskUseCase *TaskUsecaseMock
 	}
 
+	type ResponseData struct {
+		Message    string            `json:"message"`
+		Data       []model.TaskModel `json:"data"`
+		NextCursor string            `json:"next_cursor,omitempty"`
+	}
+
 	tests := []struct {
 		name         string
 		fields       fields
+		query        string
 		wantCode     int
 		wantResponse interface{}
 	}{
 		{
 			name: "case 1 -> success when get all task handler",
 			fields: fields{taskUseCase: &TaskUsecaseMock{
-				GetAllTaskFunc: func(ctx context.Context) ([]model.TaskModel, error) {
-					return []model.TaskModel{
-						{
-							ID:       1,
-							TaskName: "task 1",
-							IsDone:   true,
-						},
-						{
-							ID:       2,
-							TaskName: "task 2",
-							IsDone:   false,
+				GetAllTaskFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					return model.TaskPage{
+						Tasks: []model.TaskModel{
+							{
+								ID:       1,
+								TaskName: "task 1",
+								IsDone:   true,
+							},
+							{
+								ID:       2,
+								TaskName: "task 2",
+								IsDone:   false,
+							},
 						},
 					}, nil
 				},
 			}},
 			wantCode: http.StatusOK,
-			wantResponse: []model.TaskModel{
-				{
-					ID:       1,
-					TaskName: "task 1",
-					IsDone:   true,
-				},
-				{
-					ID:       2,
-					TaskName: "task 2",
-					IsDone:   false,
+			wantResponse: ResponseData{
+				Message: "Task List",
+				Data: []model.TaskModel{
+					{
+						ID:       1,
+						TaskName: "task 1",
+						IsDone:   true,
+					},
+					{
+						ID:       2,
+						TaskName: "task 2",
+						IsDone:   false,
+					},
 				},
 			},
 		},
 		{
 			name: "case 2 -> fail internal server error when get all task handler",
 			fields: fields{taskUseCase: &TaskUsecaseMock{
-				GetAllTaskFunc: func(ctx context.Context) ([]model.TaskModel, error) {
-					return nil, errors.New("database error")
+				GetAllTaskFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					return model.TaskPage{}, errors.New("database error")
 				},
 			}},
 			wantCode: http.StatusInternalServerError,
@@ -103,6 +116,67 @@ func TestHandler_GetAll(t *testing.T) {
 				Error:   nil,
 			},
 		},
+		{
+			name: "case 3 -> fail service unavailable when get all task handler",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				GetAllTaskFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					return model.TaskPage{}, apperror.Wrap(apperror.ErrUnavailable, "storage unavailable", errors.New("connection refused"))
+				},
+			}},
+			wantCode: http.StatusServiceUnavailable,
+			wantResponse: util.ErrorResponse{
+				Message: "storage unavailable",
+				Error:   nil,
+			},
+		},
+		{
+			name: "case 4 -> success pass limit and cursor and return next cursor",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				GetAllTaskFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					if filter.Limit != 1 || filter.Cursor != "abc" {
+						return model.TaskPage{}, errors.New("unexpected filter")
+					}
+					return model.TaskPage{
+						Tasks:      []model.TaskModel{{ID: 3, TaskName: "task 3"}},
+						NextCursor: "def",
+					}, nil
+				},
+			}},
+			query:    "?limit=1&cursor=abc",
+			wantCode: http.StatusOK,
+			wantResponse: ResponseData{
+				Message:    "Task List",
+				Data:       []model.TaskModel{{ID: 3, TaskName: "task 3"}},
+				NextCursor: "def",
+			},
+		},
+		{
+			name: "case 5 -> fail when limit out of range",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				GetAllTaskFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					return model.TaskPage{}, nil
+				},
+			}},
+			query:    "?limit=1000",
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []model.ErrorField{
+				{
+					FieldName: "limit",
+					Message:   "limit must be between 1 and 100",
+				},
+			}},
+		},
+		{
+			name: "case 6 -> fail when cursor is invalid",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				GetAllTaskFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					return model.TaskPage{}, apperror.New(apperror.ErrValidation, "invalid cursor")
+				},
+			}},
+			query:        "?cursor=bad",
+			wantCode:     http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "invalid cursor"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -114,21 +188,13 @@ func TestHandler_GetAll(t *testing.T) {
 			router := chi.NewRouter()
 			router.Get("/api/tasks", h.GetAll)
 			recorder := httptest.NewRecorder()
-			request, _ := http.NewRequest("GET", "/api/tasks", nil)
+			request, _ := http.NewRequest("GET", "/api/tasks"+tt.query, nil)
 			router.ServeHTTP(recorder, request)
 			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
 
-			decoder_data := tt.wantResponse
-
-			err := json.NewDecoder(recorder.Body).Decode(&decoder_data)
-			assert.NoError(t, err, "unmarshal error")
-
-			expect, err := json.Marshal(tt.wantResponse)
+			jsonExpect, err := json.Marshal(tt.wantResponse)
 			assert.NoError(t, err, "marshal error")
-			err = json.Unmarshal(expect, &tt.wantResponse)
-			assert.NoError(t, err, "unmarshal error")
-
-			assert.Equal(t, tt.wantResponse, decoder_data, "handler response")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
 		})
 	}
 }
@@ -295,6 +361,42 @@ func TestHandler_Update(t *testing.T) {
 			wantCode:     http.StatusNotFound,
 			wantResponse: util.ErrorResponse{Message: "To do list not found"},
 		},
+		{
+			name: "case 4 -> failed when update cause task does not exist",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				UpdateTaskFunc: func(ctx context.Context, request model.TaskModel) (model.TaskModel, error) {
+					return request, apperror.New(apperror.ErrNotFound, "task not found")
+				},
+			}},
+			redId: 7,
+			request: model.TaskModel{
+				TaskName: "cek",
+				IsDone:   false,
+			},
+			wantCode: http.StatusNotFound,
+			wantResponse: ResponseStandard{
+				Message: "task not found",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+		{
+			name: "case 5 -> failed when update cause conflict",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				UpdateTaskFunc: func(ctx context.Context, request model.TaskModel) (model.TaskModel, error) {
+					return request, apperror.New(apperror.ErrConflict, "task already exists")
+				},
+			}},
+			redId: 7,
+			request: model.TaskModel{
+				TaskName: "cek",
+				IsDone:   false,
+			},
+			wantCode: http.StatusConflict,
+			wantResponse: ResponseStandard{
+				Message: "task already exists",
+				Data:    StatusRespose{Success: false},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -365,13 +467,13 @@ func TestHandler_Delete(t *testing.T) {
 			name: "case 2 -> fail when update cause already deleted task handler",
 			fields: fields{taskUseCase: &TaskUsecaseMock{
 				DeleteTaskFunc: func(ctx context.Context, r model.TaskModel) error {
-					return errors.New("error, delete task failed")
+					return apperror.New(apperror.ErrNotFound, "task not found")
 				},
 			}},
 			redId:    1,
-			wantCode: http.StatusOK,
+			wantCode: http.StatusNotFound,
 			wantResponse: ResponseData{
-				Message: "error, delete task failed",
+				Message: "task not found",
 				Data: StatusRespose{
 					Success: false,
 				},
@@ -390,6 +492,22 @@ func TestHandler_Delete(t *testing.T) {
 				Message: "To do list not found",
 			},
 		},
+		{
+			name: "case 4 -> fail when delete cause storage unavailable task handler",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				DeleteTaskFunc: func(ctx context.Context, r model.TaskModel) error {
+					return apperror.Wrap(apperror.ErrUnavailable, "storage unavailable", errors.New("dial tcp: connection refused"))
+				},
+			}},
+			redId:    1,
+			wantCode: http.StatusServiceUnavailable,
+			wantResponse: ResponseData{
+				Message: "storage unavailable",
+				Data: StatusRespose{
+					Success: false,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -414,3 +532,159 @@ func TestHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Move(t *testing.T) {
+	listID := int64(2)
+
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when move task to a list",
+			taskUseCase: &TaskUsecaseMock{
+				MoveTaskFunc: func(ctx context.Context, id int64, listID *int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1", ListID: listID}, nil
+				},
+			},
+			body:     `{"list_id":2}`,
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Task Moved",
+				Data:    model.TaskModel{ID: 1, TaskName: "task 1", ListID: &listID},
+			},
+		},
+		{
+			name: "case 2 -> success when take task out of its list",
+			taskUseCase: &TaskUsecaseMock{
+				MoveTaskFunc: func(ctx context.Context, id int64, listID *int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1", ListID: listID}, nil
+				},
+			},
+			body:     `{"list_id":null}`,
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Task Moved",
+				Data:    model.TaskModel{ID: 1, TaskName: "task 1"},
+			},
+		},
+		{
+			name: "case 3 -> fail when list does not exist",
+			taskUseCase: &TaskUsecaseMock{
+				MoveTaskFunc: func(ctx context.Context, id int64, listID *int64) (model.TaskModel, error) {
+					return model.TaskModel{}, apperror.New(apperror.ErrValidation, "list not found")
+				},
+			},
+			body:     `{"list_id":9}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: ResponseStandard{
+				Message: "list not found",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Put("/api/task/{id}/list", h.Move)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("PUT", "/api/task/1/list", bytes.NewBufferString(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_GetByID(t *testing.T) {
+	type fields struct {
+		taskUseCase *TaskUsecaseMock
+	}
+
+	type ResponseData struct {
+		Message string          `json:"message"`
+		Data    model.TaskModel `json:"data"`
+	}
+
+	tests := []struct {
+		name         string
+		fields       fields
+		redId        interface{}
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when get task by id handler",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				GetTaskFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1", IsDone: true}, nil
+				},
+			}},
+			redId:    1,
+			wantCode: http.StatusOK,
+			wantResponse: ResponseData{
+				Message: "Task Found",
+				Data: model.TaskModel{
+					ID:       1,
+					TaskName: "task 1",
+					IsDone:   true,
+				},
+			},
+		},
+		{
+			name: "case 2 -> fail when task does not exist",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				GetTaskFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "task not found")
+				},
+			}},
+			redId:        2,
+			wantCode:     http.StatusNotFound,
+			wantResponse: util.ErrorResponse{Message: "task not found"},
+		},
+		{
+			name: "case 3 -> fail when invalid id",
+			fields: fields{taskUseCase: &TaskUsecaseMock{
+				GetTaskFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{}, nil
+				},
+			}},
+			redId:        "1s",
+			wantCode:     http.StatusNotFound,
+			wantResponse: util.ErrorResponse{Message: "To do list not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.fields.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Get("/api/task/{id}", h.GetByID)
+			recorder := httptest.NewRecorder()
+
+			id := fmt.Sprintf("%v", tt.redId)
+
+			request, _ := http.NewRequest("GET", "/api/task/"+id, nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package task
+
+import (
+	"fmt"
+	"net/http"
+	util "to-do-list/pkg/response"
+)
+
+// Trash lists the deleted tasks that have not been purged yet.
+func (h *Handler) Trash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := h.useCase.GetTrash(ctx)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := ResponseStandard{
+		Message: "Trash",
+		Data:    data,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Trash] Response error")
+	}
+}
+
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		status = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.useCase.RestoreTask(ctx, id)
+
+	responses := ResponseStandard{
+		Message: "Task Restored",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	} else {
+		w.Header().Set("ETag", ETag(data.Version))
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Restore] Response error")
+	}
+}
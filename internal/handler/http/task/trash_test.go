@@ -0,0 +1,110 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Trash(t *testing.T) {
+	deletedAt := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		method       string
+		url          string
+		wantCode     int
+		wantETag     string
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when list trash",
+			taskUseCase: &TaskUsecaseMock{
+				GetTrashFunc: func(ctx context.Context) ([]model.TaskModel, error) {
+					return []model.TaskModel{{ID: 1, TaskName: "task 1", Version: 2, DeletedAt: &deletedAt}}, nil
+				},
+			},
+			method:   "GET",
+			url:      "/api/trash",
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Trash",
+				Data:    []model.TaskModel{{ID: 1, TaskName: "task 1", Version: 2, DeletedAt: &deletedAt}},
+			},
+		},
+		{
+			name: "case 2 -> success when restore task",
+			taskUseCase: &TaskUsecaseMock{
+				RestoreTaskFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1", Version: 3}, nil
+				},
+			},
+			method:   "POST",
+			url:      "/api/task/1/restore",
+			wantCode: http.StatusOK,
+			wantETag: `"3"`,
+			wantResponse: ResponseStandard{
+				Message: "Task Restored",
+				Data:    model.TaskModel{ID: 1, TaskName: "task 1", Version: 3},
+			},
+		},
+		{
+			name: "case 3 -> restore a subtask of a trashed parent",
+			taskUseCase: &TaskUsecaseMock{
+				RestoreTaskFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{}, apperror.New(apperror.ErrConflict, "the parent task is in the trash, restore it first")
+				},
+			},
+			method:   "POST",
+			url:      "/api/task/2/restore",
+			wantCode: http.StatusConflict,
+			wantResponse: ResponseStandard{
+				Message: "the parent task is in the trash, restore it first",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+		{
+			name: "case 4 -> database unavailable when list trash",
+			taskUseCase: &TaskUsecaseMock{
+				GetTrashFunc: func(ctx context.Context) ([]model.TaskModel, error) {
+					return nil, apperror.New(apperror.ErrUnavailable, "database unavailable")
+				},
+			},
+			method:       "GET",
+			url:          "/api/trash",
+			wantCode:     http.StatusServiceUnavailable,
+			wantResponse: util.ErrorResponse{Message: "database unavailable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Get("/api/trash", h.Trash)
+			router.Post("/api/task/{id}/restore", h.Restore)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest(tt.method, tt.url, nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+			assert.Equal(t, tt.wantETag, recorder.Header().Get("ETag"))
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package task
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	model "to-do-list/internal/model/task"
+	util "to-do-list/pkg/response"
+)
+
+// Undo reverses the last change of the signed in user.
+func (h *Handler) Undo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := h.useCase.Undo(ctx)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	responses := ResponseStandard{
+		Message: "Undone",
+		Data:    data,
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Undo] Response error")
+	}
+}
+
+// Revert puts a task back to the version in the to query parameter.
+func (h *Handler) Revert(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		status = http.StatusOK
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	to, err := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+	if err != nil || to < 1 {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []model.ErrorField{{FieldName: "to", Message: "to must be a version of the task"}},
+		}, http.StatusUnprocessableEntity, w)
+		return
+	}
+
+	version, ok := h.expectedVersion(w, r, id)
+	if !ok {
+		return
+	}
+
+	data, err := h.useCase.RevertTask(ctx, id, to, version)
+
+	responses := ResponseStandard{
+		Message: "Task Reverted",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = StatusRespose{Success: false}
+	} else {
+		w.Header().Set("ETag", ETag(data.Version))
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Revert] Response error")
+	}
+}
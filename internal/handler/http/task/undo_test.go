@@ -0,0 +1,128 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Undo(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		taskUseCase  *TaskUsecaseMock
+		method       string
+		url          string
+		header       map[string]string
+		wantCode     int
+		wantETag     string
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when undo",
+			taskUseCase: &TaskUsecaseMock{
+				UndoFunc: func(ctx context.Context) ([]model.TaskModel, error) {
+					return []model.TaskModel{{ID: 1, TaskName: "task 1", Version: 3}}, nil
+				},
+			},
+			method:   "POST",
+			url:      "/api/undo",
+			wantCode: http.StatusOK,
+			wantResponse: ResponseStandard{
+				Message: "Undone",
+				Data:    []model.TaskModel{{ID: 1, TaskName: "task 1", Version: 3}},
+			},
+		},
+		{
+			name: "case 2 -> undo a change made over since",
+			taskUseCase: &TaskUsecaseMock{
+				UndoFunc: func(ctx context.Context) ([]model.TaskModel, error) {
+					return nil, apperror.New(apperror.ErrConflict, "task 1 has changed since")
+				},
+			},
+			method:       "POST",
+			url:          "/api/undo",
+			wantCode:     http.StatusConflict,
+			wantResponse: util.ErrorResponse{Message: "task 1 has changed since"},
+		},
+		{
+			name: "case 3 -> success when revert with If-Match",
+			taskUseCase: &TaskUsecaseMock{
+				GetTaskFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task one", Version: 4}, nil
+				},
+				RevertTaskFunc: func(ctx context.Context, id, to, version int64) (model.TaskModel, error) {
+					assert.Equal(t, int64(2), to)
+					assert.Equal(t, int64(4), version)
+					return model.TaskModel{ID: id, TaskName: "task 1", Version: 5}, nil
+				},
+			},
+			method:   "POST",
+			url:      "/api/task/1/revert?to=2",
+			header:   map[string]string{"If-Match": `"4"`},
+			wantCode: http.StatusOK,
+			wantETag: `"5"`,
+			wantResponse: ResponseStandard{
+				Message: "Task Reverted",
+				Data:    model.TaskModel{ID: 1, TaskName: "task 1", Version: 5},
+			},
+		},
+		{
+			name:     "case 4 -> revert without a version",
+			method:   "POST",
+			url:      "/api/task/1/revert",
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{
+				Message: "Invalid Request Data",
+				Error:   []model.ErrorField{{FieldName: "to", Message: "to must be a version of the task"}},
+			},
+		},
+		{
+			name: "case 5 -> revert to a version never recorded",
+			taskUseCase: &TaskUsecaseMock{
+				RevertTaskFunc: func(ctx context.Context, id, to, version int64) (model.TaskModel, error) {
+					return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "version not found in the history of the task")
+				},
+			},
+			method:   "POST",
+			url:      "/api/task/1/revert?to=9",
+			wantCode: http.StatusNotFound,
+			wantResponse: ResponseStandard{
+				Message: "version not found in the history of the task",
+				Data:    StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				useCase: tt.taskUseCase,
+			}
+
+			router := chi.NewRouter()
+			router.Post("/api/undo", h.Undo)
+			router.Post("/api/task/{id}/revert", h.Revert)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest(tt.method, tt.url, nil)
+			for key, value := range tt.header {
+				request.Header.Set(key, value)
+			}
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+			assert.Equal(t, tt.wantETag, recorder.Header().Get("ETag"))
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
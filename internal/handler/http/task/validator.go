@@ -2,13 +2,30 @@ package task
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/rrule"
 
 	"gopkg.in/go-playground/validator.v9"
 )
 
+// messages of the custom tags, the built in ones read "<field> is <tag>"
+var messages = map[string]string{
+	"timezone":          "%v is not an IANA timezone",
+	"remind_before_due": "%v is after DueAt",
+	"rrule":             "%v is not a supported RFC 5545 recurrence rule",
+	"needs_due":         "%v needs DueAt",
+}
+
 func Validate(request model.TaskModel) []model.ErrorField {
 	validate := validator.New()
+	_ = validate.RegisterValidation("timezone", isTimezone)
+	_ = validate.RegisterValidation("remind_before_due", isRemindBeforeDue)
+	_ = validate.RegisterValidation("rrule", isRRule)
+	_ = validate.RegisterValidation("needs_due", hasDue)
 	err := validate.Struct(request)
 
 	if err != nil {
@@ -19,6 +36,9 @@ func Validate(request model.TaskModel) []model.ErrorField {
 		for _, err := range err.(validator.ValidationErrors) {
 			errorField.FieldName = err.Field()
 			errorField.Message = fmt.Sprintf("%v is %v", err.Field(), err.ActualTag())
+			if message, ok := messages[err.ActualTag()]; ok {
+				errorField.Message = fmt.Sprintf(message, err.Field())
+			}
 			arrErrorField = append(arrErrorField, errorField)
 		}
 
@@ -26,3 +46,203 @@ func Validate(request model.TaskModel) []model.ErrorField {
 	}
 	return nil
 }
+
+func isTimezone(fl validator.FieldLevel) bool {
+	_, err := time.LoadLocation(fl.Field().String())
+	return err == nil
+}
+
+func isRemindBeforeDue(fl validator.FieldLevel) bool {
+	task, ok := fl.Parent().Interface().(model.TaskModel)
+	if !ok || task.RemindAt == nil || task.DueAt == nil {
+		return true
+	}
+	return !task.RemindAt.After(*task.DueAt)
+}
+
+func isRRule(fl validator.FieldLevel) bool {
+	_, err := rrule.Parse(fl.Field().String())
+	return err == nil
+}
+
+// hasDue holds for tasks with a DueAt, the first occurrence of a rule.
+func hasDue(fl validator.FieldLevel) bool {
+	task, ok := fl.Parent().Interface().(model.TaskModel)
+	return !ok || task.DueAt != nil
+}
+
+// ParseFilter reads the list query parameters. Missing parameters keep their
+// zero value and are defaulted by the usecase.
+func ParseFilter(query url.Values) (model.TaskFilter, []model.ErrorField) {
+	var (
+		filter = model.TaskFilter{
+			Query:  strings.TrimSpace(query.Get("q")),
+			Cursor: query.Get("cursor"),
+		}
+		arrErrorField []model.ErrorField
+	)
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > model.MaxPageLimit {
+			arrErrorField = append(arrErrorField, model.ErrorField{
+				FieldName: "limit",
+				Message:   fmt.Sprintf("limit must be between 1 and %d", model.MaxPageLimit),
+			})
+		}
+		filter.Limit = n
+	}
+
+	if isDone := query.Get("is_done"); isDone != "" {
+		b, err := strconv.ParseBool(isDone)
+		if err != nil {
+			arrErrorField = append(arrErrorField, model.ErrorField{
+				FieldName: "is_done",
+				Message:   "is_done must be true or false",
+			})
+		} else {
+			filter.IsDone = &b
+		}
+	}
+
+	if priority := query.Get("priority"); priority != "" {
+		p, err := model.ParsePriority(priority)
+		if err != nil {
+			arrErrorField = append(arrErrorField, model.ErrorField{
+				FieldName: "priority",
+				Message:   err.Error(),
+			})
+		} else {
+			filter.Priority = &p
+		}
+	}
+
+	for _, tag := range query["tag"] {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	if due := query.Get("due"); due != "" {
+		if due != model.DueOverdue && due != model.DueToday && due != model.DueWeek {
+			arrErrorField = append(arrErrorField, model.ErrorField{
+				FieldName: "due",
+				Message:   fmt.Sprintf("due must be one of %s, %s, %s", model.DueOverdue, model.DueToday, model.DueWeek),
+			})
+		}
+		filter.Due = due
+	}
+
+	if tz := query.Get("tz"); tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			arrErrorField = append(arrErrorField, model.ErrorField{
+				FieldName: "tz",
+				Message:   "tz must be an IANA timezone",
+			})
+		}
+		filter.Timezone = tz
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		seen := map[string]bool{}
+		for _, key := range strings.Split(sort, ",") {
+			field := model.SortField{Field: strings.TrimPrefix(key, "-"), Desc: strings.HasPrefix(key, "-")}
+			if !isSortField(field.Field) || seen[field.Field] {
+				arrErrorField = append(arrErrorField, model.ErrorField{
+					FieldName: "sort",
+					Message:   fmt.Sprintf("sort must be a comma separated list of %s, prefixed with - for descending", strings.Join(model.SortFields, ", ")),
+				})
+				break
+			}
+			seen[field.Field] = true
+			filter.Sort = append(filter.Sort, field)
+		}
+	}
+
+	return filter, arrErrorField
+}
+
+func isSortField(field string) bool {
+	for _, f := range model.SortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEventFilter reads the query of the history and audit endpoints.
+// from and to are RFC 3339 times, to excluded.
+func ParseEventFilter(query url.Values) (model.EventFilter, []model.ErrorField) {
+	var (
+		filter = model.EventFilter{
+			Actor:  strings.TrimSpace(query.Get("actor")),
+			Cursor: query.Get("cursor"),
+		}
+		arrErrorField []model.ErrorField
+	)
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > model.MaxPageLimit {
+			arrErrorField = append(arrErrorField, model.ErrorField{
+				FieldName: "limit",
+				Message:   fmt.Sprintf("limit must be between 1 and %d", model.MaxPageLimit),
+			})
+		}
+		filter.Limit = n
+	}
+
+	if taskID := query.Get("task_id"); taskID != "" {
+		id, err := strconv.ParseInt(taskID, 10, 64)
+		if err != nil {
+			arrErrorField = append(arrErrorField, model.ErrorField{
+				FieldName: "task_id",
+				Message:   "task_id must be a number",
+			})
+		} else {
+			filter.TaskID = &id
+		}
+	}
+
+	if action := query.Get("action"); action != "" {
+		switch action {
+		case model.EventCreate, model.EventUpdate, model.EventDelete, model.EventRestore:
+		default:
+			arrErrorField = append(arrErrorField, model.ErrorField{
+				FieldName: "action",
+				Message:   fmt.Sprintf("action must be one of %s, %s, %s, %s", model.EventCreate, model.EventUpdate, model.EventDelete, model.EventRestore),
+			})
+		}
+		filter.Action = action
+	}
+
+	for _, bound := range []struct {
+		name string
+		dest **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := query.Get(bound.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			arrErrorField = append(arrErrorField, model.ErrorField{
+				FieldName: bound.name,
+				Message:   bound.name + " must be an RFC 3339 time",
+			})
+			continue
+		}
+		*bound.dest = &t
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		arrErrorField = append(arrErrorField, model.ErrorField{
+			FieldName: "to",
+			Message:   "to must be after from",
+		})
+	}
+
+	return filter, arrErrorField
+}
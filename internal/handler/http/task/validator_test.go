@@ -1,13 +1,19 @@
 package task
 
 import (
+	"net/url"
 	"testing"
+	"time"
 	model "to-do-list/internal/model/task"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestValidator(t *testing.T) {
+	dueAt := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+	before := dueAt.Add(-time.Hour)
+	after := dueAt.Add(time.Minute)
+
 	tests := []struct {
 		name string
 		arg  model.TaskModel
@@ -30,6 +36,98 @@ func TestValidator(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "case 3 -> reminder before deadline in a timezone",
+			arg: model.TaskModel{
+				TaskName: "test1",
+				DueAt:    &dueAt,
+				Timezone: "Asia/Jakarta",
+				RemindAt: &before,
+			},
+			want: nil,
+		},
+		{
+			name: "case 4 -> reminder after deadline",
+			arg: model.TaskModel{
+				TaskName: "test1",
+				DueAt:    &dueAt,
+				RemindAt: &after,
+			},
+			want: []model.ErrorField{
+				{
+					FieldName: "RemindAt",
+					Message:   "RemindAt is after DueAt",
+				},
+			},
+		},
+		{
+			name: "case 5 -> unknown timezone",
+			arg: model.TaskModel{
+				TaskName: "test1",
+				Timezone: "Mars/Olympus",
+			},
+			want: []model.ErrorField{
+				{
+					FieldName: "Timezone",
+					Message:   "Timezone is not an IANA timezone",
+				},
+			},
+		},
+		{
+			name: "case 6 -> empty tag",
+			arg: model.TaskModel{
+				TaskName: "test1",
+				Tags:     []string{"home", ""},
+			},
+			want: []model.ErrorField{
+				{
+					FieldName: "Tags[1]",
+					Message:   "Tags[1] is required",
+				},
+			},
+		},
+		{
+			name: "case 7 -> recurring task repeating from completion",
+			arg: model.TaskModel{
+				TaskName:   "test1",
+				DueAt:      &dueAt,
+				RRule:      "FREQ=DAILY;INTERVAL=3",
+				RepeatFrom: model.RepeatFromCompletion,
+			},
+			want: nil,
+		},
+		{
+			name: "case 8 -> invalid rule and repeat mode",
+			arg: model.TaskModel{
+				TaskName:   "test1",
+				DueAt:      &dueAt,
+				RRule:      "FREQ=HOURLY",
+				RepeatFrom: "never",
+			},
+			want: []model.ErrorField{
+				{
+					FieldName: "RRule",
+					Message:   "RRule is not a supported RFC 5545 recurrence rule",
+				},
+				{
+					FieldName: "RepeatFrom",
+					Message:   "RepeatFrom is oneof",
+				},
+			},
+		},
+		{
+			name: "case 9 -> rule without a due date",
+			arg: model.TaskModel{
+				TaskName: "test1",
+				RRule:    "FREQ=MONTHLY;BYMONTHDAY=1",
+			},
+			want: []model.ErrorField{
+				{
+					FieldName: "RRule",
+					Message:   "RRule needs DueAt",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -40,3 +138,105 @@ func TestValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFilter(t *testing.T) {
+	high := model.PriorityHigh
+
+	tests := []struct {
+		name      string
+		arg       url.Values
+		want      model.TaskFilter
+		wantError []model.ErrorField
+	}{
+		{
+			name: "case 1 -> no parameters",
+			arg:  url.Values{},
+			want: model.TaskFilter{},
+		},
+		{
+			name: "case 2 -> limit and cursor",
+			arg:  url.Values{"limit": {"10"}, "cursor": {"abc"}},
+			want: model.TaskFilter{Limit: 10, Cursor: "abc"},
+		},
+		{
+			name: "case 3 -> limit not a number",
+			arg:  url.Values{"limit": {"ten"}},
+			want: model.TaskFilter{},
+			wantError: []model.ErrorField{
+				{
+					FieldName: "limit",
+					Message:   "limit must be between 1 and 100",
+				},
+			},
+		},
+		{
+			name: "case 4 -> status, name and sort",
+			arg:  url.Values{"is_done": {"false"}, "q": {" milk "}, "sort": {"task_name,-id"}},
+			want: model.TaskFilter{
+				IsDone: new(bool),
+				Query:  "milk",
+				Sort:   []model.SortField{{Field: "task_name"}, {Field: "id", Desc: true}},
+			},
+		},
+		{
+			name: "case 5 -> invalid status and unknown sort field",
+			arg:  url.Values{"is_done": {"maybe"}, "sort": {"-owner"}},
+			want: model.TaskFilter{},
+			wantError: []model.ErrorField{
+				{
+					FieldName: "is_done",
+					Message:   "is_done must be true or false",
+				},
+				{
+					FieldName: "sort",
+					Message:   "sort must be a comma separated list of id, task_name, is_done, due_at, priority, rank, prefixed with - for descending",
+				},
+			},
+		},
+		{
+			name: "case 6 -> due window in a timezone",
+			arg:  url.Values{"due": {"today"}, "tz": {"Asia/Jakarta"}},
+			want: model.TaskFilter{Due: model.DueToday, Timezone: "Asia/Jakarta"},
+		},
+		{
+			name: "case 7 -> unknown due window and timezone",
+			arg:  url.Values{"due": {"someday"}, "tz": {"Mars/Olympus"}},
+			want: model.TaskFilter{Due: "someday", Timezone: "Mars/Olympus"},
+			wantError: []model.ErrorField{
+				{
+					FieldName: "due",
+					Message:   "due must be one of overdue, today, week",
+				},
+				{
+					FieldName: "tz",
+					Message:   "tz must be an IANA timezone",
+				},
+			},
+		},
+		{
+			name: "case 8 -> priority and tags",
+			arg:  url.Values{"priority": {"high"}, "tag": {"Home", " work", ""}},
+			want: model.TaskFilter{Priority: &high, Tags: []string{"home", "work"}},
+		},
+		{
+			name: "case 9 -> unknown priority",
+			arg:  url.Values{"priority": {"urgent"}},
+			want: model.TaskFilter{},
+			wantError: []model.ErrorField{
+				{
+					FieldName: "priority",
+					Message:   "priority must be one of none, low, medium, high",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseFilter(tt.arg)
+
+			assert.Equal(t, tt.want, filter)
+			assert.Equal(t, tt.wantError, err)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	model "to-do-list/internal/model/user"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CreateAPIKey answers the key itself, it is the only time it can be read.
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.APIKeyRequest{}
+		status  = http.StatusCreated
+	)
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.CreateAPIKey(ctx, request)
+
+	responses := util.ResponseStandard{
+		Message: "API Key Created",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Create API Key] Response error")
+	}
+}
+
+func (h *Handler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	responses, err := h.useCase.GetAPIKeys(ctx)
+
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if err := util.ResponseJSON(responses, http.StatusOK, w); err != nil {
+		fmt.Println("[Get API Keys] Response error")
+	}
+}
+
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "API key not found"}, http.StatusNotFound, w)
+		return
+	}
+
+	status := http.StatusOK
+	responses := util.ResponseStandard{
+		Message: "API Key Revoked",
+		Data:    util.StatusRespose{Success: true},
+	}
+
+	if err := h.useCase.RevokeAPIKey(ctx, id); err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Revoke API Key] Response error")
+	}
+}
@@ -0,0 +1,107 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_CreateAPIKey(t *testing.T) {
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	useCase := &UserUsecaseMock{
+		CreateAPIKeyFunc: func(ctx context.Context, r model.APIKeyRequest) (model.APIKeyModel, error) {
+			return model.APIKeyModel{ID: 1, Name: r.Name, Prefix: "tdl_abcdefgh", ReadOnly: r.ReadOnly, CreatedAt: createdAt, Key: "tdl_abcdefghsecret", Hash: "hash"}, nil
+		},
+	}
+
+	h := NewHandler(useCase)
+
+	router := chi.NewRouter()
+	router.Post("/api/keys", h.CreateAPIKey)
+	recorder := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/api/keys", bytes.NewBufferString(`{"name":"backup","read_only":true}`))
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusCreated, recorder.Code, "error code")
+
+	jsonExpect, err := json.Marshal(util.ResponseStandard{
+		Message: "API Key Created",
+		Data:    model.APIKeyModel{ID: 1, Name: "backup", Prefix: "tdl_abcdefgh", ReadOnly: true, CreatedAt: createdAt, Key: "tdl_abcdefghsecret"},
+	})
+	assert.NoError(t, err, "marshal error")
+	assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+	assert.NotContains(t, recorder.Body.String(), "hash")
+}
+
+func TestHandler_RevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *UserUsecaseMock
+		id           string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when revoke key",
+			useCase: &UserUsecaseMock{
+				RevokeAPIKeyFunc: func(ctx context.Context, id int64) error {
+					return nil
+				},
+			},
+			id:       "1",
+			wantCode: http.StatusOK,
+			wantResponse: util.ResponseStandard{
+				Message: "API Key Revoked",
+				Data:    util.StatusRespose{Success: true},
+			},
+		},
+		{
+			name: "case 2 -> fail when key is not found",
+			useCase: &UserUsecaseMock{
+				RevokeAPIKeyFunc: func(ctx context.Context, id int64) error {
+					return apperror.New(apperror.ErrNotFound, "api key not found")
+				},
+			},
+			id:       "1",
+			wantCode: http.StatusNotFound,
+			wantResponse: util.ResponseStandard{
+				Message: "api key not found",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+		{
+			name:         "case 3 -> fail when id is not a number",
+			useCase:      &UserUsecaseMock{},
+			id:           "abc",
+			wantCode:     http.StatusNotFound,
+			wantResponse: util.ErrorResponse{Message: "API key not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Delete("/api/keys/{id}", h.RevokeAPIKey)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("DELETE", "/api/keys/"+tt.id, nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	taskmodel "to-do-list/internal/model/task"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/requestinfo"
+	util "to-do-list/pkg/response"
+)
+
+type Handler struct {
+	useCase UserUsecase
+}
+
+func NewHandler(useCase UserUsecase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+type UserUsecase interface {
+	Register(ctx context.Context, r model.Credentials) (model.UserModel, error)
+	Login(ctx context.Context, r model.Credentials) (model.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (model.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Authenticate(ctx context.Context, credential string) (model.Principal, error)
+	CreateAPIKey(ctx context.Context, r model.APIKeyRequest) (model.APIKeyModel, error)
+	GetAPIKeys(ctx context.Context) ([]model.APIKeyModel, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+}
+
+// APIKeyHeader carries an API key, as an alternative to the Authorization
+// header.
+const APIKeyHeader = "X-API-Key"
+
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.Credentials{}
+		status  = http.StatusCreated
+	)
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.Register(ctx, request)
+
+	responses := util.ResponseStandard{
+		Message: "User Registered",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Register User] Response error")
+	}
+}
+
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.Credentials{}
+	)
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.Login(ctx, request)
+	h.respondTokens(w, "Logged In", data, err)
+}
+
+// Refresh trades a refresh token for a new pair, the old refresh token
+// stops working.
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.RefreshRequest{}
+	)
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.Refresh(ctx, request.RefreshToken)
+	h.respondTokens(w, "Token Refreshed", data, err)
+}
+
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.RefreshRequest{}
+		status  = http.StatusOK
+	)
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	err := h.useCase.Logout(ctx, request.RefreshToken)
+
+	responses := util.ResponseStandard{
+		Message: "Logged Out",
+		Data:    util.StatusRespose{Success: true},
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Logout User] Response error")
+	}
+}
+
+func (h *Handler) respondTokens(w http.ResponseWriter, message string, data model.TokenPair, err error) {
+	status := http.StatusOK
+
+	responses := util.ResponseStandard{
+		Message: message,
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+		if status == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[" + message + "] Response error")
+	}
+}
+
+// Middleware lets through requests with a valid access token or API key
+// and answers 401 to the others. The signed in user becomes the owner and
+// the actor of the request, the workspace of a limited API key its
+// workspace.
+func (h *Handler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credential := credentialOf(r)
+		if credential == "" {
+			unauthorized(w, "missing access token or API key")
+			return
+		}
+
+		principal, err := h.useCase.Authenticate(r.Context(), credential)
+		if err != nil {
+			unauthorized(w, util.MessageFromError(err))
+			return
+		}
+
+		ctx := requestinfo.WithUserID(r.Context(), principal.UserID)
+		ctx = requestinfo.WithActor(ctx, principal.Email)
+		if principal.ReadOnly {
+			ctx = requestinfo.WithReadOnly(ctx)
+		}
+		if principal.WorkspaceID != 0 {
+			ctx = requestinfo.WithWorkspaceID(ctx, principal.WorkspaceID)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Scope answers 403 to requests of read only API keys that could change
+// something, only GET, HEAD and OPTIONS go through. It runs after
+// Middleware.
+func (h *Handler) Scope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if requestinfo.ReadOnly(r.Context()) {
+				util.ResponseErrorJSON(&util.ErrorResponse{Message: "API key is read only"}, http.StatusForbidden, w)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// credentialOf reads an API key from X-API-Key, or an access token or an
+// API key from "Authorization: Bearer".
+func credentialOf(r *http.Request) string {
+	if key := strings.TrimSpace(r.Header.Get(APIKeyHeader)); key != "" {
+		return key
+	}
+	scheme, credential, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(credential)
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	util.ResponseErrorJSON(&util.ErrorResponse{Message: message}, http.StatusUnauthorized, w)
+}
+
+// decode reads and validates the request body, answering 422 itself when
+// the body is unusable.
+func decode(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	if err := json.Unmarshal(reqBody, request); err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []taskmodel.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	if validate := Validate(request); validate != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   validate,
+		}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	return true
+}
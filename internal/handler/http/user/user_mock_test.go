@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+	model "to-do-list/internal/model/user"
+)
+
+type UserUsecaseMock struct {
+	RegisterFunc     func(ctx context.Context, r model.Credentials) (model.UserModel, error)
+	LoginFunc        func(ctx context.Context, r model.Credentials) (model.TokenPair, error)
+	RefreshFunc      func(ctx context.Context, refreshToken string) (model.TokenPair, error)
+	LogoutFunc       func(ctx context.Context, refreshToken string) error
+	AuthenticateFunc func(ctx context.Context, credential string) (model.Principal, error)
+	CreateAPIKeyFunc func(ctx context.Context, r model.APIKeyRequest) (model.APIKeyModel, error)
+	GetAPIKeysFunc   func(ctx context.Context) ([]model.APIKeyModel, error)
+	RevokeAPIKeyFunc func(ctx context.Context, id int64) error
+}
+
+func (m *UserUsecaseMock) Register(ctx context.Context, r model.Credentials) (model.UserModel, error) {
+	return m.RegisterFunc(ctx, r)
+}
+
+func (m *UserUsecaseMock) Login(ctx context.Context, r model.Credentials) (model.TokenPair, error) {
+	return m.LoginFunc(ctx, r)
+}
+
+func (m *UserUsecaseMock) Refresh(ctx context.Context, refreshToken string) (model.TokenPair, error) {
+	return m.RefreshFunc(ctx, refreshToken)
+}
+
+func (m *UserUsecaseMock) Logout(ctx context.Context, refreshToken string) error {
+	return m.LogoutFunc(ctx, refreshToken)
+}
+
+func (m *UserUsecaseMock) Authenticate(ctx context.Context, credential string) (model.Principal, error) {
+	return m.AuthenticateFunc(ctx, credential)
+}
+
+func (m *UserUsecaseMock) CreateAPIKey(ctx context.Context, r model.APIKeyRequest) (model.APIKeyModel, error) {
+	return m.CreateAPIKeyFunc(ctx, r)
+}
+
+func (m *UserUsecaseMock) GetAPIKeys(ctx context.Context) ([]model.APIKeyModel, error) {
+	return m.GetAPIKeysFunc(ctx)
+}
+
+func (m *UserUsecaseMock) RevokeAPIKey(ctx context.Context, id int64) error {
+	return m.RevokeAPIKeyFunc(ctx, id)
+}
@@ -0,0 +1,237 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	taskmodel "to-do-list/internal/model/task"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Register(t *testing.T) {
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		useCase      *UserUsecaseMock
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when register user",
+			useCase: &UserUsecaseMock{
+				RegisterFunc: func(ctx context.Context, r model.Credentials) (model.UserModel, error) {
+					return model.UserModel{ID: 1, Email: r.Email, PasswordHash: "hash", CreatedAt: createdAt}, nil
+				},
+			},
+			body:     `{"email":"alice@example.com","password":"correct horse"}`,
+			wantCode: http.StatusCreated,
+			wantResponse: util.ResponseStandard{
+				Message: "User Registered",
+				Data:    model.UserModel{ID: 1, Email: "alice@example.com", CreatedAt: createdAt},
+			},
+		},
+		{
+			name:     "case 2 -> fail when password is short",
+			useCase:  &UserUsecaseMock{},
+			body:     `{"email":"alice@example.com","password":"short"}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []taskmodel.ErrorField{
+				{FieldName: "Password", Message: "Password is min"},
+			}},
+		},
+		{
+			name: "case 3 -> fail when email is taken",
+			useCase: &UserUsecaseMock{
+				RegisterFunc: func(ctx context.Context, r model.Credentials) (model.UserModel, error) {
+					return model.UserModel{}, apperror.New(apperror.ErrConflict, "user already exists")
+				},
+			},
+			body:     `{"email":"alice@example.com","password":"correct horse"}`,
+			wantCode: http.StatusConflict,
+			wantResponse: util.ResponseStandard{
+				Message: "user already exists",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Post("/api/auth/register", h.Register)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/auth/register", bytes.NewBufferString(tt.body))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_Login(t *testing.T) {
+	pair := model.TokenPair{AccessToken: "access", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "refresh"}
+
+	tests := []struct {
+		name         string
+		useCase      *UserUsecaseMock
+		wantCode     int
+		wantHeader   string
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when credentials are valid",
+			useCase: &UserUsecaseMock{
+				LoginFunc: func(ctx context.Context, r model.Credentials) (model.TokenPair, error) {
+					return pair, nil
+				},
+			},
+			wantCode:     http.StatusOK,
+			wantResponse: util.ResponseStandard{Message: "Logged In", Data: pair},
+		},
+		{
+			name: "case 2 -> fail when credentials are wrong",
+			useCase: &UserUsecaseMock{
+				LoginFunc: func(ctx context.Context, r model.Credentials) (model.TokenPair, error) {
+					return model.TokenPair{}, apperror.New(apperror.ErrUnauthorized, "invalid email or password")
+				},
+			},
+			wantCode:   http.StatusUnauthorized,
+			wantHeader: "Bearer",
+			wantResponse: util.ResponseStandard{
+				Message: "invalid email or password",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase)
+
+			router := chi.NewRouter()
+			router.Post("/api/auth/login", h.Login)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/auth/login", bytes.NewBufferString(`{"email":"alice@example.com","password":"correct horse"}`))
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+			assert.Equal(t, tt.wantHeader, recorder.Header().Get("WWW-Authenticate"))
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_Middleware(t *testing.T) {
+	useCase := &UserUsecaseMock{
+		AuthenticateFunc: func(ctx context.Context, credential string) (model.Principal, error) {
+			switch credential {
+			case "valid":
+				return model.Principal{UserID: 7, Email: "alice@example.com"}, nil
+			case "tdl_key":
+				return model.Principal{UserID: 7, Email: "alice@example.com", ReadOnly: true, WorkspaceID: 2}, nil
+			}
+			return model.Principal{}, apperror.New(apperror.ErrUnauthorized, "invalid access token")
+		},
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		header   http.Header
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "case 1 -> valid token reaches the handler as its user",
+			method:   "GET",
+			header:   http.Header{"Authorization": {"Bearer valid"}},
+			wantCode: http.StatusOK,
+			wantBody: "7 alice@example.com false 0",
+		},
+		{
+			name:     "case 2 -> missing header",
+			method:   "GET",
+			wantCode: http.StatusUnauthorized,
+			wantBody: `{"Message":"missing access token or API key","Error":null}`,
+		},
+		{
+			name:     "case 3 -> other scheme",
+			method:   "GET",
+			header:   http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+			wantCode: http.StatusUnauthorized,
+			wantBody: `{"Message":"missing access token or API key","Error":null}`,
+		},
+		{
+			name:     "case 4 -> invalid token",
+			method:   "GET",
+			header:   http.Header{"Authorization": {"Bearer forged"}},
+			wantCode: http.StatusUnauthorized,
+			wantBody: `{"Message":"invalid access token","Error":null}`,
+		},
+		{
+			name:     "case 5 -> read only API key from X-API-Key reads in its workspace",
+			method:   "GET",
+			header:   http.Header{"X-Api-Key": {"tdl_key"}},
+			wantCode: http.StatusOK,
+			wantBody: "7 alice@example.com true 2",
+		},
+		{
+			name:     "case 6 -> read only API key as bearer token cannot write",
+			method:   "POST",
+			header:   http.Header{"Authorization": {"Bearer tdl_key"}},
+			wantCode: http.StatusForbidden,
+			wantBody: `{"Message":"API key is read only","Error":null}`,
+		},
+		{
+			name:     "case 7 -> access token writes",
+			method:   "POST",
+			header:   http.Header{"Authorization": {"Bearer valid"}},
+			wantCode: http.StatusOK,
+			wantBody: "7 alice@example.com false 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(useCase)
+
+			router := chi.NewRouter()
+			router.Use(h.Middleware, h.Scope)
+			router.HandleFunc("/api/tasks", func(w http.ResponseWriter, r *http.Request) {
+				ctx := r.Context()
+				fmt.Fprintf(w, "%d %s %t %d", requestinfo.UserID(ctx), requestinfo.Actor(ctx), requestinfo.ReadOnly(ctx), requestinfo.WorkspaceID(ctx))
+			})
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest(tt.method, "/api/tasks", nil)
+			if tt.header != nil {
+				request.Header = tt.header
+			}
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+			assert.Equal(t, tt.wantBody, recorder.Body.String())
+			if tt.wantCode == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
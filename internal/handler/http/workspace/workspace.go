@@ -0,0 +1,252 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	taskmodel "to-do-list/internal/model/task"
+	model "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/requestinfo"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Header names the workspace of a request by its slug.
+const Header = "X-Workspace"
+
+type Handler struct {
+	useCase WorkspaceUsecase
+	domain  string
+}
+
+// NewHandler serves the workspaces. With a domain, requests to
+// <slug>.<domain> work in the workspace of the slug when they do not send
+// the Header.
+func NewHandler(useCase WorkspaceUsecase, domain string) *Handler {
+	return &Handler{useCase: useCase, domain: strings.ToLower(strings.Trim(domain, "."))}
+}
+
+type WorkspaceUsecase interface {
+	Resolve(ctx context.Context, slug string) (model.WorkspaceModel, error)
+	GetWorkspaces(ctx context.Context) ([]model.WorkspaceModel, error)
+	CreateWorkspace(ctx context.Context, r model.WorkspaceModel) (model.WorkspaceModel, error)
+	GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error)
+	AddMember(ctx context.Context, id int64, r model.MemberRequest) (model.MemberModel, error)
+	RemoveMember(ctx context.Context, id int64, userID int64) error
+}
+
+// Middleware runs every request in a workspace of the signed in user, the
+// one named by the Header or the subdomain, else the one of the API key,
+// else the default one. It answers 404 for workspaces of others and runs
+// after the user middleware.
+func (h *Handler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspace, err := h.useCase.Resolve(r.Context(), h.slugOf(r))
+		if err != nil {
+			util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+			return
+		}
+
+		ctx := requestinfo.WithWorkspaceID(r.Context(), workspace.ID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// slugOf reads the slug from the Header, or from the subdomain of the
+// domain. Deeper subdomains name no workspace.
+func (h *Handler) slugOf(r *http.Request) string {
+	if slug := strings.TrimSpace(r.Header.Get(Header)); slug != "" {
+		return slug
+	}
+	if h.domain == "" {
+		return ""
+	}
+
+	host := r.Host
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		host = name
+	}
+	host = strings.ToLower(host)
+
+	if !strings.HasSuffix(host, "."+h.domain) {
+		return ""
+	}
+	slug := strings.TrimSuffix(host, "."+h.domain)
+	if strings.Contains(slug, ".") {
+		return ""
+	}
+	return slug
+}
+
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	data, err := h.useCase.GetWorkspaces(ctx)
+
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if err := util.ResponseJSON(data, http.StatusOK, w); err != nil {
+		fmt.Println("[Get Workspaces] Response error")
+	}
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.WorkspaceModel{}
+		status  = http.StatusCreated
+	)
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.CreateWorkspace(ctx, request)
+
+	responses := util.ResponseStandard{
+		Message: "Workspace Created",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Create Workspace] Response error")
+	}
+}
+
+func (h *Handler) Members(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.useCase.GetMembers(ctx, id)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: util.MessageFromError(err)}, util.StatusFromError(err), w)
+		return
+	}
+
+	if err := util.ResponseJSON(data, http.StatusOK, w); err != nil {
+		fmt.Println("[Get Workspace Members] Response error")
+	}
+}
+
+// AddMember adds a user to the workspace by email.
+func (h *Handler) AddMember(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		request = model.MemberRequest{}
+		status  = http.StatusCreated
+	)
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	if !decode(w, r, &request) {
+		return
+	}
+
+	data, err := h.useCase.AddMember(ctx, id, request)
+
+	responses := util.ResponseStandard{
+		Message: "Member Added",
+		Data:    data,
+	}
+
+	if err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Add Workspace Member] Response error")
+	}
+}
+
+// RemoveMember takes a user out of the workspace. Members remove
+// themselves to leave it.
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := urlID(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.ParseInt(chi.URLParam(r, "user_id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Member not found"}, http.StatusNotFound, w)
+		return
+	}
+
+	status := http.StatusOK
+	responses := util.ResponseStandard{
+		Message: "Member Removed",
+		Data:    util.StatusRespose{Success: true},
+	}
+
+	if err := h.useCase.RemoveMember(ctx, id, userID); err != nil {
+		status = util.StatusFromError(err)
+		responses.Message = util.MessageFromError(err)
+		responses.Data = util.StatusRespose{Success: false}
+	}
+
+	if err := util.ResponseJSON(responses, status, w); err != nil {
+		fmt.Println("[Remove Workspace Member] Response error")
+	}
+}
+
+func urlID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Workspace not found"}, http.StatusNotFound, w)
+		return 0, false
+	}
+	return id, true
+}
+
+// decode reads and validates the request body, answering 422 itself when
+// the body is unusable.
+func decode(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{Message: "Invalid Request Data"}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	if err := json.Unmarshal(reqBody, request); err != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   []taskmodel.ErrorField{{FieldName: "body", Message: err.Error()}},
+		}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	if validate := Validate(request); validate != nil {
+		util.ResponseErrorJSON(&util.ErrorResponse{
+			Message: "Invalid Request Data",
+			Error:   validate,
+		}, http.StatusUnprocessableEntity, w)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,39 @@
+package workspace
+
+import (
+	"context"
+	model "to-do-list/internal/model/workspace"
+)
+
+type WorkspaceUsecaseMock struct {
+	ResolveFunc         func(ctx context.Context, slug string) (model.WorkspaceModel, error)
+	GetWorkspacesFunc   func(ctx context.Context) ([]model.WorkspaceModel, error)
+	CreateWorkspaceFunc func(ctx context.Context, r model.WorkspaceModel) (model.WorkspaceModel, error)
+	GetMembersFunc      func(ctx context.Context, id int64) ([]model.MemberModel, error)
+	AddMemberFunc       func(ctx context.Context, id int64, r model.MemberRequest) (model.MemberModel, error)
+	RemoveMemberFunc    func(ctx context.Context, id int64, userID int64) error
+}
+
+func (m *WorkspaceUsecaseMock) Resolve(ctx context.Context, slug string) (model.WorkspaceModel, error) {
+	return m.ResolveFunc(ctx, slug)
+}
+
+func (m *WorkspaceUsecaseMock) GetWorkspaces(ctx context.Context) ([]model.WorkspaceModel, error) {
+	return m.GetWorkspacesFunc(ctx)
+}
+
+func (m *WorkspaceUsecaseMock) CreateWorkspace(ctx context.Context, r model.WorkspaceModel) (model.WorkspaceModel, error) {
+	return m.CreateWorkspaceFunc(ctx, r)
+}
+
+func (m *WorkspaceUsecaseMock) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	return m.GetMembersFunc(ctx, id)
+}
+
+func (m *WorkspaceUsecaseMock) AddMember(ctx context.Context, id int64, r model.MemberRequest) (model.MemberModel, error) {
+	return m.AddMemberFunc(ctx, id, r)
+}
+
+func (m *WorkspaceUsecaseMock) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	return m.RemoveMemberFunc(ctx, id, userID)
+}
@@ -0,0 +1,266 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	taskmodel "to-do-list/internal/model/task"
+	model "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+	util "to-do-list/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// resolver answers like the usecase for a user who is a member of the
+// default workspace and of acme, but not of globex.
+func resolver(t *testing.T, wantSlug string) *WorkspaceUsecaseMock {
+	return &WorkspaceUsecaseMock{
+		ResolveFunc: func(ctx context.Context, slug string) (model.WorkspaceModel, error) {
+			assert.Equal(t, wantSlug, slug, "slug of the request")
+			switch slug {
+			case "":
+				return model.WorkspaceModel{ID: model.DefaultID, Slug: "default"}, nil
+			case "acme":
+				return model.WorkspaceModel{ID: 2, Slug: "acme"}, nil
+			case "pinned":
+				return model.WorkspaceModel{}, apperror.New(apperror.ErrForbidden, "API key is limited to another workspace")
+			}
+			return model.WorkspaceModel{}, apperror.New(apperror.ErrNotFound, "workspace not found")
+		},
+	}
+}
+
+func TestHandler_Middleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		domain        string
+		host          string
+		header        string
+		wantSlug      string
+		wantCode      int
+		wantWorkspace int64
+		wantResponse  interface{}
+	}{
+		{
+			name:          "case 1 -> default workspace without a slug",
+			host:          "todo.example.com",
+			wantCode:      http.StatusOK,
+			wantWorkspace: model.DefaultID,
+		},
+		{
+			name:          "case 2 -> workspace named by the header",
+			host:          "todo.example.com",
+			header:        "acme",
+			wantSlug:      "acme",
+			wantCode:      http.StatusOK,
+			wantWorkspace: 2,
+		},
+		{
+			name:          "case 3 -> workspace named by the subdomain",
+			domain:        "todo.example.com",
+			host:          "acme.todo.example.com:8080",
+			wantSlug:      "acme",
+			wantCode:      http.StatusOK,
+			wantWorkspace: 2,
+		},
+		{
+			name:          "case 4 -> header wins over the subdomain",
+			domain:        "todo.example.com",
+			host:          "globex.todo.example.com",
+			header:        "acme",
+			wantSlug:      "acme",
+			wantCode:      http.StatusOK,
+			wantWorkspace: 2,
+		},
+		{
+			name:          "case 5 -> subdomains are ignored without a domain",
+			host:          "acme.todo.example.com",
+			wantCode:      http.StatusOK,
+			wantWorkspace: model.DefaultID,
+		},
+		{
+			name:          "case 6 -> deeper subdomains name no workspace",
+			domain:        "todo.example.com",
+			host:          "www.acme.todo.example.com",
+			wantCode:      http.StatusOK,
+			wantWorkspace: model.DefaultID,
+		},
+		{
+			name:         "case 7 -> workspace of other users is not found",
+			domain:       "todo.example.com",
+			host:         "globex.todo.example.com",
+			wantSlug:     "globex",
+			wantCode:     http.StatusNotFound,
+			wantResponse: util.ErrorResponse{Message: "workspace not found"},
+		},
+		{
+			name:         "case 8 -> API key limited to another workspace",
+			header:       "pinned",
+			wantSlug:     "pinned",
+			wantCode:     http.StatusForbidden,
+			wantResponse: util.ErrorResponse{Message: "API key is limited to another workspace"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(resolver(t, tt.wantSlug), tt.domain)
+
+			reached := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				assert.Equal(t, tt.wantWorkspace, requestinfo.WorkspaceID(r.Context()))
+			})
+
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", "/api/tasks", nil)
+			request.Host = tt.host
+			if tt.header != "" {
+				request.Header.Set(Header, tt.header)
+			}
+			h.Middleware(next).ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+			assert.Equal(t, tt.wantResponse == nil, reached, "reached the handler")
+			if tt.wantResponse != nil {
+				jsonExpect, err := json.Marshal(tt.wantResponse)
+				assert.NoError(t, err, "marshal error")
+				assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+			}
+		})
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *WorkspaceUsecaseMock
+		body         string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when create a workspace",
+			useCase: &WorkspaceUsecaseMock{
+				CreateWorkspaceFunc: func(ctx context.Context, r model.WorkspaceModel) (model.WorkspaceModel, error) {
+					r.ID = 2
+					r.Role = model.RoleOwner
+					return r, nil
+				},
+			},
+			body:     `{"name":"Acme","slug":"acme"}`,
+			wantCode: http.StatusCreated,
+			wantResponse: util.ResponseStandard{
+				Message: "Workspace Created",
+				Data:    model.WorkspaceModel{ID: 2, Name: "Acme", Slug: "acme", Role: model.RoleOwner},
+			},
+		},
+		{
+			name:     "case 2 -> fail when slug is missing",
+			useCase:  &WorkspaceUsecaseMock{},
+			body:     `{"name":"Acme"}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantResponse: util.ErrorResponse{Message: "Invalid Request Data", Error: []taskmodel.ErrorField{
+				{FieldName: "Slug", Message: "Slug is required"},
+			}},
+		},
+		{
+			name: "case 3 -> fail when slug is taken",
+			useCase: &WorkspaceUsecaseMock{
+				CreateWorkspaceFunc: func(ctx context.Context, r model.WorkspaceModel) (model.WorkspaceModel, error) {
+					return model.WorkspaceModel{}, apperror.New(apperror.ErrConflict, "workspace already exists")
+				},
+			},
+			body:     `{"name":"Acme","slug":"acme"}`,
+			wantCode: http.StatusConflict,
+			wantResponse: util.ResponseStandard{
+				Message: "workspace already exists",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase, "")
+
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("POST", "/api/workspaces", bytes.NewBufferString(tt.body))
+			http.HandlerFunc(h.Create).ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
+
+func TestHandler_RemoveMember(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCase      *WorkspaceUsecaseMock
+		url          string
+		wantCode     int
+		wantResponse interface{}
+	}{
+		{
+			name: "case 1 -> success when remove a member",
+			useCase: &WorkspaceUsecaseMock{
+				RemoveMemberFunc: func(ctx context.Context, id int64, userID int64) error {
+					return nil
+				},
+			},
+			url:      "/api/workspaces/2/members/4",
+			wantCode: http.StatusOK,
+			wantResponse: util.ResponseStandard{
+				Message: "Member Removed",
+				Data:    util.StatusRespose{Success: true},
+			},
+		},
+		{
+			name: "case 2 -> fail when the caller does not own the workspace",
+			useCase: &WorkspaceUsecaseMock{
+				RemoveMemberFunc: func(ctx context.Context, id int64, userID int64) error {
+					return apperror.New(apperror.ErrForbidden, "only the owner of the workspace manages its members")
+				},
+			},
+			url:      "/api/workspaces/2/members/4",
+			wantCode: http.StatusForbidden,
+			wantResponse: util.ResponseStandard{
+				Message: "only the owner of the workspace manages its members",
+				Data:    util.StatusRespose{Success: false},
+			},
+		},
+		{
+			name:         "case 3 -> fail when user id is not a number",
+			useCase:      &WorkspaceUsecaseMock{},
+			url:          "/api/workspaces/2/members/bob",
+			wantCode:     http.StatusNotFound,
+			wantResponse: util.ErrorResponse{Message: "Member not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.useCase, "")
+
+			router := chi.NewRouter()
+			router.Delete("/api/workspaces/{id}/members/{user_id}", h.RemoveMember)
+			recorder := httptest.NewRecorder()
+			request, _ := http.NewRequest("DELETE", tt.url, nil)
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantCode, recorder.Code, "error code")
+
+			jsonExpect, err := json.Marshal(tt.wantResponse)
+			assert.NoError(t, err, "marshal error")
+			assert.Equal(t, jsonExpect, recorder.Body.Bytes(), "handler response")
+		})
+	}
+}
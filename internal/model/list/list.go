@@ -0,0 +1,30 @@
+package list
+
+// swagger:model List
+type ListModel struct {
+	// ID of list
+	// in: int64
+	ID int64 `json:"id"`
+	// Name of list
+	// in: string
+	Name string `json:"name" validate:"required,max=100"`
+	// Number of tasks in the list
+	// in: int64
+	TaskCount int64 `json:"task_count"`
+	// Role of the signed in user in the list, owner for their own lists
+	// in: string
+	Role Role `json:"role,omitempty"`
+}
+
+// What happens to the tasks of a deleted list.
+const (
+	DeleteCascade  = "cascade"
+	DeleteReassign = "reassign"
+)
+
+// DeleteOptions of a list. With DeleteReassign the tasks move to the list
+// To, or to no list when To is nil.
+type DeleteOptions struct {
+	Mode string
+	To   *int64
+}
@@ -0,0 +1,62 @@
+package list
+
+import "time"
+
+// Role of a user in a list. Viewers read its tasks, editors change them
+// too and owners also share the list and rename it.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+var roleRank = map[Role]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+// Allows reports whether the role grants at least the rights of need.
+func (r Role) Allows(need Role) bool {
+	return roleRank[r] > 0 && roleRank[r] >= roleRank[need]
+}
+
+// Access is what the signed in user may do with a list, or with a task in
+// one. OwnerID is the user the list and its tasks belong to, the one who
+// created the list.
+type Access struct {
+	OwnerID int64
+	Role    Role
+}
+
+// swagger:model Member
+type MemberModel struct {
+	// ID of the shared list
+	// in: int64
+	ListID int64 `json:"list_id"`
+	// Name of the shared list, only in invitations
+	// in: string
+	ListName string `json:"list_name,omitempty"`
+	// ID of the member
+	// in: int64
+	UserID int64 `json:"user_id"`
+	// Email of the member
+	// in: string
+	Email string `json:"email,omitempty"`
+	// Role of the member, viewer, editor or owner
+	// in: string
+	Role Role `json:"role"`
+	// When the member was invited
+	// in: time
+	InvitedAt time.Time `json:"invited_at"`
+	// When the member accepted, null while the invitation is pending
+	// in: time
+	AcceptedAt *time.Time `json:"accepted_at"`
+}
+
+// InviteRequest shares a list with a user. Inviting a member again changes
+// their role.
+type InviteRequest struct {
+	// in: string
+	Email string `json:"email" validate:"required,email"`
+	// in: string
+	Role Role `json:"role" validate:"required,oneof=viewer editor owner"`
+}
@@ -0,0 +1,63 @@
+package list
+
+// Lists are scoped to their owner, the last argument of the queries that
+// take an id from the client. Members of a shared list reach it through the
+// usecase, which runs their requests as the owner once their role allows.
+
+// FetchAllListQuery returns the lists of the user and those shared with
+// them.
+const FetchAllListQuery = `SELECT lists.id, lists.name, COUNT(tasks.id), COALESCE(list_members.role, 'owner') FROM lists
+LEFT JOIN list_members ON list_members.list_id = lists.id AND list_members.user_id = $1 AND list_members.accepted_at IS NOT NULL
+LEFT JOIN tasks ON tasks.list_id = lists.id AND tasks.deleted_at IS NULL
+WHERE lists.owner_id=$1 OR list_members.user_id IS NOT NULL GROUP BY lists.id, list_members.role ORDER BY lists.id`
+
+const FetchListByIdQuery = `SELECT lists.id, lists.name, COUNT(tasks.id) FROM lists LEFT JOIN tasks ON tasks.list_id = lists.id AND tasks.deleted_at IS NULL WHERE lists.id=$1 AND lists.owner_id=$2 GROUP BY lists.id`
+
+const InsertListReturnIdQuery = `INSERT INTO lists (name, owner_id) VALUES ($1, $2) RETURNING id`
+
+const UpdateListQuery = `UPDATE lists SET name=$1 WHERE id=$2 AND owner_id=$3`
+
+const LockListQuery = `SELECT id FROM lists WHERE id=$1 AND owner_id=$2 FOR UPDATE`
+
+// The queries below follow LockListQuery, the tasks of a list belong to its
+// owner.
+// TrashListTasksQuery moves the tasks of the list to the trash, out of any
+// list since the list goes. They come back from the trash without a list.
+const TrashListTasksQuery = `UPDATE tasks SET deleted_at = now(), list_id = NULL, version = version + 1 WHERE list_id=$1 AND deleted_at IS NULL RETURNING id`
+
+// DetachListTrashQuery takes the tasks already in the trash out of the list.
+// Their version stays, so undoing their deletion still finds them where it
+// left them.
+const DetachListTrashQuery = `UPDATE tasks SET list_id = NULL WHERE list_id=$1`
+
+const ReassignListTasksQuery = `UPDATE tasks SET list_id=$2, version = version + 1 WHERE list_id=$1 RETURNING id`
+
+const DeleteListQuery = `DELETE FROM lists WHERE id=$1`
+
+// The owner of a list is never one of its members, the access queries tell
+// them apart by owner_id.
+
+const FetchListAccessQuery = `SELECT lists.owner_id, list_members.role FROM lists
+LEFT JOIN list_members ON list_members.list_id = lists.id AND list_members.user_id = $2 AND list_members.accepted_at IS NOT NULL
+WHERE lists.id=$1`
+
+const FetchTaskAccessQuery = `SELECT tasks.owner_id, list_members.role FROM tasks
+LEFT JOIN list_members ON list_members.list_id = tasks.list_id AND list_members.user_id = $2 AND list_members.accepted_at IS NOT NULL
+WHERE tasks.id=$1`
+
+const FetchMembersQuery = `SELECT list_members.list_id, list_members.user_id, users.email, list_members.role, list_members.created_at, list_members.accepted_at
+FROM list_members JOIN users ON users.id = list_members.user_id WHERE list_members.list_id=$1 ORDER BY list_members.created_at, list_members.user_id`
+
+// InviteMemberQuery invites the user with an email, or changes the role of
+// a member. The owner cannot be invited to their own list.
+const InviteMemberQuery = `INSERT INTO list_members (list_id, user_id, role)
+SELECT lists.id, users.id, $2 FROM lists JOIN users ON users.email = $3 AND users.id <> lists.owner_id WHERE lists.id=$1
+ON CONFLICT (list_id, user_id) DO UPDATE SET role = EXCLUDED.role
+RETURNING user_id, created_at, accepted_at`
+
+const AcceptInvitationQuery = `UPDATE list_members SET accepted_at=now() WHERE list_id=$1 AND user_id=$2 AND accepted_at IS NULL RETURNING role, created_at, accepted_at`
+
+const DeleteMemberQuery = `DELETE FROM list_members WHERE list_id=$1 AND user_id=$2`
+
+const FetchInvitationsQuery = `SELECT list_members.list_id, lists.name, list_members.role, list_members.created_at FROM list_members
+JOIN lists ON lists.id = list_members.list_id WHERE list_members.user_id=$1 AND list_members.accepted_at IS NULL ORDER BY list_members.created_at, list_members.list_id`
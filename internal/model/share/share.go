@@ -0,0 +1,48 @@
+package share
+
+import (
+	"time"
+	listmodel "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+)
+
+// swagger:model Link
+type LinkModel struct {
+	// ID of link
+	// in: int64
+	ID int64 `json:"id"`
+	// List the link shows, null for a link to a task
+	// in: int64
+	ListID *int64 `json:"list_id,omitempty"`
+	// Task the link shows, null for a link to a list
+	// in: int64
+	TaskID *int64 `json:"task_id,omitempty"`
+	// Link stops working from then on, null when it never expires
+	// in: time
+	ExpiresAt *time.Time `json:"expires_at"`
+	// in: time
+	CreatedAt time.Time `json:"created_at"`
+	// Path of the public page, anyone holding it reads the list or the task
+	// in: string
+	URL string `json:"url"`
+	// UserID is who created the link, the link reads as them
+	UserID int64 `json:"-"`
+	// WorkspaceID is where the link was created, the link reads in it
+	WorkspaceID int64 `json:"-"`
+}
+
+// LinkRequest creates a link to a list or to a task, one of them.
+type LinkRequest struct {
+	// in: int64
+	ListID *int64 `json:"list_id"`
+	// in: int64
+	TaskID *int64 `json:"task_id"`
+	// in: time
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// SharedModel is what a link shows, the list or the task.
+type SharedModel struct {
+	List *listmodel.ListModel `json:"list,omitempty"`
+	Task *taskmodel.TaskModel `json:"task,omitempty"`
+}
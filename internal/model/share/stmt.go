@@ -0,0 +1,14 @@
+package share
+
+// Links belong to the user and the workspace they were created in, the last
+// two arguments of the queries that take them.
+
+const InsertLinkReturnIdQuery = `INSERT INTO share_links (list_id, task_id, expires_at, user_id, workspace_id) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+
+const FetchLinksQuery = `SELECT id, list_id, task_id, expires_at, created_at FROM share_links WHERE user_id=$1 AND workspace_id=$2 ORDER BY id`
+
+// FetchLinkQuery reads a link for a visitor, who is not signed in, so it is
+// not scoped to a user.
+const FetchLinkQuery = `SELECT id, list_id, task_id, expires_at, created_at, user_id, workspace_id FROM share_links WHERE id=$1`
+
+const DeleteLinkQuery = `DELETE FROM share_links WHERE id=$1 AND user_id=$2 AND workspace_id=$3`
@@ -0,0 +1,22 @@
+package tag
+
+// Tags are scoped to their owner, the last argument of the queries that
+// take an id from the client.
+const FetchAllTagQuery = `SELECT tags.id, tags.name, COUNT(task_tags.task_id) FROM tags LEFT JOIN task_tags ON task_tags.tag_id = tags.id WHERE tags.owner_id=$1 GROUP BY tags.id ORDER BY tags.name`
+
+const FetchTagByIdQuery = `SELECT tags.id, tags.name, COUNT(task_tags.task_id) FROM tags LEFT JOIN task_tags ON task_tags.tag_id = tags.id WHERE tags.id=$1 AND tags.owner_id=$2 GROUP BY tags.id`
+
+const InsertTagReturnIdQuery = `INSERT INTO tags (name, owner_id) VALUES ($1, $2) RETURNING id`
+
+const RenameTagQuery = `UPDATE tags SET name=$1 WHERE id=$2 AND owner_id=$3`
+
+const DeleteTagQuery = `DELETE FROM tags WHERE id=$1 AND owner_id=$2`
+
+const CountTagsQuery = `SELECT COUNT(*) FROM tags WHERE id = ANY($1) AND owner_id=$2`
+
+// TouchTagTasksQuery raises the version of the tasks carrying a tag, whose
+// representation changes with the tag.
+const TouchTagTasksQuery = `UPDATE tasks SET version = version + 1 WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id=$1) AND owner_id=$2 RETURNING id`
+
+// MergeTaskTagsQuery follows CountTagsQuery, which found both tags.
+const MergeTaskTagsQuery = `INSERT INTO task_tags (task_id, tag_id) SELECT task_id, $2 FROM task_tags WHERE tag_id=$1 ON CONFLICT DO NOTHING`
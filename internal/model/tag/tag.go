@@ -0,0 +1,22 @@
+package tag
+
+// swagger:model Tag
+type TagModel struct {
+	// ID of tag
+	// in: int64
+	ID int64 `json:"id"`
+	// Name of tag, lower case and unique
+	// in: string
+	Name string `json:"name" validate:"required,max=50"`
+	// Number of tasks carrying the tag
+	// in: int64
+	TaskCount int64 `json:"task_count"`
+}
+
+// MergeRequest moves every task of a tag to the tag Into and removes the
+// merged tag.
+type MergeRequest struct {
+	// ID of the tag that remains
+	// in: int64
+	Into int64 `json:"into" validate:"required"`
+}
@@ -0,0 +1,75 @@
+package task
+
+// Operations of a batch.
+const (
+	BatchCreate   = "create"
+	BatchUpdate   = "update"
+	BatchDelete   = "delete"
+	BatchComplete = "complete"
+)
+
+// Modes of a batch. All or nothing is the default.
+const (
+	BatchAllOrNothing = "all_or_nothing"
+	BatchBestEffort   = "best_effort"
+)
+
+// MaxBatchOperations bounds the operations of one batch.
+const MaxBatchOperations = 500
+
+type BatchRequest struct {
+	// all_or_nothing or best_effort
+	// in: string
+	Mode string `json:"mode"`
+	// Operations, run in order
+	// in: []BatchOperation
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation creates Task, updates the task ID with Task, or deletes or
+// completes the task ID. Updates and deletes with a Version only apply to
+// that version of the task.
+type BatchOperation struct {
+	Op      string     `json:"op"`
+	ID      int64      `json:"id,omitempty"`
+	Version int64      `json:"version,omitempty"`
+	Task    *TaskModel `json:"task,omitempty"`
+}
+
+// BatchOptions are the rules the repo applies to a batch.
+type BatchOptions struct {
+	// roll back every operation when one fails
+	AllOrNothing bool
+	// refuse to complete a task while some of its subtasks are open
+	BlockOpenSubtasks bool
+}
+
+// BatchResult is the outcome of the operation at the same index. Task is
+// the created or changed task and Before the task the operation found, Err
+// why the operation failed. RolledBack operations were undone because
+// another one of an all or nothing batch failed.
+type BatchResult struct {
+	Op         string
+	ID         int64
+	Task       *TaskModel
+	Before     *TaskModel
+	Err        error
+	RolledBack bool
+}
+
+// BatchChanges returns what the operations of a batch did to their tasks,
+// leaving out those that failed or found nothing to change.
+func BatchChanges(results []BatchResult) []Change {
+	changes := []Change{}
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+		case result.Op == BatchCreate:
+			changes = append(changes, Change{After: result.Task})
+		case result.Before != nil:
+			// completing a done task finds nothing to change
+			changes = append(changes, Change{Before: result.Before, After: result.Task})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,21 @@
+package task
+
+// Dependency says the task TaskID is blocked by the task BlockedBy until
+// BlockedBy is done.
+//
+// swagger:model Dependency
+type Dependency struct {
+	// ID of the blocked task
+	// in: int64
+	TaskID int64 `json:"task_id"`
+	// ID of the task it waits for
+	// in: int64
+	BlockedBy int64 `json:"blocked_by"`
+}
+
+// DependencyRequest blocks a task by another one.
+type DependencyRequest struct {
+	// ID of the task to wait for
+	// in: int64
+	BlockedBy int64 `json:"blocked_by"`
+}
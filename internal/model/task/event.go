@@ -0,0 +1,130 @@
+package task
+
+import "time"
+
+// Actions of a TaskEvent.
+const (
+	EventCreate  = "create"
+	EventUpdate  = "update"
+	EventDelete  = "delete"
+	EventRestore = "restore"
+)
+
+// TaskEvent records one change to a task. Before is absent on creations and
+// restores, After on deletions.
+//
+// swagger:model TaskEvent
+type TaskEvent struct {
+	// ID of the event, later events have higher ids
+	// in: int64
+	ID int64 `json:"id"`
+	// ID of the changed task
+	// in: int64
+	TaskID int64 `json:"task_id"`
+	// create, update, delete or restore
+	// in: string
+	Action string `json:"action"`
+	// The task before the change
+	// in: Task
+	Before *TaskModel `json:"before,omitempty"`
+	// The task after the change
+	// in: Task
+	After *TaskModel `json:"after,omitempty"`
+	// Who made the change
+	// in: string
+	Actor string `json:"actor"`
+	// ID of the request that made the change
+	// in: string
+	RequestID string `json:"request_id,omitempty"`
+	// When the change was recorded
+	// in: time
+	CreatedAt time.Time `json:"created_at"`
+	// ID of the event this change undid, absent on other changes
+	// in: int64
+	Reverts int64 `json:"reverts,omitempty"`
+	// OwnerID is the owner of the task, only read for undo
+	OwnerID int64 `json:"-"`
+}
+
+// Change is a task as a write found it and as it left it. Before is nil
+// when the task was not there before, because the write created it or took
+// it out of the trash, After when the write moved it to the trash.
+type Change struct {
+	Before *TaskModel
+	After  *TaskModel
+}
+
+// Changes pairs the tasks before and after a write by id, leaving out those
+// on one side only.
+func Changes(before, after []TaskModel) []Change {
+	found := map[int64]*TaskModel{}
+	for i := range before {
+		found[before[i].ID] = &before[i]
+	}
+	changes := []Change{}
+	for i := range after {
+		if previous, ok := found[after[i].ID]; ok {
+			changes = append(changes, Change{Before: previous, After: &after[i]})
+		}
+	}
+	return changes
+}
+
+// Record turns the changes of a write into the events that describe them.
+// The repo calls it before the write commits and appends the events in the
+// same transaction, so a change is saved with its events or not at all. A
+// nil Record records nothing.
+type Record func(changes []Change) []TaskEvent
+
+// RewindStep puts the task TaskID back into State, or into the trash when
+// State is nil. Expected is the version the task must be at for the step to
+// apply, 0 for any.
+type RewindStep struct {
+	TaskID   int64
+	Expected int64
+	State    *TaskModel
+}
+
+// RewindResult is the task before and after a RewindStep. Before is nil when
+// the task had been purged and was created again with its id, After has a
+// DeletedAt when the step moved the task to the trash.
+type RewindResult struct {
+	Before *TaskModel
+	After  TaskModel
+}
+
+// RewindChanges returns what the steps of a rewind did to their tasks. A
+// task that was in the trash or purged before comes back.
+func RewindChanges(results []RewindResult) []Change {
+	changes := make([]Change, len(results))
+	for i := range results {
+		before, after := results[i].Before, &results[i].After
+		switch {
+		case after.DeletedAt != nil:
+			changes[i] = Change{Before: before}
+		case before == nil || before.DeletedAt != nil:
+			changes[i] = Change{After: after}
+		default:
+			changes[i] = Change{Before: before, After: after}
+		}
+	}
+	return changes
+}
+
+// EventFilter selects one page of events, newest first. Nil and empty
+// fields do not filter. From is inclusive and To exclusive. Cursor is the
+// NextCursor of the previous page.
+type EventFilter struct {
+	TaskID *int64
+	Actor  string
+	Action string
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Cursor string
+}
+
+type EventPage struct {
+	Events     []TaskEvent `json:"events"`
+	NextCursor string      `json:"next_cursor"`
+}
@@ -0,0 +1,38 @@
+package task
+
+import (
+	"strings"
+	"to-do-list/pkg/jsonpatch"
+)
+
+// TaskPatch changes some fields of the JSON representation of a task, the
+// others keep their value.
+type TaskPatch interface {
+	Apply(doc []byte) ([]byte, error)
+}
+
+// MergePatch is an RFC 7396 merge patch, sent as application/merge-patch+json.
+type MergePatch []byte
+
+func (p MergePatch) Apply(doc []byte) ([]byte, error) {
+	return jsonpatch.Merge(doc, p)
+}
+
+// JSONPatch is an RFC 6902 patch, sent as application/json-patch+json.
+type JSONPatch []jsonpatch.Operation
+
+func (p JSONPatch) Apply(doc []byte) ([]byte, error) {
+	return jsonpatch.Apply(doc, p)
+}
+
+// FieldErrors carries the validation failures of a patched task so they
+// reach the client like those of a full update.
+type FieldErrors []ErrorField
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, field := range e {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, ", ")
+}
@@ -1,9 +1,202 @@
 package task
 
-const FetchAllTaskQuery = `SELECT id, task_name, is_done FROM tasks`
+// TaskColumns is the column list every task query scans, in this order.
+const TaskColumns = `id, task_name, is_done, due_at, timezone, remind_at, priority, list_id, parent_id, position, version, ` +
+	`COALESCE((SELECT array_agg(tags.name ORDER BY tags.name) FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id), '{}'), ` +
+	`rrule, repeat_from, rank`
 
-const InsertTaskReturnIdQuery = `INSERT INTO tasks (task_name, is_done) VALUES ($1, $2) RETURNING id`
+// BlockedColumn tells whether an open task blocks the task. Trashed
+// blockers do not count.
+const BlockedColumn = `EXISTS (SELECT 1 FROM task_dependencies JOIN tasks blocker ON blocker.id = task_dependencies.blocked_by ` +
+	`WHERE task_dependencies.task_id = tasks.id AND NOT blocker.is_done AND blocker.deleted_at IS NULL)`
 
-const UpdateTaskQuery = `UPDATE tasks SET task_name=$1, is_done=$2 WHERE id=$3`
+// SelectTaskQuery is completed with WHERE, ORDER BY and LIMIT clauses built
+// from a TaskFilter.
+const SelectTaskQuery = `SELECT ` + TaskColumns + `, ` + BlockedColumn + ` FROM tasks`
 
-const DeleteTaskQuery = `DELETE FROM tasks WHERE id=$1`
+// Every query below only sees the tasks of one owner in one workspace,
+// passed as the last two arguments, unless its comment says otherwise.
+// Trashed tasks are left out unless the name of the query says otherwise.
+const FetchTaskByIdQuery = `SELECT ` + TaskColumns + ` FROM tasks WHERE id=$1 AND owner_id=$2 AND workspace_id=$3 AND deleted_at IS NULL`
+
+// InsertTaskReturnIdQuery appends a subtask after the other subtasks of its
+// parent.
+const InsertTaskReturnIdQuery = `INSERT INTO tasks (task_name, is_done, due_at, timezone, remind_at, priority, list_id, parent_id, rrule, repeat_from, rank, owner_id, workspace_id, position) ` +
+	`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, COALESCE((SELECT MAX(position) + 1 FROM tasks WHERE parent_id = $8), 0)) RETURNING id, position, version`
+
+// LastRankQuery returns the key new tasks are appended after. Trashed tasks
+// count, so they come back where they were. Keys are unique among all the
+// tasks of the owner, in every workspace.
+const LastRankQuery = `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE owner_id=$1`
+
+// UpdateTaskQuery only matches while the task is still at version $11, or
+// at any version when $11 is 0.
+const UpdateTaskQuery = `UPDATE tasks SET task_name=$1, is_done=$2, due_at=$3, timezone=$4, remind_at=$5, priority=$6, list_id=$7, ` +
+	`rrule=$8, repeat_from=$9, version = version + 1 ` +
+	`WHERE id=$10 AND ($11 = 0 OR version = $11) AND owner_id=$12 AND workspace_id=$13 AND deleted_at IS NULL RETURNING version`
+
+const FetchTaskVersionQuery = `SELECT version FROM tasks WHERE id=$1 AND owner_id=$2 AND workspace_id=$3 AND deleted_at IS NULL`
+
+// MoveTaskQuery moves a task together with its subtasks.
+const MoveTaskQuery = `UPDATE tasks SET list_id=$1, version = version + 1 WHERE (id=$2 OR parent_id=$2) AND owner_id=$3 AND workspace_id=$4 AND deleted_at IS NULL RETURNING id`
+
+const MoveSubtasksQuery = `UPDATE tasks SET list_id=$1, version = version + 1 WHERE parent_id=$2 AND owner_id=$3 AND workspace_id=$4 AND deleted_at IS NULL RETURNING id`
+
+const FetchSubtasksQuery = `SELECT ` + TaskColumns + ` FROM tasks WHERE parent_id=$1 AND owner_id=$2 AND workspace_id=$3 AND deleted_at IS NULL ORDER BY position, id`
+
+const SetTaskDoneQuery = `UPDATE tasks SET is_done=$1, version = version + 1 WHERE id=$2 AND owner_id=$3 AND workspace_id=$4 AND deleted_at IS NULL`
+
+const LockSubtasksQuery = `SELECT id FROM tasks WHERE parent_id=$1 AND owner_id=$2 AND workspace_id=$3 AND deleted_at IS NULL FOR UPDATE`
+
+// ReorderSubtasksQuery numbers the subtasks after their index in $2.
+const ReorderSubtasksQuery = `UPDATE tasks SET position = array_position($2::integer[], id) - 1, version = version + 1 WHERE parent_id=$1 AND owner_id=$3 AND workspace_id=$4 AND deleted_at IS NULL`
+
+const LockTaskQuery = `SELECT ` + TaskColumns + ` FROM tasks WHERE id=$1 AND owner_id=$2 AND workspace_id=$3 AND deleted_at IS NULL FOR UPDATE`
+
+const HasOpenSubtasksQuery = `SELECT EXISTS (SELECT 1 FROM tasks WHERE parent_id=$1 AND owner_id=$2 AND workspace_id=$3 AND NOT is_done AND deleted_at IS NULL)`
+
+// RollUpTaskQuery marks a task done when all its subtasks are and open when
+// one of them is. Tasks without subtasks are left alone.
+const RollUpTaskQuery = `UPDATE tasks SET is_done = NOT is_done, version = version + 1 WHERE id=$1 AND owner_id=$2 AND workspace_id=$3 AND deleted_at IS NULL ` +
+	`AND EXISTS (SELECT 1 FROM tasks sub WHERE sub.parent_id = tasks.id AND sub.deleted_at IS NULL) ` +
+	`AND is_done = EXISTS (SELECT 1 FROM tasks sub WHERE sub.parent_id = tasks.id AND NOT sub.is_done AND sub.deleted_at IS NULL) RETURNING id`
+
+// TrashTaskQuery moves a task to the trash and follows the same version rule
+// as UpdateTaskQuery.
+const TrashTaskQuery = `UPDATE tasks SET deleted_at = now(), version = version + 1 ` +
+	`WHERE id=$1 AND ($2 = 0 OR version = $2) AND owner_id=$3 AND workspace_id=$4 AND deleted_at IS NULL RETURNING parent_id`
+
+// TrashSubtasksQuery sends the subtasks along with their parent. now() is
+// the start of the transaction, so they share its deleted_at and come back
+// with it.
+const TrashSubtasksQuery = `UPDATE tasks SET deleted_at = now(), version = version + 1 WHERE parent_id=$1 AND owner_id=$2 AND workspace_id=$3 AND deleted_at IS NULL RETURNING id`
+
+// FetchTrashQuery lists trashed tasks, most recently trashed first.
+const FetchTrashQuery = `SELECT ` + TaskColumns + `, deleted_at FROM tasks WHERE owner_id=$1 AND workspace_id=$2 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id`
+
+// LockTrashedTaskQuery also returns whether the parent is in the trash.
+const LockTrashedTaskQuery = `SELECT parent_id, deleted_at, ` +
+	`EXISTS (SELECT 1 FROM tasks parent WHERE parent.id = tasks.parent_id AND parent.deleted_at IS NOT NULL) ` +
+	`FROM tasks WHERE id=$1 AND owner_id=$2 AND workspace_id=$3 AND deleted_at IS NOT NULL FOR UPDATE`
+
+// RestoreTaskQuery brings back a task with the subtasks trashed together
+// with it, subtasks trashed on their own before stay in the trash.
+const RestoreTaskQuery = `UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE (id=$1 OR parent_id=$1) AND owner_id=$3 AND workspace_id=$4 AND deleted_at=$2 RETURNING id`
+
+// PurgeTrashQuery removes the tasks of every owner and workspace trashed
+// before $1 for good, their subtasks go with them by the foreign key.
+const PurgeTrashQuery = `DELETE FROM tasks WHERE deleted_at < $1`
+
+// a batch in best effort mode undoes a failed operation up to its savepoint
+const (
+	SavepointBatchQuery         = `SAVEPOINT batch_operation`
+	RollbackBatchSavepointQuery = `ROLLBACK TO SAVEPOINT batch_operation`
+	ReleaseBatchSavepointQuery  = `RELEASE SAVEPOINT batch_operation`
+)
+
+const InsertTagNamesQuery = `INSERT INTO tags (name, owner_id) SELECT unnest($1::varchar[]), $2 ON CONFLICT (owner_id, name) DO NOTHING`
+
+// DeleteTaskTagsQuery follows a query that found the task of the owner.
+const DeleteTaskTagsQuery = `DELETE FROM task_tags WHERE task_id=$1`
+
+const InsertTaskTagsQuery = `INSERT INTO task_tags (task_id, tag_id) SELECT $1, id FROM tags WHERE name = ANY($2) AND owner_id=$3`
+
+const InsertTaskEventQuery = `INSERT INTO task_events (task_id, action, before, after, actor, request_id, reverts, owner_id, workspace_id, user_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+const TaskEventColumns = `task_events.id, task_events.task_id, task_events.action, task_events.before, task_events.after, ` +
+	`task_events.actor, task_events.request_id, task_events.created_at, COALESCE(task_events.reverts, 0)`
+
+// SelectTaskEventQuery is completed with WHERE, ORDER BY and LIMIT clauses
+// built from an EventFilter.
+const SelectTaskEventQuery = `SELECT ` + TaskEventColumns + ` FROM task_events`
+
+// FetchUndoableEventsQuery returns the events of the last request of user
+// $1 in workspace $2 that has not been undone, on the tasks of whichever
+// owner, newest first, with that owner. Undos are not undone, events
+// without a request id are undone one at a time, and the changes a request
+// made to the tasks of another owner are undone next.
+const FetchUndoableEventsQuery = `WITH last AS (` +
+	`SELECT id, request_id, owner_id FROM task_events WHERE user_id=$1 AND workspace_id=$2 AND owner_id IS NOT NULL AND reverts IS NULL ` +
+	`AND NOT EXISTS (SELECT 1 FROM task_events undo WHERE undo.reverts = task_events.id) ORDER BY id DESC LIMIT 1) ` +
+	`SELECT ` + TaskEventColumns + `, task_events.owner_id FROM task_events, last WHERE task_events.user_id=$1 AND task_events.workspace_id=$2 ` +
+	`AND task_events.owner_id = last.owner_id AND task_events.reverts IS NULL ` +
+	`AND (task_events.id = last.id OR (last.request_id <> '' AND task_events.request_id = last.request_id)) ` +
+	`AND NOT EXISTS (SELECT 1 FROM task_events undo WHERE undo.reverts = task_events.id) ORDER BY task_events.id DESC`
+
+// FetchTaskSnapshotQuery finds the latest recorded state of task $1 at
+// version $2, before or after a change.
+const FetchTaskSnapshotQuery = `SELECT snapshot FROM (` +
+	`SELECT id, after AS snapshot FROM task_events WHERE task_id=$1 AND owner_id=$3 AND workspace_id=$4 AND after IS NOT NULL ` +
+	`UNION ALL SELECT id, before FROM task_events WHERE task_id=$1 AND owner_id=$3 AND workspace_id=$4 AND before IS NOT NULL) snapshots ` +
+	`WHERE (snapshot->>'version')::bigint = $2 ORDER BY id DESC LIMIT 1`
+
+// LockAnyTaskQuery also finds trashed tasks.
+const LockAnyTaskQuery = `SELECT ` + TaskColumns + `, deleted_at FROM tasks WHERE id=$1 AND owner_id=$2 AND workspace_id=$3 FOR UPDATE`
+
+// RewindTaskQuery writes back a recorded state. A subtask keeps the list of
+// its parent, which may have moved since, and a task whose list has been
+// deleted since is in no list.
+const RewindTaskQuery = `UPDATE tasks SET task_name=$1, is_done=$2, due_at=$3, timezone=$4, remind_at=$5, priority=$6, ` +
+	`list_id = CASE WHEN parent_id IS NULL THEN (SELECT lists.id FROM lists WHERE lists.id = $7::integer AND lists.owner_id = $13) ELSE (SELECT parent.list_id FROM tasks parent WHERE parent.id = tasks.parent_id) END, ` +
+	`position=$8, rrule=$9, repeat_from=$10, rank = COALESCE(NULLIF($11, ''), rank), version = version + 1 WHERE id=$12 AND owner_id=$13 AND workspace_id=$14`
+
+// InsertTaskWithIdQuery creates a purged task again under its old id, in no
+// list when its list has been deleted since.
+const InsertTaskWithIdQuery = `INSERT INTO tasks (id, task_name, is_done, due_at, timezone, remind_at, priority, list_id, parent_id, position, rrule, repeat_from, rank, version, owner_id, workspace_id) ` +
+	`VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT lists.id FROM lists WHERE lists.id = $8::integer AND lists.owner_id = $15), $9, $10, $11, $12, $13, $14, $15, $16)`
+
+// LockListTasksQuery locks the live tasks of list $1 with their workspace,
+// before the list goes. The tasks of a list belong to its owner, in any of
+// their workspaces.
+const LockListTasksQuery = `SELECT ` + TaskColumns + `, workspace_id FROM tasks WHERE list_id=$1 AND deleted_at IS NULL ORDER BY id FOR UPDATE`
+
+// LockDependenciesQuery makes the changes to the dependencies of owner $1
+// wait for each other until the end of the transaction, so two of them
+// never each add half of a loop.
+const LockDependenciesQuery = `SELECT pg_advisory_xact_lock(hashtext('task_dependencies'), $1)`
+
+// InsertDependencyQuery only adds the edge between two live tasks, and
+// adding it twice changes nothing.
+const InsertDependencyQuery = `INSERT INTO task_dependencies (task_id, blocked_by) ` +
+	`SELECT task.id, blocker.id FROM tasks task, tasks blocker ` +
+	`WHERE task.id=$1 AND blocker.id=$2 AND task.owner_id=$3 AND blocker.owner_id=$3 AND task.workspace_id=$4 AND blocker.workspace_id=$4 AND task.deleted_at IS NULL AND blocker.deleted_at IS NULL ` +
+	`ON CONFLICT (task_id, blocked_by) DO UPDATE SET task_id = EXCLUDED.task_id RETURNING task_id`
+
+const DeleteDependencyQuery = `DELETE FROM task_dependencies WHERE task_id=$1 AND blocked_by=$2 AND task_id IN (SELECT id FROM tasks WHERE owner_id=$3 AND workspace_id=$4)`
+
+const FetchDependenciesQuery = `SELECT task_id, blocked_by FROM task_dependencies WHERE task_id IN (SELECT id FROM tasks WHERE owner_id=$1 AND workspace_id=$2) ORDER BY task_id, blocked_by`
+
+// FetchBlockersQuery lists the live tasks a task waits for.
+const FetchBlockersQuery = `SELECT ` + TaskColumns + ` FROM tasks JOIN task_dependencies ON task_dependencies.blocked_by = tasks.id ` +
+	`WHERE task_dependencies.task_id=$1 AND tasks.owner_id=$2 AND tasks.workspace_id=$3 AND tasks.deleted_at IS NULL ORDER BY tasks.id`
+
+const FetchOpenTasksQuery = `SELECT ` + TaskColumns + `, ` + BlockedColumn + ` FROM tasks WHERE owner_id=$1 AND workspace_id=$2 AND NOT is_done AND deleted_at IS NULL ORDER BY id`
+
+// LockRankQuery locks a top level task about to get a new key.
+const LockRankQuery = `SELECT rank FROM tasks WHERE id=$1 AND owner_id=$2 AND workspace_id=$3 AND parent_id IS NULL AND deleted_at IS NULL FOR UPDATE`
+
+const FetchRankQuery = `SELECT rank FROM tasks WHERE id=$1 AND owner_id=$2 AND workspace_id=$3 AND parent_id IS NULL AND deleted_at IS NULL`
+
+// RankBeforeQuery and RankAfterQuery find the neighbour of key $1 on each
+// side among the other top level tasks, empty when there is none. They look
+// in every workspace of the owner, where keys are unique.
+const (
+	RankBeforeQuery = `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE rank < $1 AND id <> $2 AND owner_id=$3 AND parent_id IS NULL AND deleted_at IS NULL`
+	RankAfterQuery  = `SELECT COALESCE(MIN(rank), '') FROM tasks WHERE rank > $1 AND id <> $2 AND owner_id=$3 AND parent_id IS NULL AND deleted_at IS NULL`
+)
+
+// SetRankQuery keeps the current key when $1 is empty.
+const SetRankQuery = `UPDATE tasks SET rank = COALESCE(NULLIF($1, ''), rank), version = version + 1 WHERE id=$2 AND owner_id=$3 AND workspace_id=$4`
+
+// LongRankOwnersQuery returns the owners with a key longer than $1, for
+// every owner.
+const LongRankOwnersQuery = `SELECT DISTINCT owner_id FROM tasks WHERE owner_id IS NOT NULL AND length(rank) > $1 ORDER BY owner_id`
+
+// LockRanksQuery returns every task in the manual order, trashed ones and
+// subtasks included so they keep their place. Like the queries after it, it
+// spans the workspaces of the owner.
+const LockRanksQuery = `SELECT id FROM tasks WHERE owner_id=$1 ORDER BY rank, id FOR UPDATE`
+
+// SpreadRanksQuery gives task $1[i] the key $2[i]. The order does not
+// change, so versions stay.
+const SpreadRanksQuery = `UPDATE tasks SET rank = spread.rank FROM unnest($1::integer[], $2::varchar[]) AS spread(id, rank) WHERE tasks.id = spread.id AND tasks.owner_id=$3`
@@ -1,5 +1,7 @@
 package task
 
+import "time"
+
 const ListTableName = "task"
 
 // swagger:model Task 
@@ -13,6 +15,80 @@ type TaskModel struct {
 	// Task status
 	// in: bool
 	IsDone   bool   `json:"is_done"`
+	// Deadline of task
+	// in: time
+	DueAt *time.Time `json:"due_at,omitempty"`
+	// IANA timezone of the owner, used for "today" and "this week". Defaults to UTC
+	// in: string
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	// When to remind the owner, not after DueAt
+	// in: time
+	RemindAt *time.Time `json:"remind_at,omitempty" validate:"omitempty,remind_before_due"`
+	// RFC 5545 recurrence rule. Completing the task creates its next occurrence. Needs DueAt
+	// in: string
+	RRule string `json:"rrule,omitempty" validate:"omitempty,max=500,rrule,needs_due"`
+	// What the next occurrence follows: due, the schedule of DueAt, or completion, the day the task is done. Defaults to due
+	// in: string
+	RepeatFrom string `json:"repeat_from,omitempty" validate:"omitempty,oneof=due completion"`
+	// Task priority: none, low, medium or high
+	// in: string
+	Priority Priority `json:"priority"`
+	// Labels of task, lower case
+	// in: []string
+	Tags []string `json:"tags,omitempty" validate:"max=20,dive,required,max=50"`
+	// List the task belongs to, none when empty
+	// in: int64
+	ListID *int64 `json:"list_id,omitempty"`
+	// Task this task is a subtask of. Subtasks cannot have subtasks
+	// in: int64
+	ParentID *int64 `json:"parent_id,omitempty"`
+	// Position of a subtask among the subtasks of its parent, from 0
+	// in: int
+	Position int `json:"position"`
+	// Sort key of the task in the manual order, see sort=rank and /task/{id}/move. Keys compare as bytes and may all change when they are spread again, the order stays
+	// in: string
+	Rank string `json:"rank,omitempty"`
+	// Version of the task, raised by every change to it
+	// in: int64
+	Version int64 `json:"version"`
+	// When the task was moved to the trash, only set on trashed tasks
+	// in: time
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Whether an open task blocks this one, only set on tasks listed by GetAll and the plan
+	// in: bool
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+// ReorderRequest lists every subtask of a parent once, in the new order.
+type ReorderRequest struct {
+	// IDs of the subtasks
+	// in: []int64
+	IDs []int64 `json:"ids" validate:"required"`
+}
+
+// RepositionRequest puts a top level task right before or right after
+// another one in the manual order. Exactly one of them is set.
+type RepositionRequest struct {
+	// ID of the task to go before
+	// in: int64
+	Before *int64 `json:"before,omitempty"`
+	// ID of the task to go after
+	// in: int64
+	After *int64 `json:"after,omitempty"`
+}
+
+// DoneRequest completes or reopens a task.
+type DoneRequest struct {
+	// New status of the task
+	// in: bool
+	IsDone bool `json:"is_done"`
+}
+
+// MoveRequest puts a task in another list, or in none when ListID is null.
+type MoveRequest struct {
+	// ID of the target list
+	// in: int64
+	ListID *int64 `json:"list_id"`
 }
 
 type ValidationResponse struct {
@@ -24,3 +100,61 @@ type ErrorField struct {
 	FieldName string `json:"field"`
 	Message   string `json:"message"`
 }
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// SortFields are the task fields a list can be ordered by.
+var SortFields = []string{"id", "task_name", "is_done", "due_at", "priority", "rank"}
+
+// DefaultTimezone applies to tasks and filters without a timezone.
+const DefaultTimezone = "UTC"
+
+// What the next occurrence of a recurring task follows, see RepeatFrom.
+const (
+	RepeatFromDue        = "due"
+	RepeatFromCompletion = "completion"
+)
+
+// Due windows of TaskFilter.Due, relative to the filter timezone.
+const (
+	DueOverdue = "overdue"
+	DueToday   = "today"
+	DueWeek    = "week"
+)
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// TaskFilter selects one page of tasks. Nil and empty fields do not filter.
+// Cursor is the opaque value returned as NextCursor by the previous page,
+// empty for the first page, and is only valid with the same Sort.
+//
+// Due and Timezone are what the client asked for; the usecase resolves them
+// into the absolute DueFrom (inclusive) and DueTo (exclusive) bounds the repo
+// filters on.
+//
+// A task matches Tags when it carries every one of them.
+type TaskFilter struct {
+	IsDone   *bool
+	Query    string
+	Priority *Priority
+	ListID   *int64
+	Tags     []string
+	Due      string
+	Timezone string
+	DueFrom  *time.Time
+	DueTo    *time.Time
+	Sort     []SortField
+	Limit    int
+	Cursor   string
+}
+
+type TaskPage struct {
+	Tasks      []TaskModel `json:"tasks"`
+	NextCursor string      `json:"next_cursor"`
+}
@@ -0,0 +1,52 @@
+package user
+
+import "time"
+
+// swagger:model APIKey
+type APIKeyModel struct {
+	// ID of key
+	// in: int64
+	ID int64 `json:"id"`
+	// Name given to the key, like the script that uses it
+	// in: string
+	Name string `json:"name"`
+	// First characters of the key, to tell keys apart
+	// in: string
+	Prefix string `json:"prefix"`
+	// Key only reads, it cannot change anything
+	// in: bool
+	ReadOnly bool `json:"read_only"`
+	// Workspace the key is limited to, the one it was created in. Keys of
+	// before workspaces have none and work in any workspace of their user
+	// in: int64
+	WorkspaceID *int64 `json:"workspace_id"`
+	// in: time
+	CreatedAt time.Time `json:"created_at"`
+	// Last time the key authenticated a request, null when never used
+	// in: time
+	LastUsedAt *time.Time `json:"last_used_at"`
+	// Key itself, only sent in the response that creates it
+	// in: string
+	Key string `json:"key,omitempty"`
+	// Hash is what is stored instead of the key
+	Hash string `json:"-"`
+}
+
+// APIKeyRequest creates an API key.
+type APIKeyRequest struct {
+	// in: string
+	Name string `json:"name" validate:"required,max=100"`
+	// in: bool
+	ReadOnly bool `json:"read_only"`
+}
+
+// Principal is who a request acts for, signed in with an access token or an
+// API key.
+type Principal struct {
+	UserID int64
+	Email  string
+	// ReadOnly is set for API keys limited to reading
+	ReadOnly bool
+	// WorkspaceID is set for API keys limited to a workspace
+	WorkspaceID int64
+}
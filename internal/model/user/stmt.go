@@ -0,0 +1,30 @@
+package user
+
+const InsertUserReturnIdQuery = `INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, created_at`
+
+const FetchUserByEmailQuery = `SELECT id, email, password_hash, created_at FROM users WHERE email=$1`
+
+const FetchUserByIdQuery = `SELECT id, email, password_hash, created_at FROM users WHERE id=$1`
+
+const InsertRefreshTokenQuery = `INSERT INTO refresh_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)`
+
+// TakeRefreshTokenQuery removes the token as it reads it, so a token is
+// only ever used once.
+const TakeRefreshTokenQuery = `DELETE FROM refresh_tokens WHERE token_hash=$1 RETURNING user_id, expires_at`
+
+const DeleteExpiredRefreshTokensQuery = `DELETE FROM refresh_tokens WHERE user_id=$1 AND expires_at < now()`
+
+// The owner of API keys is the last placeholder, like the owner of tasks.
+
+const InsertAPIKeyReturnIdQuery = `INSERT INTO api_keys (name, prefix, key_hash, read_only, workspace_id, user_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+
+const FetchAPIKeysQuery = `SELECT id, name, prefix, read_only, workspace_id, created_at, last_used_at FROM api_keys WHERE user_id=$1 ORDER BY id`
+
+const DeleteAPIKeyQuery = `DELETE FROM api_keys WHERE id=$1 AND user_id=$2`
+
+const FetchAPIKeyByHashQuery = `SELECT api_keys.id, api_keys.user_id, users.email, api_keys.read_only, api_keys.workspace_id FROM api_keys
+JOIN users ON users.id = api_keys.user_id WHERE api_keys.key_hash=$1`
+
+// TouchAPIKeyQuery records the use of a key at most once a minute, so a
+// busy script does not write on every request.
+const TouchAPIKeyQuery = `UPDATE api_keys SET last_used_at=now() WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')`
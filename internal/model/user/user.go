@@ -0,0 +1,57 @@
+package user
+
+import "time"
+
+// swagger:model User
+type UserModel struct {
+	// ID of user
+	// in: int64
+	ID int64 `json:"id"`
+	// Email of user, lower case and unique
+	// in: string
+	Email string `json:"email"`
+	// PasswordHash is never sent to clients
+	PasswordHash string `json:"-"`
+	// Time the account was created
+	// in: time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Credentials register a user or sign them in.
+type Credentials struct {
+	// Email names the actor of the changes a user makes, which is up to
+	// 100 characters
+	// in: string
+	Email string `json:"email" validate:"required,email,max=100"`
+	// in: string
+	Password string `json:"password" validate:"required,min=8,max=128"`
+}
+
+// RefreshRequest carries a refresh token, to trade it for new tokens or to
+// revoke it.
+type RefreshRequest struct {
+	// in: string
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// swagger:model TokenPair
+type TokenPair struct {
+	// Signed token to send as "Authorization: Bearer <token>"
+	// in: string
+	AccessToken string `json:"access_token"`
+	// in: string
+	TokenType string `json:"token_type"`
+	// Seconds until the access token expires
+	// in: int64
+	ExpiresIn int64 `json:"expires_in"`
+	// Opaque token that gets a new pair once, until it expires
+	// in: string
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshToken is a refresh token as stored, only its hash is kept.
+type RefreshToken struct {
+	Hash      string
+	UserID    int64
+	ExpiresAt time.Time
+}
@@ -0,0 +1,38 @@
+package workspace
+
+// A user sees the default workspace, DefaultID, and those they are a member
+// of. The user is the last argument of the queries below. The owner of a
+// workspace is one of its members too.
+
+const FetchWorkspacesQuery = `SELECT workspaces.id, workspaces.name, workspaces.slug, workspaces.owner_id, workspaces.created_at FROM workspaces
+LEFT JOIN workspace_members ON workspace_members.workspace_id = workspaces.id AND workspace_members.user_id = $1
+WHERE workspaces.id = 1 OR workspace_members.user_id IS NOT NULL ORDER BY workspaces.id`
+
+const FetchWorkspaceByIdQuery = `SELECT workspaces.id, workspaces.name, workspaces.slug, workspaces.owner_id, workspaces.created_at FROM workspaces
+LEFT JOIN workspace_members ON workspace_members.workspace_id = workspaces.id AND workspace_members.user_id = $2
+WHERE workspaces.id=$1 AND (workspaces.id = 1 OR workspace_members.user_id IS NOT NULL)`
+
+const FetchWorkspaceBySlugQuery = `SELECT workspaces.id, workspaces.name, workspaces.slug, workspaces.owner_id, workspaces.created_at FROM workspaces
+LEFT JOIN workspace_members ON workspace_members.workspace_id = workspaces.id AND workspace_members.user_id = $2
+WHERE workspaces.slug=$1 AND (workspaces.id = 1 OR workspace_members.user_id IS NOT NULL)`
+
+const InsertWorkspaceReturnIdQuery = `INSERT INTO workspaces (name, slug, owner_id) VALUES ($1, $2, $3) RETURNING id, created_at`
+
+// The queries below take a workspace the usecase found for the user.
+
+const FetchMembersQuery = `SELECT workspace_members.workspace_id, workspace_members.user_id, users.email, workspaces.owner_id, workspace_members.created_at
+FROM workspace_members JOIN users ON users.id = workspace_members.user_id JOIN workspaces ON workspaces.id = workspace_members.workspace_id
+WHERE workspace_members.workspace_id=$1 ORDER BY workspace_members.created_at, workspace_members.user_id`
+
+const InsertMemberQuery = `INSERT INTO workspace_members (workspace_id, user_id) VALUES ($1, $2) RETURNING created_at`
+
+// AddMemberQuery adds the user with an email, adding a member again
+// changes nothing.
+const AddMemberQuery = `INSERT INTO workspace_members (workspace_id, user_id)
+SELECT $1, users.id FROM users WHERE users.email = $2
+ON CONFLICT (workspace_id, user_id) DO UPDATE SET workspace_id = EXCLUDED.workspace_id
+RETURNING user_id, created_at`
+
+// DeleteMemberQuery never removes the owner, who would lose the workspace.
+const DeleteMemberQuery = `DELETE FROM workspace_members WHERE workspace_id=$1 AND user_id=$2
+AND user_id IS DISTINCT FROM (SELECT owner_id FROM workspaces WHERE id=$1)`
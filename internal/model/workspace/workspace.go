@@ -0,0 +1,59 @@
+package workspace
+
+import "time"
+
+// DefaultID is the workspace of requests that name none. It is open to
+// every user and holds the tasks created before workspaces existed.
+const DefaultID = 1
+
+// Role of a user in a workspace. The owner created it and manages its
+// members, members only work with their tasks in it.
+type Role string
+
+const (
+	RoleMember Role = "member"
+	RoleOwner  Role = "owner"
+)
+
+// swagger:model Workspace
+type WorkspaceModel struct {
+	// ID of workspace
+	// in: int64
+	ID int64 `json:"id"`
+	// Name of workspace
+	// in: string
+	Name string `json:"name" validate:"required,max=100"`
+	// Slug names the workspace in the X-Workspace header and in subdomains
+	// in: string
+	Slug string `json:"slug" validate:"required,max=50"`
+	// Role of the signed in user in the workspace
+	// in: string
+	Role Role `json:"role,omitempty"`
+	// in: time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// swagger:model WorkspaceMember
+type MemberModel struct {
+	// ID of the workspace
+	// in: int64
+	WorkspaceID int64 `json:"workspace_id"`
+	// ID of the member
+	// in: int64
+	UserID int64 `json:"user_id"`
+	// Email of the member
+	// in: string
+	Email string `json:"email"`
+	// Role of the member, member or owner
+	// in: string
+	Role Role `json:"role"`
+	// When the member joined
+	// in: time
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// MemberRequest adds a user to a workspace.
+type MemberRequest struct {
+	// in: string
+	Email string `json:"email" validate:"required,email"`
+}
@@ -0,0 +1,45 @@
+package dberror
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"to-do-list/pkg/apperror"
+
+	"github.com/lib/pq"
+)
+
+// Wrap translates database errors into the apperror taxonomy so the upper
+// layers never have to know about the driver. entity names the row in the
+// client facing messages, op describes the failed operation for the logs.
+func Wrap(err error, entity string, op string) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.New(apperror.ErrNotFound, entity+" not found")
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code.Name() == "unique_violation":
+			return apperror.Wrap(apperror.ErrConflict, entity+" already exists", err)
+		case pqErr.Code.Class() == "23" || pqErr.Code.Class() == "22":
+			return apperror.Wrap(apperror.ErrValidation, entity+" data rejected by storage", err)
+		case pqErr.Code.Class() == "08" || pqErr.Code.Class() == "53" || pqErr.Code.Class() == "57":
+			return apperror.Wrap(apperror.ErrUnavailable, "storage unavailable", err)
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return apperror.Wrap(apperror.ErrUnavailable, "storage unavailable", err)
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,209 @@
+package list
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	taskrepo "to-do-list/internal/repo/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type Repo struct {
+	Db    *sql.DB
+	Redis *redis.Client
+}
+
+func NewListRepository(db *sql.DB, redis *redis.Client) *Repo {
+	return &Repo{
+		Db:    db,
+		Redis: redis,
+	}
+}
+
+func (r *Repo) GetAll(ctx context.Context) ([]model.ListModel, error) {
+
+	var Lists = []model.ListModel{}
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchAllListQuery, requestinfo.UserID(ctx))
+	if err != nil {
+		fmt.Println("Error on Repo :", err)
+		return nil, dbError(err, "fetch lists")
+	}
+
+	defer rows.Close()
+
+	list_row := model.ListModel{}
+
+	for rows.Next() {
+		if err := rows.Scan(&list_row.ID, &list_row.Name, &list_row.TaskCount, &list_row.Role); err != nil {
+			return nil, dbError(err, "scan list")
+		}
+		Lists = append(Lists, list_row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch lists")
+	}
+
+	return Lists, nil
+}
+
+func (r *Repo) GetByID(ctx context.Context, id int64) (model.ListModel, error) {
+
+	list := model.ListModel{}
+
+	err := r.Db.QueryRowContext(ctx, model.FetchListByIdQuery, id, requestinfo.OwnerID(ctx)).Scan(&list.ID, &list.Name, &list.TaskCount)
+	if err != nil {
+		return list, dbError(err, "fetch list")
+	}
+
+	return list, nil
+}
+
+func (r *Repo) Create(ctx context.Context, list model.ListModel) (model.ListModel, error) {
+
+	err := r.Db.QueryRowContext(ctx, model.InsertListReturnIdQuery, list.Name, requestinfo.OwnerID(ctx)).Scan(&list.ID)
+	if err != nil {
+		fmt.Println(err)
+		return list, dbError(err, "create list")
+	}
+
+	return list, nil
+}
+
+func (r *Repo) Update(ctx context.Context, list model.ListModel) (model.ListModel, error) {
+
+	res, err := r.Db.ExecContext(ctx, model.UpdateListQuery, list.Name, list.ID, requestinfo.OwnerID(ctx))
+	if err != nil {
+		fmt.Println(err)
+		return list, dbError(err, "update list")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return list, dbError(err, "update list")
+	}
+	if affected == 0 {
+		return list, apperror.New(apperror.ErrNotFound, "list not found")
+	}
+
+	return r.GetByID(ctx, list.ID)
+}
+
+// Delete removes the list and, depending on the mode, moves its tasks to
+// the trash or to another list. Both lists are locked so no task is added
+// to them meanwhile. It returns the live tasks of the list by workspace, as
+// they were before, and records what it did to them in their workspace.
+func (r *Repo) Delete(ctx context.Context, id int64, opts model.DeleteOptions, record taskmodel.Record) (map[int64][]taskmodel.TaskModel, error) {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, dbError(err, "delete list")
+	}
+	defer tx.Rollback()
+
+	if err := lockList(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	var (
+		query = model.TrashListTasksQuery
+		args  = []interface{}{id}
+	)
+
+	if opts.Mode == model.DeleteReassign {
+		if opts.To != nil {
+			err := lockList(ctx, tx, *opts.To)
+			if errors.Is(err, apperror.ErrNotFound) {
+				return nil, apperror.New(apperror.ErrValidation, "list to reassign to not found")
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		query = model.ReassignListTasksQuery
+		args = append(args, opts.To)
+	}
+
+	tasks, err := taskrepo.LockListTasks(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	taskIds, err := returnedIds(ctx, tx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Mode != model.DeleteReassign {
+		if _, err := tx.ExecContext(ctx, model.DetachListTrashQuery, id); err != nil {
+			fmt.Println(err)
+			return nil, dbError(err, "delete list")
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, model.DeleteListQuery, id); err != nil {
+		fmt.Println(err)
+		return nil, dbError(err, "delete list")
+	}
+
+	for workspace, before := range tasks {
+		changes := make([]taskmodel.Change, len(before))
+		for i := range before {
+			changes[i].Before = &before[i]
+			if opts.Mode == model.DeleteReassign {
+				after := before[i]
+				after.ListID = opts.To
+				after.Version++
+				changes[i].After = &after
+			}
+		}
+		if err := taskrepo.RecordChanges(requestinfo.WithWorkspaceID(ctx, workspace), tx, record, changes...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(err, "delete list")
+	}
+
+	taskrepo.Invalidate(requestinfo.Detach(ctx), r.Redis, taskIds...)
+
+	return tasks, nil
+}
+
+func lockList(ctx context.Context, tx *sql.Tx, id int64) error {
+	var locked int64
+	if err := tx.QueryRowContext(ctx, model.LockListQuery, id, requestinfo.OwnerID(ctx)).Scan(&locked); err != nil {
+		return dbError(err, "lock list")
+	}
+	return nil
+}
+
+// returnedIds runs a statement returning the ids of the tasks it changed,
+// whose cached copies have to go.
+func returnedIds(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, dbError(err, "update list tasks")
+	}
+
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var taskId int64
+		if err := rows.Scan(&taskId); err != nil {
+			return nil, dbError(err, "scan list task")
+		}
+		ids = append(ids, taskId)
+	}
+
+	return ids, dbError(rows.Err(), "update list tasks")
+}
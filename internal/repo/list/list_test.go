@@ -0,0 +1,226 @@
+package list
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var (
+	// every request below is made by user testOwner
+	testOwner = int64(1)
+	userCtx   = requestinfo.WithUserID(context.Background(), testOwner)
+
+	lockedTaskColumns = []string{"id", "task_name", "is_done", "due_at", "timezone", "remind_at", "priority", "list_id", "parent_id", "position", "version", "tags", "rrule", "repeat_from", "rank", "workspace_id"}
+)
+
+func mockDBAndRedis(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *redis.Client) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mr, err := miniredis.Run()
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rclient := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	return db, mock, rclient
+}
+
+func TestRepo_GetAll(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "count", "role"}).
+		AddRow(1, "Inbox", 2, "owner").
+		AddRow(2, "Work", 0, "viewer")
+
+	mock.ExpectQuery(`SELECT lists.id, lists.name, COUNT\(tasks.id\), (.+) FROM lists (.*) ORDER BY lists.id`).WithArgs(testOwner).WillReturnRows(rows)
+
+	repo := NewListRepository(db, rclient)
+	result, err := repo.GetAll(userCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ListModel{
+		{ID: 1, Name: "Inbox", TaskCount: 2, Role: model.RoleOwner},
+		{ID: 2, Name: "Work", TaskCount: 0, Role: model.RoleViewer},
+	}, result)
+}
+
+func TestRepo_Update(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.ListModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> rename list",
+			mock: func() {
+				mock.ExpectExec(`UPDATE lists SET name=(.*) WHERE id=(.*)`).WithArgs("Home", 1, testOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT (.*) FROM lists (.*) WHERE lists.id=(.*)`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count"}).AddRow(1, "Home", 4))
+			},
+			want:    model.ListModel{ID: 1, Name: "Home", TaskCount: 4},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> list not found",
+			mock: func() {
+				mock.ExpectExec(`UPDATE lists SET name=(.*) WHERE id=(.*)`).WithArgs("Home", 1, testOwner).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want:    model.ListModel{ID: 1, Name: "Home"},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewListRepository(db, rclient)
+			result, err := repo.Update(ctx, model.ListModel{ID: 1, Name: "Home"})
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_Delete(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+	to := int64(2)
+
+	listID := int64(1)
+	locked := map[int64][]taskmodel.TaskModel{5: {{ID: 7, TaskName: "task 7", ListID: &listID, Version: 3}}}
+	lockTasks := func() {
+		mock.ExpectQuery(`SELECT (.+), workspace_id FROM tasks WHERE list_id=(.*) AND deleted_at IS NULL ORDER BY id FOR UPDATE`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(lockedTaskColumns).AddRow(7, "task 7", false, nil, "", nil, 0, 1, nil, 0, 3, nil, "", "", "", 5))
+	}
+
+	tests := []struct {
+		name      string
+		opts      model.DeleteOptions
+		mock      func()
+		wantTasks map[int64][]taskmodel.TaskModel
+		want      error
+	}{
+		{
+			name: "case 1 -> delete list and move its tasks to the trash",
+			opts: model.DeleteOptions{Mode: model.DeleteCascade},
+			mock: func() {
+				rclient.SAdd(ctx, "tasks:1:workspaces", 5)
+				rclient.Set(ctx, "ws:5:task:1:v0:7", "{}", 0)
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM lists WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				lockTasks()
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\), list_id = NULL(.*) WHERE list_id=(.*) AND deleted_at IS NULL RETURNING id`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectExec(`UPDATE tasks SET list_id = NULL(.*) WHERE list_id=(.*)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`DELETE FROM lists WHERE id=(.*)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantTasks: locked,
+			want:      nil,
+		},
+		{
+			name: "case 2 -> delete list and move its tasks to another list",
+			opts: model.DeleteOptions{Mode: model.DeleteReassign, To: &to},
+			mock: func() {
+				rclient.SAdd(ctx, "tasks:1:workspaces", 5)
+				rclient.Set(ctx, "ws:5:task:1:v0:7", "{}", 0)
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM lists WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(`SELECT id FROM lists WHERE id=(.*) FOR UPDATE`).WithArgs(2, testOwner).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				lockTasks()
+				mock.ExpectQuery(`UPDATE tasks SET list_id=(.*) WHERE list_id=(.*) RETURNING id`).WithArgs(1, &to).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectExec(`DELETE FROM lists WHERE id=(.*)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantTasks: locked,
+			want:      nil,
+		},
+		{
+			name: "case 3 -> delete list and take its tasks out of any list",
+			opts: model.DeleteOptions{Mode: model.DeleteReassign},
+			mock: func() {
+				rclient.SAdd(ctx, "tasks:1:workspaces", 5)
+				rclient.Set(ctx, "ws:5:task:1:v0:7", "{}", 0)
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM lists WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				lockTasks()
+				mock.ExpectQuery(`UPDATE tasks SET list_id=(.*) WHERE list_id=(.*) RETURNING id`).WithArgs(1, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectExec(`DELETE FROM lists WHERE id=(.*)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantTasks: locked,
+			want:      nil,
+		},
+		{
+			name: "case 4 -> list not found",
+			opts: model.DeleteOptions{Mode: model.DeleteCascade},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM lists WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			want: apperror.ErrNotFound,
+		},
+		{
+			name: "case 5 -> list to reassign to not found",
+			opts: model.DeleteOptions{Mode: model.DeleteReassign, To: &to},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM lists WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(`SELECT id FROM lists WHERE id=(.*) FOR UPDATE`).WithArgs(2, testOwner).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			want: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// copies are cached under the list version, start each case at 0
+			rclient.FlushAll(ctx)
+			tt.mock()
+			repo := NewListRepository(db, rclient)
+			tasks, err := repo.Delete(ctx, 1, tt.opts, nil)
+			assert.Equal(t, tt.wantTasks, tasks)
+			if tt.want == nil {
+				assert.NoError(t, err)
+
+				_, err = rclient.Get(ctx, "ws:5:task:1:v0:7").Result()
+				assert.Equal(t, redis.Nil, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package list
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	model "to-do-list/internal/model/list"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// ListAccess returns what the signed in user may do with a list. Lists
+// they can not see are not found.
+func (r *Repo) ListAccess(ctx context.Context, id int64) (model.Access, error) {
+	return r.access(ctx, model.FetchListAccessQuery, id, "list")
+}
+
+// TaskAccess returns what the signed in user may do with a task, through
+// the list it is in. Tasks they can not see are not found.
+func (r *Repo) TaskAccess(ctx context.Context, id int64) (model.Access, error) {
+	return r.access(ctx, model.FetchTaskAccessQuery, id, "task")
+}
+
+func (r *Repo) access(ctx context.Context, query string, id int64, entity string) (model.Access, error) {
+
+	var (
+		user    = requestinfo.UserID(ctx)
+		ownerID sql.NullInt64
+		role    sql.NullString
+	)
+
+	err := r.Db.QueryRowContext(ctx, query, id, user).Scan(&ownerID, &role)
+	if err == sql.ErrNoRows {
+		return model.Access{}, apperror.New(apperror.ErrNotFound, entity+" not found")
+	}
+	if err != nil {
+		return model.Access{}, dbError(err, "fetch "+entity+" access")
+	}
+
+	switch {
+	case ownerID.Valid && ownerID.Int64 == user && user != 0:
+		return model.Access{OwnerID: user, Role: model.RoleOwner}, nil
+	case ownerID.Valid && role.Valid:
+		return model.Access{OwnerID: ownerID.Int64, Role: model.Role(role.String)}, nil
+	}
+	return model.Access{}, apperror.New(apperror.ErrNotFound, entity+" not found")
+}
+
+func (r *Repo) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+
+	members := []model.MemberModel{}
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchMembersQuery, id)
+	if err != nil {
+		fmt.Println(err)
+		return nil, dbError(err, "fetch members")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			member     model.MemberModel
+			acceptedAt sql.NullTime
+		)
+		if err := rows.Scan(&member.ListID, &member.UserID, &member.Email, &member.Role, &member.InvitedAt, &acceptedAt); err != nil {
+			return nil, dbError(err, "scan member")
+		}
+		if acceptedAt.Valid {
+			member.AcceptedAt = &acceptedAt.Time
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch members")
+	}
+
+	return members, nil
+}
+
+// Invite adds the user with the email of the request to the members of the
+// list, pending until they accept. A member keeps their acceptance when
+// their role changes.
+func (r *Repo) Invite(ctx context.Context, id int64, invite model.InviteRequest) (model.MemberModel, error) {
+
+	var (
+		member     = model.MemberModel{ListID: id, Email: invite.Email, Role: invite.Role}
+		acceptedAt sql.NullTime
+	)
+
+	err := r.Db.QueryRowContext(ctx, model.InviteMemberQuery, id, invite.Role, invite.Email).Scan(&member.UserID, &member.InvitedAt, &acceptedAt)
+	if err == sql.ErrNoRows {
+		return member, apperror.New(apperror.ErrNotFound, "no other user with this email")
+	}
+	if err != nil {
+		fmt.Println(err)
+		return member, dbError(err, "invite member")
+	}
+	if acceptedAt.Valid {
+		member.AcceptedAt = &acceptedAt.Time
+	}
+
+	return member, nil
+}
+
+// Accept accepts the pending invitation of the signed in user to a list.
+func (r *Repo) Accept(ctx context.Context, id int64) (model.MemberModel, error) {
+
+	var (
+		member     = model.MemberModel{ListID: id, UserID: requestinfo.UserID(ctx)}
+		acceptedAt time.Time
+	)
+
+	err := r.Db.QueryRowContext(ctx, model.AcceptInvitationQuery, id, member.UserID).Scan(&member.Role, &member.InvitedAt, &acceptedAt)
+	if err == sql.ErrNoRows {
+		return member, apperror.New(apperror.ErrNotFound, "invitation not found")
+	}
+	if err != nil {
+		fmt.Println(err)
+		return member, dbError(err, "accept invitation")
+	}
+	member.AcceptedAt = &acceptedAt
+
+	return member, nil
+}
+
+// RemoveMember revokes the membership or the pending invitation of a user.
+func (r *Repo) RemoveMember(ctx context.Context, id int64, userID int64) error {
+
+	res, err := r.Db.ExecContext(ctx, model.DeleteMemberQuery, id, userID)
+	if err != nil {
+		fmt.Println(err)
+		return dbError(err, "remove member")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err, "remove member")
+	}
+	if affected == 0 {
+		return apperror.New(apperror.ErrNotFound, "member not found")
+	}
+
+	return nil
+}
+
+// GetInvitations returns the pending invitations of the signed in user.
+func (r *Repo) GetInvitations(ctx context.Context) ([]model.MemberModel, error) {
+
+	var (
+		user        = requestinfo.UserID(ctx)
+		invitations = []model.MemberModel{}
+	)
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchInvitationsQuery, user)
+	if err != nil {
+		fmt.Println(err)
+		return nil, dbError(err, "fetch invitations")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		invitation := model.MemberModel{UserID: user}
+		if err := rows.Scan(&invitation.ListID, &invitation.ListName, &invitation.Role, &invitation.InvitedAt); err != nil {
+			return nil, dbError(err, "scan invitation")
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch invitations")
+	}
+
+	return invitations, nil
+}
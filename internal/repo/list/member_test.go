@@ -0,0 +1,143 @@
+package list
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/list"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestRepo_TaskAccess(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.Access
+		wantErr error
+	}{
+		{
+			name: "case 1 -> task of the user",
+			mock: func() {
+				mock.ExpectQuery(`SELECT tasks.owner_id, list_members.role FROM tasks (.+) WHERE tasks.id=(.*)`).WithArgs(1, testOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"owner_id", "role"}).AddRow(testOwner, nil))
+			},
+			want: model.Access{OwnerID: testOwner, Role: model.RoleOwner},
+		},
+		{
+			name: "case 2 -> task of a list shared with the user",
+			mock: func() {
+				mock.ExpectQuery(`SELECT tasks.owner_id, list_members.role FROM tasks (.+) WHERE tasks.id=(.*)`).WithArgs(1, testOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"owner_id", "role"}).AddRow(2, "viewer"))
+			},
+			want: model.Access{OwnerID: 2, Role: model.RoleViewer},
+		},
+		{
+			name: "case 3 -> task of another user",
+			mock: func() {
+				mock.ExpectQuery(`SELECT tasks.owner_id, list_members.role FROM tasks (.+) WHERE tasks.id=(.*)`).WithArgs(1, testOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"owner_id", "role"}).AddRow(2, nil))
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name: "case 4 -> unknown task",
+			mock: func() {
+				mock.ExpectQuery(`SELECT tasks.owner_id, list_members.role FROM tasks (.+) WHERE tasks.id=(.*)`).WithArgs(1, testOwner).
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewListRepository(db, rclient)
+			result, err := repo.TaskAccess(userCtx, 1)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_Invite(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	invitedAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	invite := model.InviteRequest{Email: "bob@example.com", Role: model.RoleEditor}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.MemberModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> invite user",
+			mock: func() {
+				mock.ExpectQuery(`INSERT INTO list_members (.+) ON CONFLICT (.+) RETURNING user_id, created_at, accepted_at`).WithArgs(1, model.RoleEditor, "bob@example.com").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "created_at", "accepted_at"}).AddRow(2, invitedAt, nil))
+			},
+			want: model.MemberModel{ListID: 1, UserID: 2, Email: "bob@example.com", Role: model.RoleEditor, InvitedAt: invitedAt},
+		},
+		{
+			name: "case 2 -> no user with the email",
+			mock: func() {
+				mock.ExpectQuery(`INSERT INTO list_members (.+) ON CONFLICT (.+) RETURNING user_id, created_at, accepted_at`).WithArgs(1, model.RoleEditor, "bob@example.com").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "created_at", "accepted_at"}))
+			},
+			want:    model.MemberModel{ListID: 1, Email: "bob@example.com", Role: model.RoleEditor},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewListRepository(db, rclient)
+			result, err := repo.Invite(userCtx, 1, invite)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_Accept(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	invitedAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	acceptedAt := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`UPDATE list_members SET accepted_at=now\(\) WHERE list_id=(.*) AND user_id=(.*) AND accepted_at IS NULL`).WithArgs(1, testOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "created_at", "accepted_at"}).AddRow("viewer", invitedAt, acceptedAt))
+	mock.ExpectQuery(`UPDATE list_members SET accepted_at=now\(\) WHERE list_id=(.*) AND user_id=(.*) AND accepted_at IS NULL`).WithArgs(1, testOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "created_at", "accepted_at"}))
+
+	repo := NewListRepository(db, rclient)
+
+	result, err := repo.Accept(userCtx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.MemberModel{ListID: 1, UserID: testOwner, Role: model.RoleViewer, InvitedAt: invitedAt, AcceptedAt: &acceptedAt}, result)
+
+	_, err = repo.Accept(userCtx, 1)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
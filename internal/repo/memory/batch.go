@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// Batch runs the operations in one write and returns a result per
+// operation. In all or nothing mode the first failing operation drops the
+// whole batch and ends it, its result carries the error. Otherwise only the
+// failing operations are undone.
+func (r *TaskRepo) Batch(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error) {
+	results := make([]model.BatchResult, len(ops))
+	err := r.store.write(func(t *tables) error {
+		for i, op := range ops {
+			results[i] = model.BatchResult{Op: op.Op, ID: op.ID}
+		}
+
+		for i, op := range ops {
+			// the savepoint of the operation
+			saved := t.clone()
+
+			task, before, err := t.runOperation(ctx, op, opts)
+			if err != nil {
+				results[i].Err = err
+				if opts.AllOrNothing {
+					return errRollback
+				}
+				*t = *saved
+				continue
+			}
+
+			results[i].ID = task.ID
+			results[i].Before = before
+			if op.Op != model.BatchDelete {
+				results[i].Task = &task
+			}
+		}
+		return t.record(ctx, record, model.BatchChanges(results)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// runOperation applies one operation and returns the task it created or
+// changed and the task it found before. A completion of a done task changes
+// nothing and finds no task.
+func (t *tables) runOperation(ctx context.Context, op model.BatchOperation, opts model.BatchOptions) (model.TaskModel, *model.TaskModel, error) {
+	switch op.Op {
+	case model.BatchCreate:
+		task, err := t.insertTask(ctx, *op.Task)
+		return task, nil, err
+
+	case model.BatchUpdate:
+		current, err := t.lockTask(ctx, op.ID)
+		if err != nil {
+			return current, nil, err
+		}
+		task := *op.Task
+		task.ID = op.ID
+		task.Version = op.Version
+		if task.Version != 0 && task.Version != current.Version {
+			return current, nil, apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+		}
+		if task.IsDone && !current.IsDone && opts.BlockOpenSubtasks {
+			if err := t.checkOpenSubtasks(ctx, task.ID); err != nil {
+				return current, nil, err
+			}
+		}
+		if current.ParentID != nil && !sameID(current.ListID, task.ListID) {
+			return current, nil, apperror.New(apperror.ErrValidation, "a subtask stays in the list of its parent")
+		}
+		if current.ParentID != nil && task.RRule != "" {
+			return current, nil, apperror.New(apperror.ErrValidation, "a subtask cannot repeat")
+		}
+		task.ParentID = current.ParentID
+		task.Position = current.Position
+
+		task, err = t.updateTask(ctx, task)
+		if err != nil || task.IsDone == current.IsDone {
+			return task, &current, err
+		}
+		t.rollUp(ctx, task.ParentID)
+		return task, &current, nil
+
+	case model.BatchComplete:
+		task, err := t.lockTask(ctx, op.ID)
+		if err != nil || task.IsDone {
+			return task, nil, err
+		}
+		current := task
+		if opts.BlockOpenSubtasks {
+			if err := t.checkOpenSubtasks(ctx, task.ID); err != nil {
+				return task, nil, err
+			}
+		}
+		row := t.tasks[task.ID]
+		row.task.IsDone = true
+		row.task.Version++
+		t.tasks[task.ID] = row
+		task.IsDone = true
+		task.Version++
+		t.rollUp(ctx, task.ParentID)
+		return task, &current, nil
+
+	case model.BatchDelete:
+		current, err := t.lockTask(ctx, op.ID)
+		if err != nil {
+			return model.TaskModel{ID: op.ID}, nil, err
+		}
+		parentID, err := t.trashTask(ctx, op.ID, op.Version)
+		if err != nil {
+			return model.TaskModel{ID: op.ID}, nil, err
+		}
+		t.rollUp(ctx, parentID)
+		return model.TaskModel{ID: op.ID}, &current, nil
+
+	default:
+		return model.TaskModel{}, nil, apperror.New(apperror.ErrValidation, "unknown operation "+op.Op)
+	}
+}
+
+func (t *tables) lockTask(ctx context.Context, id int64) (model.TaskModel, error) {
+	row, found := t.liveTask(ctx, id)
+	if !found {
+		return model.TaskModel{}, notFound("task")
+	}
+	return t.readTask(row), nil
+}
+
+func (t *tables) checkOpenSubtasks(ctx context.Context, id int64) error {
+	for _, row := range t.liveSubtasks(ctx, id) {
+		if !row.task.IsDone {
+			return apperror.New(apperror.ErrConflict, "task has open subtasks")
+		}
+	}
+	return nil
+}
+
+// rollUp makes the status of the parent match its subtasks: done when all
+// of them are and open when one of them is. Tasks without subtasks are left
+// alone.
+func (t *tables) rollUp(ctx context.Context, parentID *int64) {
+	if parentID == nil {
+		return
+	}
+	row, found := t.liveTask(ctx, *parentID)
+	subtasks := t.liveSubtasks(ctx, *parentID)
+	if !found || len(subtasks) == 0 {
+		return
+	}
+	open := false
+	for _, subtask := range subtasks {
+		open = open || !subtask.task.IsDone
+	}
+	if row.task.IsDone == open {
+		row.task.IsDone = !open
+		row.task.Version++
+		t.tasks[row.task.ID] = row
+	}
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// AddDependency blocks a task by another one. Both have to be live tasks,
+// adding the same dependency twice changes nothing. check runs on the
+// dependencies of the owner in the same write, and its error cancels it.
+func (r *TaskRepo) AddDependency(ctx context.Context, dependency model.Dependency, check func([]model.Dependency) error) error {
+	return r.store.write(func(t *tables) error {
+		if err := check(t.ownDependencies(ctx)); err != nil {
+			return err
+		}
+		_, taskFound := t.liveTask(ctx, dependency.TaskID)
+		_, blockerFound := t.liveTask(ctx, dependency.BlockedBy)
+		if !taskFound || !blockerFound {
+			return notFound("task")
+		}
+		if dependency.TaskID == dependency.BlockedBy {
+			return rejected("task")
+		}
+		key := dependencyKey{task: dependency.TaskID, blockedBy: dependency.BlockedBy}
+		if _, found := t.dependencies[key]; !found {
+			t.dependencies[key] = t.now
+		}
+		return nil
+	})
+}
+
+func (r *TaskRepo) RemoveDependency(ctx context.Context, dependency model.Dependency) error {
+	return r.store.write(func(t *tables) error {
+		key := dependencyKey{task: dependency.TaskID, blockedBy: dependency.BlockedBy}
+		_, found := t.dependencies[key]
+		if _, taskFound := t.anyTask(ctx, dependency.TaskID); !found || !taskFound {
+			return apperror.New(apperror.ErrNotFound, "dependency not found")
+		}
+		delete(t.dependencies, key)
+		return nil
+	})
+}
+
+// GetDependencies returns every edge, those of trashed tasks included since
+// they can come back.
+func (r *TaskRepo) GetDependencies(ctx context.Context) ([]model.Dependency, error) {
+	var dependencies []model.Dependency
+	err := r.store.read(func(t *tables) error {
+		dependencies = t.ownDependencies(ctx)
+		return nil
+	})
+	return dependencies, err
+}
+
+// ownDependencies returns the edges of the tasks of ctx in order.
+func (t *tables) ownDependencies(ctx context.Context) []model.Dependency {
+	dependencies := []model.Dependency{}
+	for key := range t.dependencies {
+		if _, found := t.anyTask(ctx, key.task); found {
+			dependencies = append(dependencies, model.Dependency{TaskID: key.task, BlockedBy: key.blockedBy})
+		}
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].TaskID != dependencies[j].TaskID {
+			return dependencies[i].TaskID < dependencies[j].TaskID
+		}
+		return dependencies[i].BlockedBy < dependencies[j].BlockedBy
+	})
+	return dependencies
+}
+
+// GetBlockers returns the live tasks the task waits for, done or not.
+func (r *TaskRepo) GetBlockers(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	tasks := []model.TaskModel{}
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.ownTasks(ctx) {
+			if _, found := t.dependencies[dependencyKey{task: id, blockedBy: row.task.ID}]; found && row.deletedAt == nil {
+				tasks = append(tasks, t.readTask(row))
+			}
+		}
+		return nil
+	})
+	return tasks, err
+}
+
+// GetOpenTasks returns every live task that is not done, with its blocked
+// flag.
+func (r *TaskRepo) GetOpenTasks(ctx context.Context) ([]model.TaskModel, error) {
+	tasks := []model.TaskModel{}
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.ownTasks(ctx) {
+			if row.deletedAt == nil && !row.task.IsDone {
+				task := t.readTask(row)
+				task.Blocked = t.blocked(task.ID)
+				tasks = append(tasks, task)
+			}
+		}
+		return nil
+	})
+	return tasks, err
+}
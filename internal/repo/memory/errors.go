@@ -0,0 +1,20 @@
+package memory
+
+import "to-do-list/pkg/apperror"
+
+// The errors below are the ones dberror.Wrap makes of the same failures in
+// Postgres, so callers cannot tell the backends apart.
+
+func notFound(entity string) error {
+	return apperror.New(apperror.ErrNotFound, entity+" not found")
+}
+
+// conflict is a unique violation.
+func conflict(entity string) error {
+	return apperror.New(apperror.ErrConflict, entity+" already exists")
+}
+
+// rejected is a violated foreign key or check constraint.
+func rejected(entity string) error {
+	return apperror.New(apperror.ErrValidation, entity+" data rejected by storage")
+}
@@ -0,0 +1,252 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// eventRow keeps the tasks of an event as JSON, like the jsonb columns, so
+// later changes to them do not rewrite the history.
+type eventRow struct {
+	event         model.TaskEvent
+	before, after []byte
+	ownerID       int64
+	workspaceID   int64
+	userID        int64
+}
+
+// AppendEvents adds events to the audit log of the user of ctx, all of them
+// or none.
+func (r *TaskRepo) AppendEvents(ctx context.Context, events ...model.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return r.store.write(func(t *tables) error {
+		return t.appendEvents(ctx, events...)
+	})
+}
+
+// record appends what record makes of changes to the audit log, in the
+// write that made them.
+func (t *tables) record(ctx context.Context, record model.Record, changes ...model.Change) error {
+	if record == nil || len(changes) == 0 {
+		return nil
+	}
+	return t.appendEvents(ctx, record(changes)...)
+}
+
+// recordUpdate appends what record makes of the change of before into the
+// task as it is now.
+func (t *tables) recordUpdate(ctx context.Context, record model.Record, before model.TaskModel) error {
+	row, found := t.liveTask(ctx, before.ID)
+	if !found {
+		return notFound("task")
+	}
+	after := t.readTask(row)
+	return t.record(ctx, record, model.Change{Before: &before, After: &after})
+}
+
+func (t *tables) appendEvents(ctx context.Context, events ...model.TaskEvent) error {
+	owner, workspace := requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)
+	if _, found := t.workspaces[workspace]; !found {
+		return rejected("task")
+	}
+
+	for _, event := range events {
+		row := eventRow{ownerID: owner, workspaceID: workspace, userID: requestinfo.UserID(ctx)}
+
+		var err error
+		if row.before, err = snapshot(event.Before); err != nil {
+			return err
+		}
+		if row.after, err = snapshot(event.After); err != nil {
+			return err
+		}
+		if event.Reverts != 0 && !t.hasEvent(event.Reverts) {
+			return rejected("task")
+		}
+
+		event.ID, event.CreatedAt = next(&t.seq.events), t.now
+		event.Before, event.After = nil, nil
+		row.event = event
+		t.events = append(t.events, row)
+	}
+	return nil
+}
+
+// GetEvents returns one page of the audit log, newest first.
+func (r *TaskRepo) GetEvents(ctx context.Context, filter model.EventFilter) (model.EventPage, error) {
+	page := model.EventPage{Events: []model.TaskEvent{}}
+
+	var last int64
+	if filter.Cursor != "" {
+		var err error
+		if last, err = strconv.ParseInt(filter.Cursor, 10, 64); err != nil {
+			return page, apperror.New(apperror.ErrValidation, "invalid cursor")
+		}
+	}
+
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.ownEvents(ctx) {
+			event := row.event
+			switch {
+			case filter.TaskID != nil && event.TaskID != *filter.TaskID,
+				filter.Actor != "" && event.Actor != filter.Actor,
+				filter.Action != "" && event.Action != filter.Action,
+				filter.From != nil && event.CreatedAt.Before(*filter.From),
+				filter.To != nil && !event.CreatedAt.Before(*filter.To),
+				last != 0 && event.ID >= last:
+				continue
+			}
+
+			event, err := row.read()
+			if err != nil {
+				return err
+			}
+			page.Events = append(page.Events, event)
+			if len(page.Events) > filter.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return page, err
+	}
+
+	if len(page.Events) > filter.Limit {
+		page.Events = page.Events[:filter.Limit]
+		page.NextCursor = strconv.FormatInt(page.Events[filter.Limit-1].ID, 10)
+	}
+
+	return page, nil
+}
+
+// GetUndoableEvents returns the events of the last change of user that has
+// not been undone yet, newest first, none when there is nothing to undo.
+// The change can be to the tasks of another owner, whom the events name.
+// Undos are not undone, events without a request id are undone one at a
+// time, and the changes a request made to the tasks of another owner are
+// undone next.
+func (r *TaskRepo) GetUndoableEvents(ctx context.Context, user int64) ([]model.TaskEvent, error) {
+	events := []model.TaskEvent{}
+	err := r.store.read(func(t *tables) error {
+		reverted := map[int64]bool{}
+		for _, row := range t.events {
+			reverted[row.event.Reverts] = true
+		}
+
+		var last *eventRow
+		workspace := requestinfo.WorkspaceID(ctx)
+		for i := len(t.events) - 1; i >= 0; i-- {
+			row := t.events[i]
+			if row.userID != user || row.workspaceID != workspace || row.event.Reverts != 0 || reverted[row.event.ID] {
+				continue
+			}
+			if last == nil {
+				last = &row
+			}
+			if row.ownerID != last.ownerID || row.event.ID != last.event.ID && (last.event.RequestID == "" || row.event.RequestID != last.event.RequestID) {
+				continue
+			}
+
+			event, err := row.read()
+			if err != nil {
+				return err
+			}
+			event.OwnerID = row.ownerID
+			events = append(events, event)
+		}
+		return nil
+	})
+	return events, err
+}
+
+// GetSnapshot returns the task as it was recorded at version, by the latest
+// event that recorded it.
+func (r *TaskRepo) GetSnapshot(ctx context.Context, id, version int64) (model.TaskModel, error) {
+	var task model.TaskModel
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.ownEvents(ctx) {
+			if row.event.TaskID != id {
+				continue
+			}
+			event, err := row.read()
+			if err != nil {
+				return err
+			}
+			for _, snapshot := range []*model.TaskModel{event.After, event.Before} {
+				if snapshot != nil && snapshot.Version == version {
+					task = *snapshot
+					return nil
+				}
+			}
+		}
+		return apperror.New(apperror.ErrNotFound, "version not found in the history of the task")
+	})
+	return task, err
+}
+
+// ownEvents returns the events of the owner of ctx in its workspace,
+// newest first.
+func (t *tables) ownEvents(ctx context.Context) []eventRow {
+	owner, workspace := requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)
+	rows := []eventRow{}
+	for i := len(t.events) - 1; i >= 0; i-- {
+		if t.events[i].ownerID == owner && t.events[i].workspaceID == workspace {
+			rows = append(rows, t.events[i])
+		}
+	}
+	return rows
+}
+
+func (t *tables) hasEvent(id int64) bool {
+	for _, row := range t.events {
+		if row.event.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// read returns the event with its tasks.
+func (row eventRow) read() (model.TaskEvent, error) {
+	event := row.event
+
+	var err error
+	if event.Before, err = readSnapshot(row.before); err != nil {
+		return event, fmt.Errorf("read task snapshot: %w", err)
+	}
+	if event.After, err = readSnapshot(row.after); err != nil {
+		return event, fmt.Errorf("read task snapshot: %w", err)
+	}
+	return event, nil
+}
+
+func snapshot(task *model.TaskModel) ([]byte, error) {
+	if task == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot task: %w", err)
+	}
+	return data, nil
+}
+
+func readSnapshot(data []byte) (*model.TaskModel, error) {
+	if data == nil {
+		return nil, nil
+	}
+	task := &model.TaskModel{}
+	if err := json.Unmarshal(data, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
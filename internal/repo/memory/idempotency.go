@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+	redis_client "to-do-list/pkg/redis"
+)
+
+const (
+	// a request holding a key longer than this is taken as dead, and its
+	// duplicates stop waiting for it
+	idempotencyLockTTL  = 30 * time.Second
+	idempotencyPollWait = 50 * time.Millisecond
+)
+
+// idempotencyRecord has no response while the first request runs.
+type idempotencyRecord struct {
+	fingerprint string
+	response    *redis_client.IdempotentResponse
+	expiresAt   time.Time
+}
+
+// IdempotencyStore is redis_client.IdempotencyStore for a single process.
+type IdempotencyStore struct {
+	mu       sync.Mutex
+	records  map[string]idempotencyRecord
+	window   time.Duration
+	lockTTL  time.Duration
+	pollWait time.Duration
+}
+
+func NewIdempotencyStore(window time.Duration) *IdempotencyStore {
+	if window <= 0 {
+		window = redis_client.DefaultIdempotencyWindow
+	}
+	return &IdempotencyStore{
+		records:  map[string]idempotencyRecord{},
+		window:   window,
+		lockTTL:  idempotencyLockTTL,
+		pollWait: idempotencyPollWait,
+	}
+}
+
+// Begin claims the key for a request. It returns nil when the caller has
+// to handle the request and then Complete or Release the key, or the stored
+// response when the request was already handled. A duplicate arriving while
+// the first request runs waits for its response.
+func (s *IdempotencyStore) Begin(ctx context.Context, key string, fingerprint string) (*redis_client.IdempotentResponse, error) {
+	deadline := time.Now().Add(s.lockTTL)
+	for {
+		record, claimed := s.claim(key, fingerprint)
+		if claimed {
+			return nil, nil
+		}
+		if record.fingerprint != fingerprint {
+			return nil, redis_client.ErrIdempotencyMismatch
+		}
+		if record.response != nil {
+			return record.response, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, redis_client.ErrIdempotencyInProgress
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.pollWait):
+		}
+	}
+}
+
+// claim stores a pending record for a free key, or returns the record
+// holding it.
+func (s *IdempotencyStore) claim(key string, fingerprint string) (idempotencyRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if record, found := s.records[key]; found && now.Before(record.expiresAt) {
+		return record, false
+	}
+	s.records[key] = idempotencyRecord{fingerprint: fingerprint, expiresAt: now.Add(s.lockTTL)}
+	return idempotencyRecord{}, true
+}
+
+// Complete stores the response of a key claimed with Begin for the window
+// of the store.
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, fingerprint string, response redis_client.IdempotentResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for other, record := range s.records {
+		if !now.Before(record.expiresAt) {
+			delete(s.records, other)
+		}
+	}
+	s.records[key] = idempotencyRecord{fingerprint: fingerprint, response: &response, expiresAt: now.Add(s.window)}
+	return nil
+}
+
+// Release frees a key claimed with Begin without a response, so a retry
+// handles the request again.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+	"to-do-list/pkg/apperror"
+	redis_client "to-do-list/pkg/redis"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestIdempotencyStore() *IdempotencyStore {
+	store := NewIdempotencyStore(time.Hour)
+	store.lockTTL = 500 * time.Millisecond
+	store.pollWait = 10 * time.Millisecond
+	return store
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	response := redis_client.IdempotentResponse{Status: 201, Header: map[string]string{"ETag": `"1"`}, Body: []byte(`{"id":1}`)}
+
+	t.Run("case 1 -> first request claims the key, retries get its response", func(t *testing.T) {
+		store := newTestIdempotencyStore()
+
+		got, err := store.Begin(ctx, "a", "body")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		assert.NoError(t, store.Complete(ctx, "a", "body", response))
+
+		got, err = store.Begin(ctx, "a", "body")
+		assert.NoError(t, err)
+		assert.Equal(t, &response, got)
+	})
+
+	t.Run("case 2 -> key reused with another body", func(t *testing.T) {
+		store := newTestIdempotencyStore()
+
+		_, _ = store.Begin(ctx, "a", "body")
+		assert.NoError(t, store.Complete(ctx, "a", "body", response))
+
+		_, err := store.Begin(ctx, "a", "other body")
+		assert.ErrorIs(t, err, apperror.ErrValidation)
+	})
+
+	t.Run("case 3 -> duplicate waits for the running request", func(t *testing.T) {
+		store := newTestIdempotencyStore()
+
+		_, _ = store.Begin(ctx, "a", "body")
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = store.Complete(ctx, "a", "body", response)
+		}()
+
+		got, err := store.Begin(ctx, "a", "body")
+		assert.NoError(t, err)
+		assert.Equal(t, &response, got)
+	})
+
+	t.Run("case 4 -> duplicate gives up on a request that does not finish", func(t *testing.T) {
+		store := newTestIdempotencyStore()
+		store.lockTTL = 5 * time.Second
+
+		_, _ = store.Begin(ctx, "a", "body")
+		store.lockTTL = 50 * time.Millisecond
+
+		_, err := store.Begin(ctx, "a", "body")
+		assert.ErrorIs(t, err, apperror.ErrConflict)
+	})
+
+	t.Run("case 5 -> released or expired key is claimed again", func(t *testing.T) {
+		store := newTestIdempotencyStore()
+
+		_, _ = store.Begin(ctx, "a", "body")
+		assert.NoError(t, store.Release(ctx, "a"))
+
+		got, err := store.Begin(ctx, "a", "body")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		store.window = time.Millisecond
+		assert.NoError(t, store.Complete(ctx, "a", "body", response))
+		time.Sleep(5 * time.Millisecond)
+
+		got, err = store.Begin(ctx, "a", "other body")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,361 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+type listRow struct {
+	id      int64
+	name    string
+	ownerID int64
+}
+
+type listMemberRow struct {
+	role       model.Role
+	invitedAt  time.Time
+	acceptedAt *time.Time
+}
+
+type ListRepo struct {
+	store *Store
+}
+
+func NewListRepository(store *Store) *ListRepo {
+	return &ListRepo{
+		store: store,
+	}
+}
+
+// GetAll returns the lists of the user and those shared with them.
+func (r *ListRepo) GetAll(ctx context.Context) ([]model.ListModel, error) {
+	lists := []model.ListModel{}
+	user := requestinfo.UserID(ctx)
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.lists {
+			role := model.RoleOwner
+			if member, found := t.listMembers[memberKey{group: row.id, user: user}]; found && member.acceptedAt != nil {
+				role = member.role
+			} else if row.ownerID != user {
+				continue
+			}
+			list := t.list(row)
+			list.Role = role
+			lists = append(lists, list)
+		}
+		return nil
+	})
+	sort.Slice(lists, func(i, j int) bool { return lists[i].ID < lists[j].ID })
+	return lists, err
+}
+
+func (r *ListRepo) GetByID(ctx context.Context, id int64) (model.ListModel, error) {
+	var list model.ListModel
+	err := r.store.read(func(t *tables) error {
+		row, found := t.ownList(ctx, id)
+		if !found {
+			return notFound("list")
+		}
+		list = t.list(row)
+		return nil
+	})
+	return list, err
+}
+
+func (r *ListRepo) Create(ctx context.Context, list model.ListModel) (model.ListModel, error) {
+	owner := requestinfo.OwnerID(ctx)
+	err := r.store.write(func(t *tables) error {
+		if _, found := t.users[owner]; !found {
+			return rejected("list")
+		}
+		list.ID = next(&t.seq.lists)
+		t.lists[list.ID] = listRow{id: list.ID, name: list.Name, ownerID: owner}
+		return nil
+	})
+	return list, err
+}
+
+func (r *ListRepo) Update(ctx context.Context, list model.ListModel) (model.ListModel, error) {
+	err := r.store.write(func(t *tables) error {
+		row, found := t.ownList(ctx, list.ID)
+		if !found {
+			return notFound("list")
+		}
+		row.name = list.Name
+		t.lists[row.id] = row
+		return nil
+	})
+	if err != nil {
+		return list, err
+	}
+	return r.GetByID(ctx, list.ID)
+}
+
+// Delete removes the list and, depending on the mode, moves its tasks to
+// the trash or to another list. It returns the live tasks of the list by
+// workspace, as they were before, and records what it did to them in their
+// workspace.
+func (r *ListRepo) Delete(ctx context.Context, id int64, opts model.DeleteOptions, record taskmodel.Record) (map[int64][]taskmodel.TaskModel, error) {
+	tasks := map[int64][]taskmodel.TaskModel{}
+	err := r.store.write(func(t *tables) error {
+		if _, found := t.ownList(ctx, id); !found {
+			return notFound("list")
+		}
+
+		reassign := opts.Mode == model.DeleteReassign
+		if reassign && opts.To != nil {
+			if _, found := t.ownList(ctx, *opts.To); !found {
+				return apperror.New(apperror.ErrValidation, "list to reassign to not found")
+			}
+		}
+
+		// a cascade moves the tasks to the trash out of any list, those
+		// already there keep their time and version
+		now := t.now
+		for taskID, row := range t.tasks {
+			if !sameID(row.task.ListID, &id) {
+				continue
+			}
+			if row.deletedAt == nil {
+				tasks[row.workspaceID] = append(tasks[row.workspaceID], t.readTask(row))
+			}
+			switch {
+			case reassign:
+				row.task.ListID = opts.To
+				row.task.Version++
+			case row.deletedAt == nil:
+				row.deletedAt = &now
+				row.task.ListID = nil
+				row.task.Version++
+			default:
+				row.task.ListID = nil
+			}
+			t.tasks[taskID] = row
+		}
+		for workspace, before := range tasks {
+			sort.Slice(before, func(i, j int) bool { return before[i].ID < before[j].ID })
+
+			changes := make([]taskmodel.Change, len(before))
+			for i := range before {
+				changes[i].Before = &before[i]
+				if reassign {
+					after := before[i]
+					after.ListID = opts.To
+					after.Version++
+					changes[i].After = &after
+				}
+			}
+			if err := t.record(requestinfo.WithWorkspaceID(ctx, workspace), record, changes...); err != nil {
+				return err
+			}
+		}
+
+		for key := range t.listMembers {
+			if key.group == id {
+				delete(t.listMembers, key)
+			}
+		}
+		for linkID, link := range t.links {
+			if sameID(link.ListID, &id) {
+				delete(t.links, linkID)
+			}
+		}
+		delete(t.lists, id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ListAccess returns what the signed in user may do with a list. Lists
+// they can not see are not found.
+func (r *ListRepo) ListAccess(ctx context.Context, id int64) (model.Access, error) {
+	var access model.Access
+	err := r.store.read(func(t *tables) error {
+		row, found := t.lists[id]
+		if !found {
+			return notFound("list")
+		}
+		var err error
+		access, err = t.access(ctx, row.ownerID, &row.id, "list")
+		return err
+	})
+	return access, err
+}
+
+// TaskAccess returns what the signed in user may do with a task, through
+// the list it is in. Tasks they can not see are not found.
+func (r *ListRepo) TaskAccess(ctx context.Context, id int64) (model.Access, error) {
+	var access model.Access
+	err := r.store.read(func(t *tables) error {
+		row, found := t.tasks[id]
+		if !found {
+			return notFound("task")
+		}
+		var err error
+		access, err = t.access(ctx, row.ownerID, row.task.ListID, "task")
+		return err
+	})
+	return access, err
+}
+
+// access is the role of the signed in user in something of owner in the
+// list listID, owners first and then accepted members.
+func (t *tables) access(ctx context.Context, owner int64, listID *int64, entity string) (model.Access, error) {
+	user := requestinfo.UserID(ctx)
+	if owner != 0 && owner == user {
+		return model.Access{OwnerID: user, Role: model.RoleOwner}, nil
+	}
+	if listID != nil && owner != 0 {
+		if member, found := t.listMembers[memberKey{group: *listID, user: user}]; found && member.acceptedAt != nil {
+			return model.Access{OwnerID: owner, Role: member.role}, nil
+		}
+	}
+	return model.Access{}, notFound(entity)
+}
+
+func (r *ListRepo) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	members := []model.MemberModel{}
+	err := r.store.read(func(t *tables) error {
+		for key, row := range t.listMembers {
+			if key.group == id {
+				members = append(members, model.MemberModel{
+					ListID:     id,
+					UserID:     key.user,
+					Email:      t.users[key.user].email,
+					Role:       row.role,
+					InvitedAt:  row.invitedAt,
+					AcceptedAt: row.acceptedAt,
+				})
+			}
+		}
+		return nil
+	})
+	sortMembers(members)
+	return members, err
+}
+
+// Invite adds the user with the email of the request to the members of the
+// list, pending until they accept. A member keeps their acceptance when
+// their role changes.
+func (r *ListRepo) Invite(ctx context.Context, id int64, invite model.InviteRequest) (model.MemberModel, error) {
+	member := model.MemberModel{ListID: id, Email: invite.Email, Role: invite.Role}
+	err := r.store.write(func(t *tables) error {
+		list, found := t.lists[id]
+		user, userFound := t.userByEmail(invite.Email)
+		if !found || !userFound || user.id == list.ownerID {
+			return apperror.New(apperror.ErrNotFound, "no other user with this email")
+		}
+		switch invite.Role {
+		case model.RoleViewer, model.RoleEditor, model.RoleOwner:
+		default:
+			return rejected("list")
+		}
+
+		key := memberKey{group: id, user: user.id}
+		row, found := t.listMembers[key]
+		if !found {
+			row.invitedAt = t.now
+		}
+		row.role = invite.Role
+		t.listMembers[key] = row
+
+		member.UserID, member.InvitedAt, member.AcceptedAt = user.id, row.invitedAt, row.acceptedAt
+		return nil
+	})
+	return member, err
+}
+
+// Accept accepts the pending invitation of the signed in user to a list.
+func (r *ListRepo) Accept(ctx context.Context, id int64) (model.MemberModel, error) {
+	member := model.MemberModel{ListID: id, UserID: requestinfo.UserID(ctx)}
+	err := r.store.write(func(t *tables) error {
+		key := memberKey{group: id, user: member.UserID}
+		row, found := t.listMembers[key]
+		if !found || row.acceptedAt != nil {
+			return apperror.New(apperror.ErrNotFound, "invitation not found")
+		}
+		now := t.now
+		row.acceptedAt = &now
+		t.listMembers[key] = row
+
+		member.Role, member.InvitedAt, member.AcceptedAt = row.role, row.invitedAt, row.acceptedAt
+		return nil
+	})
+	return member, err
+}
+
+// RemoveMember revokes the membership or the pending invitation of a user.
+func (r *ListRepo) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	return r.store.write(func(t *tables) error {
+		key := memberKey{group: id, user: userID}
+		if _, found := t.listMembers[key]; !found {
+			return notFound("member")
+		}
+		delete(t.listMembers, key)
+		return nil
+	})
+}
+
+// GetInvitations returns the pending invitations of the signed in user.
+func (r *ListRepo) GetInvitations(ctx context.Context) ([]model.MemberModel, error) {
+	invitations := []model.MemberModel{}
+	user := requestinfo.UserID(ctx)
+	err := r.store.read(func(t *tables) error {
+		for key, row := range t.listMembers {
+			if key.user == user && row.acceptedAt == nil {
+				invitations = append(invitations, model.MemberModel{
+					ListID:    key.group,
+					ListName:  t.lists[key.group].name,
+					UserID:    user,
+					Role:      row.role,
+					InvitedAt: row.invitedAt,
+				})
+			}
+		}
+		return nil
+	})
+	sort.Slice(invitations, func(i, j int) bool {
+		if !invitations[i].InvitedAt.Equal(invitations[j].InvitedAt) {
+			return invitations[i].InvitedAt.Before(invitations[j].InvitedAt)
+		}
+		return invitations[i].ListID < invitations[j].ListID
+	})
+	return invitations, err
+}
+
+// ownList finds a list of the owner of ctx.
+func (t *tables) ownList(ctx context.Context, id int64) (listRow, bool) {
+	row, found := t.lists[id]
+	if !found || row.ownerID != requestinfo.OwnerID(ctx) {
+		return listRow{}, false
+	}
+	return row, true
+}
+
+// list reads a list with the number of its live tasks.
+func (t *tables) list(row listRow) model.ListModel {
+	list := model.ListModel{ID: row.id, Name: row.name}
+	for _, task := range t.tasks {
+		if task.deletedAt == nil && sameID(task.task.ListID, &row.id) {
+			list.TaskCount++
+		}
+	}
+	return list
+}
+
+func sortMembers(members []model.MemberModel) {
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].InvitedAt.Equal(members[j].InvitedAt) {
+			return members[i].InvitedAt.Before(members[j].InvitedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+}
@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"testing"
+	"to-do-list/internal/repo/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Backend {
+		store := NewStore()
+		return repotest.Backend{
+			Tasks:      NewTaskRepository(store),
+			Lists:      NewListRepository(store),
+			Tags:       NewTagRepository(store),
+			Shares:     NewShareRepository(store),
+			Users:      NewUserRepository(store),
+			Workspaces: NewWorkspaceRepository(store),
+		}
+	})
+}
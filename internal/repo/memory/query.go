@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// sortFields compare two tasks on an API sort field. Tasks without a
+// deadline sort after every deadline, like the infinity of the SQL query.
+var sortFields = map[string]func(a, b model.TaskModel) int{
+	"id": func(a, b model.TaskModel) int { return compareInt(a.ID, b.ID) },
+	"task_name": func(a, b model.TaskModel) int {
+		return strings.Compare(a.TaskName, b.TaskName)
+	},
+	"is_done": func(a, b model.TaskModel) int {
+		return compareInt(boolInt(a.IsDone), boolInt(b.IsDone))
+	},
+	"priority": func(a, b model.TaskModel) int {
+		return compareInt(int64(a.Priority), int64(b.Priority))
+	},
+	"rank": func(a, b model.TaskModel) int { return strings.Compare(a.Rank, b.Rank) },
+	"due_at": func(a, b model.TaskModel) int {
+		switch {
+		case a.DueAt == nil && b.DueAt == nil:
+			return 0
+		case a.DueAt == nil:
+			return 1
+		case b.DueAt == nil:
+			return -1
+		}
+		return compareInt(a.DueAt.UnixNano(), b.DueAt.UnixNano())
+	},
+}
+
+// cursor is the last task of a page, reduced to the fields tasks sort by,
+// with the sort it was made for.
+type cursor struct {
+	Sort     string         `json:"s"`
+	ID       int64          `json:"id"`
+	TaskName string         `json:"name,omitempty"`
+	IsDone   bool           `json:"done,omitempty"`
+	DueAt    *time.Time     `json:"due,omitempty"`
+	Priority model.Priority `json:"priority,omitempty"`
+	Rank     string         `json:"rank,omitempty"`
+}
+
+func (r *TaskRepo) GetAll(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+	page := model.TaskPage{Tasks: []model.TaskModel{}}
+
+	order := orderOf(filter)
+	for _, field := range order {
+		if _, ok := sortFields[field.Field]; !ok {
+			return page, apperror.New(apperror.ErrValidation, "unknown sort field "+field.Field)
+		}
+	}
+
+	var after *model.TaskModel
+	if filter.Cursor != "" {
+		last, err := decodeCursor(filter.Cursor, order)
+		if err != nil {
+			return page, err
+		}
+		after = &last
+	}
+
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.ownTasks(ctx) {
+			if row.deletedAt != nil {
+				continue
+			}
+			task := t.readTask(row)
+			if !matches(task, filter) || (after != nil && compareTasks(order, task, *after) <= 0) {
+				continue
+			}
+			task.Blocked = t.blocked(task.ID)
+			page.Tasks = append(page.Tasks, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return page, err
+	}
+
+	sort.Slice(page.Tasks, func(i, j int) bool { return compareTasks(order, page.Tasks[i], page.Tasks[j]) < 0 })
+
+	if len(page.Tasks) > filter.Limit {
+		page.Tasks = page.Tasks[:filter.Limit]
+		page.NextCursor = encodeCursor(order, page.Tasks[filter.Limit-1])
+	}
+
+	return page, nil
+}
+
+// matches applies every filter but the cursor to a task.
+func matches(task model.TaskModel, filter model.TaskFilter) bool {
+	if filter.IsDone != nil && task.IsDone != *filter.IsDone {
+		return false
+	}
+	if filter.Query != "" && !strings.Contains(strings.ToLower(task.TaskName), strings.ToLower(filter.Query)) {
+		return false
+	}
+	if filter.ListID != nil && !sameID(task.ListID, filter.ListID) {
+		return false
+	}
+	if filter.Priority != nil && task.Priority != *filter.Priority {
+		return false
+	}
+	for _, tag := range filter.Tags {
+		found := false
+		for _, name := range task.Tags {
+			found = found || name == tag
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.DueFrom != nil && (task.DueAt == nil || task.DueAt.Before(*filter.DueFrom)) {
+		return false
+	}
+	if filter.DueTo != nil && (task.DueAt == nil || !task.DueAt.Before(*filter.DueTo)) {
+		return false
+	}
+	return true
+}
+
+// orderOf returns the requested sort with id appended as the tie breaker so
+// every task has a unique position for the cursor.
+func orderOf(filter model.TaskFilter) []model.SortField {
+	order := []model.SortField{}
+	for _, field := range filter.Sort {
+		order = append(order, field)
+		if field.Field == "id" {
+			return order
+		}
+	}
+	return append(order, model.SortField{Field: "id"})
+}
+
+func compareTasks(order []model.SortField, a, b model.TaskModel) int {
+	for _, field := range order {
+		c := sortFields[field.Field](a, b)
+		if field.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func sortKey(order []model.SortField) string {
+	keys := make([]string, len(order))
+	for i, field := range order {
+		keys[i] = field.Field
+		if field.Desc {
+			keys[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+func encodeCursor(order []model.SortField, last model.TaskModel) string {
+	data, _ := json.Marshal(cursor{
+		Sort:     sortKey(order),
+		ID:       last.ID,
+		TaskName: last.TaskName,
+		IsDone:   last.IsDone,
+		DueAt:    last.DueAt,
+		Priority: last.Priority,
+		Rank:     last.Rank,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string, order []model.SortField) (model.TaskModel, error) {
+	c := cursor{}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return model.TaskModel{}, apperror.Wrap(apperror.ErrValidation, "invalid cursor", err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return model.TaskModel{}, apperror.Wrap(apperror.ErrValidation, "invalid cursor", err)
+	}
+	if c.Sort != sortKey(order) {
+		return model.TaskModel{}, apperror.New(apperror.ErrValidation, "cursor does not match sort")
+	}
+
+	return model.TaskModel{ID: c.ID, TaskName: c.TaskName, IsDone: c.IsDone, DueAt: c.DueAt, Priority: c.Priority, Rank: c.Rank}, nil
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/rank"
+)
+
+// Reposition puts a top level task right before or right after another one
+// in the manual order. Only the moved task gets a new key, unless its new
+// neighbours leave no room and every key is spread again.
+func (r *TaskRepo) Reposition(ctx context.Context, id int64, place model.RepositionRequest, record model.Record) (model.TaskModel, error) {
+	anchor, after := place.Before, false
+	if place.After != nil {
+		anchor, after = place.After, true
+	}
+	if anchor == nil {
+		return model.TaskModel{}, apperror.New(apperror.ErrValidation, "before or after is required")
+	}
+
+	err := r.store.write(func(t *tables) error {
+		row, found := t.liveTask(ctx, id)
+		if !found || row.task.ParentID != nil {
+			return notFound("task")
+		}
+		before := t.readTask(row)
+		anchorRow, found := t.liveTask(ctx, *anchor)
+		if !found || anchorRow.task.ParentID != nil {
+			return apperror.New(apperror.ErrNotFound, "anchor task not found")
+		}
+
+		// the neighbours are looked for in every workspace of the owner,
+		// where keys are unique
+		lower, upper := "", anchorRow.task.Rank
+		if after {
+			lower, upper = anchorRow.task.Rank, ""
+		}
+		for _, other := range t.tasks {
+			if other.ownerID != row.ownerID || other.task.ID == id || other.task.ParentID != nil || other.deletedAt != nil {
+				continue
+			}
+			key := other.task.Rank
+			if after && key > anchorRow.task.Rank && (upper == "" || key < upper) {
+				upper = key
+			}
+			if !after && key < anchorRow.task.Rank && key > lower {
+				lower = key
+			}
+		}
+
+		key, err := rank.Between(lower, upper)
+		if err != nil || len(key) > rank.MaxLen {
+			t.spreadRanks(row.ownerID, id, *anchor, after)
+			row = t.tasks[id]
+			key = row.task.Rank
+		}
+
+		row.task.Rank = key
+		row.task.Version++
+		t.tasks[id] = row
+		return t.recordUpdate(ctx, record, before)
+	})
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Rebalance spreads the keys of the manual order of every user again once
+// one of them is longer than rank.MaxLen, and returns how many tasks got a
+// new key.
+func (r *TaskRepo) Rebalance(ctx context.Context) (int64, error) {
+	var spread int64
+	err := r.store.write(func(t *tables) error {
+		long := map[int64]bool{}
+		for _, row := range t.tasks {
+			if row.ownerID != 0 && len(row.task.Rank) > rank.MaxLen {
+				long[row.ownerID] = true
+			}
+		}
+		for owner := range long {
+			t.spreadRanks(owner, 0, 0, false)
+			for _, row := range t.tasks {
+				if row.ownerID == owner {
+					spread++
+				}
+			}
+		}
+		return nil
+	})
+	return spread, err
+}
+
+// spreadRanks gives every task of owner, trashed ones and subtasks
+// included, an evenly spaced key in the current order, with task id taken
+// out and put next to anchor. An id of 0 moves nothing. The order does not
+// change, so versions stay.
+func (t *tables) spreadRanks(owner, id, anchor int64, after bool) {
+	rows := []taskRow{}
+	for _, row := range t.tasks {
+		if row.ownerID == owner {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].task.Rank != rows[j].task.Rank {
+			return rows[i].task.Rank < rows[j].task.Rank
+		}
+		return rows[i].task.ID < rows[j].task.ID
+	})
+
+	order := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		other := row.task.ID
+		if other == id {
+			continue
+		}
+		if other == anchor && !after {
+			order = append(order, id)
+		}
+		order = append(order, other)
+		if other == anchor && after {
+			order = append(order, id)
+		}
+	}
+
+	keys := rank.Spread(len(order))
+	for i, taskID := range order {
+		row := t.tasks[taskID]
+		row.task.Rank = keys[i]
+		t.tasks[taskID] = row
+	}
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// Rewind applies the steps in order in one write and returns the task
+// before and after each of them. A task is only checked against the
+// Expected version of its first step, the later ones find the version the
+// earlier ones left.
+func (r *TaskRepo) Rewind(ctx context.Context, steps []model.RewindStep, record model.Record) ([]model.RewindResult, error) {
+	results := make([]model.RewindResult, 0, len(steps))
+	err := r.store.write(func(t *tables) error {
+		checked := map[int64]bool{}
+		for _, step := range steps {
+			if checked[step.TaskID] {
+				step.Expected = 0
+			}
+			checked[step.TaskID] = true
+
+			result, err := t.rewindTask(ctx, step)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return t.record(ctx, record, model.RewindChanges(results)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (t *tables) rewindTask(ctx context.Context, step model.RewindStep) (model.RewindResult, error) {
+	result := model.RewindResult{}
+
+	current, found := t.lockAnyTask(ctx, step.TaskID)
+
+	var err error
+	switch {
+	case !found && step.State == nil:
+		return result, apperror.New(apperror.ErrConflict, fmt.Sprintf("task %d has been purged", step.TaskID))
+
+	case !found:
+		err = t.recreateTask(ctx, *step.State)
+
+	case step.Expected != 0 && current.Version != step.Expected:
+		return result, apperror.New(apperror.ErrConflict, fmt.Sprintf("task %d has changed since", step.TaskID))
+
+	case step.State == nil:
+		result.Before = &current
+		if current.DeletedAt == nil {
+			var parentID *int64
+			if parentID, err = t.trashTask(ctx, current.ID, current.Version); err == nil {
+				t.rollUp(ctx, parentID)
+			}
+		}
+
+	default:
+		result.Before = &current
+		err = t.rewriteTask(ctx, current, *step.State)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.After, _ = t.lockAnyTask(ctx, step.TaskID)
+	return result, nil
+}
+
+// rewriteTask takes current out of the trash when it is there and writes
+// state over it. A subtask keeps the list of its parent, which may have
+// moved since, and a task whose list has been deleted since is in no list.
+func (t *tables) rewriteTask(ctx context.Context, current, state model.TaskModel) error {
+	if current.DeletedAt != nil {
+		if err := t.restoreTask(ctx, current.ID); err != nil {
+			return err
+		}
+	}
+
+	row := t.tasks[current.ID]
+	if row.task.ParentID != nil {
+		state.ListID = t.tasks[*row.task.ParentID].task.ListID
+	} else {
+		state.ListID = t.keptList(ctx, state.ListID)
+	}
+	if err := checkValues(state); err != nil {
+		return err
+	}
+
+	row.task.TaskName = state.TaskName
+	row.task.IsDone = state.IsDone
+	row.task.DueAt = state.DueAt
+	row.task.Timezone = state.Timezone
+	row.task.RemindAt = state.RemindAt
+	row.task.Priority = state.Priority
+	row.task.ListID = state.ListID
+	row.task.Position = state.Position
+	row.task.RRule = state.RRule
+	row.task.RepeatFrom = state.RepeatFrom
+	if state.Rank != "" {
+		row.task.Rank = state.Rank
+	}
+	row.task.Version++
+	row.tagIDs = t.tagIDs(row.ownerID, state.Tags)
+	t.tasks[current.ID] = row
+
+	if current.ParentID == nil {
+		t.moveSubtasks(ctx, current.ID, state.ListID)
+	}
+
+	t.rollUp(ctx, current.ParentID)
+	return nil
+}
+
+// recreateTask creates a purged task again with its id, in no list when its
+// list has been deleted since. A subtask needs its parent back first.
+func (t *tables) recreateTask(ctx context.Context, state model.TaskModel) error {
+	if state.ParentID != nil {
+		parent, found := t.lockAnyTask(ctx, *state.ParentID)
+		if !found || parent.DeletedAt != nil {
+			return apperror.New(apperror.ErrConflict, "the parent task is gone, bring it back first")
+		}
+		state.ListID = parent.ListID
+	} else {
+		state.ListID = t.keptList(ctx, state.ListID)
+	}
+
+	if _, found := t.tasks[state.ID]; found {
+		return conflict("task")
+	}
+	if err := t.checkTask(ctx, state); err != nil {
+		return err
+	}
+
+	state.Version++
+	t.saveTask(ctx, state, nil)
+
+	t.rollUp(ctx, state.ParentID)
+	return nil
+}
+
+// keptList returns listID while the list is still there.
+func (t *tables) keptList(ctx context.Context, listID *int64) *int64 {
+	if listID == nil {
+		return nil
+	}
+	if _, found := t.ownList(ctx, *listID); !found {
+		return nil
+	}
+	return listID
+}
+
+// lockAnyTask also finds trashed tasks.
+func (t *tables) lockAnyTask(ctx context.Context, id int64) (model.TaskModel, bool) {
+	row, found := t.anyTask(ctx, id)
+	if !found {
+		return model.TaskModel{}, false
+	}
+	task := t.readTask(row)
+	task.DeletedAt = row.deletedAt
+	return task, true
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	model "to-do-list/internal/model/share"
+	"to-do-list/pkg/requestinfo"
+)
+
+// linkRow is a share link. A link to a list or to a task goes with it.
+type linkRow = model.LinkModel
+
+type ShareRepo struct {
+	store *Store
+}
+
+func NewShareRepository(store *Store) *ShareRepo {
+	return &ShareRepo{
+		store: store,
+	}
+}
+
+// Create stores a link created by the signed in user in the workspace of
+// the request.
+func (r *ShareRepo) Create(ctx context.Context, link model.LinkModel) (model.LinkModel, error) {
+	link.UserID, link.WorkspaceID = requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx)
+	err := r.store.write(func(t *tables) error {
+		if (link.ListID == nil) == (link.TaskID == nil) {
+			return rejected("share link")
+		}
+		_, userFound := t.users[link.UserID]
+		_, workspaceFound := t.workspaces[link.WorkspaceID]
+		if !userFound || !workspaceFound {
+			return rejected("share link")
+		}
+		if link.ListID != nil {
+			if _, found := t.lists[*link.ListID]; !found {
+				return rejected("share link")
+			}
+		}
+		if link.TaskID != nil {
+			if _, found := t.tasks[*link.TaskID]; !found {
+				return rejected("share link")
+			}
+		}
+
+		link.ID, link.CreatedAt = next(&t.seq.links), t.now
+		link.URL = ""
+		t.links[link.ID] = link
+		return nil
+	})
+	return link, err
+}
+
+// GetAll returns the links the signed in user created in the workspace of
+// the request.
+func (r *ShareRepo) GetAll(ctx context.Context) ([]model.LinkModel, error) {
+	links := []model.LinkModel{}
+	user, workspace := requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx)
+	err := r.store.read(func(t *tables) error {
+		for _, link := range t.links {
+			if link.UserID == user && link.WorkspaceID == workspace {
+				links = append(links, link)
+			}
+		}
+		return nil
+	})
+	sort.Slice(links, func(i, j int) bool { return links[i].ID < links[j].ID })
+	return links, err
+}
+
+// GetByID reads any link, it serves visitors who are not signed in.
+func (r *ShareRepo) GetByID(ctx context.Context, id int64) (model.LinkModel, error) {
+	var link model.LinkModel
+	err := r.store.read(func(t *tables) error {
+		var found bool
+		if link, found = t.links[id]; !found {
+			return notFound("share link")
+		}
+		return nil
+	})
+	return link, err
+}
+
+// Delete revokes a link of the signed in user in the workspace of the
+// request.
+func (r *ShareRepo) Delete(ctx context.Context, id int64) error {
+	user, workspace := requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx)
+	return r.store.write(func(t *tables) error {
+		link, found := t.links[id]
+		if !found || link.UserID != user || link.WorkspaceID != workspace {
+			return notFound("share link")
+		}
+		delete(t.links, id)
+		return nil
+	})
+}
@@ -0,0 +1,148 @@
+// Package memory keeps the data of every repo in the memory of the process,
+// so the server runs without Postgres and Redis. Nothing outlives the
+// process. The repos follow the schema of schema/: the same scoping,
+// constraints, cascades and versions, which internal/repo/repotest checks
+// against both backends.
+package memory
+
+import (
+	"errors"
+	"sync"
+	"time"
+	workspacemodel "to-do-list/internal/model/workspace"
+)
+
+// Driver is the value of database.driver that selects this backend.
+const Driver = "memory"
+
+// errRollback ends a write whose changes are dropped without it failing.
+var errRollback = errors.New("memory: rollback")
+
+// Store holds the tables of every repo. One lock serializes every read and
+// write, a write works on a copy of the tables that replaces them once it
+// succeeds, like a transaction.
+type Store struct {
+	mu     sync.Mutex
+	tables *tables
+}
+
+func NewStore() *Store {
+	t := &tables{
+		seq:              &sequences{},
+		users:            map[int64]userRow{},
+		refreshTokens:    map[string]refreshTokenRow{},
+		apiKeys:          map[int64]apiKeyRow{},
+		workspaces:       map[int64]workspaceRow{},
+		workspaceMembers: map[memberKey]time.Time{},
+		lists:            map[int64]listRow{},
+		listMembers:      map[memberKey]listMemberRow{},
+		tasks:            map[int64]taskRow{},
+		tags:             map[int64]tagRow{},
+		dependencies:     map[dependencyKey]time.Time{},
+		links:            map[int64]linkRow{},
+	}
+
+	// the default workspace comes with the schema, like in schema/17
+	t.workspaces[workspacemodel.DefaultID] = workspaceRow{
+		id:        workspacemodel.DefaultID,
+		name:      "Default",
+		slug:      "default",
+		createdAt: time.Now(),
+	}
+	t.seq.workspaces = workspacemodel.DefaultID
+
+	return &Store{tables: t}
+}
+
+// read runs fn on the tables. fn must not change them.
+func (s *Store) read(fn func(t *tables) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tables.now = time.Now()
+	return fn(s.tables)
+}
+
+// write runs fn on a copy of the tables and keeps its changes only when it
+// returns nil, errRollback drops them and write returns nil. Every change of
+// one write shares the time t.now.
+func (s *Store) write(fn func(t *tables) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tables.clone()
+	t.now = time.Now()
+	if err := fn(t); err == errRollback {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	s.tables = t
+	return nil
+}
+
+// sequences hand out ids. Like sequences in Postgres they are shared by
+// every copy of the tables, a rolled back write does not give its ids back.
+type sequences struct {
+	users, apiKeys, workspaces, lists, tasks, tags, events, links int64
+}
+
+func next(seq *int64) int64 {
+	*seq++
+	return *seq
+}
+
+type memberKey struct {
+	group int64
+	user  int64
+}
+
+type dependencyKey struct {
+	task      int64
+	blockedBy int64
+}
+
+type tables struct {
+	now time.Time
+	seq *sequences
+
+	users            map[int64]userRow
+	refreshTokens    map[string]refreshTokenRow
+	apiKeys          map[int64]apiKeyRow
+	workspaces       map[int64]workspaceRow
+	workspaceMembers map[memberKey]time.Time
+	lists            map[int64]listRow
+	listMembers      map[memberKey]listMemberRow
+	tasks            map[int64]taskRow
+	tags             map[int64]tagRow
+	dependencies     map[dependencyKey]time.Time
+	events           []eventRow
+	links            map[int64]linkRow
+}
+
+// clone copies the tables. Rows are values and their slices are replaced,
+// never changed in place, so a shallow copy of each map is enough.
+func (t *tables) clone() *tables {
+	c := *t
+	c.users = copyMap(t.users)
+	c.refreshTokens = copyMap(t.refreshTokens)
+	c.apiKeys = copyMap(t.apiKeys)
+	c.workspaces = copyMap(t.workspaces)
+	c.workspaceMembers = copyMap(t.workspaceMembers)
+	c.lists = copyMap(t.lists)
+	c.listMembers = copyMap(t.listMembers)
+	c.tasks = copyMap(t.tasks)
+	c.tags = copyMap(t.tags)
+	c.dependencies = copyMap(t.dependencies)
+	c.events = append([]eventRow(nil), t.events...)
+	c.links = copyMap(t.links)
+	return &c
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	c := make(map[K]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	model "to-do-list/internal/model/tag"
+	"to-do-list/pkg/requestinfo"
+)
+
+type tagRow struct {
+	id      int64
+	name    string
+	ownerID int64
+}
+
+type TagRepo struct {
+	store *Store
+}
+
+func NewTagRepository(store *Store) *TagRepo {
+	return &TagRepo{
+		store: store,
+	}
+}
+
+func (r *TagRepo) GetAll(ctx context.Context) ([]model.TagModel, error) {
+	tags := []model.TagModel{}
+	owner := requestinfo.OwnerID(ctx)
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.tags {
+			if row.ownerID == owner {
+				tags = append(tags, t.tag(row))
+			}
+		}
+		return nil
+	})
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, err
+}
+
+func (r *TagRepo) Create(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+	owner := requestinfo.OwnerID(ctx)
+	err := r.store.write(func(t *tables) error {
+		if _, found := t.tagByName(owner, tag.Name); found {
+			return conflict("tag")
+		}
+		if _, found := t.users[owner]; !found {
+			return rejected("tag")
+		}
+		tag.ID = next(&t.seq.tags)
+		t.tags[tag.ID] = tagRow{id: tag.ID, name: tag.Name, ownerID: owner}
+		return nil
+	})
+	return tag, err
+}
+
+// Rename changes the name in place, every task carrying the tag shows the
+// new name.
+func (r *TagRepo) Rename(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+	err := r.store.write(func(t *tables) error {
+		row, found := t.ownTag(ctx, tag.ID)
+		if !found {
+			return notFound("tag")
+		}
+		if other, found := t.tagByName(row.ownerID, tag.Name); found && other.id != row.id {
+			return conflict("tag")
+		}
+		row.name = tag.Name
+		t.tags[row.id] = row
+		t.touchTagged(ctx, row.id)
+		tag = t.tag(row)
+		return nil
+	})
+	return tag, err
+}
+
+// Merge moves the tasks of tag from to tag into and deletes tag from.
+func (r *TagRepo) Merge(ctx context.Context, from int64, into int64) (model.TagModel, error) {
+	var tag model.TagModel
+	err := r.store.write(func(t *tables) error {
+		_, fromFound := t.ownTag(ctx, from)
+		intoRow, intoFound := t.ownTag(ctx, into)
+		if !fromFound || !intoFound || from == into {
+			return notFound("tag")
+		}
+
+		t.touchTagged(ctx, from)
+		for id, row := range t.tasks {
+			if row.hasTag(from) {
+				tags := []int64{into}
+				for _, tagID := range row.tagIDs {
+					if tagID != from && tagID != into {
+						tags = append(tags, tagID)
+					}
+				}
+				row.tagIDs = tags
+				t.tasks[id] = row
+			}
+		}
+		delete(t.tags, from)
+
+		tag = t.tag(intoRow)
+		return nil
+	})
+	return tag, err
+}
+
+func (r *TagRepo) Delete(ctx context.Context, tag model.TagModel) error {
+	return r.store.write(func(t *tables) error {
+		if _, found := t.ownTag(ctx, tag.ID); !found {
+			return notFound("tag")
+		}
+		t.touchTagged(ctx, tag.ID)
+		t.dropTag(tag.ID)
+		return nil
+	})
+}
+
+// ownTag finds a tag of the owner of ctx.
+func (t *tables) ownTag(ctx context.Context, id int64) (tagRow, bool) {
+	row, found := t.tags[id]
+	if !found || row.ownerID != requestinfo.OwnerID(ctx) {
+		return tagRow{}, false
+	}
+	return row, true
+}
+
+func (t *tables) tagByName(owner int64, name string) (tagRow, bool) {
+	for _, row := range t.tags {
+		if row.ownerID == owner && row.name == name {
+			return row, true
+		}
+	}
+	return tagRow{}, false
+}
+
+// tag reads a tag with the number of tasks carrying it, trashed ones too.
+func (t *tables) tag(row tagRow) model.TagModel {
+	tag := model.TagModel{ID: row.id, Name: row.name}
+	for _, task := range t.tasks {
+		if task.hasTag(row.id) {
+			tag.TaskCount++
+		}
+	}
+	return tag
+}
+
+// touchTagged raises the version of the tasks of the owner of ctx carrying
+// a tag, whose representation changes with the tag.
+func (t *tables) touchTagged(ctx context.Context, id int64) {
+	owner := requestinfo.OwnerID(ctx)
+	for taskID, row := range t.tasks {
+		if row.ownerID == owner && row.hasTag(id) {
+			row.task.Version++
+			t.tasks[taskID] = row
+		}
+	}
+}
+
+// dropTag deletes a tag and takes it off its tasks.
+func (t *tables) dropTag(id int64) {
+	for taskID, row := range t.tasks {
+		if row.hasTag(id) {
+			tags := []int64{}
+			for _, tagID := range row.tagIDs {
+				if tagID != id {
+					tags = append(tags, tagID)
+				}
+			}
+			row.tagIDs = tags
+			t.tasks[taskID] = row
+		}
+	}
+	delete(t.tags, id)
+}
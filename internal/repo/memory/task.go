@@ -0,0 +1,464 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/rank"
+	"to-do-list/pkg/requestinfo"
+)
+
+// taskRow is a task as stored. Tags, Blocked and DeletedAt of task are
+// left empty, they are read from tagIDs, the dependencies and deletedAt.
+type taskRow struct {
+	task        model.TaskModel
+	ownerID     int64
+	workspaceID int64
+	tagIDs      []int64
+	deletedAt   *time.Time
+}
+
+func (row taskRow) hasTag(id int64) bool {
+	for _, tagID := range row.tagIDs {
+		if tagID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskRepo is the task repo of the usecase, with its audit log.
+type TaskRepo struct {
+	store *Store
+}
+
+func NewTaskRepository(store *Store) *TaskRepo {
+	return &TaskRepo{
+		store: store,
+	}
+}
+
+func (r *TaskRepo) GetByID(ctx context.Context, id int64) (model.TaskModel, error) {
+	var task model.TaskModel
+	err := r.store.read(func(t *tables) error {
+		row, found := t.liveTask(ctx, id)
+		if !found {
+			return notFound("task")
+		}
+		task = t.readTask(row)
+		return nil
+	})
+	return task, err
+}
+
+func (r *TaskRepo) Create(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+	err := r.store.write(func(t *tables) error {
+		var err error
+		if task, err = t.insertTask(ctx, task); err != nil {
+			return err
+		}
+		created := task
+		return t.record(ctx, record, model.Change{After: &created})
+	})
+	return task, err
+}
+
+// GetSubtasks returns the subtasks of a task in their order.
+func (r *TaskRepo) GetSubtasks(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	var tasks []model.TaskModel
+	err := r.store.read(func(t *tables) error {
+		tasks = t.readTasks(t.liveSubtasks(ctx, id))
+		return nil
+	})
+	return tasks, err
+}
+
+// Reorder numbers the subtasks of a task in the order of ids, which has to
+// list every one of them once. Every subtask is a change to record.
+func (r *TaskRepo) Reorder(ctx context.Context, id int64, ids []int64, record model.Record) error {
+	return r.store.write(func(t *tables) error {
+		before := t.readTasks(t.liveSubtasks(ctx, id))
+		current := map[int64]bool{}
+		for _, task := range before {
+			current[task.ID] = true
+		}
+
+		seen := map[int64]bool{}
+		for _, subtaskID := range ids {
+			if !current[subtaskID] || seen[subtaskID] {
+				return apperror.New(apperror.ErrValidation, "ids must list every subtask once")
+			}
+			seen[subtaskID] = true
+		}
+		if len(seen) != len(current) {
+			return apperror.New(apperror.ErrValidation, "ids must list every subtask once")
+		}
+
+		for position, subtaskID := range ids {
+			row := t.tasks[subtaskID]
+			row.task.Position = position
+			row.task.Version++
+			t.tasks[subtaskID] = row
+		}
+		after := t.readTasks(t.liveSubtasks(ctx, id))
+		return t.record(ctx, record, model.Changes(before, after)...)
+	})
+}
+
+// SetDone changes only the status of a task.
+func (r *TaskRepo) SetDone(ctx context.Context, id int64, done bool, record model.Record) error {
+	return r.store.write(func(t *tables) error {
+		row, found := t.liveTask(ctx, id)
+		if !found {
+			return notFound("task")
+		}
+		before := t.readTask(row)
+		row.task.IsDone = done
+		row.task.Version++
+		t.tasks[id] = row
+		return t.recordUpdate(ctx, record, before)
+	})
+}
+
+func (r *TaskRepo) Update(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+	err := r.store.write(func(t *tables) error {
+		row, _ := t.liveTask(ctx, task.ID)
+		before := t.readTask(row)
+		var err error
+		if task, err = t.updateTask(ctx, task); err != nil {
+			return err
+		}
+		after := task
+		return t.record(ctx, record, model.Change{Before: &before, After: &after})
+	})
+	return task, err
+}
+
+// Move changes the list of a task and its subtasks and returns the moved
+// task.
+func (r *TaskRepo) Move(ctx context.Context, id int64, listID *int64, record model.Record) (model.TaskModel, error) {
+	err := r.store.write(func(t *tables) error {
+		row, _ := t.liveTask(ctx, id)
+		before := t.readTask(row)
+		moved := false
+		for _, row := range t.ownTasks(ctx) {
+			if row.deletedAt != nil || (row.task.ID != id && !sameID(row.task.ParentID, &id)) {
+				continue
+			}
+			if err := t.checkList(ctx, listID); err != nil {
+				return err
+			}
+			row.task.ListID = listID
+			row.task.Version++
+			t.tasks[row.task.ID] = row
+			moved = true
+		}
+		if !moved {
+			return notFound("task")
+		}
+		return t.recordUpdate(ctx, record, before)
+	})
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Delete moves a task and its subtasks to the trash.
+func (r *TaskRepo) Delete(ctx context.Context, task model.TaskModel, record model.Record) error {
+	return r.store.write(func(t *tables) error {
+		row, _ := t.liveTask(ctx, task.ID)
+		before := t.readTask(row)
+		if _, err := t.trashTask(ctx, task.ID, task.Version); err != nil {
+			return err
+		}
+		return t.record(ctx, record, model.Change{Before: &before})
+	})
+}
+
+// ownTasks returns the tasks of the owner of ctx in its workspace, trashed
+// ones too, in the order of their ids.
+func (t *tables) ownTasks(ctx context.Context) []taskRow {
+	owner, workspace := requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)
+	rows := []taskRow{}
+	for _, row := range t.tasks {
+		if row.ownerID == owner && row.workspaceID == workspace {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].task.ID < rows[j].task.ID })
+	return rows
+}
+
+// anyTask finds a task of the owner of ctx in its workspace, trashed or
+// not.
+func (t *tables) anyTask(ctx context.Context, id int64) (taskRow, bool) {
+	row, found := t.tasks[id]
+	if !found || row.ownerID != requestinfo.OwnerID(ctx) || row.workspaceID != requestinfo.WorkspaceID(ctx) {
+		return taskRow{}, false
+	}
+	return row, true
+}
+
+// liveTask is anyTask for tasks out of the trash.
+func (t *tables) liveTask(ctx context.Context, id int64) (taskRow, bool) {
+	row, found := t.anyTask(ctx, id)
+	if !found || row.deletedAt != nil {
+		return taskRow{}, false
+	}
+	return row, true
+}
+
+// liveSubtasks returns the subtasks of a task out of the trash, in their
+// order.
+func (t *tables) liveSubtasks(ctx context.Context, id int64) []taskRow {
+	rows := []taskRow{}
+	for _, row := range t.ownTasks(ctx) {
+		if row.deletedAt == nil && sameID(row.task.ParentID, &id) {
+			rows = append(rows, row)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].task.Position < rows[j].task.Position })
+	return rows
+}
+
+// readTask returns a task with the names of its tags in order.
+func (t *tables) readTask(row taskRow) model.TaskModel {
+	task := row.task
+	task.Tags = nil
+	for _, id := range row.tagIDs {
+		task.Tags = append(task.Tags, t.tags[id].name)
+	}
+	sort.Strings(task.Tags)
+	return task
+}
+
+func (t *tables) readTasks(rows []taskRow) []model.TaskModel {
+	tasks := make([]model.TaskModel, 0, len(rows))
+	for _, row := range rows {
+		tasks = append(tasks, t.readTask(row))
+	}
+	return tasks
+}
+
+// blocked tells whether an open task out of the trash blocks the task.
+func (t *tables) blocked(id int64) bool {
+	for dependency := range t.dependencies {
+		if dependency.task != id {
+			continue
+		}
+		if blocker, found := t.tasks[dependency.blockedBy]; found && !blocker.task.IsDone && blocker.deletedAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// insertTask saves a new task of the owner of ctx in its workspace. A
+// subtask goes after the other subtasks of its parent, every task after
+// every other task of the owner in the manual order.
+func (t *tables) insertTask(ctx context.Context, task model.TaskModel) (model.TaskModel, error) {
+	owner := requestinfo.OwnerID(ctx)
+
+	last := ""
+	for _, row := range t.tasks {
+		if row.ownerID == owner && row.task.Rank > last {
+			last = row.task.Rank
+		}
+	}
+	key, err := rank.After(last)
+	if err != nil {
+		return task, fmt.Errorf("create task: %w", err)
+	}
+	task.Rank = key
+
+	if err := t.checkTask(ctx, task); err != nil {
+		return task, err
+	}
+
+	task.Position = 0
+	if task.ParentID != nil {
+		for _, row := range t.tasks {
+			if sameID(row.task.ParentID, task.ParentID) && row.task.Position+1 > task.Position {
+				task.Position = row.task.Position + 1
+			}
+		}
+	}
+
+	task.ID, task.Version = next(&t.seq.tasks), 1
+	t.saveTask(ctx, task, nil)
+
+	return task, nil
+}
+
+// saveTask stores task as a task of the owner of ctx in its workspace with
+// the tags of task, created for the owner when they do not have them yet.
+func (t *tables) saveTask(ctx context.Context, task model.TaskModel, deletedAt *time.Time) {
+	row := taskRow{
+		ownerID:     requestinfo.OwnerID(ctx),
+		workspaceID: requestinfo.WorkspaceID(ctx),
+		deletedAt:   deletedAt,
+	}
+	row.tagIDs = t.tagIDs(row.ownerID, task.Tags)
+
+	row.task = task
+	row.task.Tags, row.task.Blocked, row.task.DeletedAt = nil, false, nil
+	t.tasks[task.ID] = row
+}
+
+// tagIDs returns the ids of the tags of owner named names, once each.
+func (t *tables) tagIDs(owner int64, names []string) []int64 {
+	ids := []int64{}
+	for _, name := range names {
+		tag, found := t.tagByName(owner, name)
+		if !found {
+			tag = tagRow{id: next(&t.seq.tags), name: name, ownerID: owner}
+			t.tags[tag.id] = tag
+		}
+		if !containsID(ids, tag.id) {
+			ids = append(ids, tag.id)
+		}
+	}
+	return ids
+}
+
+// checkTask enforces the foreign keys of a task of the owner of ctx.
+func (t *tables) checkTask(ctx context.Context, task model.TaskModel) error {
+	if _, found := t.users[requestinfo.OwnerID(ctx)]; !found {
+		return rejected("task")
+	}
+	if _, found := t.workspaces[requestinfo.WorkspaceID(ctx)]; !found {
+		return rejected("task")
+	}
+	if err := checkValues(task); err != nil {
+		return err
+	}
+	if task.ParentID != nil {
+		if _, found := t.tasks[*task.ParentID]; !found {
+			return rejected("task")
+		}
+	}
+	return t.checkList(ctx, task.ListID)
+}
+
+// checkValues enforces the check constraints of the tasks table.
+func checkValues(task model.TaskModel) error {
+	if task.Priority < model.PriorityNone || task.Priority > model.PriorityHigh {
+		return rejected("task")
+	}
+	if task.RemindAt != nil && task.DueAt != nil && task.RemindAt.After(*task.DueAt) {
+		return rejected("task")
+	}
+	switch task.RepeatFrom {
+	case "", model.RepeatFromDue, model.RepeatFromCompletion:
+		return nil
+	}
+	return rejected("task")
+}
+
+// checkList only lets a task in a list of its owner, a list of another user
+// is as good as missing.
+func (t *tables) checkList(ctx context.Context, listID *int64) error {
+	if listID == nil {
+		return nil
+	}
+	if _, found := t.ownList(ctx, *listID); !found {
+		return apperror.New(apperror.ErrValidation, "list not found")
+	}
+	return nil
+}
+
+// updateTask saves task and returns it with its new version. Its subtasks
+// follow it to its list.
+func (t *tables) updateTask(ctx context.Context, task model.TaskModel) (model.TaskModel, error) {
+	row, found := t.liveTask(ctx, task.ID)
+	if !found || (task.Version != 0 && task.Version != row.task.Version) {
+		return task, t.missingOrChanged(ctx, task.ID)
+	}
+	if err := checkValues(task); err != nil {
+		return task, err
+	}
+	if err := t.checkList(ctx, task.ListID); err != nil {
+		return task, err
+	}
+
+	row.task.TaskName = task.TaskName
+	row.task.IsDone = task.IsDone
+	row.task.DueAt = task.DueAt
+	row.task.Timezone = task.Timezone
+	row.task.RemindAt = task.RemindAt
+	row.task.Priority = task.Priority
+	row.task.ListID = task.ListID
+	row.task.RRule = task.RRule
+	row.task.RepeatFrom = task.RepeatFrom
+	row.task.Version++
+	row.tagIDs = t.tagIDs(row.ownerID, task.Tags)
+	t.tasks[task.ID] = row
+	task.Version = row.task.Version
+
+	t.moveSubtasks(ctx, task.ID, task.ListID)
+
+	return task, nil
+}
+
+// moveSubtasks puts the subtasks of a task out of the trash in a list.
+func (t *tables) moveSubtasks(ctx context.Context, id int64, listID *int64) {
+	for _, row := range t.liveSubtasks(ctx, id) {
+		row.task.ListID = listID
+		row.task.Version++
+		t.tasks[row.task.ID] = row
+	}
+}
+
+// missingOrChanged tells why a versioned write matched no task: either the
+// task does not exist or it moved past the expected version.
+func (t *tables) missingOrChanged(ctx context.Context, id int64) error {
+	if _, found := t.liveTask(ctx, id); !found {
+		return notFound("task")
+	}
+	return apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+}
+
+// dropTask deletes a task for good, with its subtasks and everything that
+// points to it.
+func (t *tables) dropTask(id int64) {
+	if _, found := t.tasks[id]; !found {
+		return
+	}
+	delete(t.tasks, id)
+
+	for dependency := range t.dependencies {
+		if dependency.task == id || dependency.blockedBy == id {
+			delete(t.dependencies, dependency)
+		}
+	}
+	for linkID, link := range t.links {
+		if sameID(link.TaskID, &id) {
+			delete(t.links, linkID)
+		}
+	}
+	for subtaskID, row := range t.tasks {
+		if sameID(row.task.ParentID, &id) {
+			t.dropTask(subtaskID)
+		}
+	}
+}
+
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
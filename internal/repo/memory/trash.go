@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// GetTrash returns the trashed tasks, most recently trashed first.
+func (r *TaskRepo) GetTrash(ctx context.Context) ([]model.TaskModel, error) {
+	tasks := []model.TaskModel{}
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.ownTasks(ctx) {
+			if row.deletedAt == nil {
+				continue
+			}
+			task := t.readTask(row)
+			task.DeletedAt = row.deletedAt
+			tasks = append(tasks, task)
+		}
+		return nil
+	})
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].DeletedAt.After(*tasks[j].DeletedAt) })
+	return tasks, err
+}
+
+// Restore takes a task out of the trash together with the subtasks that
+// were trashed with it and returns the restored task. A subtask cannot come
+// back while its parent is in the trash.
+func (r *TaskRepo) Restore(ctx context.Context, id int64, record model.Record) (model.TaskModel, error) {
+	err := r.store.write(func(t *tables) error {
+		if err := t.restoreTask(ctx, id); err != nil {
+			return err
+		}
+		row, _ := t.liveTask(ctx, id)
+		restored := t.readTask(row)
+		return t.record(ctx, record, model.Change{After: &restored})
+	})
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Purge removes the tasks of every user trashed before the given time for
+// good and returns how many it removed. Subtasks going with their parent
+// are not counted.
+func (r *TaskRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := r.store.write(func(t *tables) error {
+		ids := []int64{}
+		for id, row := range t.tasks {
+			if row.deletedAt != nil && row.deletedAt.Before(before) {
+				ids = append(ids, id)
+			}
+		}
+		for _, id := range ids {
+			t.dropTask(id)
+		}
+		purged = int64(len(ids))
+		return nil
+	})
+	return purged, err
+}
+
+// restoreTask takes a trashed task out of the trash, with the subtasks
+// trashed together with it.
+func (t *tables) restoreTask(ctx context.Context, id int64) error {
+	row, found := t.anyTask(ctx, id)
+	if !found || row.deletedAt == nil {
+		return apperror.New(apperror.ErrNotFound, "task not found in trash")
+	}
+	if row.task.ParentID != nil {
+		if parent, found := t.tasks[*row.task.ParentID]; found && parent.deletedAt != nil {
+			return apperror.New(apperror.ErrConflict, "the parent task is in the trash, restore it first")
+		}
+	}
+
+	deletedAt := *row.deletedAt
+	for _, other := range t.ownTasks(ctx) {
+		if other.task.ID != id && !sameID(other.task.ParentID, &id) {
+			continue
+		}
+		if other.deletedAt == nil || !other.deletedAt.Equal(deletedAt) {
+			continue
+		}
+		other.deletedAt = nil
+		other.task.Version++
+		t.tasks[other.task.ID] = other
+	}
+	return nil
+}
+
+// trashTask moves a task and its subtasks to the trash and returns the
+// parent of the task. It follows the same version rule as updateTask.
+func (t *tables) trashTask(ctx context.Context, id, version int64) (*int64, error) {
+	row, found := t.liveTask(ctx, id)
+	if !found || (version != 0 && version != row.task.Version) {
+		return nil, t.missingOrChanged(ctx, id)
+	}
+
+	now := t.now
+	for _, subtask := range t.liveSubtasks(ctx, id) {
+		subtask.deletedAt = &now
+		subtask.task.Version++
+		t.tasks[subtask.task.ID] = subtask
+	}
+	row.deletedAt = &now
+	row.task.Version++
+	t.tasks[id] = row
+
+	return row.task.ParentID, nil
+}
@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/requestinfo"
+)
+
+type userRow struct {
+	id           int64
+	email        string
+	passwordHash string
+	createdAt    time.Time
+}
+
+type refreshTokenRow struct {
+	userID    int64
+	expiresAt time.Time
+}
+
+type apiKeyRow struct {
+	key    model.APIKeyModel
+	userID int64
+}
+
+// apiKeyTouchEvery is how often the use of a key is recorded, see
+// model.TouchAPIKeyQuery.
+const apiKeyTouchEvery = time.Minute
+
+type UserRepo struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepo {
+	return &UserRepo{
+		store: store,
+	}
+}
+
+func (r *UserRepo) Create(ctx context.Context, user model.UserModel) (model.UserModel, error) {
+	err := r.store.write(func(t *tables) error {
+		if _, found := t.userByEmail(user.Email); found {
+			return conflict("user")
+		}
+		user.ID, user.CreatedAt = next(&t.seq.users), t.now
+		t.users[user.ID] = userRow{id: user.ID, email: user.Email, passwordHash: user.PasswordHash, createdAt: user.CreatedAt}
+		return nil
+	})
+	return user, err
+}
+
+func (r *UserRepo) GetByEmail(ctx context.Context, email string) (model.UserModel, error) {
+	var user model.UserModel
+	err := r.store.read(func(t *tables) error {
+		row, found := t.userByEmail(email)
+		if !found {
+			return notFound("user")
+		}
+		user = row.toModel()
+		return nil
+	})
+	return user, err
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, id int64) (model.UserModel, error) {
+	var user model.UserModel
+	err := r.store.read(func(t *tables) error {
+		row, found := t.users[id]
+		if !found {
+			return notFound("user")
+		}
+		user = row.toModel()
+		return nil
+	})
+	return user, err
+}
+
+// SaveRefreshToken stores a new refresh token and drops the expired ones of
+// the same user.
+func (r *UserRepo) SaveRefreshToken(ctx context.Context, token model.RefreshToken) error {
+	return r.store.write(func(t *tables) error {
+		for hash, row := range t.refreshTokens {
+			if row.userID == token.UserID && row.expiresAt.Before(t.now) {
+				delete(t.refreshTokens, hash)
+			}
+		}
+		if _, found := t.users[token.UserID]; !found {
+			return rejected("user")
+		}
+		if _, found := t.refreshTokens[token.Hash]; found {
+			return conflict("user")
+		}
+		t.refreshTokens[token.Hash] = refreshTokenRow{userID: token.UserID, expiresAt: token.ExpiresAt}
+		return nil
+	})
+}
+
+// TakeRefreshToken removes the refresh token with the given hash and
+// returns it. Expired tokens are returned too, the caller checks.
+func (r *UserRepo) TakeRefreshToken(ctx context.Context, hash string) (model.RefreshToken, error) {
+	token := model.RefreshToken{Hash: hash}
+	err := r.store.write(func(t *tables) error {
+		row, found := t.refreshTokens[hash]
+		if !found {
+			return notFound("refresh token")
+		}
+		delete(t.refreshTokens, hash)
+		token.UserID, token.ExpiresAt = row.userID, row.expiresAt
+		return nil
+	})
+	return token, err
+}
+
+// CreateAPIKey stores a key of the signed in user, limited to the workspace
+// of the request.
+func (r *UserRepo) CreateAPIKey(ctx context.Context, key model.APIKeyModel) (model.APIKeyModel, error) {
+	if workspace := requestinfo.WorkspaceID(ctx); workspace != 0 {
+		key.WorkspaceID = &workspace
+	}
+
+	user := requestinfo.UserID(ctx)
+	err := r.store.write(func(t *tables) error {
+		if _, found := t.users[user]; !found {
+			return rejected("api key")
+		}
+		if key.WorkspaceID != nil {
+			if _, found := t.workspaces[*key.WorkspaceID]; !found {
+				return rejected("api key")
+			}
+		}
+		for _, row := range t.apiKeys {
+			if row.key.Hash == key.Hash {
+				return conflict("api key")
+			}
+		}
+		key.ID, key.CreatedAt = next(&t.seq.apiKeys), t.now
+		stored := key
+		stored.Key = ""
+		t.apiKeys[key.ID] = apiKeyRow{key: stored, userID: user}
+		return nil
+	})
+	return key, err
+}
+
+func (r *UserRepo) GetAPIKeys(ctx context.Context) ([]model.APIKeyModel, error) {
+	keys := []model.APIKeyModel{}
+	user := requestinfo.UserID(ctx)
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.apiKeys {
+			if row.userID == user {
+				key := row.key
+				key.Hash = ""
+				keys = append(keys, key)
+			}
+		}
+		return nil
+	})
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, err
+}
+
+// RevokeAPIKey deletes a key of the signed in user.
+func (r *UserRepo) RevokeAPIKey(ctx context.Context, id int64) error {
+	user := requestinfo.UserID(ctx)
+	return r.store.write(func(t *tables) error {
+		row, found := t.apiKeys[id]
+		if !found || row.userID != user {
+			return notFound("api key")
+		}
+		delete(t.apiKeys, id)
+		return nil
+	})
+}
+
+// UseAPIKey returns who the key with the given hash belongs to and records
+// that it was used.
+func (r *UserRepo) UseAPIKey(ctx context.Context, hash string) (model.Principal, error) {
+	var principal model.Principal
+	err := r.store.write(func(t *tables) error {
+		for id, row := range t.apiKeys {
+			if row.key.Hash != hash {
+				continue
+			}
+			principal = model.Principal{
+				UserID:   row.userID,
+				Email:    t.users[row.userID].email,
+				ReadOnly: row.key.ReadOnly,
+			}
+			if row.key.WorkspaceID != nil {
+				principal.WorkspaceID = *row.key.WorkspaceID
+			}
+			if row.key.LastUsedAt == nil || row.key.LastUsedAt.Before(t.now.Add(-apiKeyTouchEvery)) {
+				now := t.now
+				row.key.LastUsedAt = &now
+				t.apiKeys[id] = row
+			}
+			return nil
+		}
+		return notFound("api key")
+	})
+	return principal, err
+}
+
+func (t *tables) userByEmail(email string) (userRow, bool) {
+	for _, row := range t.users {
+		if row.email == email {
+			return row, true
+		}
+	}
+	return userRow{}, false
+}
+
+func (row userRow) toModel() model.UserModel {
+	return model.UserModel{ID: row.id, Email: row.email, PasswordHash: row.passwordHash, CreatedAt: row.createdAt}
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+	model "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+type workspaceRow struct {
+	id        int64
+	name      string
+	slug      string
+	ownerID   int64
+	createdAt time.Time
+}
+
+type WorkspaceRepo struct {
+	store *Store
+}
+
+func NewWorkspaceRepository(store *Store) *WorkspaceRepo {
+	return &WorkspaceRepo{
+		store: store,
+	}
+}
+
+// GetAll returns the workspaces of the signed in user, the default one
+// first.
+func (r *WorkspaceRepo) GetAll(ctx context.Context) ([]model.WorkspaceModel, error) {
+	workspaces := []model.WorkspaceModel{}
+	user := requestinfo.UserID(ctx)
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.workspaces {
+			if t.seesWorkspace(row.id, user) {
+				workspaces = append(workspaces, row.toModel(user))
+			}
+		}
+		return nil
+	})
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].ID < workspaces[j].ID })
+	return workspaces, err
+}
+
+// GetByID returns a workspace of the signed in user. The others are not
+// found.
+func (r *WorkspaceRepo) GetByID(ctx context.Context, id int64) (model.WorkspaceModel, error) {
+	return r.fetch(ctx, func(row workspaceRow) bool { return row.id == id })
+}
+
+// GetBySlug is GetByID for the slug of the workspace.
+func (r *WorkspaceRepo) GetBySlug(ctx context.Context, slug string) (model.WorkspaceModel, error) {
+	return r.fetch(ctx, func(row workspaceRow) bool { return row.slug == slug })
+}
+
+func (r *WorkspaceRepo) fetch(ctx context.Context, match func(row workspaceRow) bool) (model.WorkspaceModel, error) {
+	var workspace model.WorkspaceModel
+	user := requestinfo.UserID(ctx)
+	err := r.store.read(func(t *tables) error {
+		for _, row := range t.workspaces {
+			if match(row) && t.seesWorkspace(row.id, user) {
+				workspace = row.toModel(user)
+				return nil
+			}
+		}
+		return notFound("workspace")
+	})
+	return workspace, err
+}
+
+// Create stores a workspace owned by the signed in user, who becomes its
+// first member.
+func (r *WorkspaceRepo) Create(ctx context.Context, workspace model.WorkspaceModel) (model.WorkspaceModel, error) {
+	user := requestinfo.UserID(ctx)
+	err := r.store.write(func(t *tables) error {
+		for _, row := range t.workspaces {
+			if row.slug == workspace.Slug {
+				return conflict("workspace")
+			}
+		}
+		if _, found := t.users[user]; !found {
+			return rejected("workspace")
+		}
+		workspace.ID, workspace.CreatedAt = next(&t.seq.workspaces), t.now
+		t.workspaces[workspace.ID] = workspaceRow{id: workspace.ID, name: workspace.Name, slug: workspace.Slug, ownerID: user, createdAt: t.now}
+		t.workspaceMembers[memberKey{group: workspace.ID, user: user}] = t.now
+		return nil
+	})
+	workspace.Role = model.RoleOwner
+	return workspace, err
+}
+
+func (r *WorkspaceRepo) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	members := []model.MemberModel{}
+	err := r.store.read(func(t *tables) error {
+		for key, joinedAt := range t.workspaceMembers {
+			if key.group != id {
+				continue
+			}
+			member := model.MemberModel{WorkspaceID: id, UserID: key.user, Email: t.users[key.user].email, Role: model.RoleMember, JoinedAt: joinedAt}
+			if t.workspaces[id].ownerID == key.user {
+				member.Role = model.RoleOwner
+			}
+			members = append(members, member)
+		}
+		return nil
+	})
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].JoinedAt.Equal(members[j].JoinedAt) {
+			return members[i].JoinedAt.Before(members[j].JoinedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+	return members, err
+}
+
+// AddMember adds the user with an email to a workspace, adding a member
+// again changes nothing.
+func (r *WorkspaceRepo) AddMember(ctx context.Context, id int64, email string) (model.MemberModel, error) {
+	member := model.MemberModel{WorkspaceID: id, Email: email, Role: model.RoleMember}
+	err := r.store.write(func(t *tables) error {
+		user, found := t.userByEmail(email)
+		if !found {
+			return apperror.New(apperror.ErrNotFound, "no user with this email")
+		}
+		if _, found := t.workspaces[id]; !found {
+			return rejected("member")
+		}
+		key := memberKey{group: id, user: user.id}
+		if _, found := t.workspaceMembers[key]; !found {
+			t.workspaceMembers[key] = t.now
+		}
+		member.UserID, member.JoinedAt = user.id, t.workspaceMembers[key]
+		return nil
+	})
+	return member, err
+}
+
+// RemoveMember takes a user out of a workspace, never its owner.
+func (r *WorkspaceRepo) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	return r.store.write(func(t *tables) error {
+		key := memberKey{group: id, user: userID}
+		if _, found := t.workspaceMembers[key]; !found || t.workspaces[id].ownerID == userID {
+			return notFound("member")
+		}
+		delete(t.workspaceMembers, key)
+		return nil
+	})
+}
+
+// seesWorkspace tells whether user works in the workspace id, the default
+// one is open to every user.
+func (t *tables) seesWorkspace(id, user int64) bool {
+	if id == model.DefaultID {
+		return true
+	}
+	_, found := t.workspaceMembers[memberKey{group: id, user: user}]
+	return found
+}
+
+func (row workspaceRow) toModel(user int64) model.WorkspaceModel {
+	workspace := model.WorkspaceModel{ID: row.id, Name: row.name, Slug: row.slug, Role: model.RoleMember, CreatedAt: row.createdAt}
+	if row.ownerID != 0 && row.ownerID == user {
+		workspace.Role = model.RoleOwner
+	}
+	return workspace
+}
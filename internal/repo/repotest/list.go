@@ -0,0 +1,255 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+	listmodel "to-do-list/internal/model/list"
+	sharemodel "to-do-list/internal/model/share"
+	tagmodel "to-do-list/internal/model/tag"
+	model "to-do-list/internal/model/task"
+	workspacemodel "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLists(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+	bob := signUp(t, b, "bob@example.com")
+
+	home, err := b.Lists.Create(ctx, listmodel.ListModel{Name: "Home"})
+	noError(t, err)
+	work, err := b.Lists.Create(ctx, listmodel.ListModel{Name: "Work"})
+	noError(t, err)
+	task, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "paint", ListID: &home.ID}, nil)
+	noError(t, err)
+	trashed, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "sand", ListID: &home.ID}, nil)
+	noError(t, err)
+	noError(t, b.Tasks.Delete(ctx, trashed, nil))
+
+	lists, err := b.Lists.GetAll(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, lists, 2) {
+		assert.Equal(t, listmodel.ListModel{ID: home.ID, Name: "Home", TaskCount: 1, Role: listmodel.RoleOwner}, lists[0])
+		assert.Equal(t, work.ID, lists[1].ID)
+	}
+	lists, err = b.Lists.GetAll(bob)
+	assert.NoError(t, err)
+	assert.Empty(t, lists)
+
+	renamed, err := b.Lists.Update(ctx, listmodel.ListModel{ID: home.ID, Name: "House"})
+	assert.NoError(t, err)
+	assert.Equal(t, "House", renamed.Name)
+	assert.Equal(t, int64(1), renamed.TaskCount)
+	_, err = b.Lists.Update(bob, listmodel.ListModel{ID: home.ID, Name: "Bob's"})
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	_, err = b.Lists.GetByID(bob, home.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	_, err = b.Lists.Delete(ctx, home.ID, listmodel.DeleteOptions{Mode: listmodel.DeleteReassign, To: int64Ptr(work.ID + 1000)}, nil)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	_, err = b.Lists.Delete(bob, home.ID, listmodel.DeleteOptions{Mode: listmodel.DeleteCascade}, nil)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	// the live tasks come back as they were, by workspace
+	moved, err := b.Lists.Delete(ctx, home.ID, listmodel.DeleteOptions{Mode: listmodel.DeleteReassign, To: &work.ID}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, moved[workspacemodel.DefaultID], 1) {
+		assert.Equal(t, task.ID, moved[workspacemodel.DefaultID][0].ID)
+		assert.Equal(t, &home.ID, moved[workspacemodel.DefaultID][0].ListID)
+	}
+	got, err := b.Tasks.GetByID(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, &work.ID, got.ListID)
+	assert.Equal(t, task.Version+1, got.Version)
+	_, err = b.Lists.GetByID(ctx, home.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	// a cascade moves the tasks to the trash, out of the deleted list,
+	// and takes those already there out of it too
+	deleted, err := b.Lists.Delete(ctx, work.ID, listmodel.DeleteOptions{Mode: listmodel.DeleteCascade}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64][]model.TaskModel{workspacemodel.DefaultID: {got}}, deleted)
+	_, err = b.Tasks.GetByID(ctx, task.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	trash, err := b.Tasks.GetTrash(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int64{task.ID, trashed.ID}, taskIDs(trash))
+	for _, trashedTask := range trash {
+		assert.Nil(t, trashedTask.ListID)
+	}
+
+	// undoing the deletion brings the task back, in no list
+	results, err := b.Tasks.Rewind(ctx, []model.RewindStep{{TaskID: task.ID, Expected: got.Version + 1, State: &got}}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Nil(t, results[0].After.DeletedAt)
+		assert.Nil(t, results[0].After.ListID)
+	}
+}
+
+func testListMembers(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+	bob := signUp(t, b, "bob@example.com")
+
+	list, err := b.Lists.Create(ctx, listmodel.ListModel{Name: "Home"})
+	noError(t, err)
+	task, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "paint", ListID: &list.ID}, nil)
+	noError(t, err)
+
+	_, err = b.Lists.Invite(ctx, list.ID, listmodel.InviteRequest{Email: "nobody@example.com", Role: listmodel.RoleViewer})
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	_, err = b.Lists.Invite(ctx, list.ID, listmodel.InviteRequest{Email: "ann@example.com", Role: listmodel.RoleViewer})
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	member, err := b.Lists.Invite(ctx, list.ID, listmodel.InviteRequest{Email: "bob@example.com", Role: listmodel.RoleViewer})
+	assert.NoError(t, err)
+	assert.Equal(t, listmodel.RoleViewer, member.Role)
+	assert.Nil(t, member.AcceptedAt)
+
+	invitations, err := b.Lists.GetInvitations(bob)
+	assert.NoError(t, err)
+	if assert.Len(t, invitations, 1) {
+		assert.Equal(t, list.ID, invitations[0].ListID)
+		assert.Equal(t, "Home", invitations[0].ListName)
+	}
+	_, err = b.Lists.ListAccess(bob, list.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	accepted, err := b.Lists.Accept(bob, list.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, accepted.AcceptedAt)
+	_, err = b.Lists.Accept(bob, list.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	access, err := b.Lists.ListAccess(bob, list.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, listmodel.Access{OwnerID: requestinfo.UserID(ctx), Role: listmodel.RoleViewer}, access)
+	access, err = b.Lists.TaskAccess(bob, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, listmodel.RoleViewer, access.Role)
+	access, err = b.Lists.TaskAccess(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, listmodel.RoleOwner, access.Role)
+
+	lists, err := b.Lists.GetAll(bob)
+	assert.NoError(t, err)
+	if assert.Len(t, lists, 1) {
+		assert.Equal(t, listmodel.RoleViewer, lists[0].Role)
+	}
+
+	// a new role keeps the acceptance
+	member, err = b.Lists.Invite(ctx, list.ID, listmodel.InviteRequest{Email: "bob@example.com", Role: listmodel.RoleEditor})
+	assert.NoError(t, err)
+	assert.NotNil(t, member.AcceptedAt)
+	members, err := b.Lists.GetMembers(ctx, list.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, "bob@example.com", members[0].Email)
+		assert.Equal(t, listmodel.RoleEditor, members[0].Role)
+	}
+
+	assert.NoError(t, b.Lists.RemoveMember(ctx, list.ID, member.UserID))
+	assert.ErrorIs(t, b.Lists.RemoveMember(ctx, list.ID, member.UserID), apperror.ErrNotFound)
+	_, err = b.Lists.TaskAccess(bob, task.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func testTags(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+	bob := signUp(t, b, "bob@example.com")
+
+	first, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "milk", Tags: []string{"home", "shop"}}, nil)
+	noError(t, err)
+	second, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "rent", Tags: []string{"home"}}, nil)
+	noError(t, err)
+
+	tags, err := b.Tags.GetAll(ctx)
+	assert.NoError(t, err)
+	if !assert.Len(t, tags, 2) {
+		return
+	}
+	home, shop := tags[0], tags[1]
+	assert.Equal(t, tagmodel.TagModel{ID: home.ID, Name: "home", TaskCount: 2}, home)
+	assert.Equal(t, tagmodel.TagModel{ID: shop.ID, Name: "shop", TaskCount: 1}, shop)
+
+	_, err = b.Tags.Create(ctx, tagmodel.TagModel{Name: "home"})
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	_, err = b.Tags.Create(bob, tagmodel.TagModel{Name: "home"})
+	assert.NoError(t, err)
+
+	renamed, err := b.Tags.Rename(ctx, tagmodel.TagModel{ID: shop.ID, Name: "store"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), renamed.TaskCount)
+	got, err := b.Tasks.GetByID(ctx, first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"home", "store"}, got.Tags)
+	assert.Greater(t, got.Version, first.Version)
+	_, err = b.Tags.Rename(ctx, tagmodel.TagModel{ID: shop.ID, Name: "home"})
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	_, err = b.Tags.Rename(bob, tagmodel.TagModel{ID: shop.ID, Name: "bob's"})
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	_, err = b.Tags.Merge(ctx, home.ID, home.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	merged, err := b.Tags.Merge(ctx, shop.ID, home.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, tagmodel.TagModel{ID: home.ID, Name: "home", TaskCount: 2}, merged)
+	got, err = b.Tasks.GetByID(ctx, first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"home"}, got.Tags)
+
+	assert.NoError(t, b.Tags.Delete(ctx, home))
+	assert.ErrorIs(t, b.Tags.Delete(ctx, home), apperror.ErrNotFound)
+	got, err = b.Tasks.GetByID(ctx, second.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, got.Tags)
+	tags, err = b.Tags.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+}
+
+func testShareLinks(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+	bob := signUp(t, b, "bob@example.com")
+
+	task, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "paint"}, nil)
+	noError(t, err)
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	link, err := b.Shares.Create(ctx, sharemodel.LinkModel{TaskID: &task.ID, ExpiresAt: &expiresAt})
+	assert.NoError(t, err)
+	assert.NotZero(t, link.ID)
+	_, err = b.Shares.Create(ctx, sharemodel.LinkModel{})
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+
+	links, err := b.Shares.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, links, 1)
+	links, err = b.Shares.GetAll(bob)
+	assert.NoError(t, err)
+	assert.Empty(t, links)
+
+	// visitors of a link are not signed in
+	got, err := b.Shares.GetByID(context.Background(), link.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, &task.ID, got.TaskID)
+	assert.True(t, expiresAt.Equal(*got.ExpiresAt))
+
+	assert.ErrorIs(t, b.Shares.Delete(bob, link.ID), apperror.ErrNotFound)
+	assert.NoError(t, b.Shares.Delete(ctx, link.ID))
+	_, err = b.Shares.GetByID(context.Background(), link.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	// links go with what they share
+	list, err := b.Lists.Create(ctx, listmodel.ListModel{Name: "Home"})
+	noError(t, err)
+	link, err = b.Shares.Create(ctx, sharemodel.LinkModel{ListID: &list.ID})
+	noError(t, err)
+	_, err = b.Lists.Delete(ctx, list.ID, listmodel.DeleteOptions{Mode: listmodel.DeleteCascade}, nil)
+	noError(t, err)
+	_, err = b.Shares.GetByID(context.Background(), link.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
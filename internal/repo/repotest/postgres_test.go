@@ -0,0 +1,72 @@
+package repotest_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	list_repo "to-do-list/internal/repo/list"
+	"to-do-list/internal/repo/repotest"
+	share_repo "to-do-list/internal/repo/share"
+	tag_repo "to-do-list/internal/repo/tag"
+	task_repo "to-do-list/internal/repo/task"
+	user_repo "to-do-list/internal/repo/user"
+	workspace_repo "to-do-list/internal/repo/workspace"
+	"to-do-list/pkg/migrate"
+	redis_client "to-do-list/pkg/redis"
+	"to-do-list/schema"
+
+	"github.com/alicebob/miniredis"
+	_ "github.com/lib/pq"
+)
+
+// databaseEnv names the data source of a Postgres database the suite may
+// wipe, the Postgres backend is skipped without it.
+const databaseEnv = "TO_DO_LIST_TEST_DATABASE"
+
+const resetQuery = `TRUNCATE tasks, tags, task_tags, lists, list_members, task_events, task_dependencies, users, refresh_tokens, api_keys, share_links, workspaces, workspace_members RESTART IDENTITY CASCADE;
+INSERT INTO workspaces (id, name, slug) VALUES (1, 'Default', 'default');
+SELECT setval(pg_get_serial_sequence('workspaces', 'id'), 1)`
+
+func TestPostgres(t *testing.T) {
+	source := os.Getenv(databaseEnv)
+	if source == "" {
+		t.Skipf("set %s to run the suite against Postgres", databaseEnv)
+	}
+
+	db, err := sql.Open("postgres", source)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening the database", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, schema.FS)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when loading the migrations", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("an error '%s' was not expected when migrating the database", err)
+	}
+
+	repotest.Run(t, func(t *testing.T) repotest.Backend {
+		if _, err := db.Exec(resetQuery); err != nil {
+			t.Fatalf("an error '%s' was not expected when emptying the database", err)
+		}
+
+		mr, err := miniredis.Run()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+		}
+		t.Cleanup(mr.Close)
+		redis := redis_client.NewRedisClient(mr.Addr(), "")
+
+		return repotest.Backend{
+			Tasks:      task_repo.NewTaskRepository(db, redis),
+			Lists:      list_repo.NewListRepository(db, redis),
+			Tags:       tag_repo.NewTagRepository(db, redis),
+			Shares:     share_repo.NewShareRepository(db),
+			Users:      user_repo.NewUserRepository(db),
+			Workspaces: workspace_repo.NewWorkspaceRepository(db),
+		}
+	})
+}
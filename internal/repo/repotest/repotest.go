@@ -0,0 +1,104 @@
+// Package repotest is the conformance suite of the storage backends. Every
+// backend runs it against fresh storage, so the usecases see the same
+// scoping, versions, errors and cascades whichever backend serves them.
+package repotest
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/task"
+	usermodel "to-do-list/internal/model/user"
+	workspacemodel "to-do-list/internal/model/workspace"
+	list_usecase "to-do-list/internal/usecase/list"
+	share_usecase "to-do-list/internal/usecase/share"
+	tag_usecase "to-do-list/internal/usecase/tag"
+	usecase "to-do-list/internal/usecase/task"
+	user_usecase "to-do-list/internal/usecase/user"
+	workspace_usecase "to-do-list/internal/usecase/workspace"
+	"to-do-list/pkg/requestinfo"
+)
+
+// Tasks is the task repo of the usecase with its audit log, which the
+// suite also fills with AppendEvents.
+type Tasks interface {
+	usecase.Repo
+	usecase.EventRepo
+	AppendEvents(ctx context.Context, events ...model.TaskEvent) error
+}
+
+// Lists is the list repo of the usecase, which also tells the task usecase
+// who may reach a task.
+type Lists interface {
+	list_usecase.Repo
+	usecase.Sharing
+}
+
+// Backend holds the repos of one backend, all on the same storage.
+type Backend struct {
+	Tasks      Tasks
+	Lists      Lists
+	Tags       tag_usecase.Repo
+	Shares     share_usecase.Repo
+	Users      user_usecase.Repo
+	Workspaces workspace_usecase.Repo
+}
+
+// Run runs the suite, each test on the empty storage of a new backend with
+// only the default workspace.
+func Run(t *testing.T, newBackend func(t *testing.T) Backend) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, b Backend)
+	}{
+		{"tasks", testTasks},
+		{"task scoping", testTaskScoping},
+		{"task filters and pages", testTaskPages},
+		{"subtasks", testSubtasks},
+		{"trash", testTrash},
+		{"dependencies", testDependencies},
+		{"batch", testBatch},
+		{"manual order", testManualOrder},
+		{"events", testEvents},
+		{"recorded events", testRecordedEvents},
+		{"rewind", testRewind},
+		{"lists", testLists},
+		{"list members", testListMembers},
+		{"tags", testTags},
+		{"share links", testShareLinks},
+		{"users", testUsers},
+		{"api keys", testAPIKeys},
+		{"workspaces", testWorkspaces},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newBackend(t))
+		})
+	}
+}
+
+// signUp creates a user and returns a context of their requests in the
+// default workspace.
+func signUp(t *testing.T, b Backend, email string) context.Context {
+	t.Helper()
+
+	user, err := b.Users.Create(context.Background(), usermodel.UserModel{Email: email, PasswordHash: "hash"})
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating a user", err)
+	}
+
+	ctx := requestinfo.WithUserID(context.Background(), user.ID)
+	return requestinfo.WithWorkspaceID(ctx, workspacemodel.DefaultID)
+}
+
+// noError stops the test on an error of a step the rest of it builds on.
+func noError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected", err)
+	}
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
@@ -0,0 +1,658 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	workspacemodel "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTasks(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+
+	task, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "write", Tags: []string{"b", "a"}, Priority: model.PriorityHigh}, nil)
+	noError(t, err)
+	assert.NotZero(t, task.ID)
+	assert.Equal(t, int64(1), task.Version)
+	assert.NotEmpty(t, task.Rank)
+
+	got, err := b.Tasks.GetByID(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "write", got.TaskName)
+	assert.Equal(t, []string{"a", "b"}, got.Tags)
+	assert.Equal(t, model.PriorityHigh, got.Priority)
+
+	got.TaskName, got.Tags = "rewrite", []string{"c"}
+	updated, err := b.Tasks.Update(ctx, got, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	_, err = b.Tasks.Update(ctx, got, nil)
+	assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+
+	assert.NoError(t, b.Tasks.SetDone(ctx, task.ID, true, nil))
+	got, err = b.Tasks.GetByID(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "rewrite", got.TaskName)
+	assert.Equal(t, []string{"c"}, got.Tags)
+	assert.True(t, got.IsDone)
+	assert.Equal(t, int64(3), got.Version)
+
+	_, err = b.Tasks.Update(ctx, model.TaskModel{ID: task.ID + 1000, TaskName: "missing"}, nil)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.ErrorIs(t, b.Tasks.SetDone(ctx, task.ID+1000, true, nil), apperror.ErrNotFound)
+
+	due := time.Now().Add(time.Hour)
+	remind := due.Add(time.Hour)
+	_, err = b.Tasks.Create(ctx, model.TaskModel{TaskName: "late reminder", DueAt: &due, RemindAt: &remind}, nil)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+
+	assert.ErrorIs(t, b.Tasks.Delete(ctx, model.TaskModel{ID: task.ID, Version: 1}, nil), apperror.ErrPreconditionFailed)
+	assert.NoError(t, b.Tasks.Delete(ctx, model.TaskModel{ID: task.ID}, nil))
+	_, err = b.Tasks.GetByID(ctx, task.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func testTaskScoping(t *testing.T, b Backend) {
+	ann := signUp(t, b, "ann@example.com")
+	bob := signUp(t, b, "bob@example.com")
+
+	task, err := b.Tasks.Create(ann, model.TaskModel{TaskName: "ann's"}, nil)
+	noError(t, err)
+
+	_, err = b.Tasks.GetByID(bob, task.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	_, err = b.Tasks.Update(bob, model.TaskModel{ID: task.ID, TaskName: "bob's"}, nil)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.ErrorIs(t, b.Tasks.Delete(bob, model.TaskModel{ID: task.ID}, nil), apperror.ErrNotFound)
+	page, err := b.Tasks.GetAll(bob, model.TaskFilter{Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Tasks)
+
+	workspace, err := b.Workspaces.Create(ann, workspacemodel.WorkspaceModel{Name: "Team", Slug: "team"})
+	noError(t, err)
+	team := requestinfo.WithWorkspaceID(ann, workspace.ID)
+
+	_, err = b.Tasks.GetByID(team, task.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	teamTask, err := b.Tasks.Create(team, model.TaskModel{TaskName: "team's"}, nil)
+	assert.NoError(t, err)
+
+	page, err = b.Tasks.GetAll(ann, model.TaskFilter{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{task.ID}, taskIDs(page.Tasks))
+	page, err = b.Tasks.GetAll(team, model.TaskFilter{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{teamTask.ID}, taskIDs(page.Tasks))
+
+	list, err := b.Lists.Create(bob, listmodel.ListModel{Name: "bob's"})
+	noError(t, err)
+	_, err = b.Tasks.Create(ann, model.TaskModel{TaskName: "in bob's list", ListID: &list.ID}, nil)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	_, err = b.Tasks.Create(ann, model.TaskModel{TaskName: "in no list", ListID: int64Ptr(list.ID + 1000)}, nil)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+}
+
+func testTaskPages(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+
+	base := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	soon, later := base.Add(time.Hour), base.Add(48*time.Hour)
+	tasks := []model.TaskModel{
+		{TaskName: "Buy milk", Priority: model.PriorityLow, Tags: []string{"home"}, DueAt: &soon},
+		{TaskName: "buy bread", Priority: model.PriorityHigh, IsDone: true, Tags: []string{"home", "shop"}},
+		{TaskName: "call mom"},
+		{TaskName: "pay rent", Priority: model.PriorityHigh, Tags: []string{"home"}, DueAt: &later},
+		{TaskName: "walk", Priority: model.PriorityMedium, IsDone: true},
+	}
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		created, err := b.Tasks.Create(ctx, task, nil)
+		noError(t, err)
+		ids[i] = created.ID
+	}
+
+	high, open := model.PriorityHigh, false
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   []int64
+	}{
+		{"case 1 -> every task by id", model.TaskFilter{}, ids},
+		{"case 2 -> open tasks", model.TaskFilter{IsDone: &open}, []int64{ids[0], ids[2], ids[3]}},
+		{"case 3 -> name contains the query in any case", model.TaskFilter{Query: "BUY"}, []int64{ids[0], ids[1]}},
+		{"case 4 -> tasks carrying every tag", model.TaskFilter{Tags: []string{"home", "shop"}}, []int64{ids[1]}},
+		{"case 5 -> priority", model.TaskFilter{Priority: &high}, []int64{ids[1], ids[3]}},
+		{"case 6 -> due window", model.TaskFilter{DueFrom: &base, DueTo: timePtr(base.Add(24 * time.Hour))}, []int64{ids[0]}},
+		{"case 7 -> sorted by priority, the id breaks ties", model.TaskFilter{Sort: []model.SortField{{Field: "priority", Desc: true}}}, []int64{ids[1], ids[3], ids[4], ids[0], ids[2]}},
+		{"case 8 -> tasks without a deadline last", model.TaskFilter{Sort: []model.SortField{{Field: "due_at"}}}, []int64{ids[0], ids[3], ids[1], ids[2], ids[4]}},
+		{"case 9 -> tasks without a deadline first backwards", model.TaskFilter{Sort: []model.SortField{{Field: "due_at", Desc: true}}}, []int64{ids[1], ids[2], ids[4], ids[3], ids[0]}},
+		{"case 10 -> manual order", model.TaskFilter{Sort: []model.SortField{{Field: "rank"}}}, ids},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// two tasks a page, so every test crosses pages
+			filter, got := tt.filter, []int64{}
+			filter.Limit = 2
+			for pages := 0; pages < len(ids); pages++ {
+				page, err := b.Tasks.GetAll(ctx, filter)
+				if !assert.NoError(t, err) {
+					return
+				}
+				got = append(got, taskIDs(page.Tasks)...)
+				if page.NextCursor == "" {
+					break
+				}
+				filter.Cursor = page.NextCursor
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	page, err := b.Tasks.GetAll(ctx, model.TaskFilter{Limit: 10, Tags: []string{"shop"}})
+	assert.NoError(t, err)
+	if assert.Len(t, page.Tasks, 1) {
+		assert.Equal(t, []string{"home", "shop"}, page.Tasks[0].Tags)
+	}
+
+	_, err = b.Tasks.GetAll(ctx, model.TaskFilter{Limit: 10, Sort: []model.SortField{{Field: "owner_id"}}})
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	_, err = b.Tasks.GetAll(ctx, model.TaskFilter{Limit: 10, Cursor: "not a cursor"})
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+
+	page, err = b.Tasks.GetAll(ctx, model.TaskFilter{Limit: 1, Sort: []model.SortField{{Field: "priority"}}})
+	noError(t, err)
+	_, err = b.Tasks.GetAll(ctx, model.TaskFilter{Limit: 1, Cursor: page.NextCursor})
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+}
+
+func testSubtasks(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+
+	list, err := b.Lists.Create(ctx, listmodel.ListModel{Name: "Home"})
+	noError(t, err)
+	parent, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "move out"}, nil)
+	noError(t, err)
+	first, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "pack", ParentID: &parent.ID}, nil)
+	noError(t, err)
+	second, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "clean", ParentID: &parent.ID}, nil)
+	noError(t, err)
+	assert.Equal(t, 0, first.Position)
+	assert.Equal(t, 1, second.Position)
+
+	_, err = b.Tasks.Create(ctx, model.TaskModel{TaskName: "orphan", ParentID: int64Ptr(second.ID + 1000)}, nil)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+
+	subtasks, err := b.Tasks.GetSubtasks(ctx, parent.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{first.ID, second.ID}, taskIDs(subtasks))
+
+	assert.NoError(t, b.Tasks.Reorder(ctx, parent.ID, []int64{second.ID, first.ID}, nil))
+	subtasks, err = b.Tasks.GetSubtasks(ctx, parent.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{second.ID, first.ID}, taskIDs(subtasks))
+	assert.ErrorIs(t, b.Tasks.Reorder(ctx, parent.ID, []int64{first.ID}, nil), apperror.ErrValidation)
+	assert.ErrorIs(t, b.Tasks.Reorder(ctx, parent.ID, []int64{first.ID, first.ID}, nil), apperror.ErrValidation)
+
+	moved, err := b.Tasks.Move(ctx, parent.ID, &list.ID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &list.ID, moved.ListID)
+	got, err := b.Tasks.GetByID(ctx, first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, &list.ID, got.ListID)
+
+	moved.ListID = nil
+	_, err = b.Tasks.Update(ctx, moved, nil)
+	assert.NoError(t, err)
+	got, err = b.Tasks.GetByID(ctx, second.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, got.ListID)
+
+	_, err = b.Tasks.Move(ctx, parent.ID+1000, nil, nil)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func testTrash(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+
+	parent, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "move out"}, nil)
+	noError(t, err)
+	subtask, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "pack", ParentID: &parent.ID}, nil)
+	noError(t, err)
+	other, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "walk"}, nil)
+	noError(t, err)
+
+	assert.NoError(t, b.Tasks.Delete(ctx, parent, nil))
+	trash, err := b.Tasks.GetTrash(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{parent.ID, subtask.ID}, taskIDs(trash))
+	for _, task := range trash {
+		assert.NotNil(t, task.DeletedAt)
+	}
+	page, err := b.Tasks.GetAll(ctx, model.TaskFilter{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{other.ID}, taskIDs(page.Tasks))
+
+	_, err = b.Tasks.Restore(ctx, subtask.ID, nil)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	_, err = b.Tasks.Restore(ctx, other.ID, nil)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	restored, err := b.Tasks.Restore(ctx, parent.ID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, parent.ID, restored.ID)
+	_, err = b.Tasks.GetByID(ctx, subtask.ID)
+	assert.NoError(t, err)
+
+	// a subtask trashed on its own stays in the trash
+	assert.NoError(t, b.Tasks.Delete(ctx, model.TaskModel{ID: subtask.ID}, nil))
+	assert.NoError(t, b.Tasks.Delete(ctx, model.TaskModel{ID: parent.ID}, nil))
+	_, err = b.Tasks.Restore(ctx, parent.ID, nil)
+	assert.NoError(t, err)
+	trash, err = b.Tasks.GetTrash(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{subtask.ID}, taskIDs(trash))
+
+	purged, err := b.Tasks.Purge(ctx, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	trash, err = b.Tasks.GetTrash(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+}
+
+func testDependencies(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+	bob := signUp(t, b, "bob@example.com")
+
+	task, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "paint"}, nil)
+	noError(t, err)
+	blocker, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "buy paint"}, nil)
+	noError(t, err)
+	dependency := model.Dependency{TaskID: task.ID, BlockedBy: blocker.ID}
+
+	assert.NoError(t, b.Tasks.AddDependency(ctx, dependency, noCheck))
+	assert.NoError(t, b.Tasks.AddDependency(ctx, dependency, noCheck))
+	assert.ErrorIs(t, b.Tasks.AddDependency(ctx, model.Dependency{TaskID: task.ID, BlockedBy: task.ID}, noCheck), apperror.ErrValidation)
+	assert.ErrorIs(t, b.Tasks.AddDependency(ctx, model.Dependency{TaskID: task.ID, BlockedBy: blocker.ID + 1000}, noCheck), apperror.ErrNotFound)
+	assert.ErrorIs(t, b.Tasks.AddDependency(bob, model.Dependency{TaskID: blocker.ID, BlockedBy: task.ID}, noCheck), apperror.ErrNotFound)
+
+	// the check sees the dependencies of the owner and can cancel the new one
+	var checked []model.Dependency
+	err = b.Tasks.AddDependency(ctx, model.Dependency{TaskID: blocker.ID, BlockedBy: task.ID}, func(dependencies []model.Dependency) error {
+		checked = dependencies
+		return apperror.New(apperror.ErrConflict, "dependency would create a cycle")
+	})
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	assert.Equal(t, []model.Dependency{dependency}, checked)
+
+	dependencies, err := b.Tasks.GetDependencies(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Dependency{dependency}, dependencies)
+
+	open, err := b.Tasks.GetOpenTasks(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, open, 2) {
+		assert.True(t, open[0].Blocked)
+		assert.False(t, open[1].Blocked)
+	}
+	blockers, err := b.Tasks.GetBlockers(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{blocker.ID}, taskIDs(blockers))
+
+	assert.NoError(t, b.Tasks.SetDone(ctx, blocker.ID, true, nil))
+	open, err = b.Tasks.GetOpenTasks(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, open, 1) {
+		assert.False(t, open[0].Blocked)
+	}
+
+	// the dependency of a trashed blocker stays for when it comes back
+	assert.NoError(t, b.Tasks.Delete(ctx, model.TaskModel{ID: blocker.ID}, nil))
+	blockers, err = b.Tasks.GetBlockers(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, blockers)
+	dependencies, err = b.Tasks.GetDependencies(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, dependencies, 1)
+
+	assert.ErrorIs(t, b.Tasks.RemoveDependency(bob, dependency), apperror.ErrNotFound)
+	assert.NoError(t, b.Tasks.RemoveDependency(ctx, dependency))
+	assert.ErrorIs(t, b.Tasks.RemoveDependency(ctx, dependency), apperror.ErrNotFound)
+}
+
+func noCheck([]model.Dependency) error { return nil }
+
+func testBatch(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+
+	task, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "walk"}, nil)
+	noError(t, err)
+	ops := []model.BatchOperation{
+		{Op: model.BatchCreate, Task: &model.TaskModel{TaskName: "run"}},
+		{Op: model.BatchUpdate, ID: task.ID, Version: 5, Task: &model.TaskModel{TaskName: "stroll"}},
+		{Op: model.BatchComplete, ID: task.ID},
+	}
+
+	results, err := b.Tasks.Batch(ctx, ops, model.BatchOptions{AllOrNothing: true}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 3) {
+		assert.NoError(t, results[0].Err)
+		assert.ErrorIs(t, results[1].Err, apperror.ErrPreconditionFailed)
+	}
+	page, err := b.Tasks.GetAll(ctx, model.TaskFilter{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{task.ID}, taskIDs(page.Tasks))
+
+	results, err = b.Tasks.Batch(ctx, ops, model.BatchOptions{}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 3) {
+		assert.NoError(t, results[0].Err)
+		assert.ErrorIs(t, results[1].Err, apperror.ErrPreconditionFailed)
+		assert.NoError(t, results[2].Err)
+		if assert.NotNil(t, results[2].Task) && assert.NotNil(t, results[2].Before) {
+			assert.True(t, results[2].Task.IsDone)
+			assert.False(t, results[2].Before.IsDone)
+		}
+	}
+	page, err = b.Tasks.GetAll(ctx, model.TaskFilter{Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, page.Tasks, 2)
+
+	parent, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "move out"}, nil)
+	noError(t, err)
+	subtask, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "pack", ParentID: &parent.ID}, nil)
+	noError(t, err)
+
+	results, err = b.Tasks.Batch(ctx, []model.BatchOperation{{Op: model.BatchComplete, ID: parent.ID}}, model.BatchOptions{AllOrNothing: true, BlockOpenSubtasks: true}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.ErrorIs(t, results[0].Err, apperror.ErrConflict)
+	}
+
+	// a subtask repeats with its parent only
+	repeat := subtask
+	repeat.RRule = "FREQ=DAILY"
+	results, err = b.Tasks.Batch(ctx, []model.BatchOperation{{Op: model.BatchUpdate, ID: subtask.ID, Task: &repeat}}, model.BatchOptions{AllOrNothing: true}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.ErrorIs(t, results[0].Err, apperror.ErrValidation)
+	}
+
+	// the parent follows its last subtask
+	_, err = b.Tasks.Batch(ctx, []model.BatchOperation{{Op: model.BatchComplete, ID: subtask.ID}}, model.BatchOptions{AllOrNothing: true, BlockOpenSubtasks: true}, nil)
+	assert.NoError(t, err)
+	got, err := b.Tasks.GetByID(ctx, parent.ID)
+	assert.NoError(t, err)
+	assert.True(t, got.IsDone)
+
+	results, err = b.Tasks.Batch(ctx, []model.BatchOperation{{Op: model.BatchDelete, ID: subtask.ID}}, model.BatchOptions{AllOrNothing: true}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.NoError(t, results[0].Err)
+		assert.Nil(t, results[0].Task)
+		assert.NotNil(t, results[0].Before)
+	}
+	_, err = b.Tasks.GetByID(ctx, subtask.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func testManualOrder(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+
+	ids := make([]int64, 3)
+	for i, name := range []string{"a", "b", "c"} {
+		task, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: name}, nil)
+		noError(t, err)
+		ids[i] = task.ID
+	}
+	order := func() []int64 {
+		page, err := b.Tasks.GetAll(ctx, model.TaskFilter{Limit: 10, Sort: []model.SortField{{Field: "rank"}}})
+		assert.NoError(t, err)
+		return taskIDs(page.Tasks)
+	}
+
+	moved, err := b.Tasks.Reposition(ctx, ids[2], model.RepositionRequest{Before: &ids[0]}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), moved.Version)
+	assert.Equal(t, []int64{ids[2], ids[0], ids[1]}, order())
+
+	_, err = b.Tasks.Reposition(ctx, ids[0], model.RepositionRequest{After: &ids[1]}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{ids[2], ids[1], ids[0]}, order())
+
+	_, err = b.Tasks.Reposition(ctx, ids[0], model.RepositionRequest{}, nil)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	_, err = b.Tasks.Reposition(ctx, ids[0], model.RepositionRequest{After: int64Ptr(ids[2] + 1000)}, nil)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	subtask, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "d", ParentID: &ids[0]}, nil)
+	noError(t, err)
+	_, err = b.Tasks.Reposition(ctx, subtask.ID, model.RepositionRequest{After: &ids[1]}, nil)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	spread, err := b.Tasks.Rebalance(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, spread)
+}
+
+func testEvents(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+	bob := signUp(t, b, "bob@example.com")
+
+	task, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "draft"}, nil)
+	noError(t, err)
+	updated := task
+	updated.TaskName, updated.Version = "final", 2
+
+	noError(t, b.Tasks.AppendEvents(ctx,
+		model.TaskEvent{TaskID: task.ID, Action: model.EventCreate, After: &task, Actor: "ann", RequestID: "r1"},
+		model.TaskEvent{TaskID: task.ID, Action: model.EventUpdate, Before: &task, After: &updated, Actor: "ann", RequestID: "r1"},
+	))
+
+	page, err := b.Tasks.GetEvents(ctx, model.EventFilter{Limit: 1})
+	assert.NoError(t, err)
+	if !assert.Len(t, page.Events, 1) {
+		return
+	}
+	update := page.Events[0]
+	assert.Equal(t, model.EventUpdate, update.Action)
+	assert.Equal(t, "final", update.After.TaskName)
+	assert.Equal(t, "draft", update.Before.TaskName)
+	assert.Equal(t, "r1", update.RequestID)
+	assert.False(t, update.CreatedAt.IsZero())
+
+	page, err = b.Tasks.GetEvents(ctx, model.EventFilter{Limit: 1, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	if !assert.Len(t, page.Events, 1) {
+		return
+	}
+	create := page.Events[0]
+	assert.Equal(t, model.EventCreate, create.Action)
+	assert.Nil(t, create.Before)
+	assert.Less(t, create.ID, update.ID)
+	assert.Empty(t, page.NextCursor)
+
+	page, err = b.Tasks.GetEvents(ctx, model.EventFilter{Limit: 10, Action: model.EventCreate, TaskID: &task.ID, Actor: "ann"})
+	assert.NoError(t, err)
+	assert.Len(t, page.Events, 1)
+	page, err = b.Tasks.GetEvents(bob, model.EventFilter{Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Events)
+	_, err = b.Tasks.GetEvents(ctx, model.EventFilter{Limit: 10, Cursor: "x"})
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+
+	ann, bobID := requestinfo.UserID(ctx), requestinfo.UserID(bob)
+	undoable, err := b.Tasks.GetUndoableEvents(ctx, ann)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{update.ID, create.ID}, eventIDs(undoable))
+	assert.Equal(t, ann, undoable[0].OwnerID)
+
+	// an undo is not undone, and what it undid is not undone again
+	noError(t, b.Tasks.AppendEvents(ctx, model.TaskEvent{TaskID: task.ID, Action: model.EventUpdate, Before: &updated, After: &task, Actor: "ann", Reverts: update.ID}))
+	undoable, err = b.Tasks.GetUndoableEvents(ctx, ann)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{create.ID}, eventIDs(undoable))
+	undoable, err = b.Tasks.GetUndoableEvents(ctx, bobID)
+	assert.NoError(t, err)
+	assert.Empty(t, undoable)
+
+	// the change of a member to a task of ann is undone by the member, as ann
+	noError(t, b.Tasks.AppendEvents(requestinfo.WithOwnerID(bob, ann), model.TaskEvent{TaskID: task.ID, Action: model.EventUpdate, Before: &task, After: &updated, Actor: "bob", RequestID: "r2"}))
+	undoable, err = b.Tasks.GetUndoableEvents(bob, bobID)
+	assert.NoError(t, err)
+	if assert.Len(t, undoable, 1) {
+		assert.Equal(t, "r2", undoable[0].RequestID)
+		assert.Equal(t, ann, undoable[0].OwnerID)
+	}
+	undoable, err = b.Tasks.GetUndoableEvents(ctx, ann)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{create.ID}, eventIDs(undoable))
+
+	snapshot, err := b.Tasks.GetSnapshot(ctx, task.ID, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "final", snapshot.TaskName)
+	_, err = b.Tasks.GetSnapshot(ctx, task.ID, 9)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	_, err = b.Tasks.GetSnapshot(bob, task.ID, 2)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func testRecordedEvents(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+
+	task, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "draft"}, record(model.EventCreate, 0))
+	noError(t, err)
+	changed := task
+	changed.TaskName = "final"
+	changed, err = b.Tasks.Update(ctx, changed, record(model.EventUpdate, 0))
+	noError(t, err)
+
+	page, err := b.Tasks.GetEvents(ctx, model.EventFilter{Limit: 10})
+	noError(t, err)
+	if !assert.Len(t, page.Events, 2) {
+		return
+	}
+	update := page.Events[0]
+	assert.Equal(t, "draft", update.Before.TaskName)
+	assert.Equal(t, changed.Version, update.After.Version)
+	assert.Equal(t, task.ID, page.Events[1].After.ID)
+
+	// a write whose events are not saved is not saved either, nor is a
+	// failed write recorded
+	_, err = b.Tasks.Update(ctx, model.TaskModel{ID: task.ID, TaskName: "lost", Timezone: "UTC", Version: changed.Version}, record(model.EventUpdate, update.ID+1000))
+	assert.Error(t, err)
+	assert.Error(t, b.Tasks.Delete(ctx, changed, record(model.EventDelete, update.ID+1000)))
+	_, err = b.Tasks.Update(ctx, task, record(model.EventUpdate, 0))
+	assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+	got, err := b.Tasks.GetByID(ctx, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "final", got.TaskName)
+
+	// a nil record records nothing
+	noError(t, b.Tasks.Delete(ctx, changed, nil))
+	restored, err := b.Tasks.Restore(ctx, task.ID, record(model.EventRestore, 0))
+	noError(t, err)
+
+	page, err = b.Tasks.GetEvents(ctx, model.EventFilter{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, page.Events, 3) {
+		assert.Equal(t, model.EventRestore, page.Events[0].Action)
+		assert.Nil(t, page.Events[0].Before)
+		assert.Equal(t, restored.Version, page.Events[0].After.Version)
+	}
+}
+
+// record describes every change as action, one undoing the event reverts
+// when it is not 0.
+func record(action string, reverts int64) model.Record {
+	return func(changes []model.Change) []model.TaskEvent {
+		events := make([]model.TaskEvent, len(changes))
+		for i, change := range changes {
+			events[i] = model.TaskEvent{Action: action, Before: change.Before, After: change.After, Actor: "ann", Reverts: reverts}
+			if change.After != nil {
+				events[i].TaskID = change.After.ID
+			} else {
+				events[i].TaskID = change.Before.ID
+			}
+		}
+		return events
+	}
+}
+
+func testRewind(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+
+	draft, err := b.Tasks.Create(ctx, model.TaskModel{TaskName: "draft", Tags: []string{"a"}}, nil)
+	noError(t, err)
+	final := draft
+	final.TaskName, final.Tags = "final", []string{"b"}
+	final, err = b.Tasks.Update(ctx, final, nil)
+	noError(t, err)
+
+	results, err := b.Tasks.Rewind(ctx, []model.RewindStep{{TaskID: draft.ID, Expected: final.Version, State: &draft}}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) && assert.NotNil(t, results[0].Before) {
+		assert.Equal(t, "final", results[0].Before.TaskName)
+		assert.Equal(t, "draft", results[0].After.TaskName)
+		assert.Equal(t, []string{"a"}, results[0].After.Tags)
+		assert.Equal(t, final.Version+1, results[0].After.Version)
+	}
+
+	_, err = b.Tasks.Rewind(ctx, []model.RewindStep{{TaskID: draft.ID, Expected: final.Version, State: &draft}}, nil)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+
+	results, err = b.Tasks.Rewind(ctx, []model.RewindStep{{TaskID: draft.ID}}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.NotNil(t, results[0].After.DeletedAt)
+	}
+	_, err = b.Tasks.GetByID(ctx, draft.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	// a failing step undoes the steps before it
+	_, err = b.Tasks.Rewind(ctx, []model.RewindStep{{TaskID: draft.ID, State: &draft}, {TaskID: draft.ID + 1000}}, nil)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	_, err = b.Tasks.GetByID(ctx, draft.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	_, err = b.Tasks.Purge(ctx, time.Now().Add(time.Minute))
+	noError(t, err)
+	results, err = b.Tasks.Rewind(ctx, []model.RewindStep{{TaskID: draft.ID, State: &final}}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Nil(t, results[0].Before)
+		assert.Equal(t, draft.ID, results[0].After.ID)
+	}
+	got, err := b.Tasks.GetByID(ctx, draft.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "final", got.TaskName)
+	assert.Equal(t, []string{"b"}, got.Tags)
+	assert.Equal(t, final.Version+1, got.Version)
+}
+
+func taskIDs(tasks []model.TaskModel) []int64 {
+	ids := []int64{}
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func eventIDs(events []model.TaskEvent) []int64 {
+	ids := []int64{}
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func timePtr(v time.Time) *time.Time {
+	return &v
+}
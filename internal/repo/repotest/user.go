@@ -0,0 +1,132 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/user"
+	workspacemodel "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testUsers(t *testing.T, b Backend) {
+	ctx := context.Background()
+
+	user, err := b.Users.Create(ctx, model.UserModel{Email: "ann@example.com", PasswordHash: "hash"})
+	assert.NoError(t, err)
+	assert.NotZero(t, user.ID)
+	assert.False(t, user.CreatedAt.IsZero())
+	_, err = b.Users.Create(ctx, model.UserModel{Email: "ann@example.com", PasswordHash: "other"})
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+
+	got, err := b.Users.GetByEmail(ctx, "ann@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, got.ID)
+	assert.Equal(t, "hash", got.PasswordHash)
+	got, err = b.Users.GetByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "ann@example.com", got.Email)
+	_, err = b.Users.GetByID(ctx, user.ID+1000)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	_, err = b.Users.GetByEmail(ctx, "bob@example.com")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	// a refresh token is used once
+	expiresAt := time.Now().Add(time.Hour)
+	assert.NoError(t, b.Users.SaveRefreshToken(ctx, model.RefreshToken{Hash: "token", UserID: user.ID, ExpiresAt: expiresAt}))
+	token, err := b.Users.TakeRefreshToken(ctx, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, token.UserID)
+	assert.WithinDuration(t, expiresAt, token.ExpiresAt, time.Second)
+	_, err = b.Users.TakeRefreshToken(ctx, "token")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func testAPIKeys(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+	bob := signUp(t, b, "bob@example.com")
+
+	key, err := b.Users.CreateAPIKey(ctx, model.APIKeyModel{Name: "ci", Prefix: "tdl_abc", Hash: "hash", ReadOnly: true})
+	assert.NoError(t, err)
+	assert.NotZero(t, key.ID)
+	assert.Equal(t, int64Ptr(workspacemodel.DefaultID), key.WorkspaceID)
+
+	principal, err := b.Users.UseAPIKey(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, model.Principal{UserID: requestinfo.UserID(ctx), Email: "ann@example.com", ReadOnly: true, WorkspaceID: workspacemodel.DefaultID}, principal)
+	_, err = b.Users.UseAPIKey(context.Background(), "other")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	keys, err := b.Users.GetAPIKeys(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.Equal(t, "tdl_abc", keys[0].Prefix)
+		assert.Empty(t, keys[0].Hash)
+		assert.NotNil(t, keys[0].LastUsedAt)
+	}
+	keys, err = b.Users.GetAPIKeys(bob)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	assert.ErrorIs(t, b.Users.RevokeAPIKey(bob, key.ID), apperror.ErrNotFound)
+	assert.NoError(t, b.Users.RevokeAPIKey(ctx, key.ID))
+	_, err = b.Users.UseAPIKey(context.Background(), "hash")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func testWorkspaces(t *testing.T, b Backend) {
+	ctx := signUp(t, b, "ann@example.com")
+	bob := signUp(t, b, "bob@example.com")
+
+	workspaces, err := b.Workspaces.GetAll(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, workspaces, 1) {
+		assert.Equal(t, int64(workspacemodel.DefaultID), workspaces[0].ID)
+		assert.Equal(t, workspacemodel.RoleMember, workspaces[0].Role)
+	}
+
+	team, err := b.Workspaces.Create(ctx, workspacemodel.WorkspaceModel{Name: "Team", Slug: "team"})
+	assert.NoError(t, err)
+	assert.Equal(t, workspacemodel.RoleOwner, team.Role)
+	_, err = b.Workspaces.Create(bob, workspacemodel.WorkspaceModel{Name: "Other team", Slug: "team"})
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+
+	got, err := b.Workspaces.GetBySlug(ctx, "team")
+	assert.NoError(t, err)
+	assert.Equal(t, team.ID, got.ID)
+	assert.Equal(t, workspacemodel.RoleOwner, got.Role)
+	_, err = b.Workspaces.GetBySlug(bob, "team")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	_, err = b.Workspaces.GetByID(bob, team.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	_, err = b.Workspaces.AddMember(ctx, team.ID, "nobody@example.com")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	member, err := b.Workspaces.AddMember(ctx, team.ID, "bob@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, requestinfo.UserID(bob), member.UserID)
+	again, err := b.Workspaces.AddMember(ctx, team.ID, "bob@example.com")
+	assert.NoError(t, err)
+	assert.True(t, member.JoinedAt.Equal(again.JoinedAt))
+
+	members, err := b.Workspaces.GetMembers(ctx, team.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, members, 2) {
+		assert.Equal(t, workspacemodel.MemberModel{WorkspaceID: team.ID, UserID: requestinfo.UserID(ctx), Email: "ann@example.com", Role: workspacemodel.RoleOwner, JoinedAt: members[0].JoinedAt}, members[0])
+		assert.Equal(t, workspacemodel.RoleMember, members[1].Role)
+	}
+	got, err = b.Workspaces.GetByID(bob, team.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, workspacemodel.RoleMember, got.Role)
+	workspaces, err = b.Workspaces.GetAll(bob)
+	assert.NoError(t, err)
+	assert.Len(t, workspaces, 2)
+
+	assert.ErrorIs(t, b.Workspaces.RemoveMember(ctx, team.ID, requestinfo.UserID(ctx)), apperror.ErrNotFound)
+	assert.NoError(t, b.Workspaces.RemoveMember(ctx, team.ID, member.UserID))
+	_, err = b.Workspaces.GetByID(bob, team.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
@@ -0,0 +1,128 @@
+package share
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	model "to-do-list/internal/model/share"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+type Repo struct {
+	Db *sql.DB
+}
+
+func NewShareRepository(db *sql.DB) *Repo {
+	return &Repo{
+		Db: db,
+	}
+}
+
+// Create stores a link created by the signed in user in the workspace of
+// the request.
+func (r *Repo) Create(ctx context.Context, link model.LinkModel) (model.LinkModel, error) {
+
+	link.UserID, link.WorkspaceID = requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx)
+
+	err := r.Db.QueryRowContext(ctx, model.InsertLinkReturnIdQuery, link.ListID, link.TaskID, link.ExpiresAt, link.UserID, link.WorkspaceID).
+		Scan(&link.ID, &link.CreatedAt)
+	if err != nil {
+		fmt.Println(err)
+		return link, dbError(err, "create share link")
+	}
+
+	return link, nil
+}
+
+// GetAll returns the links the signed in user created in the workspace of
+// the request.
+func (r *Repo) GetAll(ctx context.Context) ([]model.LinkModel, error) {
+
+	links := []model.LinkModel{}
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchLinksQuery, requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		fmt.Println(err)
+		return nil, dbError(err, "fetch share links")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			link      model.LinkModel
+			listID    sql.NullInt64
+			taskID    sql.NullInt64
+			expiresAt sql.NullTime
+		)
+		if err := rows.Scan(&link.ID, &listID, &taskID, &expiresAt, &link.CreatedAt); err != nil {
+			return nil, dbError(err, "scan share link")
+		}
+		link.ListID, link.TaskID, link.ExpiresAt = nullID(listID), nullID(taskID), nullTime(expiresAt)
+		link.UserID, link.WorkspaceID = requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx)
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch share links")
+	}
+
+	return links, nil
+}
+
+// GetByID reads any link, it serves visitors who are not signed in.
+func (r *Repo) GetByID(ctx context.Context, id int64) (model.LinkModel, error) {
+
+	var (
+		link      model.LinkModel
+		listID    sql.NullInt64
+		taskID    sql.NullInt64
+		expiresAt sql.NullTime
+	)
+
+	err := r.Db.QueryRowContext(ctx, model.FetchLinkQuery, id).
+		Scan(&link.ID, &listID, &taskID, &expiresAt, &link.CreatedAt, &link.UserID, &link.WorkspaceID)
+	if err != nil {
+		return link, dbError(err, "fetch share link")
+	}
+	link.ListID, link.TaskID, link.ExpiresAt = nullID(listID), nullID(taskID), nullTime(expiresAt)
+
+	return link, nil
+}
+
+// Delete revokes a link of the signed in user in the workspace of the
+// request.
+func (r *Repo) Delete(ctx context.Context, id int64) error {
+
+	res, err := r.Db.ExecContext(ctx, model.DeleteLinkQuery, id, requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		fmt.Println(err)
+		return dbError(err, "revoke share link")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err, "revoke share link")
+	}
+	if affected == 0 {
+		return apperror.New(apperror.ErrNotFound, "share link not found")
+	}
+
+	return nil
+}
+
+func nullID(id sql.NullInt64) *int64 {
+	if !id.Valid {
+		return nil
+	}
+	return &id.Int64
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,112 @@
+package share
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/share"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var (
+	testOwner     = int64(1)
+	testWorkspace = int64(5)
+	userCtx       = requestinfo.WithWorkspaceID(requestinfo.WithUserID(context.Background(), testOwner), testWorkspace)
+)
+
+func mockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func TestRepo_Create(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	listID := int64(4)
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(24 * time.Hour)
+
+	mock.ExpectQuery(`INSERT INTO share_links (.*) RETURNING id, created_at`).WithArgs(&listID, nil, &expiresAt, testOwner, testWorkspace).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+
+	repo := NewShareRepository(db)
+	result, err := repo.Create(userCtx, model.LinkModel{ListID: &listID, ExpiresAt: &expiresAt})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.LinkModel{ID: 3, ListID: &listID, ExpiresAt: &expiresAt, CreatedAt: createdAt, UserID: testOwner, WorkspaceID: testWorkspace}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_GetByID(t *testing.T) {
+
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	taskID := int64(7)
+
+	tests := []struct {
+		name    string
+		mock    func(mock sqlmock.Sqlmock)
+		want    model.LinkModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> link of another user is read for a visitor",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM share_links WHERE id=(.*)`).WithArgs(int64(3)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id", "task_id", "expires_at", "created_at", "user_id", "workspace_id"}).
+						AddRow(3, nil, 7, nil, createdAt, 2, 6))
+			},
+			want: model.LinkModel{ID: 3, TaskID: &taskID, CreatedAt: createdAt, UserID: 2, WorkspaceID: 6},
+		},
+		{
+			name: "case 2 -> revoked link is not found",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM share_links WHERE id=(.*)`).WithArgs(int64(3)).
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := mockDB(t)
+			tt.mock(mock)
+
+			repo := NewShareRepository(db)
+			result, err := repo.GetByID(context.Background(), 3)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_Delete(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	mock.ExpectExec(`DELETE FROM share_links WHERE id=(.*) AND user_id=(.*)`).WithArgs(int64(3), testOwner, testWorkspace).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewShareRepository(db)
+	err := repo.Delete(userCtx, 3)
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
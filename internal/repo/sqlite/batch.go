@@ -0,0 +1,170 @@
+package sqlite
+
+import (
+	"context"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// Batch runs the operations in one transaction and returns a result per
+// operation. In all or nothing mode the first failing operation rolls back
+// the whole batch and ends it, its result carries the error. Otherwise each
+// operation runs in a savepoint and only the failing ones are undone.
+func (r *TaskRepo) Batch(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error) {
+	results := make([]model.BatchResult, len(ops))
+	err := r.store.write(ctx, func(t *tx) error {
+		for i, op := range ops {
+			results[i] = model.BatchResult{Op: op.Op, ID: op.ID}
+		}
+
+		for i, op := range ops {
+			var (
+				task   model.TaskModel
+				before *model.TaskModel
+			)
+			err := t.savepoint(ctx, func() error {
+				var err error
+				task, before, err = t.runOperation(ctx, op, opts)
+				return err
+			})
+			if err != nil {
+				results[i].Err = wrap(err, "task", "run batch operation")
+				if opts.AllOrNothing {
+					return errRollback
+				}
+				continue
+			}
+
+			results[i].ID = task.ID
+			results[i].Before = before
+			if op.Op != model.BatchDelete {
+				results[i].Task = &task
+			}
+		}
+		return t.record(ctx, record, model.BatchChanges(results)...)
+	})
+	if err != nil {
+		return nil, wrap(err, "task", "run batch")
+	}
+	return results, nil
+}
+
+// runOperation applies one operation and returns the task it created or
+// changed and the task it found before. A completion of a done task changes
+// nothing and finds no task.
+func (t *tx) runOperation(ctx context.Context, op model.BatchOperation, opts model.BatchOptions) (model.TaskModel, *model.TaskModel, error) {
+	switch op.Op {
+	case model.BatchCreate:
+		task, err := t.insertTask(ctx, *op.Task)
+		return task, nil, err
+
+	case model.BatchUpdate:
+		current, err := t.liveTask(ctx, op.ID)
+		if err != nil {
+			return current, nil, err
+		}
+		task := *op.Task
+		task.ID = op.ID
+		task.Version = op.Version
+		if task.Version != 0 && task.Version != current.Version {
+			return current, nil, apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+		}
+		if task.IsDone && !current.IsDone && opts.BlockOpenSubtasks {
+			if err := t.checkOpenSubtasks(ctx, task.ID); err != nil {
+				return current, nil, err
+			}
+		}
+		if current.ParentID != nil && !sameID(current.ListID, task.ListID) {
+			return current, nil, apperror.New(apperror.ErrValidation, "a subtask stays in the list of its parent")
+		}
+		if current.ParentID != nil && task.RRule != "" {
+			return current, nil, apperror.New(apperror.ErrValidation, "a subtask cannot repeat")
+		}
+		task.ParentID = current.ParentID
+		task.Position = current.Position
+
+		task, err = t.updateTask(ctx, task)
+		if err != nil || task.IsDone == current.IsDone {
+			return task, &current, err
+		}
+		return task, &current, t.rollUp(ctx, task.ParentID)
+
+	case model.BatchComplete:
+		task, err := t.liveTask(ctx, op.ID)
+		if err != nil || task.IsDone {
+			return task, nil, err
+		}
+		current := task
+		if opts.BlockOpenSubtasks {
+			if err := t.checkOpenSubtasks(ctx, task.ID); err != nil {
+				return task, nil, err
+			}
+		}
+		if _, err := t.ExecContext(ctx, `UPDATE tasks SET is_done = 1, version = version + 1 WHERE id = ?`, task.ID); err != nil {
+			return task, nil, err
+		}
+		task.IsDone = true
+		task.Version++
+		return task, &current, t.rollUp(ctx, task.ParentID)
+
+	case model.BatchDelete:
+		current, err := t.liveTask(ctx, op.ID)
+		if err != nil {
+			return model.TaskModel{ID: op.ID}, nil, err
+		}
+		parentID, err := t.trashTask(ctx, op.ID, op.Version)
+		if err != nil {
+			return model.TaskModel{ID: op.ID}, nil, err
+		}
+		return model.TaskModel{ID: op.ID}, &current, t.rollUp(ctx, parentID)
+
+	default:
+		return model.TaskModel{}, nil, apperror.New(apperror.ErrValidation, "unknown operation "+op.Op)
+	}
+}
+
+func (t *tx) checkOpenSubtasks(ctx context.Context, id int64) error {
+	subtasks, err := t.liveSubtasks(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, subtask := range subtasks {
+		if !subtask.IsDone {
+			return apperror.New(apperror.ErrConflict, "task has open subtasks")
+		}
+	}
+	return nil
+}
+
+// rollUp makes the status of the parent match its subtasks: done when all
+// of them are and open when one of them is. Tasks without subtasks are left
+// alone.
+func (t *tx) rollUp(ctx context.Context, parentID *int64) error {
+	if parentID == nil {
+		return nil
+	}
+	parent, found, err := t.anyTask(ctx, *parentID)
+	if err != nil || !found || parent.DeletedAt != nil {
+		return err
+	}
+	subtasks, err := t.liveSubtasks(ctx, *parentID)
+	if err != nil || len(subtasks) == 0 {
+		return err
+	}
+	open := false
+	for _, subtask := range subtasks {
+		open = open || !subtask.IsDone
+	}
+	if parent.IsDone != open {
+		return nil
+	}
+	_, err = t.ExecContext(ctx, `UPDATE tasks SET is_done = ?, version = version + 1 WHERE id = ?`, !open, parent.ID)
+	return err
+}
+
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
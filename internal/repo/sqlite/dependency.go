@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// AddDependency blocks a task by another one. Both have to be live tasks,
+// adding the same dependency twice changes nothing. check runs on the
+// dependencies of the owner in the same write, and its error cancels it.
+func (r *TaskRepo) AddDependency(ctx context.Context, dependency model.Dependency, check func([]model.Dependency) error) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		dependencies, err := t.ownDependencies(ctx)
+		if err != nil {
+			return err
+		}
+		if err := check(dependencies); err != nil {
+			return err
+		}
+		if _, err := t.liveTask(ctx, dependency.TaskID); err != nil {
+			return err
+		}
+		if _, err := t.liveTask(ctx, dependency.BlockedBy); err != nil {
+			return err
+		}
+		if dependency.TaskID == dependency.BlockedBy {
+			return rejected("task")
+		}
+
+		_, err = t.ExecContext(ctx, `INSERT INTO task_dependencies (task_id, blocked_by, created_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+			dependency.TaskID, dependency.BlockedBy, timeArg(t.now))
+		return err
+	})
+	return wrap(err, "task", "add dependency")
+}
+
+func (r *TaskRepo) RemoveDependency(ctx context.Context, dependency model.Dependency) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		result, err := t.ExecContext(ctx, `DELETE FROM task_dependencies WHERE task_id = ? AND blocked_by = ? `+
+			`AND EXISTS (SELECT 1 FROM tasks WHERE id = task_dependencies.task_id AND owner_id = ? AND workspace_id = ?)`,
+			dependency.TaskID, dependency.BlockedBy, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+		if err != nil {
+			return err
+		}
+		return mustAffect(result, apperror.New(apperror.ErrNotFound, "dependency not found"))
+	})
+	return wrap(err, "task", "remove dependency")
+}
+
+// GetDependencies returns every edge, those of trashed tasks included since
+// they can come back.
+func (r *TaskRepo) GetDependencies(ctx context.Context) ([]model.Dependency, error) {
+	var dependencies []model.Dependency
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		dependencies, err = t.ownDependencies(ctx)
+		return err
+	})
+	return dependencies, wrap(err, "task", "fetch dependencies")
+}
+
+// ownDependencies returns the edges of the tasks of ctx in order.
+func (t *tx) ownDependencies(ctx context.Context) ([]model.Dependency, error) {
+	rows, err := t.QueryContext(ctx, `SELECT task_dependencies.task_id, task_dependencies.blocked_by FROM task_dependencies JOIN tasks ON tasks.id = task_dependencies.task_id `+
+		`WHERE tasks.owner_id = ? AND tasks.workspace_id = ? ORDER BY task_dependencies.task_id, task_dependencies.blocked_by`,
+		requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dependencies := []model.Dependency{}
+	for rows.Next() {
+		var dependency model.Dependency
+		if err := rows.Scan(&dependency.TaskID, &dependency.BlockedBy); err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, rows.Err()
+}
+
+// GetBlockers returns the live tasks the task waits for, done or not.
+func (r *TaskRepo) GetBlockers(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	var tasks []model.TaskModel
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		tasks, err = t.queryTasks(ctx, scanAnyTask, `SELECT `+taskColumns+`, tasks.deleted_at FROM tasks JOIN task_dependencies ON task_dependencies.blocked_by = tasks.id `+
+			`WHERE task_dependencies.task_id = ? AND tasks.deleted_at IS NULL AND tasks.owner_id = ? AND tasks.workspace_id = ? ORDER BY tasks.id`,
+			id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+		return err
+	})
+	return tasks, wrap(err, "task", "fetch blockers")
+}
+
+// GetOpenTasks returns every live task that is not done, with its blocked
+// flag.
+func (r *TaskRepo) GetOpenTasks(ctx context.Context) ([]model.TaskModel, error) {
+	var tasks []model.TaskModel
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		tasks, err = t.queryTasks(ctx, scanListedTask, selectTaskQuery+` WHERE deleted_at IS NULL AND NOT is_done AND owner_id = ? AND workspace_id = ? ORDER BY id`,
+			requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+		return err
+	})
+	return tasks, wrap(err, "task", "fetch open tasks")
+}
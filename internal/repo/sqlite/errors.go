@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"to-do-list/pkg/apperror"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// The errors below are the ones dberror.Wrap makes of the same failures in
+// Postgres, so callers cannot tell the backends apart.
+
+func notFound(entity string) error {
+	return apperror.New(apperror.ErrNotFound, entity+" not found")
+}
+
+// conflict is a unique violation.
+func conflict(entity string) error {
+	return apperror.New(apperror.ErrConflict, entity+" already exists")
+}
+
+// rejected is a violated foreign key or check constraint.
+func rejected(entity string) error {
+	return apperror.New(apperror.ErrValidation, entity+" data rejected by storage")
+}
+
+// wrap translates an error of the driver into the apperror taxonomy like
+// dberror.Wrap, and leaves the errors the repos made themselves alone.
+// entity names the row in the client facing messages, op describes the
+// failed operation for the logs.
+func wrap(err error, entity string, op string) error {
+	var appErr *apperror.Error
+	if err == nil || errors.As(err, &appErr) {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound(entity)
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch code := sqliteErr.Code(); {
+		case code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return apperror.Wrap(apperror.ErrConflict, entity+" already exists", err)
+		case code&0xff == sqlite3.SQLITE_CONSTRAINT:
+			return apperror.Wrap(apperror.ErrValidation, entity+" data rejected by storage", err)
+		case code&0xff == sqlite3.SQLITE_BUSY || code&0xff == sqlite3.SQLITE_LOCKED || code&0xff == sqlite3.SQLITE_IOERR || code&0xff == sqlite3.SQLITE_FULL:
+			return apperror.Wrap(apperror.ErrUnavailable, "storage unavailable", err)
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,283 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// eventColumns are read by scanEvent.
+const eventColumns = `task_events.id, task_events.task_id, task_events.action, task_events.before, task_events.after, task_events.actor, ` +
+	`task_events.request_id, task_events.created_at, task_events.reverts`
+
+// fetchUndoableEventsQuery is model.FetchUndoableEventsQuery in SQLite.
+const fetchUndoableEventsQuery = `WITH last AS (` +
+	`SELECT id, request_id, owner_id FROM task_events WHERE user_id = ?1 AND workspace_id = ?2 AND owner_id IS NOT NULL AND reverts IS NULL ` +
+	`AND NOT EXISTS (SELECT 1 FROM task_events undo WHERE undo.reverts = task_events.id) ORDER BY id DESC LIMIT 1) ` +
+	`SELECT ` + eventColumns + `, task_events.owner_id FROM task_events, last WHERE task_events.user_id = ?1 AND task_events.workspace_id = ?2 ` +
+	`AND task_events.owner_id = last.owner_id AND task_events.reverts IS NULL ` +
+	`AND (task_events.id = last.id OR (last.request_id <> '' AND task_events.request_id = last.request_id)) ` +
+	`AND NOT EXISTS (SELECT 1 FROM task_events undo WHERE undo.reverts = task_events.id) ORDER BY task_events.id DESC`
+
+// fetchTaskSnapshotQuery is model.FetchTaskSnapshotQuery in SQLite.
+const fetchTaskSnapshotQuery = `SELECT snapshot FROM (` +
+	`SELECT id, after AS snapshot FROM task_events WHERE task_id = ?1 AND owner_id = ?3 AND workspace_id = ?4 AND after IS NOT NULL ` +
+	`UNION ALL SELECT id, before FROM task_events WHERE task_id = ?1 AND owner_id = ?3 AND workspace_id = ?4 AND before IS NOT NULL) snapshots ` +
+	`WHERE json_extract(snapshot, '$.version') = ?2 ORDER BY id DESC LIMIT 1`
+
+// AppendEvents adds events to the audit log of the user of ctx, all of them
+// or none.
+func (r *TaskRepo) AppendEvents(ctx context.Context, events ...model.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	err := r.store.write(ctx, func(t *tx) error {
+		return t.appendEvents(ctx, events...)
+	})
+	return wrap(err, "task", "record task events")
+}
+
+// record appends what record makes of changes to the audit log, in the
+// write that made them.
+func (t *tx) record(ctx context.Context, record model.Record, changes ...model.Change) error {
+	if record == nil || len(changes) == 0 {
+		return nil
+	}
+	return t.appendEvents(ctx, record(changes)...)
+}
+
+// recordUpdate appends what record makes of the change of before into the
+// task as it is now.
+func (t *tx) recordUpdate(ctx context.Context, record model.Record, before model.TaskModel) error {
+	if record == nil {
+		return nil
+	}
+	after, err := t.liveTask(ctx, before.ID)
+	if err != nil {
+		return err
+	}
+	return t.record(ctx, record, model.Change{Before: &before, After: &after})
+}
+
+func (t *tx) appendEvents(ctx context.Context, events ...model.TaskEvent) error {
+	owner, workspace := requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)
+	if found, err := t.exists(ctx, `SELECT 1 FROM workspaces WHERE id = ?`, workspace); err != nil || !found {
+		return orRejected(err, "task")
+	}
+
+	user := sql.NullInt64{Int64: requestinfo.UserID(ctx), Valid: requestinfo.UserID(ctx) != 0}
+	for _, event := range events {
+		before, err := snapshot(event.Before)
+		if err != nil {
+			return err
+		}
+		after, err := snapshot(event.After)
+		if err != nil {
+			return err
+		}
+
+		reverts := sql.NullInt64{Int64: event.Reverts, Valid: event.Reverts != 0}
+		_, err = t.ExecContext(ctx, `INSERT INTO task_events (task_id, action, before, after, actor, request_id, created_at, reverts, owner_id, workspace_id, user_id) `+
+			`VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			event.TaskID, event.Action, before, after, event.Actor, event.RequestID, timeArg(t.now), reverts, owner, workspace, user)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEvents returns one page of the audit log, newest first.
+func (r *TaskRepo) GetEvents(ctx context.Context, filter model.EventFilter) (model.EventPage, error) {
+	page := model.EventPage{Events: []model.TaskEvent{}}
+
+	query, args, err := buildEventQuery(requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx), filter)
+	if err != nil {
+		return page, err
+	}
+
+	err = r.store.read(ctx, func(t *tx) error {
+		var err error
+		page.Events, err = t.queryEvents(ctx, scanPageEvent, query, args...)
+		return err
+	})
+	if err != nil {
+		return page, wrap(err, "task", "fetch task events")
+	}
+
+	if len(page.Events) > filter.Limit {
+		page.Events = page.Events[:filter.Limit]
+		page.NextCursor = strconv.FormatInt(page.Events[filter.Limit-1].ID, 10)
+	}
+
+	return page, nil
+}
+
+// GetUndoableEvents returns the events of the last change of user that has
+// not been undone yet, newest first, none when there is nothing to undo.
+// The change can be to the tasks of another owner, whom the events name.
+func (r *TaskRepo) GetUndoableEvents(ctx context.Context, user int64) ([]model.TaskEvent, error) {
+	var events []model.TaskEvent
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		events, err = t.queryEvents(ctx, scanOwnedEvent, fetchUndoableEventsQuery, user, requestinfo.WorkspaceID(ctx))
+		return err
+	})
+	return events, wrap(err, "task", "fetch task events")
+}
+
+// GetSnapshot returns the task as it was recorded at version, by the latest
+// event that recorded it.
+func (r *TaskRepo) GetSnapshot(ctx context.Context, id, version int64) (model.TaskModel, error) {
+	var task model.TaskModel
+	err := r.store.read(ctx, func(t *tx) error {
+		var data []byte
+		err := t.QueryRowContext(ctx, fetchTaskSnapshotQuery, id, version, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)).Scan(&data)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.ErrNotFound, "version not found in the history of the task")
+		}
+		if err != nil {
+			return err
+		}
+
+		recorded, err := readSnapshot(data)
+		if err != nil {
+			return fmt.Errorf("read task snapshot: %w", err)
+		}
+		task = *recorded
+		return nil
+	})
+	return task, wrap(err, "task", "fetch task snapshot")
+}
+
+// buildEventQuery pages through the events of owner in workspace by id,
+// which grows with time. Like buildListQuery it fetches one row more than
+// the limit.
+func buildEventQuery(owner, workspace int64, filter model.EventFilter) (string, []interface{}, error) {
+	b := queryBuilder{}
+
+	b.where("owner_id = " + b.arg(owner))
+	b.where("workspace_id = " + b.arg(workspace))
+
+	if filter.TaskID != nil {
+		b.where("task_id = " + b.arg(*filter.TaskID))
+	}
+
+	if filter.Actor != "" {
+		b.where("actor = " + b.arg(filter.Actor))
+	}
+
+	if filter.Action != "" {
+		b.where("action = " + b.arg(filter.Action))
+	}
+
+	if filter.From != nil {
+		b.where("created_at >= " + b.arg(timeArg(*filter.From)))
+	}
+
+	if filter.To != nil {
+		b.where("created_at < " + b.arg(timeArg(*filter.To)))
+	}
+
+	if filter.Cursor != "" {
+		last, err := strconv.ParseInt(filter.Cursor, 10, 64)
+		if err != nil {
+			return "", nil, apperror.New(apperror.ErrValidation, "invalid cursor")
+		}
+		b.where("id < " + b.arg(last))
+	}
+
+	query := `SELECT ` + eventColumns + ` FROM task_events WHERE ` + strings.Join(b.conds, " AND ")
+	query += " ORDER BY id DESC LIMIT " + b.arg(filter.Limit+1)
+
+	return query, b.args, nil
+}
+
+// queryEvents runs a query of events and reads every row with scan.
+func (t *tx) queryEvents(ctx context.Context, scan func(row scanner) (model.TaskEvent, error), query string, args ...interface{}) ([]model.TaskEvent, error) {
+	rows, err := t.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.TaskEvent{}
+	for rows.Next() {
+		event, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// scanPageEvent reads a row of a page of the audit log, selected with
+// eventColumns.
+func scanPageEvent(row scanner) (model.TaskEvent, error) {
+	return scanEvent(row)
+}
+
+// scanOwnedEvent reads a row selected with eventColumns and the owner_id
+// column.
+func scanOwnedEvent(row scanner) (model.TaskEvent, error) {
+	var owner sql.NullInt64
+	event, err := scanEvent(row, &owner)
+	event.OwnerID = owner.Int64
+	return event, err
+}
+
+// scanEvent reads a row selected with eventColumns, followed by the columns
+// read into extra.
+func scanEvent(row scanner, extra ...interface{}) (model.TaskEvent, error) {
+	var (
+		event         model.TaskEvent
+		before, after []byte
+		reverts       sql.NullInt64
+	)
+
+	dest := []interface{}{&event.ID, &event.TaskID, &event.Action, &before, &after, &event.Actor, &event.RequestID, &timeValue{&event.CreatedAt}, &reverts}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return event, err
+	}
+	event.Reverts = reverts.Int64
+
+	var err error
+	if event.Before, err = readSnapshot(before); err != nil {
+		return event, fmt.Errorf("read task snapshot: %w", err)
+	}
+	if event.After, err = readSnapshot(after); err != nil {
+		return event, fmt.Errorf("read task snapshot: %w", err)
+	}
+	return event, nil
+}
+
+// snapshot turns a task into the JSON text of a column, NULL for none.
+func snapshot(task *model.TaskModel) (interface{}, error) {
+	if task == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot task: %w", err)
+	}
+	return string(data), nil
+}
+
+func readSnapshot(data []byte) (*model.TaskModel, error) {
+	if data == nil {
+		return nil, nil
+	}
+	task := &model.TaskModel{}
+	if err := json.Unmarshal(data, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
@@ -0,0 +1,365 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// listColumns read a list with the number of its live tasks.
+const listColumns = `lists.id, lists.name, (SELECT COUNT(*) FROM tasks WHERE tasks.list_id = lists.id AND tasks.deleted_at IS NULL)`
+
+// memberColumns are read by scanMember.
+const memberColumns = `list_members.list_id, list_members.user_id, list_members.role, list_members.created_at, list_members.accepted_at`
+
+type ListRepo struct {
+	store *Store
+}
+
+func NewListRepository(store *Store) *ListRepo {
+	return &ListRepo{
+		store: store,
+	}
+}
+
+// GetAll returns the lists of the user and those shared with them.
+func (r *ListRepo) GetAll(ctx context.Context) ([]model.ListModel, error) {
+	lists := []model.ListModel{}
+	user := requestinfo.UserID(ctx)
+	err := r.store.read(ctx, func(t *tx) error {
+		rows, err := t.QueryContext(ctx, `SELECT `+listColumns+`, COALESCE(list_members.role, ?) FROM lists `+
+			`LEFT JOIN list_members ON list_members.list_id = lists.id AND list_members.user_id = ? AND list_members.accepted_at IS NOT NULL `+
+			`WHERE lists.owner_id = ? OR list_members.user_id IS NOT NULL ORDER BY lists.id`,
+			model.RoleOwner, user, user)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var list model.ListModel
+			if err := rows.Scan(&list.ID, &list.Name, &list.TaskCount, &list.Role); err != nil {
+				return err
+			}
+			lists = append(lists, list)
+		}
+		return rows.Err()
+	})
+	return lists, wrap(err, "list", "fetch lists")
+}
+
+func (r *ListRepo) GetByID(ctx context.Context, id int64) (model.ListModel, error) {
+	var list model.ListModel
+	err := r.store.read(ctx, func(t *tx) error {
+		return t.QueryRowContext(ctx, `SELECT `+listColumns+` FROM lists WHERE id = ? AND owner_id = ?`, id, requestinfo.OwnerID(ctx)).
+			Scan(&list.ID, &list.Name, &list.TaskCount)
+	})
+	return list, wrap(err, "list", "fetch list")
+}
+
+func (r *ListRepo) Create(ctx context.Context, list model.ListModel) (model.ListModel, error) {
+	err := r.store.write(ctx, func(t *tx) error {
+		owner := requestinfo.OwnerID(ctx)
+		if found, err := t.exists(ctx, `SELECT 1 FROM users WHERE id = ?`, owner); err != nil || !found {
+			return orRejected(err, "list")
+		}
+		result, err := t.ExecContext(ctx, `INSERT INTO lists (name, owner_id) VALUES (?, ?)`, list.Name, owner)
+		if err != nil {
+			return err
+		}
+		list.ID, err = result.LastInsertId()
+		return err
+	})
+	return list, wrap(err, "list", "create list")
+}
+
+func (r *ListRepo) Update(ctx context.Context, list model.ListModel) (model.ListModel, error) {
+	err := r.store.write(ctx, func(t *tx) error {
+		result, err := t.ExecContext(ctx, `UPDATE lists SET name = ? WHERE id = ? AND owner_id = ?`, list.Name, list.ID, requestinfo.OwnerID(ctx))
+		if err != nil {
+			return err
+		}
+		return mustAffect(result, notFound("list"))
+	})
+	if err != nil {
+		return list, wrap(err, "list", "update list")
+	}
+	return r.GetByID(ctx, list.ID)
+}
+
+// Delete removes the list and, depending on the mode, moves its tasks to
+// the trash or to another list. It returns the live tasks of the list by
+// workspace, as they were before, and records what it did to them in their
+// workspace. Its members and share links go with it.
+func (r *ListRepo) Delete(ctx context.Context, id int64, opts model.DeleteOptions, record taskmodel.Record) (map[int64][]taskmodel.TaskModel, error) {
+	tasks := map[int64][]taskmodel.TaskModel{}
+	err := r.store.write(ctx, func(t *tx) error {
+		owner := requestinfo.OwnerID(ctx)
+		found, err := t.exists(ctx, `SELECT 1 FROM lists WHERE id = ? AND owner_id = ?`, id, owner)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return notFound("list")
+		}
+
+		reassign := opts.Mode == model.DeleteReassign
+		if reassign && opts.To != nil {
+			found, err = t.exists(ctx, `SELECT 1 FROM lists WHERE id = ? AND owner_id = ?`, *opts.To, owner)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return apperror.New(apperror.ErrValidation, "list to reassign to not found")
+			}
+		}
+
+		rows, err := t.QueryContext(ctx, `SELECT `+taskColumns+`, tasks.workspace_id FROM tasks WHERE list_id = ? AND deleted_at IS NULL ORDER BY id`, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var workspace int64
+			task, err := scanTask(rows, &workspace)
+			if err != nil {
+				return err
+			}
+			tasks[workspace] = append(tasks[workspace], task)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// a cascade moves the tasks to the trash out of any list, those
+		// already there keep their time and version
+		if reassign {
+			_, err = t.ExecContext(ctx, `UPDATE tasks SET list_id = ?, version = version + 1 WHERE list_id = ?`, nullInt64Arg(opts.To), id)
+		} else if _, err = t.ExecContext(ctx, `UPDATE tasks SET deleted_at = ?, list_id = NULL, version = version + 1 WHERE list_id = ? AND deleted_at IS NULL`, timeArg(t.now), id); err == nil {
+			_, err = t.ExecContext(ctx, `UPDATE tasks SET list_id = NULL WHERE list_id = ?`, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err = t.ExecContext(ctx, `DELETE FROM lists WHERE id = ?`, id); err != nil {
+			return err
+		}
+
+		for workspace, before := range tasks {
+			changes := make([]taskmodel.Change, len(before))
+			for i := range before {
+				changes[i].Before = &before[i]
+				if reassign {
+					after := before[i]
+					after.ListID = opts.To
+					after.Version++
+					changes[i].After = &after
+				}
+			}
+			if err := t.record(requestinfo.WithWorkspaceID(ctx, workspace), record, changes...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, wrap(err, "list", "delete list")
+	}
+	return tasks, nil
+}
+
+// ListAccess returns what the signed in user may do with a list. Lists
+// they can not see are not found.
+func (r *ListRepo) ListAccess(ctx context.Context, id int64) (model.Access, error) {
+	var access model.Access
+	err := r.store.read(ctx, func(t *tx) error {
+		var owner sql.NullInt64
+		if err := t.QueryRowContext(ctx, `SELECT owner_id FROM lists WHERE id = ?`, id).Scan(&owner); err != nil {
+			return err
+		}
+		var err error
+		access, err = t.access(ctx, owner.Int64, &id, "list")
+		return err
+	})
+	return access, wrap(err, "list", "fetch list access")
+}
+
+// TaskAccess returns what the signed in user may do with a task, through
+// the list it is in. Tasks they can not see are not found.
+func (r *ListRepo) TaskAccess(ctx context.Context, id int64) (model.Access, error) {
+	var access model.Access
+	err := r.store.read(ctx, func(t *tx) error {
+		var owner, listID sql.NullInt64
+		if err := t.QueryRowContext(ctx, `SELECT owner_id, list_id FROM tasks WHERE id = ?`, id).Scan(&owner, &listID); err != nil {
+			return err
+		}
+		var err error
+		access, err = t.access(ctx, owner.Int64, int64Ptr(listID), "task")
+		return err
+	})
+	return access, wrap(err, "task", "fetch task access")
+}
+
+// access is the role of the signed in user in something of owner in the
+// list listID, owners first and then accepted members.
+func (t *tx) access(ctx context.Context, owner int64, listID *int64, entity string) (model.Access, error) {
+	user := requestinfo.UserID(ctx)
+	if owner != 0 && owner == user {
+		return model.Access{OwnerID: user, Role: model.RoleOwner}, nil
+	}
+	if listID != nil && owner != 0 {
+		var role model.Role
+		err := t.QueryRowContext(ctx, `SELECT role FROM list_members WHERE list_id = ? AND user_id = ? AND accepted_at IS NOT NULL`, *listID, user).Scan(&role)
+		if err == nil {
+			return model.Access{OwnerID: owner, Role: role}, nil
+		}
+		if err != sql.ErrNoRows {
+			return model.Access{}, err
+		}
+	}
+	return model.Access{}, notFound(entity)
+}
+
+func (r *ListRepo) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	members := []model.MemberModel{}
+	err := r.store.read(ctx, func(t *tx) error {
+		rows, err := t.QueryContext(ctx, `SELECT `+memberColumns+`, users.email FROM list_members JOIN users ON users.id = list_members.user_id `+
+			`WHERE list_members.list_id = ? ORDER BY list_members.created_at, list_members.user_id`, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var email string
+			member, err := scanMember(rows, &email)
+			if err != nil {
+				return err
+			}
+			member.Email = email
+			members = append(members, member)
+		}
+		return rows.Err()
+	})
+	return members, wrap(err, "list", "fetch members")
+}
+
+// Invite adds the user with the email of the request to the members of the
+// list, pending until they accept. A member keeps their acceptance when
+// their role changes.
+func (r *ListRepo) Invite(ctx context.Context, id int64, invite model.InviteRequest) (model.MemberModel, error) {
+	var member model.MemberModel
+	err := r.store.write(ctx, func(t *tx) error {
+		var user int64
+		err := t.QueryRowContext(ctx, `SELECT users.id FROM users JOIN lists ON lists.id = ? WHERE users.email = ? AND users.id IS NOT lists.owner_id`, id, invite.Email).Scan(&user)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.ErrNotFound, "no other user with this email")
+		}
+		if err != nil {
+			return err
+		}
+		switch invite.Role {
+		case model.RoleViewer, model.RoleEditor, model.RoleOwner:
+		default:
+			return rejected("list")
+		}
+
+		_, err = t.ExecContext(ctx, `INSERT INTO list_members (list_id, user_id, role, created_at) VALUES (?, ?, ?, ?) `+
+			`ON CONFLICT (list_id, user_id) DO UPDATE SET role = excluded.role`, id, user, invite.Role, timeArg(t.now))
+		if err != nil {
+			return err
+		}
+
+		member, err = scanMember(t.QueryRowContext(ctx, `SELECT `+memberColumns+` FROM list_members WHERE list_id = ? AND user_id = ?`, id, user))
+		member.Email = invite.Email
+		return err
+	})
+	return member, wrap(err, "list", "invite member")
+}
+
+// Accept accepts the pending invitation of the signed in user to a list.
+func (r *ListRepo) Accept(ctx context.Context, id int64) (model.MemberModel, error) {
+	var member model.MemberModel
+	err := r.store.write(ctx, func(t *tx) error {
+		user := requestinfo.UserID(ctx)
+		result, err := t.ExecContext(ctx, `UPDATE list_members SET accepted_at = ? WHERE list_id = ? AND user_id = ? AND accepted_at IS NULL`, timeArg(t.now), id, user)
+		if err != nil {
+			return err
+		}
+		if err := mustAffect(result, apperror.New(apperror.ErrNotFound, "invitation not found")); err != nil {
+			return err
+		}
+
+		member, err = scanMember(t.QueryRowContext(ctx, `SELECT `+memberColumns+` FROM list_members WHERE list_id = ? AND user_id = ?`, id, user))
+		return err
+	})
+	return member, wrap(err, "list", "accept invitation")
+}
+
+// RemoveMember revokes the membership or the pending invitation of a user.
+func (r *ListRepo) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		result, err := t.ExecContext(ctx, `DELETE FROM list_members WHERE list_id = ? AND user_id = ?`, id, userID)
+		if err != nil {
+			return err
+		}
+		return mustAffect(result, notFound("member"))
+	})
+	return wrap(err, "member", "remove member")
+}
+
+// GetInvitations returns the pending invitations of the signed in user.
+func (r *ListRepo) GetInvitations(ctx context.Context) ([]model.MemberModel, error) {
+	invitations := []model.MemberModel{}
+	err := r.store.read(ctx, func(t *tx) error {
+		rows, err := t.QueryContext(ctx, `SELECT `+memberColumns+`, lists.name FROM list_members JOIN lists ON lists.id = list_members.list_id `+
+			`WHERE list_members.user_id = ? AND list_members.accepted_at IS NULL ORDER BY list_members.created_at, list_members.list_id`, requestinfo.UserID(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name string
+			invitation, err := scanMember(rows, &name)
+			if err != nil {
+				return err
+			}
+			invitation.ListName = name
+			invitations = append(invitations, invitation)
+		}
+		return rows.Err()
+	})
+	return invitations, wrap(err, "list", "fetch invitations")
+}
+
+// scanMember reads a row selected with memberColumns, followed by the
+// columns read into extra.
+func scanMember(row scanner, extra ...interface{}) (model.MemberModel, error) {
+	var (
+		member     model.MemberModel
+		acceptedAt nullTime
+	)
+	dest := []interface{}{&member.ListID, &member.UserID, &member.Role, &timeValue{&member.InvitedAt}, &acceptedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return member, err
+	}
+	member.AcceptedAt = acceptedAt.Time
+	return member, nil
+}
+
+// mustAffect returns notFoundErr when result changed no row.
+func mustAffect(result sql.Result, notFoundErr error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFoundErr
+	}
+	return nil
+}
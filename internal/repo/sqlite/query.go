@@ -0,0 +1,271 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// sortColumn ties an API sort field to its column, to how its value is read
+// from the last task of a page and to how it is read back from a cursor.
+type sortColumn struct {
+	expr   string
+	value  func(task model.TaskModel) interface{}
+	decode func(raw json.RawMessage) (interface{}, error)
+}
+
+var sortColumns = map[string]sortColumn{
+	"id": {
+		expr:   "id",
+		value:  func(task model.TaskModel) interface{} { return task.ID },
+		decode: decodeValue[int64],
+	},
+	"task_name": {
+		expr:   "task_name",
+		value:  func(task model.TaskModel) interface{} { return task.TaskName },
+		decode: decodeValue[string],
+	},
+	"is_done": {
+		expr:   "is_done",
+		value:  func(task model.TaskModel) interface{} { return task.IsDone },
+		decode: decodeValue[bool],
+	},
+	"priority": {
+		expr:   "priority",
+		value:  func(task model.TaskModel) interface{} { return int64(task.Priority) },
+		decode: decodeValue[int64],
+	},
+	"rank": {
+		expr:   "rank",
+		value:  func(task model.TaskModel) interface{} { return task.Rank },
+		decode: decodeValue[string],
+	},
+	// tasks without a deadline sort after every deadline, in both directions
+	// of the keyset comparison, as 'infinity' sorts after every stored time
+	"due_at": {
+		expr: "COALESCE(due_at, 'infinity')",
+		value: func(task model.TaskModel) interface{} {
+			if task.DueAt == nil {
+				return "infinity"
+			}
+			return timeArg(*task.DueAt)
+		},
+		decode: decodeValue[string],
+	},
+}
+
+func decodeValue[T any](raw json.RawMessage) (interface{}, error) {
+	var v T
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+// cursor is the keyset position after the last task of a page: the sort it
+// was made for and the values of the sort fields.
+type cursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+func (r *TaskRepo) GetAll(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+	page := model.TaskPage{Tasks: []model.TaskModel{}}
+
+	query, args, err := buildListQuery(requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx), filter)
+	if err != nil {
+		return page, err
+	}
+
+	err = r.store.read(ctx, func(t *tx) error {
+		page.Tasks, err = t.queryTasks(ctx, scanListedTask, query, args...)
+		return err
+	})
+	if err != nil {
+		return page, wrap(err, "task", "fetch tasks")
+	}
+
+	if len(page.Tasks) > filter.Limit {
+		page.Tasks = page.Tasks[:filter.Limit]
+		page.NextCursor = encodeCursor(orderOf(filter), page.Tasks[filter.Limit-1])
+	}
+
+	return page, nil
+}
+
+// queryBuilder collects conditions and their arguments so values never end
+// up in the SQL text.
+type queryBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+func (b *queryBuilder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return "?"
+}
+
+func (b *queryBuilder) where(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+// orderOf returns the requested sort with id appended as the tie breaker so
+// every row has a unique position for the cursor.
+func orderOf(filter model.TaskFilter) []model.SortField {
+	order := []model.SortField{}
+	for _, sort := range filter.Sort {
+		order = append(order, sort)
+		if sort.Field == "id" {
+			return order
+		}
+	}
+	return append(order, model.SortField{Field: "id"})
+}
+
+func sortKey(order []model.SortField) string {
+	keys := make([]string, len(order))
+	for i, sort := range order {
+		keys[i] = sort.Field
+		if sort.Desc {
+			keys[i] = "-" + sort.Field
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// buildListQuery turns a filter on the tasks of owner in workspace into a
+// keyset paginated query. It fetches one row more than the limit so the
+// caller can tell whether a next page exists. LIKE ignores the case of ASCII
+// letters only, where Postgres uses ILIKE.
+func buildListQuery(owner, workspace int64, filter model.TaskFilter) (string, []interface{}, error) {
+	var (
+		b     = queryBuilder{}
+		order = orderOf(filter)
+	)
+
+	for _, sort := range order {
+		if _, ok := sortColumns[sort.Field]; !ok {
+			return "", nil, apperror.New(apperror.ErrValidation, "unknown sort field "+sort.Field)
+		}
+	}
+
+	// trashed tasks are only listed by GetTrash
+	b.where("deleted_at IS NULL")
+	b.where("owner_id = " + b.arg(owner))
+	b.where("workspace_id = " + b.arg(workspace))
+
+	if filter.IsDone != nil {
+		b.where("is_done = " + b.arg(*filter.IsDone))
+	}
+
+	if filter.Query != "" {
+		b.where("task_name LIKE " + b.arg("%"+escapeLike(filter.Query)+"%") + ` ESCAPE '\'`)
+	}
+
+	if filter.ListID != nil {
+		b.where("list_id = " + b.arg(*filter.ListID))
+	}
+
+	if filter.Priority != nil {
+		b.where("priority = " + b.arg(int64(*filter.Priority)))
+	}
+
+	for _, tag := range filter.Tags {
+		b.where("EXISTS (SELECT 1 FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id AND tags.name = " + b.arg(tag) + ")")
+	}
+
+	if filter.DueFrom != nil {
+		b.where("due_at >= " + b.arg(timeArg(*filter.DueFrom)))
+	}
+
+	if filter.DueTo != nil {
+		b.where("due_at < " + b.arg(timeArg(*filter.DueTo)))
+	}
+
+	if filter.Cursor != "" {
+		after, err := decodeCursor(filter.Cursor, order)
+		if err != nil {
+			return "", nil, err
+		}
+		b.where(b.after(order, after))
+	}
+
+	query := selectTaskQuery + " WHERE " + strings.Join(b.conds, " AND ")
+
+	orderBy := make([]string, len(order))
+	for i, sort := range order {
+		orderBy[i] = sortColumns[sort.Field].expr
+		if sort.Desc {
+			orderBy[i] += " DESC"
+		}
+	}
+	query += " ORDER BY " + strings.Join(orderBy, ", ")
+	query += " LIMIT " + b.arg(filter.Limit+1)
+
+	return query, b.args, nil
+}
+
+// after expands the keyset comparison row by row, since the sort directions
+// may be mixed: (a > x) OR (a = x AND b < y) OR ...
+func (b *queryBuilder) after(order []model.SortField, values []interface{}) string {
+	var (
+		ors    = []string{}
+		equals = []string{}
+	)
+
+	for i, sort := range order {
+		expr := sortColumns[sort.Field].expr
+		op := " > "
+		if sort.Desc {
+			op = " < "
+		}
+		and := append(append([]string{}, equals...), expr+op+b.arg(values[i]))
+		ors = append(ors, "("+strings.Join(and, " AND ")+")")
+		if i < len(order)-1 {
+			equals = append(equals, expr+" = "+b.arg(values[i]))
+		}
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")"
+}
+
+func encodeCursor(order []model.SortField, last model.TaskModel) string {
+	c := cursor{Sort: sortKey(order)}
+	for _, sort := range order {
+		value, _ := json.Marshal(sortColumns[sort.Field].value(last))
+		c.Values = append(c.Values, value)
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string, order []model.SortField) ([]interface{}, error) {
+	c := cursor{}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, apperror.Wrap(apperror.ErrValidation, "invalid cursor", err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, apperror.Wrap(apperror.ErrValidation, "invalid cursor", err)
+	}
+	if c.Sort != sortKey(order) || len(c.Values) != len(order) {
+		return nil, apperror.New(apperror.ErrValidation, "cursor does not match sort")
+	}
+
+	values := make([]interface{}, len(order))
+	for i, sort := range order {
+		values[i], err = sortColumns[sort.Field].decode(c.Values[i])
+		if err != nil {
+			return nil, apperror.Wrap(apperror.ErrValidation, "invalid cursor", err)
+		}
+	}
+	return values, nil
+}
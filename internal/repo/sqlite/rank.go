@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/rank"
+	"to-do-list/pkg/requestinfo"
+)
+
+// The neighbours of an anchor are looked for in every workspace of the
+// owner, where keys are unique. Only live top level tasks count.
+const (
+	nextRankQuery     = `SELECT COALESCE(MIN(rank), '') FROM tasks WHERE owner_id = ? AND id <> ? AND parent_id IS NULL AND deleted_at IS NULL AND rank > ?`
+	previousRankQuery = `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE owner_id = ? AND id <> ? AND parent_id IS NULL AND deleted_at IS NULL AND rank < ?`
+)
+
+// Reposition puts a top level task right before or right after another one
+// in the manual order. Only the moved task gets a new key, unless its new
+// neighbours leave no room and every key is spread again.
+func (r *TaskRepo) Reposition(ctx context.Context, id int64, place model.RepositionRequest, record model.Record) (model.TaskModel, error) {
+	anchor, after := place.Before, false
+	if place.After != nil {
+		anchor, after = place.After, true
+	}
+	if anchor == nil {
+		return model.TaskModel{}, apperror.New(apperror.ErrValidation, "before or after is required")
+	}
+
+	var task model.TaskModel
+	err := r.store.write(ctx, func(t *tx) error {
+		current, err := t.liveTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		if current.ParentID != nil {
+			return notFound("task")
+		}
+		anchorTask, err := t.liveTask(ctx, *anchor)
+		if errors.Is(err, apperror.ErrNotFound) || err == nil && anchorTask.ParentID != nil {
+			return apperror.New(apperror.ErrNotFound, "anchor task not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		owner := requestinfo.OwnerID(ctx)
+		lower, upper := "", anchorTask.Rank
+		if after {
+			lower = anchorTask.Rank
+			err = t.QueryRowContext(ctx, nextRankQuery, owner, id, anchorTask.Rank).Scan(&upper)
+		} else {
+			err = t.QueryRowContext(ctx, previousRankQuery, owner, id, anchorTask.Rank).Scan(&lower)
+		}
+		if err != nil {
+			return err
+		}
+
+		key, err := rank.Between(lower, upper)
+		if err != nil || len(key) > rank.MaxLen {
+			if err := t.spreadRanks(ctx, owner, id, *anchor, after); err != nil {
+				return err
+			}
+			if err := t.QueryRowContext(ctx, `SELECT rank FROM tasks WHERE id = ?`, id).Scan(&key); err != nil {
+				return err
+			}
+		}
+
+		if _, err := t.ExecContext(ctx, `UPDATE tasks SET rank = ?, version = version + 1 WHERE id = ?`, key, id); err != nil {
+			return err
+		}
+		if task, err = t.liveTask(ctx, id); err != nil {
+			return err
+		}
+		return t.record(ctx, record, model.Change{Before: &current, After: &task})
+	})
+	return task, wrap(err, "task", "reposition task")
+}
+
+// Rebalance spreads the keys of the manual order of every user again once
+// one of them is longer than rank.MaxLen, and returns how many tasks got a
+// new key.
+func (r *TaskRepo) Rebalance(ctx context.Context) (int64, error) {
+	var spread int64
+	err := r.store.write(ctx, func(t *tx) error {
+		owners, err := t.queryIDs(ctx, `SELECT DISTINCT owner_id FROM tasks WHERE owner_id IS NOT NULL AND length(rank) > ?`, rank.MaxLen)
+		if err != nil {
+			return err
+		}
+
+		for _, owner := range owners {
+			if err := t.spreadRanks(ctx, owner, 0, 0, false); err != nil {
+				return err
+			}
+			var count int64
+			if err := t.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks WHERE owner_id = ?`, owner).Scan(&count); err != nil {
+				return err
+			}
+			spread += count
+		}
+		return nil
+	})
+	return spread, wrap(err, "task", "rebalance ranks")
+}
+
+// spreadRanks gives every task of owner, trashed ones and subtasks
+// included, an evenly spaced key in the current order, with task id taken
+// out and put next to anchor. An id of 0 moves nothing. The order does not
+// change, so versions stay.
+func (t *tx) spreadRanks(ctx context.Context, owner, id, anchor int64, after bool) error {
+	ids, err := t.queryIDs(ctx, `SELECT id FROM tasks WHERE owner_id = ? ORDER BY rank, id`, owner)
+	if err != nil {
+		return err
+	}
+
+	order := make([]int64, 0, len(ids))
+	for _, other := range ids {
+		if other == id {
+			continue
+		}
+		if other == anchor && !after {
+			order = append(order, id)
+		}
+		order = append(order, other)
+		if other == anchor && after {
+			order = append(order, id)
+		}
+	}
+
+	keys := rank.Spread(len(order))
+	for i, taskID := range order {
+		if _, err := t.ExecContext(ctx, `UPDATE tasks SET rank = ? WHERE id = ?`, keys[i], taskID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
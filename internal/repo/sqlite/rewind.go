@@ -0,0 +1,174 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// Rewind applies the steps in order in one transaction and returns the task
+// before and after each of them. A task is only checked against the
+// Expected version of its first step, the later ones find the version the
+// earlier ones left.
+func (r *TaskRepo) Rewind(ctx context.Context, steps []model.RewindStep, record model.Record) ([]model.RewindResult, error) {
+	results := make([]model.RewindResult, 0, len(steps))
+	err := r.store.write(ctx, func(t *tx) error {
+		checked := map[int64]bool{}
+		for _, step := range steps {
+			if checked[step.TaskID] {
+				step.Expected = 0
+			}
+			checked[step.TaskID] = true
+
+			result, err := t.rewindTask(ctx, step)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return t.record(ctx, record, model.RewindChanges(results)...)
+	})
+	if err != nil {
+		return nil, wrap(err, "task", "rewind tasks")
+	}
+	return results, nil
+}
+
+func (t *tx) rewindTask(ctx context.Context, step model.RewindStep) (model.RewindResult, error) {
+	result := model.RewindResult{}
+
+	current, found, err := t.anyTask(ctx, step.TaskID)
+	if err != nil {
+		return result, err
+	}
+
+	switch {
+	case !found && step.State == nil:
+		return result, apperror.New(apperror.ErrConflict, fmt.Sprintf("task %d has been purged", step.TaskID))
+
+	case !found:
+		err = t.recreateTask(ctx, *step.State)
+
+	case step.Expected != 0 && current.Version != step.Expected:
+		return result, apperror.New(apperror.ErrConflict, fmt.Sprintf("task %d has changed since", step.TaskID))
+
+	case step.State == nil:
+		result.Before = &current
+		if current.DeletedAt == nil {
+			var parentID *int64
+			if parentID, err = t.trashTask(ctx, current.ID, current.Version); err == nil {
+				err = t.rollUp(ctx, parentID)
+			}
+		}
+
+	default:
+		result.Before = &current
+		err = t.rewriteTask(ctx, current, *step.State)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.After, _, err = t.anyTask(ctx, step.TaskID)
+	return result, err
+}
+
+// rewriteTask takes current out of the trash when it is there and writes
+// state over it. A subtask keeps the list of its parent, which may have
+// moved since, and a task whose list has been deleted since is in no list.
+func (t *tx) rewriteTask(ctx context.Context, current, state model.TaskModel) error {
+	if current.DeletedAt != nil {
+		if err := t.restoreTask(ctx, current.ID); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if current.ParentID != nil {
+		var listID sql.NullInt64
+		err = t.QueryRowContext(ctx, `SELECT list_id FROM tasks WHERE id = ?`, *current.ParentID).Scan(&listID)
+		state.ListID = int64Ptr(listID)
+	} else {
+		state.ListID, err = t.keptList(ctx, state.ListID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkValues(state); err != nil {
+		return err
+	}
+
+	rank := current.Rank
+	if state.Rank != "" {
+		rank = state.Rank
+	}
+	_, err = t.ExecContext(ctx, `UPDATE tasks SET task_name = ?, is_done = ?, due_at = ?, timezone = ?, remind_at = ?, priority = ?, list_id = ?, position = ?, rrule = ?, repeat_from = ?, rank = ?, version = version + 1 WHERE id = ?`,
+		state.TaskName, state.IsDone, nullTimeArg(state.DueAt), state.Timezone, nullTimeArg(state.RemindAt), int64(state.Priority), nullInt64Arg(state.ListID), state.Position, state.RRule, state.RepeatFrom, rank, current.ID)
+	if err != nil {
+		return err
+	}
+	if err := t.saveTags(ctx, current.ID, state.Tags); err != nil {
+		return err
+	}
+
+	if current.ParentID == nil {
+		if err := t.moveSubtasks(ctx, current.ID, state.ListID); err != nil {
+			return err
+		}
+	}
+
+	return t.rollUp(ctx, current.ParentID)
+}
+
+// recreateTask creates a purged task again with its id, in no list when its
+// list has been deleted since. A subtask needs its parent back first.
+func (t *tx) recreateTask(ctx context.Context, state model.TaskModel) error {
+	var err error
+	if state.ParentID != nil {
+		parent, found, err := t.anyTask(ctx, *state.ParentID)
+		if err != nil {
+			return err
+		}
+		if !found || parent.DeletedAt != nil {
+			return apperror.New(apperror.ErrConflict, "the parent task is gone, bring it back first")
+		}
+		state.ListID = parent.ListID
+	} else if state.ListID, err = t.keptList(ctx, state.ListID); err != nil {
+		return err
+	}
+
+	found, err := t.exists(ctx, `SELECT 1 FROM tasks WHERE id = ?`, state.ID)
+	if err != nil {
+		return err
+	}
+	if found {
+		return conflict("task")
+	}
+	if err := t.checkTask(ctx, state); err != nil {
+		return err
+	}
+
+	state.Version++
+	if _, err := t.saveTask(ctx, state, nil); err != nil {
+		return err
+	}
+
+	return t.rollUp(ctx, state.ParentID)
+}
+
+// keptList returns listID while the list of the owner is still there.
+func (t *tx) keptList(ctx context.Context, listID *int64) (*int64, error) {
+	if listID == nil {
+		return nil, nil
+	}
+	if err := t.checkList(ctx, listID); err != nil {
+		if errors.Is(err, apperror.ErrValidation) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return listID, nil
+}
@@ -0,0 +1,182 @@
+-- the schema of schema/tasks.sql in SQLite. Times are kept as text in UTC,
+-- written by timeArg, so they compare in order. Version 1 of user_version.
+CREATE TABLE users(
+	id integer PRIMARY KEY AUTOINCREMENT,
+	email text NOT NULL,
+	password_hash text NOT NULL,
+	created_at text NOT NULL,
+	CONSTRAINT users_email_key UNIQUE (email)
+);
+
+CREATE TABLE refresh_tokens(
+	token_hash text NOT NULL,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	expires_at text NOT NULL,
+	created_at text NOT NULL,
+	CONSTRAINT refresh_tokens_pk PRIMARY KEY (token_hash)
+);
+
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+
+-- the default workspace is inserted by migrate
+CREATE TABLE workspaces(
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name text NOT NULL,
+	slug text NOT NULL,
+	owner_id integer REFERENCES users (id) ON DELETE SET NULL,
+	created_at text NOT NULL,
+	CONSTRAINT workspaces_slug_key UNIQUE (slug)
+);
+
+CREATE TABLE workspace_members(
+	workspace_id integer NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at text NOT NULL,
+	CONSTRAINT workspace_members_pk PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX workspace_members_user_id_idx ON workspace_members (user_id);
+
+CREATE TABLE api_keys(
+	id integer PRIMARY KEY AUTOINCREMENT,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	prefix text NOT NULL,
+	key_hash text NOT NULL,
+	read_only integer NOT NULL DEFAULT 0,
+	created_at text NOT NULL,
+	last_used_at text,
+	workspace_id integer REFERENCES workspaces (id) ON DELETE CASCADE,
+	CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash)
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id);
+
+CREATE TABLE lists(
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name text NOT NULL,
+	owner_id integer REFERENCES users (id),
+	CONSTRAINT lists_id_owner_key UNIQUE (id, owner_id)
+);
+
+CREATE INDEX lists_owner_id_idx ON lists (owner_id);
+
+CREATE TABLE list_members(
+	list_id integer NOT NULL REFERENCES lists (id) ON DELETE CASCADE,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	role text NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+	created_at text NOT NULL,
+	accepted_at text,
+	CONSTRAINT list_members_pk PRIMARY KEY (list_id, user_id)
+);
+
+CREATE INDEX list_members_user_id_idx ON list_members (user_id);
+
+CREATE TABLE tasks(
+	id integer PRIMARY KEY AUTOINCREMENT,
+	task_name text NOT NULL,
+	is_done integer NOT NULL,
+	due_at text,
+	timezone text NOT NULL DEFAULT 'UTC',
+	remind_at text,
+	priority integer NOT NULL DEFAULT 0,
+	list_id integer,
+	parent_id integer,
+	position integer NOT NULL DEFAULT 0,
+	version integer NOT NULL DEFAULT 1,
+	deleted_at text,
+	rrule text NOT NULL DEFAULT '',
+	repeat_from text NOT NULL DEFAULT '',
+	rank text NOT NULL DEFAULT '',
+	owner_id integer REFERENCES users (id),
+	workspace_id integer NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	CONSTRAINT tasks_remind_before_due CHECK (remind_at IS NULL OR due_at IS NULL OR remind_at <= due_at),
+	CONSTRAINT tasks_priority_range CHECK (priority BETWEEN 0 AND 3),
+	CONSTRAINT tasks_repeat_from_check CHECK (repeat_from IN ('', 'due', 'completion')),
+	CONSTRAINT tasks_list_id_fkey FOREIGN KEY (list_id) REFERENCES lists (id),
+	CONSTRAINT tasks_list_owner_fkey FOREIGN KEY (list_id, owner_id) REFERENCES lists (id, owner_id),
+	CONSTRAINT tasks_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE INDEX tasks_due_at_idx ON tasks (due_at);
+
+CREATE INDEX tasks_list_id_idx ON tasks (list_id);
+
+CREATE INDEX tasks_parent_id_idx ON tasks (parent_id, position);
+
+CREATE INDEX tasks_owner_rank_idx ON tasks (owner_id, rank, id);
+
+CREATE INDEX tasks_workspace_owner_idx ON tasks (workspace_id, owner_id);
+
+CREATE INDEX tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE tags(
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name text NOT NULL,
+	owner_id integer REFERENCES users (id),
+	CONSTRAINT tags_owner_name_key UNIQUE (owner_id, name)
+);
+
+CREATE TABLE task_tags(
+	task_id integer NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	tag_id integer NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+	CONSTRAINT task_tags_pk PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX task_tags_tag_id_idx ON task_tags (tag_id);
+
+CREATE TABLE task_dependencies(
+	task_id integer NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	blocked_by integer NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	created_at text NOT NULL,
+	CONSTRAINT task_dependencies_pk PRIMARY KEY (task_id, blocked_by),
+	CONSTRAINT task_dependencies_not_self CHECK (task_id <> blocked_by)
+);
+
+CREATE INDEX task_dependencies_blocked_by_idx ON task_dependencies (blocked_by);
+
+CREATE TABLE share_links(
+	id integer PRIMARY KEY AUTOINCREMENT,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	list_id integer REFERENCES lists (id) ON DELETE CASCADE,
+	task_id integer REFERENCES tasks (id) ON DELETE CASCADE,
+	expires_at text,
+	created_at text NOT NULL,
+	workspace_id integer NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	CONSTRAINT share_links_target_check CHECK ((list_id IS NULL) <> (task_id IS NULL))
+);
+
+CREATE INDEX share_links_user_id_idx ON share_links (user_id);
+
+CREATE TABLE task_events(
+	id integer PRIMARY KEY AUTOINCREMENT,
+	task_id integer NOT NULL,
+	action text NOT NULL,
+	before text,
+	after text,
+	actor text NOT NULL,
+	request_id text NOT NULL DEFAULT '',
+	created_at text NOT NULL,
+	reverts integer REFERENCES task_events (id),
+	owner_id integer REFERENCES users (id),
+	workspace_id integer NOT NULL,
+	user_id integer
+);
+
+CREATE INDEX task_events_task_id_idx ON task_events (task_id, id);
+
+CREATE INDEX task_events_reverts_idx ON task_events (reverts) WHERE reverts IS NOT NULL;
+
+CREATE INDEX task_events_workspace_owner_idx ON task_events (workspace_id, owner_id, id);
+
+CREATE INDEX task_events_workspace_user_idx ON task_events (workspace_id, user_id, id);
+
+CREATE TRIGGER task_events_no_update BEFORE UPDATE ON task_events
+BEGIN
+	SELECT RAISE(ABORT, 'task_events is append only');
+END;
+
+CREATE TRIGGER task_events_no_delete BEFORE DELETE ON task_events
+BEGIN
+	SELECT RAISE(ABORT, 'task_events is append only');
+END;
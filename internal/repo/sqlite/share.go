@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	model "to-do-list/internal/model/share"
+	"to-do-list/pkg/requestinfo"
+)
+
+// linkColumns are read by scanLink.
+const linkColumns = `id, list_id, task_id, expires_at, created_at, user_id, workspace_id`
+
+type ShareRepo struct {
+	store *Store
+}
+
+func NewShareRepository(store *Store) *ShareRepo {
+	return &ShareRepo{
+		store: store,
+	}
+}
+
+// Create stores a link created by the signed in user in the workspace of
+// the request. A link goes to a list or to a task, never both.
+func (r *ShareRepo) Create(ctx context.Context, link model.LinkModel) (model.LinkModel, error) {
+	link.UserID, link.WorkspaceID = requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx)
+	err := r.store.write(ctx, func(t *tx) error {
+		if (link.ListID == nil) == (link.TaskID == nil) {
+			return rejected("share link")
+		}
+
+		link.CreatedAt, link.URL = t.now, ""
+		result, err := t.ExecContext(ctx, `INSERT INTO share_links (user_id, list_id, task_id, expires_at, created_at, workspace_id) VALUES (?, ?, ?, ?, ?, ?)`,
+			link.UserID, nullInt64Arg(link.ListID), nullInt64Arg(link.TaskID), nullTimeArg(link.ExpiresAt), timeArg(link.CreatedAt), link.WorkspaceID)
+		if err != nil {
+			return err
+		}
+		link.ID, err = result.LastInsertId()
+		return err
+	})
+	return link, wrap(err, "share link", "create share link")
+}
+
+// GetAll returns the links the signed in user created in the workspace of
+// the request.
+func (r *ShareRepo) GetAll(ctx context.Context) ([]model.LinkModel, error) {
+	links := []model.LinkModel{}
+	err := r.store.read(ctx, func(t *tx) error {
+		rows, err := t.QueryContext(ctx, `SELECT `+linkColumns+` FROM share_links WHERE user_id = ? AND workspace_id = ? ORDER BY id`,
+			requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			link, err := scanLink(rows)
+			if err != nil {
+				return err
+			}
+			links = append(links, link)
+		}
+		return rows.Err()
+	})
+	return links, wrap(err, "share link", "fetch share links")
+}
+
+// GetByID reads any link, it serves visitors who are not signed in.
+func (r *ShareRepo) GetByID(ctx context.Context, id int64) (model.LinkModel, error) {
+	var link model.LinkModel
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		link, err = scanLink(t.QueryRowContext(ctx, `SELECT `+linkColumns+` FROM share_links WHERE id = ?`, id))
+		return err
+	})
+	return link, wrap(err, "share link", "fetch share link")
+}
+
+// Delete revokes a link of the signed in user in the workspace of the
+// request.
+func (r *ShareRepo) Delete(ctx context.Context, id int64) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		result, err := t.ExecContext(ctx, `DELETE FROM share_links WHERE id = ? AND user_id = ? AND workspace_id = ?`,
+			id, requestinfo.UserID(ctx), requestinfo.WorkspaceID(ctx))
+		if err != nil {
+			return err
+		}
+		return mustAffect(result, notFound("share link"))
+	})
+	return wrap(err, "share link", "delete share link")
+}
+
+// scanLink reads a row selected with linkColumns.
+func scanLink(row scanner) (model.LinkModel, error) {
+	var (
+		link           model.LinkModel
+		listID, taskID sql.NullInt64
+		expiresAt      nullTime
+	)
+	if err := row.Scan(&link.ID, &listID, &taskID, &expiresAt, &timeValue{&link.CreatedAt}, &link.UserID, &link.WorkspaceID); err != nil {
+		return link, err
+	}
+	link.ListID = int64Ptr(listID)
+	link.TaskID = int64Ptr(taskID)
+	link.ExpiresAt = expiresAt.Time
+	return link, nil
+}
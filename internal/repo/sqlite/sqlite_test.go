@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	model "to-do-list/internal/model/task"
+	usermodel "to-do-list/internal/model/user"
+	workspacemodel "to-do-list/internal/model/workspace"
+	"to-do-list/internal/repo/repotest"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Backend {
+		store, err := Open(filepath.Join(t.TempDir(), "todo.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return repotest.Backend{
+			Tasks:      NewTaskRepository(store),
+			Lists:      NewListRepository(store),
+			Tags:       NewTagRepository(store),
+			Shares:     NewShareRepository(store),
+			Users:      NewUserRepository(store),
+			Workspaces: NewWorkspaceRepository(store),
+		}
+	})
+}
+
+// TestUpgrade opens a database of version 1, whose events have no user, and
+// finds the change of a user by its actor.
+func TestUpgrade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.db")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := NewUserRepository(store).Create(context.Background(), usermodel.UserModel{Email: "ann@example.com", PasswordHash: "hash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := requestinfo.WithWorkspaceID(requestinfo.WithUserID(context.Background(), user.ID), workspacemodel.DefaultID)
+	if err := NewTaskRepository(store).AppendEvents(ctx, model.TaskEvent{TaskID: 1, Action: model.EventDelete, Actor: user.Email}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.db.Exec(`DROP INDEX task_events_workspace_user_idx; ALTER TABLE task_events DROP COLUMN user_id; PRAGMA user_version = 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	events, err := NewTaskRepository(store).GetUndoableEvents(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
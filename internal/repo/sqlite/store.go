@@ -0,0 +1,240 @@
+// Package sqlite keeps the data of every repo in one SQLite file, so the
+// server runs on a single machine without Postgres and Redis and keeps its
+// data across restarts. The driver is pure Go, it needs no cgo. The repos
+// follow the schema of schema/: the same scoping, constraints, cascades and
+// versions, which internal/repo/repotest checks against every backend.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+	workspacemodel "to-do-list/internal/model/workspace"
+
+	_ "modernc.org/sqlite"
+)
+
+// Driver is the value of database.driver that selects this backend.
+const Driver = "sqlite"
+
+// schema creates the tables of an empty database. It is version
+// schemaVersion of the database, kept in PRAGMA user_version.
+//
+//go:embed schema.sql
+var schema string
+
+const schemaVersion = 2
+
+// upgrades take a database of the version of their index to the next one,
+// like the numbered migrations of schema/ for Postgres.
+var upgrades = []string{
+	1: upgradeEventUsers,
+}
+
+// upgradeEventUsers is schema/18_event_users. SQLite can not disable a
+// trigger, the append only one is dropped while the events of before get
+// the user of their actor.
+const upgradeEventUsers = `ALTER TABLE task_events ADD COLUMN user_id integer;
+DROP TRIGGER task_events_no_update;
+UPDATE task_events SET user_id = (SELECT id FROM users WHERE users.email = task_events.actor);
+CREATE TRIGGER task_events_no_update BEFORE UPDATE ON task_events
+BEGIN
+	SELECT RAISE(ABORT, 'task_events is append only');
+END;
+CREATE INDEX task_events_workspace_user_idx ON task_events (workspace_id, user_id, id);`
+
+// errRollback ends a write whose changes are dropped without it failing.
+var errRollback = errors.New("sqlite: rollback")
+
+// timeLayout writes every time in UTC with the same width, so times compare
+// in order as text.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// Store is the database of every repo. It holds a single connection, which
+// serializes every read and write of the process like one lock.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the database file at path, creating it with the schema when it
+// does not exist yet.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open(Driver, path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// migrate creates the schema in an empty database, upgrades a database of an
+// earlier version and refuses one of a later version than this build knows.
+func (s *Store) migrate(ctx context.Context) error {
+	return s.write(ctx, func(t *tx) error {
+		var version int
+		if err := t.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+			return fmt.Errorf("read schema version: %w", err)
+		}
+		switch {
+		case version == schemaVersion:
+			return nil
+		case version > schemaVersion:
+			return fmt.Errorf("database schema version %d is newer than %d", version, schemaVersion)
+		case version == 0:
+			if _, err := t.ExecContext(ctx, schema); err != nil {
+				return fmt.Errorf("create schema: %w", err)
+			}
+			// the default workspace comes with the schema, like in schema/17
+			if _, err := t.ExecContext(ctx, `INSERT INTO workspaces (id, name, slug, created_at) VALUES (?, 'Default', 'default', ?)`, workspacemodel.DefaultID, timeArg(t.now)); err != nil {
+				return fmt.Errorf("create default workspace: %w", err)
+			}
+		default:
+			for ; version < schemaVersion; version++ {
+				if _, err := t.ExecContext(ctx, upgrades[version]); err != nil {
+					return fmt.Errorf("upgrade schema to version %d: %w", version+1, err)
+				}
+			}
+		}
+		if _, err := t.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, schemaVersion)); err != nil {
+			return fmt.Errorf("write schema version: %w", err)
+		}
+		return nil
+	})
+}
+
+// tx is a transaction with the time every change of it shares.
+type tx struct {
+	*sql.Tx
+	now time.Time
+}
+
+// read runs fn in a transaction that is rolled back after it. fn must not
+// write.
+func (s *Store) read(ctx context.Context, fn func(t *tx) error) error {
+	sqlTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer sqlTx.Rollback()
+
+	return fn(&tx{Tx: sqlTx, now: time.Now()})
+}
+
+// write runs fn in a transaction and commits it only when fn returns nil,
+// errRollback rolls it back and write returns nil.
+func (s *Store) write(ctx context.Context, fn func(t *tx) error) error {
+	sqlTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer sqlTx.Rollback()
+
+	if err := fn(&tx{Tx: sqlTx, now: time.Now()}); err == errRollback {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return sqlTx.Commit()
+}
+
+// savepoint runs fn in a savepoint of the transaction, which is rolled back
+// when fn fails. The transaction goes on either way.
+func (t *tx) savepoint(ctx context.Context, fn func() error) error {
+	if _, err := t.ExecContext(ctx, `SAVEPOINT operation`); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		if _, rollbackErr := t.ExecContext(ctx, `ROLLBACK TO operation`); rollbackErr != nil {
+			return rollbackErr
+		}
+		if _, releaseErr := t.ExecContext(ctx, `RELEASE operation`); releaseErr != nil {
+			return releaseErr
+		}
+		return err
+	}
+	_, err := t.ExecContext(ctx, `RELEASE operation`)
+	return err
+}
+
+// timeArg is the stored form of a time.
+func timeArg(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func nullTimeArg(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return timeArg(*t)
+}
+
+func nullInt64Arg(n *int64) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+// nullTime reads a time written by timeArg, NULL as nil.
+type nullTime struct {
+	Time *time.Time
+}
+
+func (n *nullTime) Scan(src interface{}) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		n.Time = nil
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("read time: unexpected %T", src)
+	}
+
+	t, err := time.Parse(timeLayout, text)
+	if err != nil {
+		return fmt.Errorf("read time: %w", err)
+	}
+	n.Time = &t
+	return nil
+}
+
+// timeValue is nullTime for NOT NULL columns, read into Time.
+type timeValue struct {
+	Time *time.Time
+}
+
+func (v *timeValue) Scan(src interface{}) error {
+	n := nullTime{}
+	if err := n.Scan(src); err != nil {
+		return err
+	}
+	if n.Time == nil {
+		return errors.New("read time: unexpected NULL")
+	}
+	*v.Time = *n.Time
+	return nil
+}
+
+func int64Ptr(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
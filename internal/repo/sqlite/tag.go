@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	model "to-do-list/internal/model/tag"
+	"to-do-list/pkg/requestinfo"
+)
+
+// tagColumns read a tag with the number of tasks carrying it, trashed ones
+// too.
+const tagColumns = `tags.id, tags.name, (SELECT COUNT(*) FROM task_tags WHERE task_tags.tag_id = tags.id)`
+
+type TagRepo struct {
+	store *Store
+}
+
+func NewTagRepository(store *Store) *TagRepo {
+	return &TagRepo{
+		store: store,
+	}
+}
+
+func (r *TagRepo) GetAll(ctx context.Context) ([]model.TagModel, error) {
+	tags := []model.TagModel{}
+	err := r.store.read(ctx, func(t *tx) error {
+		rows, err := t.QueryContext(ctx, `SELECT `+tagColumns+` FROM tags WHERE owner_id = ? ORDER BY name`, requestinfo.OwnerID(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var tag model.TagModel
+			if err := rows.Scan(&tag.ID, &tag.Name, &tag.TaskCount); err != nil {
+				return err
+			}
+			tags = append(tags, tag)
+		}
+		return rows.Err()
+	})
+	return tags, wrap(err, "tag", "fetch tags")
+}
+
+func (r *TagRepo) Create(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+	err := r.store.write(ctx, func(t *tx) error {
+		owner := requestinfo.OwnerID(ctx)
+		if found, err := t.exists(ctx, `SELECT 1 FROM users WHERE id = ?`, owner); err != nil || !found {
+			return orRejected(err, "tag")
+		}
+		result, err := t.ExecContext(ctx, `INSERT INTO tags (name, owner_id) VALUES (?, ?)`, tag.Name, owner)
+		if err != nil {
+			return err
+		}
+		tag.ID, err = result.LastInsertId()
+		return err
+	})
+	return tag, wrap(err, "tag", "create tag")
+}
+
+// Rename changes the name in place, every task carrying the tag shows the
+// new name.
+func (r *TagRepo) Rename(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+	err := r.store.write(ctx, func(t *tx) error {
+		result, err := t.ExecContext(ctx, `UPDATE tags SET name = ? WHERE id = ? AND owner_id = ?`, tag.Name, tag.ID, requestinfo.OwnerID(ctx))
+		if err != nil {
+			return err
+		}
+		if err := mustAffect(result, notFound("tag")); err != nil {
+			return err
+		}
+		if err := t.touchTagged(ctx, tag.ID); err != nil {
+			return err
+		}
+		tag, err = t.ownTag(ctx, tag.ID)
+		return err
+	})
+	return tag, wrap(err, "tag", "rename tag")
+}
+
+// Merge moves the tasks of tag from to tag into and deletes tag from.
+func (r *TagRepo) Merge(ctx context.Context, from int64, into int64) (model.TagModel, error) {
+	var tag model.TagModel
+	err := r.store.write(ctx, func(t *tx) error {
+		if from == into {
+			return notFound("tag")
+		}
+		if _, err := t.ownTag(ctx, from); err != nil {
+			return err
+		}
+		if _, err := t.ownTag(ctx, into); err != nil {
+			return err
+		}
+
+		if err := t.touchTagged(ctx, from); err != nil {
+			return err
+		}
+		if _, err := t.ExecContext(ctx, `INSERT INTO task_tags (task_id, tag_id) SELECT task_id, ? FROM task_tags WHERE tag_id = ? ON CONFLICT DO NOTHING`, into, from); err != nil {
+			return err
+		}
+		if _, err := t.ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, from); err != nil {
+			return err
+		}
+
+		var err error
+		tag, err = t.ownTag(ctx, into)
+		return err
+	})
+	return tag, wrap(err, "tag", "merge tags")
+}
+
+func (r *TagRepo) Delete(ctx context.Context, tag model.TagModel) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		if _, err := t.ownTag(ctx, tag.ID); err != nil {
+			return err
+		}
+		if err := t.touchTagged(ctx, tag.ID); err != nil {
+			return err
+		}
+		_, err := t.ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, tag.ID)
+		return err
+	})
+	return wrap(err, "tag", "delete tag")
+}
+
+// ownTag finds a tag of the owner of ctx.
+func (t *tx) ownTag(ctx context.Context, id int64) (model.TagModel, error) {
+	var tag model.TagModel
+	err := t.QueryRowContext(ctx, `SELECT `+tagColumns+` FROM tags WHERE id = ? AND owner_id = ?`, id, requestinfo.OwnerID(ctx)).
+		Scan(&tag.ID, &tag.Name, &tag.TaskCount)
+	if err == sql.ErrNoRows {
+		return tag, notFound("tag")
+	}
+	return tag, err
+}
+
+// touchTagged raises the version of the tasks of the owner of ctx carrying
+// a tag, whose representation changes with the tag.
+func (t *tx) touchTagged(ctx context.Context, id int64) error {
+	_, err := t.ExecContext(ctx, `UPDATE tasks SET version = version + 1 WHERE owner_id = ? AND id IN (SELECT task_id FROM task_tags WHERE tag_id = ?)`,
+		requestinfo.OwnerID(ctx), id)
+	return err
+}
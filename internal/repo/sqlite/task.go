@@ -0,0 +1,499 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/rank"
+	"to-do-list/pkg/requestinfo"
+)
+
+// tagsColumn reads the names of the tags of a task as a JSON array.
+const tagsColumn = `(SELECT json_group_array(tags.name) FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id)`
+
+// taskColumns are read by scanTask.
+const taskColumns = `tasks.id, tasks.task_name, tasks.is_done, tasks.due_at, tasks.timezone, tasks.remind_at, tasks.priority, tasks.list_id, tasks.parent_id, ` +
+	`tasks.position, tasks.version, ` + tagsColumn + `, tasks.rrule, tasks.repeat_from, tasks.rank`
+
+// blockedColumn tells whether an open task out of the trash blocks the task.
+const blockedColumn = `EXISTS (SELECT 1 FROM task_dependencies JOIN tasks blocker ON blocker.id = task_dependencies.blocked_by ` +
+	`WHERE task_dependencies.task_id = tasks.id AND NOT blocker.is_done AND blocker.deleted_at IS NULL)`
+
+// selectTaskQuery is completed with WHERE, ORDER BY and LIMIT clauses by
+// buildListQuery.
+const selectTaskQuery = `SELECT ` + taskColumns + `, ` + blockedColumn + ` FROM tasks`
+
+// The queries below only see the tasks of one owner in one workspace,
+// passed as the last two arguments, trashed ones too unless they say
+// otherwise.
+const (
+	fetchTaskQuery     = `SELECT ` + taskColumns + `, tasks.deleted_at FROM tasks WHERE id = ? AND owner_id = ? AND workspace_id = ?`
+	fetchSubtasksQuery = `SELECT ` + taskColumns + `, tasks.deleted_at FROM tasks WHERE parent_id = ? AND deleted_at IS NULL AND owner_id = ? AND workspace_id = ? ORDER BY position, id`
+)
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// TaskRepo is the task repo of the usecase, with its audit log.
+type TaskRepo struct {
+	store *Store
+}
+
+func NewTaskRepository(store *Store) *TaskRepo {
+	return &TaskRepo{
+		store: store,
+	}
+}
+
+func (r *TaskRepo) GetByID(ctx context.Context, id int64) (model.TaskModel, error) {
+	var task model.TaskModel
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		task, err = t.liveTask(ctx, id)
+		return err
+	})
+	return task, wrap(err, "task", "fetch task")
+}
+
+func (r *TaskRepo) Create(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+	err := r.store.write(ctx, func(t *tx) error {
+		var err error
+		if task, err = t.insertTask(ctx, task); err != nil {
+			return err
+		}
+		created := task
+		return t.record(ctx, record, model.Change{After: &created})
+	})
+	return task, wrap(err, "task", "create task")
+}
+
+// GetSubtasks returns the subtasks of a task in their order.
+func (r *TaskRepo) GetSubtasks(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	var tasks []model.TaskModel
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		tasks, err = t.liveSubtasks(ctx, id)
+		return err
+	})
+	return tasks, wrap(err, "task", "fetch subtasks")
+}
+
+// Reorder numbers the subtasks of a task in the order of ids, which has to
+// list every one of them once. Every subtask is a change to record.
+func (r *TaskRepo) Reorder(ctx context.Context, id int64, ids []int64, record model.Record) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		subtasks, err := t.liveSubtasks(ctx, id)
+		if err != nil {
+			return err
+		}
+		current := map[int64]bool{}
+		for _, subtask := range subtasks {
+			current[subtask.ID] = true
+		}
+
+		seen := map[int64]bool{}
+		for _, subtaskID := range ids {
+			if !current[subtaskID] || seen[subtaskID] {
+				return apperror.New(apperror.ErrValidation, "ids must list every subtask once")
+			}
+			seen[subtaskID] = true
+		}
+		if len(seen) != len(current) {
+			return apperror.New(apperror.ErrValidation, "ids must list every subtask once")
+		}
+
+		for position, subtaskID := range ids {
+			if _, err := t.ExecContext(ctx, `UPDATE tasks SET position = ?, version = version + 1 WHERE id = ?`, position, subtaskID); err != nil {
+				return err
+			}
+		}
+
+		if record == nil {
+			return nil
+		}
+		after, err := t.liveSubtasks(ctx, id)
+		if err != nil {
+			return err
+		}
+		return t.record(ctx, record, model.Changes(subtasks, after)...)
+	})
+	return wrap(err, "task", "reorder subtasks")
+}
+
+// SetDone changes only the status of a task.
+func (r *TaskRepo) SetDone(ctx context.Context, id int64, done bool, record model.Record) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		before, err := t.liveTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := t.ExecContext(ctx, `UPDATE tasks SET is_done = ?, version = version + 1 WHERE id = ?`, done, id); err != nil {
+			return err
+		}
+		return t.recordUpdate(ctx, record, before)
+	})
+	return wrap(err, "task", "set task status")
+}
+
+func (r *TaskRepo) Update(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+	err := r.store.write(ctx, func(t *tx) error {
+		before, err := t.liveTask(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		if task, err = t.updateTask(ctx, task); err != nil {
+			return err
+		}
+		after := task
+		return t.record(ctx, record, model.Change{Before: &before, After: &after})
+	})
+	return task, wrap(err, "task", "update task")
+}
+
+// Move changes the list of a task and its subtasks and returns the moved
+// task.
+func (r *TaskRepo) Move(ctx context.Context, id int64, listID *int64, record model.Record) (model.TaskModel, error) {
+	var task model.TaskModel
+	err := r.store.write(ctx, func(t *tx) error {
+		before, err := t.liveTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := t.checkList(ctx, listID); err != nil {
+			return err
+		}
+		if _, err := t.ExecContext(ctx, `UPDATE tasks SET list_id = ?, version = version + 1 WHERE id = ?`, nullInt64Arg(listID), id); err != nil {
+			return err
+		}
+		if err := t.moveSubtasks(ctx, id, listID); err != nil {
+			return err
+		}
+
+		if task, err = t.liveTask(ctx, id); err != nil {
+			return err
+		}
+		return t.record(ctx, record, model.Change{Before: &before, After: &task})
+	})
+	return task, wrap(err, "task", "move task")
+}
+
+// Delete moves a task and its subtasks to the trash.
+func (r *TaskRepo) Delete(ctx context.Context, task model.TaskModel, record model.Record) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		before, err := t.liveTask(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		if _, err := t.trashTask(ctx, task.ID, task.Version); err != nil {
+			return err
+		}
+		return t.record(ctx, record, model.Change{Before: &before})
+	})
+	return wrap(err, "task", "delete task")
+}
+
+// scanTask reads a row selected with taskColumns, followed by the columns
+// read into extra.
+func scanTask(row scanner, extra ...interface{}) (model.TaskModel, error) {
+	var (
+		task     model.TaskModel
+		dueAt    nullTime
+		remindAt nullTime
+		listID   sql.NullInt64
+		parentID sql.NullInt64
+		tags     string
+	)
+
+	dest := []interface{}{&task.ID, &task.TaskName, &task.IsDone, &dueAt, &task.Timezone, &remindAt, &task.Priority, &listID, &parentID, &task.Position, &task.Version, &tags, &task.RRule, &task.RepeatFrom, &task.Rank}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return task, err
+	}
+
+	if err := json.Unmarshal([]byte(tags), &task.Tags); err != nil {
+		return task, fmt.Errorf("read tags: %w", err)
+	}
+	if len(task.Tags) == 0 {
+		task.Tags = nil
+	}
+	sort.Strings(task.Tags)
+
+	task.DueAt = dueAt.Time
+	task.RemindAt = remindAt.Time
+	task.ListID = int64Ptr(listID)
+	task.ParentID = int64Ptr(parentID)
+
+	return task, nil
+}
+
+// scanAnyTask reads a row selected with taskColumns and the deleted_at
+// column.
+func scanAnyTask(row scanner) (model.TaskModel, error) {
+	var deletedAt nullTime
+	task, err := scanTask(row, &deletedAt)
+	task.DeletedAt = deletedAt.Time
+	return task, err
+}
+
+// scanListedTask reads a row selected with taskColumns and blockedColumn.
+func scanListedTask(row scanner) (model.TaskModel, error) {
+	var blocked bool
+	task, err := scanTask(row, &blocked)
+	task.Blocked = blocked
+	return task, err
+}
+
+// queryTasks runs a query of tasks and reads every row with scan.
+func (t *tx) queryTasks(ctx context.Context, scan func(row scanner) (model.TaskModel, error), query string, args ...interface{}) ([]model.TaskModel, error) {
+	rows, err := t.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []model.TaskModel{}
+	for rows.Next() {
+		task, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// queryIDs runs a query returning one id a row.
+func (t *tx) queryIDs(ctx context.Context, query string, args ...interface{}) ([]int64, error) {
+	rows, err := t.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// anyTask finds a task of the owner of ctx in its workspace, trashed or
+// not.
+func (t *tx) anyTask(ctx context.Context, id int64) (model.TaskModel, bool, error) {
+	task, err := scanAnyTask(t.QueryRowContext(ctx, fetchTaskQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)))
+	if err == sql.ErrNoRows {
+		return task, false, nil
+	}
+	return task, err == nil, err
+}
+
+// liveTask is anyTask for tasks out of the trash, the others are not found.
+func (t *tx) liveTask(ctx context.Context, id int64) (model.TaskModel, error) {
+	task, found, err := t.anyTask(ctx, id)
+	if err != nil {
+		return task, err
+	}
+	if !found || task.DeletedAt != nil {
+		return model.TaskModel{}, notFound("task")
+	}
+	return task, nil
+}
+
+// liveSubtasks returns the subtasks of a task out of the trash, in their
+// order.
+func (t *tx) liveSubtasks(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	return t.queryTasks(ctx, scanAnyTask, fetchSubtasksQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+}
+
+// insertTask saves a new task of the owner of ctx in its workspace. A
+// subtask goes after the other subtasks of its parent, every task after
+// every other task of the owner in the manual order.
+func (t *tx) insertTask(ctx context.Context, task model.TaskModel) (model.TaskModel, error) {
+	var last string
+	if err := t.QueryRowContext(ctx, `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE owner_id = ?`, requestinfo.OwnerID(ctx)).Scan(&last); err != nil {
+		return task, err
+	}
+	key, err := rank.After(last)
+	if err != nil {
+		return task, fmt.Errorf("create task: %w", err)
+	}
+	task.Rank = key
+
+	if err := t.checkTask(ctx, task); err != nil {
+		return task, err
+	}
+
+	task.Position = 0
+	if task.ParentID != nil {
+		if err := t.QueryRowContext(ctx, `SELECT COALESCE(MAX(position) + 1, 0) FROM tasks WHERE parent_id = ?`, *task.ParentID).Scan(&task.Position); err != nil {
+			return task, err
+		}
+	}
+
+	task.ID, task.Version = 0, 1
+	if task.ID, err = t.saveTask(ctx, task, nil); err != nil {
+		return task, err
+	}
+
+	return task, nil
+}
+
+// saveTask inserts task as a task of the owner of ctx in its workspace,
+// with the id of task unless it is 0, and returns its id.
+func (t *tx) saveTask(ctx context.Context, task model.TaskModel, deletedAt *time.Time) (int64, error) {
+	var id interface{}
+	if task.ID != 0 {
+		id = task.ID
+	}
+
+	result, err := t.ExecContext(ctx, `INSERT INTO tasks (id, task_name, is_done, due_at, timezone, remind_at, priority, list_id, parent_id, position, version, deleted_at, rrule, repeat_from, rank, owner_id, workspace_id) `+
+		`VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, task.TaskName, task.IsDone, nullTimeArg(task.DueAt), task.Timezone, nullTimeArg(task.RemindAt), int64(task.Priority), nullInt64Arg(task.ListID), nullInt64Arg(task.ParentID),
+		task.Position, task.Version, nullTimeArg(deletedAt), task.RRule, task.RepeatFrom, task.Rank, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		return 0, err
+	}
+	if task.ID, err = result.LastInsertId(); err != nil {
+		return 0, err
+	}
+
+	return task.ID, t.saveTags(ctx, task.ID, task.Tags)
+}
+
+// saveTags replaces the tags of a task of the owner of ctx with the tags
+// named names, created for the owner when they do not have them yet.
+func (t *tx) saveTags(ctx context.Context, id int64, names []string) error {
+	owner := requestinfo.OwnerID(ctx)
+
+	if _, err := t.ExecContext(ctx, `DELETE FROM task_tags WHERE task_id = ?`, id); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err := t.ExecContext(ctx, `INSERT INTO tags (name, owner_id) VALUES (?, ?) ON CONFLICT (owner_id, name) DO NOTHING`, name, owner); err != nil {
+			return err
+		}
+		if _, err := t.ExecContext(ctx, `INSERT INTO task_tags (task_id, tag_id) SELECT ?, id FROM tags WHERE owner_id = ? AND name = ? ON CONFLICT DO NOTHING`, id, owner, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTask enforces the foreign keys of a task of the owner of ctx with
+// the errors of the memory backend, before the constraints do.
+func (t *tx) checkTask(ctx context.Context, task model.TaskModel) error {
+	if found, err := t.exists(ctx, `SELECT 1 FROM users WHERE id = ?`, requestinfo.OwnerID(ctx)); err != nil || !found {
+		return orRejected(err, "task")
+	}
+	if found, err := t.exists(ctx, `SELECT 1 FROM workspaces WHERE id = ?`, requestinfo.WorkspaceID(ctx)); err != nil || !found {
+		return orRejected(err, "task")
+	}
+	if err := checkValues(task); err != nil {
+		return err
+	}
+	if task.ParentID != nil {
+		if found, err := t.exists(ctx, `SELECT 1 FROM tasks WHERE id = ?`, *task.ParentID); err != nil || !found {
+			return orRejected(err, "task")
+		}
+	}
+	return t.checkList(ctx, task.ListID)
+}
+
+// checkValues enforces the check constraints of the tasks table.
+func checkValues(task model.TaskModel) error {
+	if task.Priority < model.PriorityNone || task.Priority > model.PriorityHigh {
+		return rejected("task")
+	}
+	if task.RemindAt != nil && task.DueAt != nil && task.RemindAt.After(*task.DueAt) {
+		return rejected("task")
+	}
+	switch task.RepeatFrom {
+	case "", model.RepeatFromDue, model.RepeatFromCompletion:
+		return nil
+	}
+	return rejected("task")
+}
+
+// checkList only lets a task in a list of its owner, a list of another user
+// is as good as missing.
+func (t *tx) checkList(ctx context.Context, listID *int64) error {
+	if listID == nil {
+		return nil
+	}
+	found, err := t.exists(ctx, `SELECT 1 FROM lists WHERE id = ? AND owner_id = ?`, *listID, requestinfo.OwnerID(ctx))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return apperror.New(apperror.ErrValidation, "list not found")
+	}
+	return nil
+}
+
+// exists tells whether query returns a row.
+func (t *tx) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var one int
+	err := t.QueryRowContext(ctx, query, args...).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// orRejected returns err, or rejected(entity) when there is none.
+func orRejected(err error, entity string) error {
+	if err != nil {
+		return err
+	}
+	return rejected(entity)
+}
+
+// updateTask saves task and returns it with its new version. Its subtasks
+// follow it to its list.
+func (t *tx) updateTask(ctx context.Context, task model.TaskModel) (model.TaskModel, error) {
+	current, err := t.liveTask(ctx, task.ID)
+	if err != nil {
+		return task, err
+	}
+	if task.Version != 0 && task.Version != current.Version {
+		return task, apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+	}
+	if err := checkValues(task); err != nil {
+		return task, err
+	}
+	if err := t.checkList(ctx, task.ListID); err != nil {
+		return task, err
+	}
+
+	_, err = t.ExecContext(ctx, `UPDATE tasks SET task_name = ?, is_done = ?, due_at = ?, timezone = ?, remind_at = ?, priority = ?, list_id = ?, rrule = ?, repeat_from = ?, version = version + 1 WHERE id = ?`,
+		task.TaskName, task.IsDone, nullTimeArg(task.DueAt), task.Timezone, nullTimeArg(task.RemindAt), int64(task.Priority), nullInt64Arg(task.ListID), task.RRule, task.RepeatFrom, task.ID)
+	if err != nil {
+		return task, err
+	}
+	if err := t.saveTags(ctx, task.ID, task.Tags); err != nil {
+		return task, err
+	}
+	task.Version = current.Version + 1
+
+	if err := t.moveSubtasks(ctx, task.ID, task.ListID); err != nil {
+		return task, err
+	}
+
+	return task, nil
+}
+
+// moveSubtasks puts the subtasks of a task out of the trash in a list.
+func (t *tx) moveSubtasks(ctx context.Context, id int64, listID *int64) error {
+	_, err := t.ExecContext(ctx, `UPDATE tasks SET list_id = ?, version = version + 1 WHERE parent_id = ? AND deleted_at IS NULL AND owner_id = ? AND workspace_id = ?`,
+		nullInt64Arg(listID), id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	return err
+}
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// GetTrash returns the trashed tasks, most recently trashed first.
+func (r *TaskRepo) GetTrash(ctx context.Context) ([]model.TaskModel, error) {
+	var tasks []model.TaskModel
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		tasks, err = t.queryTasks(ctx, scanAnyTask, `SELECT `+taskColumns+`, tasks.deleted_at FROM tasks WHERE deleted_at IS NOT NULL AND owner_id = ? AND workspace_id = ? ORDER BY deleted_at DESC, id`,
+			requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+		return err
+	})
+	return tasks, wrap(err, "task", "fetch trash")
+}
+
+// Restore takes a task out of the trash together with the subtasks that
+// were trashed with it and returns the restored task. A subtask cannot come
+// back while its parent is in the trash.
+func (r *TaskRepo) Restore(ctx context.Context, id int64, record model.Record) (model.TaskModel, error) {
+	var task model.TaskModel
+	err := r.store.write(ctx, func(t *tx) error {
+		if err := t.restoreTask(ctx, id); err != nil {
+			return err
+		}
+		var err error
+		if task, err = t.liveTask(ctx, id); err != nil {
+			return err
+		}
+		return t.record(ctx, record, model.Change{After: &task})
+	})
+	return task, wrap(err, "task", "restore task")
+}
+
+// Purge removes the tasks of every user trashed before the given time for
+// good and returns how many it removed. Subtasks going with their parent
+// are not counted, the foreign keys delete them with what points to them.
+func (r *TaskRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := r.store.write(ctx, func(t *tx) error {
+		result, err := t.ExecContext(ctx, `DELETE FROM tasks WHERE deleted_at < ?`, timeArg(before))
+		if err != nil {
+			return err
+		}
+		purged, err = result.RowsAffected()
+		return err
+	})
+	return purged, wrap(err, "task", "purge trash")
+}
+
+// restoreTask takes a trashed task out of the trash, with the subtasks
+// trashed together with it.
+func (t *tx) restoreTask(ctx context.Context, id int64) error {
+	task, found, err := t.anyTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !found || task.DeletedAt == nil {
+		return apperror.New(apperror.ErrNotFound, "task not found in trash")
+	}
+	if task.ParentID != nil {
+		trashed, err := t.exists(ctx, `SELECT 1 FROM tasks WHERE id = ? AND deleted_at IS NOT NULL`, *task.ParentID)
+		if err != nil {
+			return err
+		}
+		if trashed {
+			return apperror.New(apperror.ErrConflict, "the parent task is in the trash, restore it first")
+		}
+	}
+
+	_, err = t.ExecContext(ctx, `UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE (id = ? OR parent_id = ?) AND deleted_at = ? AND owner_id = ? AND workspace_id = ?`,
+		id, id, timeArg(*task.DeletedAt), requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	return err
+}
+
+// trashTask moves a task and its subtasks to the trash and returns the
+// parent of the task. It follows the same version rule as updateTask.
+func (t *tx) trashTask(ctx context.Context, id, version int64) (*int64, error) {
+	task, err := t.liveTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && version != task.Version {
+		return nil, apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+	}
+
+	_, err = t.ExecContext(ctx, `UPDATE tasks SET deleted_at = ?, version = version + 1 WHERE (id = ? OR parent_id = ?) AND deleted_at IS NULL AND owner_id = ? AND workspace_id = ?`,
+		timeArg(t.now), id, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return task.ParentID, nil
+}
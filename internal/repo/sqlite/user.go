@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/requestinfo"
+)
+
+// apiKeyTouchEvery is how often the use of a key is recorded, see
+// model.TouchAPIKeyQuery.
+const apiKeyTouchEvery = time.Minute
+
+// apiKeyColumns are read by scanAPIKey.
+const apiKeyColumns = `id, name, prefix, key_hash, read_only, workspace_id, created_at, last_used_at`
+
+type UserRepo struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepo {
+	return &UserRepo{
+		store: store,
+	}
+}
+
+func (r *UserRepo) Create(ctx context.Context, user model.UserModel) (model.UserModel, error) {
+	err := r.store.write(ctx, func(t *tx) error {
+		user.CreatedAt = t.now
+		result, err := t.ExecContext(ctx, `INSERT INTO users (email, password_hash, created_at) VALUES (?, ?, ?)`, user.Email, user.PasswordHash, timeArg(user.CreatedAt))
+		if err != nil {
+			return err
+		}
+		user.ID, err = result.LastInsertId()
+		return err
+	})
+	return user, wrap(err, "user", "create user")
+}
+
+func (r *UserRepo) GetByEmail(ctx context.Context, email string) (model.UserModel, error) {
+	var user model.UserModel
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		user, err = scanUser(t.QueryRowContext(ctx, `SELECT id, email, password_hash, created_at FROM users WHERE email = ?`, email))
+		return err
+	})
+	return user, wrap(err, "user", "fetch user")
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, id int64) (model.UserModel, error) {
+	var user model.UserModel
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		user, err = scanUser(t.QueryRowContext(ctx, `SELECT id, email, password_hash, created_at FROM users WHERE id = ?`, id))
+		return err
+	})
+	return user, wrap(err, "user", "fetch user")
+}
+
+// SaveRefreshToken stores a new refresh token and drops the expired ones of
+// the same user.
+func (r *UserRepo) SaveRefreshToken(ctx context.Context, token model.RefreshToken) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		if _, err := t.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE user_id = ? AND expires_at < ?`, token.UserID, timeArg(t.now)); err != nil {
+			return err
+		}
+		_, err := t.ExecContext(ctx, `INSERT INTO refresh_tokens (token_hash, user_id, expires_at, created_at) VALUES (?, ?, ?, ?)`,
+			token.Hash, token.UserID, timeArg(token.ExpiresAt), timeArg(t.now))
+		return err
+	})
+	return wrap(err, "user", "save refresh token")
+}
+
+// TakeRefreshToken removes the refresh token with the given hash and
+// returns it. Expired tokens are returned too, the caller checks.
+func (r *UserRepo) TakeRefreshToken(ctx context.Context, hash string) (model.RefreshToken, error) {
+	token := model.RefreshToken{Hash: hash}
+	err := r.store.write(ctx, func(t *tx) error {
+		err := t.QueryRowContext(ctx, `DELETE FROM refresh_tokens WHERE token_hash = ? RETURNING user_id, expires_at`, hash).
+			Scan(&token.UserID, &timeValue{&token.ExpiresAt})
+		if err == sql.ErrNoRows {
+			return notFound("refresh token")
+		}
+		return err
+	})
+	return token, wrap(err, "user", "take refresh token")
+}
+
+// CreateAPIKey stores a key of the signed in user, limited to the workspace
+// of the request. Only the hash of the key is kept.
+func (r *UserRepo) CreateAPIKey(ctx context.Context, key model.APIKeyModel) (model.APIKeyModel, error) {
+	if workspace := requestinfo.WorkspaceID(ctx); workspace != 0 {
+		key.WorkspaceID = &workspace
+	}
+
+	err := r.store.write(ctx, func(t *tx) error {
+		key.CreatedAt = t.now
+		result, err := t.ExecContext(ctx, `INSERT INTO api_keys (user_id, name, prefix, key_hash, read_only, created_at, workspace_id) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			requestinfo.UserID(ctx), key.Name, key.Prefix, key.Hash, key.ReadOnly, timeArg(key.CreatedAt), nullInt64Arg(key.WorkspaceID))
+		if err != nil {
+			return err
+		}
+		key.ID, err = result.LastInsertId()
+		return err
+	})
+	return key, wrap(err, "api key", "create api key")
+}
+
+func (r *UserRepo) GetAPIKeys(ctx context.Context) ([]model.APIKeyModel, error) {
+	keys := []model.APIKeyModel{}
+	err := r.store.read(ctx, func(t *tx) error {
+		rows, err := t.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = ? ORDER BY id`, requestinfo.UserID(ctx))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			key, err := scanAPIKey(rows)
+			if err != nil {
+				return err
+			}
+			key.Hash = ""
+			keys = append(keys, key)
+		}
+		return rows.Err()
+	})
+	return keys, wrap(err, "api key", "fetch api keys")
+}
+
+// RevokeAPIKey deletes a key of the signed in user.
+func (r *UserRepo) RevokeAPIKey(ctx context.Context, id int64) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		result, err := t.ExecContext(ctx, `DELETE FROM api_keys WHERE id = ? AND user_id = ?`, id, requestinfo.UserID(ctx))
+		if err != nil {
+			return err
+		}
+		return mustAffect(result, notFound("api key"))
+	})
+	return wrap(err, "api key", "revoke api key")
+}
+
+// UseAPIKey returns who the key with the given hash belongs to and records
+// that it was used, at most once every apiKeyTouchEvery.
+func (r *UserRepo) UseAPIKey(ctx context.Context, hash string) (model.Principal, error) {
+	var principal model.Principal
+	err := r.store.write(ctx, func(t *tx) error {
+		var (
+			id        int64
+			workspace sql.NullInt64
+		)
+		err := t.QueryRowContext(ctx, `SELECT api_keys.id, api_keys.user_id, users.email, api_keys.read_only, api_keys.workspace_id FROM api_keys `+
+			`JOIN users ON users.id = api_keys.user_id WHERE api_keys.key_hash = ?`, hash).
+			Scan(&id, &principal.UserID, &principal.Email, &principal.ReadOnly, &workspace)
+		if err != nil {
+			return err
+		}
+		principal.WorkspaceID = workspace.Int64
+
+		_, err = t.ExecContext(ctx, `UPDATE api_keys SET last_used_at = ? WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ?)`,
+			timeArg(t.now), id, timeArg(t.now.Add(-apiKeyTouchEvery)))
+		return err
+	})
+	return principal, wrap(err, "api key", "use api key")
+}
+
+func scanUser(row scanner) (model.UserModel, error) {
+	var user model.UserModel
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &timeValue{&user.CreatedAt})
+	return user, err
+}
+
+// scanAPIKey reads a row selected with apiKeyColumns.
+func scanAPIKey(row scanner) (model.APIKeyModel, error) {
+	var (
+		key        model.APIKeyModel
+		workspace  sql.NullInt64
+		lastUsedAt nullTime
+	)
+	if err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &key.ReadOnly, &workspace, &timeValue{&key.CreatedAt}, &lastUsedAt); err != nil {
+		return key, err
+	}
+	key.WorkspaceID = int64Ptr(workspace)
+	key.LastUsedAt = lastUsedAt.Time
+	return key, nil
+}
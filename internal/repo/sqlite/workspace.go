@@ -0,0 +1,176 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	model "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// workspaceColumns are read by scanWorkspace.
+const workspaceColumns = `id, name, slug, owner_id, created_at`
+
+// visibleWorkspace keeps the workspaces user ?1 works in, the default one
+// ?2 is open to every user.
+const visibleWorkspace = `(id = ?2 OR EXISTS (SELECT 1 FROM workspace_members WHERE workspace_members.workspace_id = workspaces.id AND workspace_members.user_id = ?1))`
+
+type WorkspaceRepo struct {
+	store *Store
+}
+
+func NewWorkspaceRepository(store *Store) *WorkspaceRepo {
+	return &WorkspaceRepo{
+		store: store,
+	}
+}
+
+// GetAll returns the workspaces of the signed in user, the default one
+// first.
+func (r *WorkspaceRepo) GetAll(ctx context.Context) ([]model.WorkspaceModel, error) {
+	workspaces := []model.WorkspaceModel{}
+	user := requestinfo.UserID(ctx)
+	err := r.store.read(ctx, func(t *tx) error {
+		rows, err := t.QueryContext(ctx, `SELECT `+workspaceColumns+` FROM workspaces WHERE `+visibleWorkspace+` ORDER BY id`, user, model.DefaultID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			workspace, err := scanWorkspace(rows, user)
+			if err != nil {
+				return err
+			}
+			workspaces = append(workspaces, workspace)
+		}
+		return rows.Err()
+	})
+	return workspaces, wrap(err, "workspace", "fetch workspaces")
+}
+
+// GetByID returns a workspace of the signed in user. The others are not
+// found.
+func (r *WorkspaceRepo) GetByID(ctx context.Context, id int64) (model.WorkspaceModel, error) {
+	return r.fetch(ctx, `id = ?3`, id)
+}
+
+// GetBySlug is GetByID for the slug of the workspace.
+func (r *WorkspaceRepo) GetBySlug(ctx context.Context, slug string) (model.WorkspaceModel, error) {
+	return r.fetch(ctx, `slug = ?3`, slug)
+}
+
+// fetch reads the workspace of the signed in user matching cond on ?3.
+func (r *WorkspaceRepo) fetch(ctx context.Context, cond string, arg interface{}) (model.WorkspaceModel, error) {
+	var workspace model.WorkspaceModel
+	user := requestinfo.UserID(ctx)
+	err := r.store.read(ctx, func(t *tx) error {
+		var err error
+		workspace, err = scanWorkspace(t.QueryRowContext(ctx, `SELECT `+workspaceColumns+` FROM workspaces WHERE `+cond+` AND `+visibleWorkspace,
+			user, model.DefaultID, arg), user)
+		return err
+	})
+	return workspace, wrap(err, "workspace", "fetch workspace")
+}
+
+// Create stores a workspace owned by the signed in user, who becomes its
+// first member.
+func (r *WorkspaceRepo) Create(ctx context.Context, workspace model.WorkspaceModel) (model.WorkspaceModel, error) {
+	user := requestinfo.UserID(ctx)
+	err := r.store.write(ctx, func(t *tx) error {
+		workspace.CreatedAt = t.now
+		result, err := t.ExecContext(ctx, `INSERT INTO workspaces (name, slug, owner_id, created_at) VALUES (?, ?, ?, ?)`,
+			workspace.Name, workspace.Slug, user, timeArg(workspace.CreatedAt))
+		if err != nil {
+			return err
+		}
+		if workspace.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+
+		_, err = t.ExecContext(ctx, `INSERT INTO workspace_members (workspace_id, user_id, created_at) VALUES (?, ?, ?)`, workspace.ID, user, timeArg(t.now))
+		return err
+	})
+	workspace.Role = model.RoleOwner
+	return workspace, wrap(err, "workspace", "create workspace")
+}
+
+func (r *WorkspaceRepo) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	members := []model.MemberModel{}
+	err := r.store.read(ctx, func(t *tx) error {
+		rows, err := t.QueryContext(ctx, `SELECT workspace_members.user_id, users.email, workspace_members.created_at, workspaces.owner_id FROM workspace_members `+
+			`JOIN users ON users.id = workspace_members.user_id JOIN workspaces ON workspaces.id = workspace_members.workspace_id `+
+			`WHERE workspace_members.workspace_id = ? ORDER BY workspace_members.created_at, workspace_members.user_id`, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var owner sql.NullInt64
+			member := model.MemberModel{WorkspaceID: id, Role: model.RoleMember}
+			if err := rows.Scan(&member.UserID, &member.Email, &timeValue{&member.JoinedAt}, &owner); err != nil {
+				return err
+			}
+			if owner.Int64 == member.UserID {
+				member.Role = model.RoleOwner
+			}
+			members = append(members, member)
+		}
+		return rows.Err()
+	})
+	return members, wrap(err, "workspace", "fetch members")
+}
+
+// AddMember adds the user with an email to a workspace, adding a member
+// again changes nothing.
+func (r *WorkspaceRepo) AddMember(ctx context.Context, id int64, email string) (model.MemberModel, error) {
+	member := model.MemberModel{WorkspaceID: id, Email: email, Role: model.RoleMember}
+	err := r.store.write(ctx, func(t *tx) error {
+		err := t.QueryRowContext(ctx, `SELECT id FROM users WHERE email = ?`, email).Scan(&member.UserID)
+		if err == sql.ErrNoRows {
+			return apperror.New(apperror.ErrNotFound, "no user with this email")
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = t.ExecContext(ctx, `INSERT INTO workspace_members (workspace_id, user_id, created_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`, id, member.UserID, timeArg(t.now))
+		if err != nil {
+			return err
+		}
+		return t.QueryRowContext(ctx, `SELECT created_at FROM workspace_members WHERE workspace_id = ? AND user_id = ?`, id, member.UserID).
+			Scan(&timeValue{&member.JoinedAt})
+	})
+	return member, wrap(err, "member", "add member")
+}
+
+// RemoveMember takes a user out of a workspace, never its owner.
+func (r *WorkspaceRepo) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	err := r.store.write(ctx, func(t *tx) error {
+		result, err := t.ExecContext(ctx, `DELETE FROM workspace_members WHERE workspace_id = ?1 AND user_id = ?2 `+
+			`AND ?2 IS NOT (SELECT owner_id FROM workspaces WHERE id = ?1)`, id, userID)
+		if err != nil {
+			return err
+		}
+		return mustAffect(result, notFound("member"))
+	})
+	return wrap(err, "member", "remove member")
+}
+
+// scanWorkspace reads a row selected with workspaceColumns, with the role
+// of user in it.
+func scanWorkspace(row scanner, user int64) (model.WorkspaceModel, error) {
+	var (
+		workspace model.WorkspaceModel
+		owner     sql.NullInt64
+	)
+	if err := row.Scan(&workspace.ID, &workspace.Name, &workspace.Slug, &owner, &timeValue{&workspace.CreatedAt}); err != nil {
+		return workspace, err
+	}
+	workspace.Role = model.RoleMember
+	if owner.Valid && owner.Int64 == user {
+		workspace.Role = model.RoleOwner
+	}
+	return workspace, nil
+}
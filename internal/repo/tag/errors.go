@@ -0,0 +1,7 @@
+package tag
+
+import "to-do-list/internal/repo/dberror"
+
+func dbError(err error, message string) error {
+	return dberror.Wrap(err, "tag", message)
+}
@@ -0,0 +1,197 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	model "to-do-list/internal/model/tag"
+	taskrepo "to-do-list/internal/repo/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/lib/pq"
+)
+
+type Repo struct {
+	Db    *sql.DB
+	Redis *redis.Client
+}
+
+func NewTagRepository(db *sql.DB, redis *redis.Client) *Repo {
+	return &Repo{
+		Db:    db,
+		Redis: redis,
+	}
+}
+
+func (r *Repo) GetAll(ctx context.Context) ([]model.TagModel, error) {
+
+	var Tags = []model.TagModel{}
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchAllTagQuery, requestinfo.OwnerID(ctx))
+	if err != nil {
+		fmt.Println("Error on Repo :", err)
+		return nil, dbError(err, "fetch tags")
+	}
+
+	defer rows.Close()
+
+	tag_row := model.TagModel{}
+
+	for rows.Next() {
+		if err := rows.Scan(&tag_row.ID, &tag_row.Name, &tag_row.TaskCount); err != nil {
+			return nil, dbError(err, "scan tag")
+		}
+		Tags = append(Tags, tag_row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch tags")
+	}
+
+	return Tags, nil
+}
+
+func (r *Repo) GetByID(ctx context.Context, id int64) (model.TagModel, error) {
+
+	tag := model.TagModel{}
+
+	err := r.Db.QueryRowContext(ctx, model.FetchTagByIdQuery, id, requestinfo.OwnerID(ctx)).Scan(&tag.ID, &tag.Name, &tag.TaskCount)
+	if err != nil {
+		return tag, dbError(err, "fetch tag")
+	}
+
+	return tag, nil
+}
+
+func (r *Repo) Create(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+
+	err := r.Db.QueryRowContext(ctx, model.InsertTagReturnIdQuery, tag.Name, requestinfo.OwnerID(ctx)).Scan(&tag.ID)
+	if err != nil {
+		fmt.Println(err)
+		return tag, dbError(err, "create tag")
+	}
+
+	return tag, nil
+}
+
+// Rename changes the name in place. Tasks reference tags by id, so every
+// task carrying the tag shows the new name.
+func (r *Repo) Rename(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+
+	res, err := r.Db.ExecContext(ctx, model.RenameTagQuery, tag.Name, tag.ID, requestinfo.OwnerID(ctx))
+	if err != nil {
+		fmt.Println(err)
+		return tag, dbError(err, "rename tag")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return tag, dbError(err, "rename tag")
+	}
+	if affected == 0 {
+		return tag, apperror.New(apperror.ErrNotFound, "tag not found")
+	}
+
+	taskIds, err := r.taskIds(ctx, r.Db, tag.ID)
+	if err != nil {
+		return tag, err
+	}
+
+	taskrepo.Invalidate(requestinfo.Detach(ctx), r.Redis, taskIds...)
+
+	return r.GetByID(ctx, tag.ID)
+}
+
+// Merge moves the tasks of tag from to tag into and deletes tag from.
+func (r *Repo) Merge(ctx context.Context, from int64, into int64) (model.TagModel, error) {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.TagModel{}, dbError(err, "merge tags")
+	}
+	defer tx.Rollback()
+
+	var found int
+	err = tx.QueryRowContext(ctx, model.CountTagsQuery, pq.Array([]int64{from, into}), requestinfo.OwnerID(ctx)).Scan(&found)
+	if err != nil {
+		return model.TagModel{}, dbError(err, "merge tags")
+	}
+	if found != 2 {
+		return model.TagModel{}, apperror.New(apperror.ErrNotFound, "tag not found")
+	}
+
+	taskIds, err := r.taskIds(ctx, tx, from)
+	if err != nil {
+		return model.TagModel{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, model.MergeTaskTagsQuery, from, into); err != nil {
+		return model.TagModel{}, dbError(err, "merge tags")
+	}
+
+	if _, err := tx.ExecContext(ctx, model.DeleteTagQuery, from, requestinfo.OwnerID(ctx)); err != nil {
+		return model.TagModel{}, dbError(err, "merge tags")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.TagModel{}, dbError(err, "merge tags")
+	}
+
+	taskrepo.Invalidate(requestinfo.Detach(ctx), r.Redis, taskIds...)
+
+	return r.GetByID(ctx, into)
+}
+
+func (r *Repo) Delete(ctx context.Context, tag model.TagModel) error {
+
+	taskIds, err := r.taskIds(ctx, r.Db, tag.ID)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.Db.ExecContext(ctx, model.DeleteTagQuery, tag.ID, requestinfo.OwnerID(ctx))
+	if err != nil {
+		fmt.Println(err)
+		return dbError(err, "delete tag")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err, "delete tag")
+	}
+	if affected == 0 {
+		return apperror.New(apperror.ErrNotFound, "tag not found")
+	}
+
+	taskrepo.Invalidate(requestinfo.Detach(ctx), r.Redis, taskIds...)
+
+	return nil
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// taskIds raises the version of the tasks carrying a tag and lists them,
+// their cached copies have to go when the tag changes.
+func (r *Repo) taskIds(ctx context.Context, q querier, id int64) ([]int64, error) {
+	rows, err := q.QueryContext(ctx, model.TouchTagTasksQuery, id, requestinfo.OwnerID(ctx))
+	if err != nil {
+		return nil, dbError(err, "fetch tagged tasks")
+	}
+
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var taskId int64
+		if err := rows.Scan(&taskId); err != nil {
+			return nil, dbError(err, "scan tagged task")
+		}
+		ids = append(ids, taskId)
+	}
+
+	return ids, dbError(rows.Err(), "fetch tagged tasks")
+}
@@ -0,0 +1,258 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	model "to-do-list/internal/model/tag"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var (
+	// every request below is made by user testOwner
+	testOwner = int64(1)
+	userCtx   = requestinfo.WithUserID(context.Background(), testOwner)
+)
+
+func mockDBAndRedis(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *redis.Client) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mr, err := miniredis.Run()
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rclient := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	return db, mock, rclient
+}
+
+func TestRepo_GetAll(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "count"}).
+		AddRow(1, "home", 2).
+		AddRow(2, "work", 0)
+
+	mock.ExpectQuery(`SELECT tags.id, tags.name, COUNT\(task_tags.task_id\) FROM tags (.*) ORDER BY tags.name`).WithArgs(testOwner).WillReturnRows(rows)
+
+	repo := NewTagRepository(db, rclient)
+	result, err := repo.GetAll(userCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TagModel{
+		{ID: 1, Name: "home", TaskCount: 2},
+		{ID: 2, Name: "work", TaskCount: 0},
+	}, result)
+}
+
+func TestRepo_Create(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.TagModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> create tag",
+			mock: func() {
+				mock.ExpectQuery(`INSERT INTO tags (.*) RETURNING id`).WithArgs("home", testOwner).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			want:    model.TagModel{ID: 1, Name: "home"},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> tag name already used",
+			mock: func() {
+				mock.ExpectQuery(`INSERT INTO tags (.*) RETURNING id`).WithArgs("home", testOwner).WillReturnError(&pq.Error{Code: "23505"})
+			},
+			want:    model.TagModel{Name: "home"},
+			wantErr: apperror.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTagRepository(db, rclient)
+			result, err := repo.Create(ctx, model.TagModel{Name: "home"})
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestRepo_Rename(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.TagModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> rename tag and drop cached tasks",
+			mock: func() {
+				rclient.SAdd(ctx, "tasks:1:workspaces", 5)
+				rclient.Set(ctx, "ws:5:task:1:v0:7", "{}", 0)
+				mock.ExpectExec(`UPDATE tags SET name=(.*) WHERE id=(.*)`).WithArgs("house", 1, testOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`UPDATE tasks SET version = version \+ 1 WHERE id IN (.*) RETURNING id`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"task_id"}).AddRow(7))
+				mock.ExpectQuery(`SELECT (.*) FROM tags (.*) WHERE tags.id=(.*)`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count"}).AddRow(1, "house", 1))
+			},
+			want:    model.TagModel{ID: 1, Name: "house", TaskCount: 1},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> tag not found",
+			mock: func() {
+				mock.ExpectExec(`UPDATE tags SET name=(.*) WHERE id=(.*)`).WithArgs("house", 1, testOwner).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want:    model.TagModel{ID: 1, Name: "house"},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTagRepository(db, rclient)
+			result, err := repo.Rename(ctx, model.TagModel{ID: 1, Name: "house"})
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			_, err = rclient.Get(ctx, "ws:5:task:1:v0:7").Result()
+			assert.Equal(t, redis.Nil, err)
+		})
+	}
+}
+
+func TestRepo_Merge(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.TagModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> merge tag into another",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM tags WHERE id = ANY\((.*)\)`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery(`UPDATE tasks SET version = version \+ 1 WHERE id IN (.*) RETURNING id`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"task_id"}).AddRow(7).AddRow(8))
+				mock.ExpectExec(`INSERT INTO task_tags (.*) ON CONFLICT DO NOTHING`).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`DELETE FROM tags WHERE id=(.*)`).WithArgs(1, testOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectQuery(`SELECT (.*) FROM tags (.*) WHERE tags.id=(.*)`).WithArgs(2, testOwner).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count"}).AddRow(2, "house", 3))
+			},
+			want:    model.TagModel{ID: 2, Name: "house", TaskCount: 3},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> one of the tags does not exist",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM tags WHERE id = ANY\((.*)\)`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			want:    model.TagModel{},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTagRepository(db, rclient)
+			result, err := repo.Merge(ctx, 1, 2)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_Delete(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+
+	tests := []struct {
+		name string
+		mock func()
+		want error
+	}{
+		{
+			name: "case 1 -> delete tag",
+			mock: func() {
+				mock.ExpectQuery(`UPDATE tasks SET version = version \+ 1 WHERE id IN (.*) RETURNING id`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"task_id"}).AddRow(7))
+				mock.ExpectExec(`DELETE FROM tags WHERE id=(.*)`).WithArgs(1, testOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: nil,
+		},
+		{
+			name: "case 2 -> tag not found",
+			mock: func() {
+				mock.ExpectQuery(`UPDATE tasks SET version = version \+ 1 WHERE id IN (.*) RETURNING id`).WithArgs(1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"task_id"}))
+				mock.ExpectExec(`DELETE FROM tags WHERE id=(.*)`).WithArgs(1, testOwner).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTagRepository(db, rclient)
+			err := repo.Delete(ctx, model.TagModel{ID: 1})
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// Batch runs the operations in one transaction and returns a result per
+// operation. In all or nothing mode the first failing operation rolls back
+// the whole batch and ends it, its result carries the error. Otherwise only
+// the failing operations are undone. The cache is invalidated once, after
+// the commit.
+func (r *Repo) Batch(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error) {
+
+	results := make([]model.BatchResult, len(ops))
+	for i, op := range ops {
+		results[i] = model.BatchResult{Op: op.Op, ID: op.ID}
+	}
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, dbError(err, "run batch")
+	}
+	defer tx.Rollback()
+
+	var changed []int64
+	for i, op := range ops {
+		if !opts.AllOrNothing {
+			if _, err := tx.ExecContext(ctx, model.SavepointBatchQuery); err != nil {
+				return nil, dbError(err, "run batch")
+			}
+		}
+
+		task, before, ids, err := runOperation(ctx, tx, op, opts)
+		if err != nil {
+			results[i].Err = err
+			if opts.AllOrNothing {
+				return results, nil
+			}
+			if _, err := tx.ExecContext(ctx, model.RollbackBatchSavepointQuery); err != nil {
+				return nil, dbError(err, "run batch")
+			}
+			continue
+		}
+
+		if !opts.AllOrNothing {
+			if _, err := tx.ExecContext(ctx, model.ReleaseBatchSavepointQuery); err != nil {
+				return nil, dbError(err, "run batch")
+			}
+		}
+
+		results[i].ID = task.ID
+		results[i].Before = before
+		if op.Op != model.BatchDelete {
+			results[i].Task = &task
+		}
+		changed = append(changed, ids...)
+	}
+
+	if err := RecordChanges(ctx, tx, record, model.BatchChanges(results)...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(err, "run batch")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx), changed...)
+
+	return results, nil
+}
+
+// runOperation applies one operation in tx and returns the task it created
+// or changed and the task it found before, with the ids of every task it
+// changed. A completion of a done task changes nothing and finds no task.
+func runOperation(ctx context.Context, tx *sql.Tx, op model.BatchOperation, opts model.BatchOptions) (model.TaskModel, *model.TaskModel, []int64, error) {
+	switch op.Op {
+	case model.BatchCreate:
+		task, err := insertTask(ctx, tx, *op.Task)
+		return task, nil, []int64{task.ID}, err
+
+	case model.BatchUpdate:
+		current, err := lockTask(ctx, tx, op.ID)
+		if err != nil {
+			return current, nil, nil, err
+		}
+		task := *op.Task
+		task.ID = op.ID
+		task.Version = op.Version
+		if task.Version != 0 && task.Version != current.Version {
+			return current, nil, nil, apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+		}
+		if task.IsDone && !current.IsDone && opts.BlockOpenSubtasks {
+			if err := checkOpenSubtasks(ctx, tx, task.ID); err != nil {
+				return current, nil, nil, err
+			}
+		}
+		if current.ParentID != nil && !sameID(current.ListID, task.ListID) {
+			return current, nil, nil, apperror.New(apperror.ErrValidation, "a subtask stays in the list of its parent")
+		}
+		if current.ParentID != nil && task.RRule != "" {
+			return current, nil, nil, apperror.New(apperror.ErrValidation, "a subtask cannot repeat")
+		}
+		task.ParentID = current.ParentID
+		task.Position = current.Position
+
+		task, ids, err := updateTask(ctx, tx, task)
+		if err != nil || task.IsDone == current.IsDone {
+			return task, &current, ids, err
+		}
+		rolledUp, err := rollUp(ctx, tx, task.ParentID)
+		return task, &current, append(ids, rolledUp...), err
+
+	case model.BatchComplete:
+		task, err := lockTask(ctx, tx, op.ID)
+		if err != nil || task.IsDone {
+			return task, nil, nil, err
+		}
+		current := task
+		if opts.BlockOpenSubtasks {
+			if err := checkOpenSubtasks(ctx, tx, task.ID); err != nil {
+				return task, nil, nil, err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, model.SetTaskDoneQuery, true, task.ID, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)); err != nil {
+			return task, nil, nil, dbError(err, "update task status")
+		}
+		task.IsDone = true
+		task.Version++
+		rolledUp, err := rollUp(ctx, tx, task.ParentID)
+		return task, &current, append(rolledUp, task.ID), err
+
+	case model.BatchDelete:
+		current, err := lockTask(ctx, tx, op.ID)
+		if err != nil {
+			return model.TaskModel{ID: op.ID}, nil, nil, err
+		}
+		parentID, ids, err := trashTask(ctx, tx, op.ID, op.Version)
+		if err != nil {
+			return model.TaskModel{ID: op.ID}, nil, nil, err
+		}
+		rolledUp, err := rollUp(ctx, tx, parentID)
+		return model.TaskModel{ID: op.ID}, &current, append(rolledUp, ids...), err
+
+	default:
+		return model.TaskModel{}, nil, nil, apperror.New(apperror.ErrValidation, "unknown operation "+op.Op)
+	}
+}
+
+func lockTask(ctx context.Context, tx *sql.Tx, id int64) (model.TaskModel, error) {
+	task, err := scanTask(tx.QueryRowContext(ctx, model.LockTaskQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)))
+	if err != nil {
+		return task, dbError(err, "fetch task")
+	}
+	return task, nil
+}
+
+func checkOpenSubtasks(ctx context.Context, tx *sql.Tx, id int64) error {
+	var open bool
+	if err := tx.QueryRowContext(ctx, model.HasOpenSubtasksQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)).Scan(&open); err != nil {
+		return dbError(err, "fetch subtasks")
+	}
+	if open {
+		return apperror.New(apperror.ErrConflict, "task has open subtasks")
+	}
+	return nil
+}
+
+// rollUp makes the status of the parent match its subtasks and returns the
+// parent id when it changed.
+func rollUp(ctx context.Context, tx *sql.Tx, parentID *int64) ([]int64, error) {
+	if parentID == nil {
+		return nil, nil
+	}
+	ids, err := queryIds(ctx, tx, model.RollUpTaskQuery, *parentID, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		return nil, dbError(err, "update parent status")
+	}
+	return ids, nil
+}
+
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,161 @@
+package task
+
+import (
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestRepo_Batch(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+	parentID := int64(1)
+
+	ops := []model.BatchOperation{
+		{Op: model.BatchCreate, Task: &model.TaskModel{TaskName: "task 4", Timezone: "UTC"}},
+		{Op: model.BatchComplete, ID: 2},
+		{Op: model.BatchDelete, ID: 3},
+	}
+
+	tests := []struct {
+		name string
+		// ops replaces the operations above when set
+		ops      []model.BatchOperation
+		opts     model.BatchOptions
+		mock     func()
+		want     []model.BatchResult
+		wantErrs []error
+		// the cache is invalidated once, on commit only
+		wantDropped bool
+	}{
+		{
+			name: "case 1 -> all or nothing batch commits every operation",
+			opts: model.BatchOptions{AllOrNothing: true},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), ''\) FROM tasks`).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+				mock.ExpectQuery(`INSERT INTO tasks (.*) RETURNING id, position, version`).WillReturnRows(sqlmock.NewRows([]string{"id", "position", "version"}).AddRow(4, 0, 1))
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(2, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(taskColumns).AddRow(2, "step 1", false, nil, "UTC", nil, 0, nil, 1, 0, 1, nil, "", "", ""))
+				mock.ExpectExec(`UPDATE tasks SET is_done=(.*) WHERE id=(.*)`).WithArgs(true, 2, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`UPDATE tasks SET is_done = NOT is_done(.*) WHERE id=(.*)`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(3, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(taskColumns).AddRow(3, "task 3", false, nil, "UTC", nil, 0, nil, nil, 0, 4, nil, "", "", ""))
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) WHERE id=(.*) RETURNING parent_id`).WithArgs(3, 0, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(nil))
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) WHERE parent_id=(.*) RETURNING id`).WithArgs(3, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectCommit()
+			},
+			want: []model.BatchResult{
+				{Op: model.BatchCreate, ID: 4, Task: &model.TaskModel{ID: 4, TaskName: "task 4", Timezone: "UTC", Rank: "W", Version: 1}},
+				{Op: model.BatchComplete, ID: 2, Task: &model.TaskModel{ID: 2, TaskName: "step 1", IsDone: true, Timezone: "UTC", ParentID: &parentID, Version: 2},
+					Before: &model.TaskModel{ID: 2, TaskName: "step 1", Timezone: "UTC", ParentID: &parentID, Version: 1}},
+				{Op: model.BatchDelete, ID: 3, Before: &model.TaskModel{ID: 3, TaskName: "task 3", Timezone: "UTC", Version: 4}},
+			},
+			wantErrs:    []error{nil, nil, nil},
+			wantDropped: true,
+		},
+		{
+			name: "case 2 -> all or nothing batch stops at the first failure",
+			opts: model.BatchOptions{AllOrNothing: true},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), ''\) FROM tasks`).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+				mock.ExpectQuery(`INSERT INTO tasks (.*) RETURNING id, position, version`).WillReturnRows(sqlmock.NewRows([]string{"id", "position", "version"}).AddRow(4, 0, 1))
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(2, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows(taskColumns))
+				mock.ExpectRollback()
+			},
+			want: []model.BatchResult{
+				{Op: model.BatchCreate, ID: 4, Task: &model.TaskModel{ID: 4, TaskName: "task 4", Timezone: "UTC", Rank: "W", Version: 1}},
+				{Op: model.BatchComplete, ID: 2},
+				{Op: model.BatchDelete, ID: 3},
+			},
+			wantErrs: []error{nil, apperror.ErrNotFound, nil},
+		},
+		{
+			name: "case 3 -> best effort batch only undoes the failed operation",
+			opts: model.BatchOptions{BlockOpenSubtasks: true},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SAVEPOINT batch_operation`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), ''\) FROM tasks`).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+				mock.ExpectQuery(`INSERT INTO tasks (.*) RETURNING id, position, version`).WillReturnError(&pq.Error{Code: "23503", Constraint: "tasks_list_id_fkey"})
+				mock.ExpectExec(`ROLLBACK TO SAVEPOINT batch_operation`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`SAVEPOINT batch_operation`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(2, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(taskColumns).AddRow(2, "task 2", false, nil, "UTC", nil, 0, nil, nil, 0, 1, nil, "", "", ""))
+				mock.ExpectQuery(`SELECT EXISTS (.*)`).WithArgs(2, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectExec(`UPDATE tasks SET is_done=(.*) WHERE id=(.*)`).WithArgs(true, 2, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`RELEASE SAVEPOINT batch_operation`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`SAVEPOINT batch_operation`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(3, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows(taskColumns))
+				mock.ExpectExec(`ROLLBACK TO SAVEPOINT batch_operation`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			want: []model.BatchResult{
+				{Op: model.BatchCreate},
+				{Op: model.BatchComplete, ID: 2, Task: &model.TaskModel{ID: 2, TaskName: "task 2", IsDone: true, Timezone: "UTC", Version: 2},
+					Before: &model.TaskModel{ID: 2, TaskName: "task 2", Timezone: "UTC", Version: 1}},
+				{Op: model.BatchDelete, ID: 3},
+			},
+			wantErrs:    []error{apperror.ErrValidation, nil, apperror.ErrNotFound},
+			wantDropped: true,
+		},
+		{
+			name: "case 4 -> a subtask cannot repeat",
+			ops:  []model.BatchOperation{{Op: model.BatchUpdate, ID: 2, Task: &model.TaskModel{TaskName: "step 1", Timezone: "UTC", RRule: "FREQ=DAILY"}}},
+			opts: model.BatchOptions{AllOrNothing: true},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(2, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(taskColumns).AddRow(2, "step 1", false, nil, "UTC", nil, 0, nil, 1, 0, 1, nil, "", "", ""))
+				mock.ExpectRollback()
+			},
+			want:     []model.BatchResult{{Op: model.BatchUpdate, ID: 2}},
+			wantErrs: []error{apperror.ErrValidation},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// copies are cached under the list version, start each case at 0
+			rclient.FlushAll(ctx)
+			rclient.Set(ctx, "ws:5:task:1:v0:2", "{}", 0)
+			version, _ := rclient.Get(ctx, "ws:5:tasks:1:version").Int64()
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			if tt.ops == nil {
+				tt.ops = ops
+			}
+			result, err := repo.Batch(ctx, tt.ops, tt.opts, nil)
+			assert.NoError(t, err)
+
+			for i := range result {
+				if tt.wantErrs[i] == nil {
+					assert.NoError(t, result[i].Err)
+				} else {
+					assert.ErrorIs(t, result[i].Err, tt.wantErrs[i])
+				}
+				result[i].Err = nil
+			}
+			assert.Equal(t, tt.want, result)
+
+			_, err = rclient.Get(ctx, "ws:5:task:1:v0:2").Result()
+			assert.Equal(t, tt.wantDropped, err == redis.Nil)
+			invalidated, _ := rclient.Get(ctx, "ws:5:tasks:1:version").Int64()
+			if tt.wantDropped {
+				assert.Equal(t, version+1, invalidated)
+			} else {
+				assert.Equal(t, version, invalidated)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+}
@@ -0,0 +1,122 @@
+package task
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Every key is partitioned by the workspace and then the owner of the
+// request, so the cached tasks of a workspace are never read from another.
+const (
+	redisTaskListVersion = "ws:%d:tasks:%d:version"
+	redisTaskGetAll      = "ws:%d:tasks:%d:v%d:%s"
+	redisTaskGetByID     = "ws:%d:task:%d:v%d:%d"
+
+	// redisTaskWorkspaces is the set of workspaces an owner has cached
+	// tasks in, where Invalidate looks for them
+	redisTaskWorkspaces = "tasks:%d:workspaces"
+
+	// copies of an old list version are never read again, the TTL only
+	// bounds how long they occupy memory
+	redisTaskCacheTTL = 10 * time.Minute
+)
+
+// listVersion is part of every page and task key. Bumping it in Invalidate
+// drops all cached copies of the user at once without having to find their
+// keys. It is read before the database, so a copy read before a write is
+// cached under the version the write replaced and never served.
+func (r *Repo) listVersion(ctx context.Context) int64 {
+	key := fmt.Sprintf(redisTaskListVersion, requestinfo.WorkspaceID(ctx), requestinfo.OwnerID(ctx))
+	version, err := r.Redis.Get(ctx, key).Int64()
+	if err != nil && err != redis.Nil {
+		fmt.Println(err)
+	}
+	return version
+}
+
+func pageKey(ctx context.Context, version int64, filter model.TaskFilter) string {
+	return fmt.Sprintf(redisTaskGetAll, requestinfo.WorkspaceID(ctx), requestinfo.OwnerID(ctx), version, filterKey(filter))
+}
+
+func taskKey(ctx context.Context, version int64, id int64) string {
+	return fmt.Sprintf(redisTaskGetByID, requestinfo.WorkspaceID(ctx), requestinfo.OwnerID(ctx), version, id)
+}
+
+// filterKey identifies a filter in page keys. Hashing keeps keys short
+// whatever the client put in the query string.
+func filterKey(filter model.TaskFilter) string {
+	data, _ := json.Marshal(filter)
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cache stores a copy under key, once the workspace of ctx is known to
+// Invalidate.
+func (r *Repo) cache(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	owner, workspace := requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)
+
+	// the request may be over, the copy is still worth keeping
+	if err := r.Redis.SAdd(context.Background(), fmt.Sprintf(redisTaskWorkspaces, owner), workspace).Err(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	_ = r.Redis.Set(context.Background(), key, data, ttl)
+}
+
+func (r *Repo) invalidate(ctx context.Context, ids ...int64) {
+	Invalidate(ctx, r.Redis, ids...)
+}
+
+// Invalidate drops every cached page and task of the owner of ctx, in every
+// workspace, and frees the copies of the given tasks without waiting for
+// their TTL. Lists and tags span the
+// workspaces of their owner, so other repos changing them reach tasks
+// outside of the workspace of ctx.
+func Invalidate(ctx context.Context, client *redis.Client, ids ...int64) {
+	owner := requestinfo.OwnerID(ctx)
+
+	workspaces := []int64{requestinfo.WorkspaceID(ctx)}
+	members, err := client.SMembers(ctx, fmt.Sprintf(redisTaskWorkspaces, owner)).Result()
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, member := range members {
+		if workspace, err := strconv.ParseInt(member, 10, 64); err == nil && workspace != workspaces[0] {
+			workspaces = append(workspaces, workspace)
+		}
+	}
+
+	keys := []string{}
+	for _, workspace := range workspaces {
+		version, err := client.Incr(ctx, fmt.Sprintf(redisTaskListVersion, workspace, owner)).Result()
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		for _, id := range ids {
+			keys = append(keys, fmt.Sprintf(redisTaskGetByID, workspace, owner, version-1, id))
+		}
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+	if err := client.Del(ctx, keys...).Err(); err != nil {
+		fmt.Println(err)
+	}
+}
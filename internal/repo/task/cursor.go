@@ -0,0 +1,51 @@
+package task
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// cursor is the keyset position after the last task of a page: the sort it
+// was made for and the values of the sort fields. It travels to clients
+// base64 encoded so they treat it as opaque.
+type cursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+func encodeCursor(order []model.SortField, last model.TaskModel) string {
+	c := cursor{Sort: sortKey(order)}
+	for _, sort := range order {
+		value, _ := json.Marshal(sortColumns[sort.Field].value(last))
+		c.Values = append(c.Values, value)
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string, order []model.SortField) ([]interface{}, error) {
+	c := cursor{}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, apperror.Wrap(apperror.ErrValidation, "invalid cursor", err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, apperror.Wrap(apperror.ErrValidation, "invalid cursor", err)
+	}
+	if c.Sort != sortKey(order) || len(c.Values) != len(order) {
+		return nil, apperror.New(apperror.ErrValidation, "cursor does not match sort")
+	}
+
+	values := make([]interface{}, len(order))
+	for i, sort := range order {
+		values[i], err = sortColumns[sort.Field].decode(c.Values[i])
+		if err != nil {
+			return nil, apperror.Wrap(apperror.ErrValidation, "invalid cursor", err)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,148 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// AddDependency blocks a task by another one. Both have to be live tasks.
+// check runs on the dependencies of the owner, which no other request
+// changes until the dependency is added, and its error cancels it.
+func (r *Repo) AddDependency(ctx context.Context, dependency model.Dependency, check func([]model.Dependency) error) error {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(err, "add dependency")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, model.LockDependenciesQuery, requestinfo.OwnerID(ctx)); err != nil {
+		fmt.Println(err)
+		return dbError(err, "lock dependencies")
+	}
+
+	dependencies, err := fetchDependencies(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if err := check(dependencies); err != nil {
+		return err
+	}
+
+	var taskID int64
+	err = tx.QueryRowContext(ctx, model.InsertDependencyQuery, dependency.TaskID, dependency.BlockedBy, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)).Scan(&taskID)
+	if err == sql.ErrNoRows {
+		return apperror.New(apperror.ErrNotFound, "task not found")
+	}
+	if err != nil {
+		fmt.Println(err)
+		return dbError(err, "add dependency")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError(err, "add dependency")
+	}
+
+	// the blocked flag of the listed task changes
+	r.invalidate(requestinfo.Detach(ctx))
+
+	return nil
+}
+
+func (r *Repo) RemoveDependency(ctx context.Context, dependency model.Dependency) error {
+
+	res, err := r.Db.ExecContext(ctx, model.DeleteDependencyQuery, dependency.TaskID, dependency.BlockedBy, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		fmt.Println(err)
+		return dbError(err, "remove dependency")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err, "remove dependency")
+	}
+	if count == 0 {
+		return apperror.New(apperror.ErrNotFound, "dependency not found")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx))
+
+	return nil
+}
+
+// GetDependencies returns every edge, those of trashed tasks included since
+// they can come back.
+func (r *Repo) GetDependencies(ctx context.Context) ([]model.Dependency, error) {
+	return fetchDependencies(ctx, r.Db)
+}
+
+func fetchDependencies(ctx context.Context, q querier) ([]model.Dependency, error) {
+
+	var Dependencies = []model.Dependency{}
+
+	rows, err := q.QueryContext(ctx, model.FetchDependenciesQuery, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+
+	if err != nil {
+		fmt.Println("Error on Repo :", err)
+		return nil, dbError(err, "fetch dependencies")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var dependency model.Dependency
+		if err := rows.Scan(&dependency.TaskID, &dependency.BlockedBy); err != nil {
+			return nil, dbError(err, "scan dependency")
+		}
+		Dependencies = append(Dependencies, dependency)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch dependencies")
+	}
+
+	return Dependencies, nil
+}
+
+// GetBlockers returns the live tasks the task waits for, done or not.
+func (r *Repo) GetBlockers(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	return r.queryTasks(ctx, "fetch blockers", scanTask, model.FetchBlockersQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+}
+
+// GetOpenTasks returns every live task that is not done, with its blocked
+// flag.
+func (r *Repo) GetOpenTasks(ctx context.Context) ([]model.TaskModel, error) {
+	return r.queryTasks(ctx, "fetch open tasks", scanListedTask, model.FetchOpenTasksQuery, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+}
+
+func (r *Repo) queryTasks(ctx context.Context, msg string, scan func(scanner, ...interface{}) (model.TaskModel, error), query string, args ...interface{}) ([]model.TaskModel, error) {
+
+	var Tasks = []model.TaskModel{}
+
+	rows, err := r.Db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		fmt.Println("Error on Repo :", err)
+		return nil, dbError(err, msg)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		task_row, err := scan(rows)
+		if err != nil {
+			return nil, dbError(err, "scan task")
+		}
+		Tasks = append(Tasks, task_row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, msg)
+	}
+
+	return Tasks, nil
+}
@@ -0,0 +1,196 @@
+package task
+
+import (
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestRepo_AddDependency(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+
+	cycle := apperror.New(apperror.ErrConflict, "dependency would create a cycle")
+
+	tests := []struct {
+		name        string
+		mock        func()
+		check       func([]model.Dependency) error
+		wantVersion int64
+		wantErr     error
+	}{
+		{
+			name: "case 1 -> add dependency",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT pg_advisory_xact_lock(.*)`).WithArgs(testOwner).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT task_id, blocked_by FROM task_dependencies`).WithArgs(testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"task_id", "blocked_by"}).AddRow(3, 2))
+				mock.ExpectQuery(`INSERT INTO task_dependencies (.*) ON CONFLICT (.*) RETURNING task_id`).WithArgs(2, 1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"task_id"}).AddRow(2))
+				mock.ExpectCommit()
+			},
+			check: func(dependencies []model.Dependency) error {
+				assert.Equal(t, []model.Dependency{{TaskID: 3, BlockedBy: 2}}, dependencies)
+				return nil
+			},
+			wantVersion: 1,
+		},
+		{
+			name: "case 2 -> task not found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT pg_advisory_xact_lock(.*)`).WithArgs(testOwner).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT task_id, blocked_by FROM task_dependencies`).WithArgs(testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"task_id", "blocked_by"}))
+				mock.ExpectQuery(`INSERT INTO task_dependencies (.*) RETURNING task_id`).WithArgs(2, 1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"task_id"}))
+				mock.ExpectRollback()
+			},
+			wantVersion: 1,
+			wantErr:     apperror.ErrNotFound,
+		},
+		{
+			name: "case 3 -> the check cancels the dependency",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT pg_advisory_xact_lock(.*)`).WithArgs(testOwner).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT task_id, blocked_by FROM task_dependencies`).WithArgs(testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"task_id", "blocked_by"}).AddRow(1, 2))
+				mock.ExpectRollback()
+			},
+			check: func(dependencies []model.Dependency) error {
+				return cycle
+			},
+			wantVersion: 1,
+			wantErr:     apperror.ErrConflict,
+		},
+		{
+			name: "case 4 -> database unavailable",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT pg_advisory_xact_lock(.*)`).WithArgs(testOwner).WillReturnError(&pq.Error{Code: "08006"})
+				mock.ExpectRollback()
+			},
+			wantVersion: 1,
+			wantErr:     apperror.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			check := tt.check
+			if check == nil {
+				check = func([]model.Dependency) error { return nil }
+			}
+			repo := NewTaskRepository(db, rclient)
+			err := repo.AddDependency(ctx, model.Dependency{TaskID: 2, BlockedBy: 1}, check)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantVersion, repo.listVersion(ctx))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_RemoveDependency(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "case 1 -> remove dependency",
+			mock: func() {
+				mock.ExpectExec(`DELETE FROM task_dependencies WHERE task_id=(.*) AND blocked_by=(.*)`).WithArgs(2, 1, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "case 2 -> dependency not found",
+			mock: func() {
+				mock.ExpectExec(`DELETE FROM task_dependencies WHERE task_id=(.*) AND blocked_by=(.*)`).WithArgs(2, 1, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			err := repo.RemoveDependency(userCtx, model.Dependency{TaskID: 2, BlockedBy: 1})
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_GetDependencies(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	rows := sqlmock.NewRows([]string{"task_id", "blocked_by"}).
+		AddRow(2, 1).
+		AddRow(3, 2)
+	mock.ExpectQuery(`SELECT task_id, blocked_by FROM task_dependencies`).WillReturnRows(rows)
+
+	repo := NewTaskRepository(db, rclient)
+	result, err := repo.GetDependencies(userCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Dependency{{TaskID: 2, BlockedBy: 1}, {TaskID: 3, BlockedBy: 2}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_GetBlockers(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	rows := sqlmock.NewRows(taskColumns).
+		AddRow(1, "task 1", true, nil, "UTC", nil, 0, nil, nil, 0, 2, nil, "", "", "")
+	mock.ExpectQuery(`SELECT (.+) FROM tasks JOIN task_dependencies (.*) WHERE task_dependencies.task_id=(.*)`).WithArgs(2, testOwner, testWorkspace).WillReturnRows(rows)
+
+	repo := NewTaskRepository(db, rclient)
+	result, err := repo.GetBlockers(userCtx, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TaskModel{{ID: 1, TaskName: "task 1", IsDone: true, Timezone: "UTC", Version: 2}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_GetOpenTasks(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	rows := sqlmock.NewRows(listedColumns).
+		AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 1, nil, "", "", "", false).
+		AddRow(2, "task 2", false, nil, "UTC", nil, 0, nil, nil, 0, 1, nil, "", "", "", true)
+	mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE owner_id=(.*) AND NOT is_done AND deleted_at IS NULL ORDER BY id`).WithArgs(testOwner, testWorkspace).WillReturnRows(rows)
+
+	repo := NewTaskRepository(db, rclient)
+	result, err := repo.GetOpenTasks(userCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TaskModel{
+		{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 1},
+		{ID: 2, TaskName: "task 2", Timezone: "UTC", Version: 1, Blocked: true},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
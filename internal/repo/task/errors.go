@@ -0,0 +1,18 @@
+package task
+
+import (
+	"errors"
+	"to-do-list/internal/repo/dberror"
+	"to-do-list/pkg/apperror"
+
+	"github.com/lib/pq"
+)
+
+func dbError(err error, message string) error {
+	var pqErr *pq.Error
+	// a list of another user is as good as missing
+	if errors.As(err, &pqErr) && (pqErr.Constraint == "tasks_list_id_fkey" || pqErr.Constraint == "tasks_list_owner_fkey") {
+		return apperror.Wrap(apperror.ErrValidation, "list not found", err)
+	}
+	return dberror.Wrap(err, "task", message)
+}
@@ -0,0 +1,264 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// AppendEvents adds events to the audit log of the user of ctx, all of them
+// or none.
+func (r *Repo) AppendEvents(ctx context.Context, events ...model.TaskEvent) error {
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(err, "record task events")
+	}
+	defer tx.Rollback()
+
+	if err := appendEvents(ctx, tx, events...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError(err, "record task events")
+	}
+
+	return nil
+}
+
+// RecordChanges appends what record makes of changes to the audit log in
+// tx, the transaction of the write that made them.
+func RecordChanges(ctx context.Context, tx *sql.Tx, record model.Record, changes ...model.Change) error {
+	if record == nil || len(changes) == 0 {
+		return nil
+	}
+	return appendEvents(ctx, tx, record(changes)...)
+}
+
+// recordUpdate appends what record makes of the change of before into the
+// task as tx finds it now.
+func recordUpdate(ctx context.Context, tx *sql.Tx, record model.Record, before model.TaskModel) error {
+	if record == nil {
+		return nil
+	}
+	after, err := lockTask(ctx, tx, before.ID)
+	if err != nil {
+		return err
+	}
+	return RecordChanges(ctx, tx, record, model.Change{Before: &before, After: &after})
+}
+
+func appendEvents(ctx context.Context, tx *sql.Tx, events ...model.TaskEvent) error {
+	for _, event := range events {
+		before, err := snapshot(event.Before)
+		if err != nil {
+			return err
+		}
+		after, err := snapshot(event.After)
+		if err != nil {
+			return err
+		}
+
+		reverts := sql.NullInt64{Int64: event.Reverts, Valid: event.Reverts != 0}
+		user := sql.NullInt64{Int64: requestinfo.UserID(ctx), Valid: requestinfo.UserID(ctx) != 0}
+		if _, err := tx.ExecContext(ctx, model.InsertTaskEventQuery, event.TaskID, event.Action, before, after, event.Actor, event.RequestID, reverts, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx), user); err != nil {
+			fmt.Println("Error on Repo :", err)
+			return dbError(err, "record task event")
+		}
+	}
+	return nil
+}
+
+// GetEvents returns one page of the audit log, newest first. Events are not
+// cached, they are read far less often than they are written.
+func (r *Repo) GetEvents(ctx context.Context, filter model.EventFilter) (model.EventPage, error) {
+
+	var Page = model.EventPage{Events: []model.TaskEvent{}}
+
+	query, args, err := buildEventQuery(requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx), filter)
+	if err != nil {
+		return Page, err
+	}
+
+	rows, err := r.Db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		fmt.Println("Error on Repo :", err)
+		return Page, dbError(err, "fetch task events")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return Page, dbError(err, "scan task event")
+		}
+		Page.Events = append(Page.Events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return Page, dbError(err, "fetch task events")
+	}
+
+	if len(Page.Events) > filter.Limit {
+		Page.Events = Page.Events[:filter.Limit]
+		Page.NextCursor = strconv.FormatInt(Page.Events[filter.Limit-1].ID, 10)
+	}
+
+	return Page, nil
+}
+
+// GetUndoableEvents returns the events of the last change of user that has
+// not been undone yet, newest first, none when there is nothing to undo.
+// The change can be to the tasks of another owner, whom the events name.
+func (r *Repo) GetUndoableEvents(ctx context.Context, user int64) ([]model.TaskEvent, error) {
+
+	var Events = []model.TaskEvent{}
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchUndoableEventsQuery, user, requestinfo.WorkspaceID(ctx))
+
+	if err != nil {
+		fmt.Println("Error on Repo :", err)
+		return nil, dbError(err, "fetch task events")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var owner int64
+		event, err := scanEvent(rows, &owner)
+		if err != nil {
+			return nil, dbError(err, "scan task event")
+		}
+		event.OwnerID = owner
+		Events = append(Events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch task events")
+	}
+
+	return Events, nil
+}
+
+// GetSnapshot returns the task as it was recorded at version.
+func (r *Repo) GetSnapshot(ctx context.Context, id, version int64) (model.TaskModel, error) {
+
+	var data []byte
+	err := r.Db.QueryRowContext(ctx, model.FetchTaskSnapshotQuery, id, version, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)).Scan(&data)
+	if err == sql.ErrNoRows {
+		return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "version not found in the history of the task")
+	}
+	if err != nil {
+		return model.TaskModel{}, dbError(err, "fetch task snapshot")
+	}
+
+	task, err := readSnapshot(data)
+	if err != nil {
+		return model.TaskModel{}, dbError(err, "read task snapshot")
+	}
+
+	return *task, nil
+}
+
+// buildEventQuery pages through the events of owner in workspace by id,
+// which grows with time. Like buildListQuery it fetches one row more than
+// the limit.
+func buildEventQuery(owner, workspace int64, filter model.EventFilter) (string, []interface{}, error) {
+	b := queryBuilder{}
+
+	b.where("owner_id = " + b.arg(owner))
+	b.where("workspace_id = " + b.arg(workspace))
+
+	if filter.TaskID != nil {
+		b.where("task_id = " + b.arg(*filter.TaskID))
+	}
+
+	if filter.Actor != "" {
+		b.where("actor = " + b.arg(filter.Actor))
+	}
+
+	if filter.Action != "" {
+		b.where("action = " + b.arg(filter.Action))
+	}
+
+	if filter.From != nil {
+		b.where("created_at >= " + b.arg(*filter.From))
+	}
+
+	if filter.To != nil {
+		b.where("created_at < " + b.arg(*filter.To))
+	}
+
+	if filter.Cursor != "" {
+		last, err := strconv.ParseInt(filter.Cursor, 10, 64)
+		if err != nil {
+			return "", nil, apperror.New(apperror.ErrValidation, "invalid cursor")
+		}
+		b.where("id < " + b.arg(last))
+	}
+
+	query := model.SelectTaskEventQuery + " WHERE " + strings.Join(b.conds, " AND ")
+	query += " ORDER BY id DESC LIMIT " + b.arg(filter.Limit+1)
+
+	return query, b.args, nil
+}
+
+// snapshot turns a task into the value of a jsonb column, NULL for none.
+func snapshot(task *model.TaskModel) (interface{}, error) {
+	if task == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot task: %w", err)
+	}
+	return data, nil
+}
+
+// scanEvent reads a row selected with model.TaskEventColumns, followed by
+// the columns read into extra.
+func scanEvent(row scanner, extra ...interface{}) (model.TaskEvent, error) {
+	var (
+		event         model.TaskEvent
+		before, after []byte
+	)
+
+	dest := []interface{}{&event.ID, &event.TaskID, &event.Action, &before, &after, &event.Actor, &event.RequestID, &event.CreatedAt, &event.Reverts}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return event, err
+	}
+
+	if event.Before, err = readSnapshot(before); err != nil {
+		return event, err
+	}
+	if event.After, err = readSnapshot(after); err != nil {
+		return event, err
+	}
+
+	return event, nil
+}
+
+func readSnapshot(data []byte) (*model.TaskModel, error) {
+	if data == nil {
+		return nil, nil
+	}
+	task := &model.TaskModel{}
+	if err := json.Unmarshal(data, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
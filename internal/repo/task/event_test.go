@@ -0,0 +1,204 @@
+package task
+
+import (
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var eventColumns = []string{"id", "task_id", "action", "before", "after", "actor", "request_id", "created_at", "reverts"}
+
+func TestRepo_AppendEvents(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	events := []model.TaskEvent{
+		{TaskID: 1, Action: model.EventCreate, After: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}, Actor: "alice", RequestID: "req-1"},
+		{TaskID: 2, Action: model.EventDelete, Before: &model.TaskModel{ID: 2, TaskName: "task 2", Version: 3}, Actor: "alice", RequestID: "req-1"},
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "case 1 -> every event recorded",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO task_events (.*)`).
+					WithArgs(1, "create", nil, []byte(`{"id":1,"task_name":"task 1","is_done":false,"priority":"none","position":0,"version":1}`), "alice", "req-1", nil, testOwner, testWorkspace, testOwner).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO task_events (.*)`).
+					WithArgs(2, "delete", []byte(`{"id":2,"task_name":"task 2","is_done":false,"priority":"none","position":0,"version":3}`), nil, "alice", "req-1", nil, testOwner, testWorkspace, testOwner).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "case 2 -> none recorded when one fails",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO task_events (.*)`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO task_events (.*)`).WillReturnError(&pq.Error{Code: "08006"})
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			err := repo.AppendEvents(userCtx, events...)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_GetEvents(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	taskID := int64(1)
+	from := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	at := from.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		filter  model.EventFilter
+		mock    func()
+		want    model.EventPage
+		wantErr error
+	}{
+		{
+			name:   "case 1 -> history of a task with next cursor",
+			filter: model.EventFilter{TaskID: &taskID, Limit: 1},
+			mock: func() {
+				rows := sqlmock.NewRows(eventColumns).
+					AddRow(5, 1, "update", []byte(`{"id":1,"task_name":"task 1","version":1}`), []byte(`{"id":1,"task_name":"task one","version":2}`), "alice", "req-2", at, 0).
+					AddRow(4, 1, "create", nil, []byte(`{"id":1,"task_name":"task 1","version":1}`), "alice", "req-1", at, 0)
+				mock.ExpectQuery(`SELECT (.+) FROM task_events WHERE owner_id = \$1 AND workspace_id = \$2 AND task_id = \$3 ORDER BY id DESC LIMIT \$4`).WithArgs(testOwner, testWorkspace, 1, 2).WillReturnRows(rows)
+			},
+			want: model.EventPage{
+				Events: []model.TaskEvent{
+					{ID: 5, TaskID: 1, Action: "update", Before: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}, After: &model.TaskModel{ID: 1, TaskName: "task one", Version: 2}, Actor: "alice", RequestID: "req-2", CreatedAt: at},
+				},
+				NextCursor: "5",
+			},
+		},
+		{
+			name:   "case 2 -> audit of a time range from a cursor",
+			filter: model.EventFilter{Actor: "alice", From: &from, To: &to, Cursor: "5", Limit: 10},
+			mock: func() {
+				rows := sqlmock.NewRows(eventColumns).
+					AddRow(4, 1, "create", nil, []byte(`{"id":1,"task_name":"task 1","version":1}`), "alice", "req-1", at, 0)
+				mock.ExpectQuery(`SELECT (.+) FROM task_events WHERE owner_id = \$1 AND workspace_id = \$2 AND actor = \$3 AND created_at >= \$4 AND created_at < \$5 AND id < \$6 ORDER BY id DESC LIMIT \$7`).
+					WithArgs(testOwner, testWorkspace, "alice", from, to, 5, 11).WillReturnRows(rows)
+			},
+			want: model.EventPage{
+				Events: []model.TaskEvent{
+					{ID: 4, TaskID: 1, Action: "create", After: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}, Actor: "alice", RequestID: "req-1", CreatedAt: at},
+				},
+			},
+		},
+		{
+			name:    "case 3 -> invalid cursor",
+			filter:  model.EventFilter{Cursor: "abc", Limit: 10},
+			mock:    func() {},
+			want:    model.EventPage{Events: []model.TaskEvent{}},
+			wantErr: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			page, err := repo.GetEvents(userCtx, tt.filter)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, page)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_GetUndoableEvents(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	at := time.Date(2026, 3, 9, 1, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(append(eventColumns, "owner_id")).
+		AddRow(7, 2, "delete", []byte(`{"id":2,"task_name":"task 2","version":3}`), nil, "alice", "req-3", at, 0, 3).
+		AddRow(6, 1, "update", []byte(`{"id":1,"task_name":"task 1","version":1}`), []byte(`{"id":1,"task_name":"task one","version":2}`), "alice", "req-3", at, 0, 3)
+	mock.ExpectQuery(`WITH last AS (.+) ORDER BY task_events.id DESC`).WithArgs(testOwner, testWorkspace).WillReturnRows(rows)
+
+	repo := NewTaskRepository(db, rclient)
+	events, err := repo.GetUndoableEvents(userCtx, testOwner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TaskEvent{
+		{ID: 7, TaskID: 2, Action: "delete", Before: &model.TaskModel{ID: 2, TaskName: "task 2", Version: 3}, Actor: "alice", RequestID: "req-3", CreatedAt: at, OwnerID: 3},
+		{ID: 6, TaskID: 1, Action: "update", Before: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}, After: &model.TaskModel{ID: 1, TaskName: "task one", Version: 2}, Actor: "alice", RequestID: "req-3", CreatedAt: at, OwnerID: 3},
+	}, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_GetSnapshot(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.TaskModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> recorded version",
+			mock: func() {
+				mock.ExpectQuery(`SELECT snapshot FROM (.+) ORDER BY id DESC LIMIT 1`).WithArgs(1, 2, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow([]byte(`{"id":1,"task_name":"task one","version":2}`)))
+			},
+			want: model.TaskModel{ID: 1, TaskName: "task one", Version: 2},
+		},
+		{
+			name: "case 2 -> version never recorded",
+			mock: func() {
+				mock.ExpectQuery(`SELECT snapshot FROM (.+)`).WithArgs(1, 2, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"snapshot"}))
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			task, err := repo.GetSnapshot(userCtx, 1, 2)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, task)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
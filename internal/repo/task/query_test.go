@@ -0,0 +1,115 @@
+package task
+
+import (
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildListQuery(t *testing.T) {
+	done := true
+	high := model.PriorityHigh
+	listID := int64(2)
+	from := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name     string
+		filter   model.TaskFilter
+		want     string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:     "case 1 -> no filter",
+			filter:   model.TaskFilter{Limit: 10},
+			want:     model.SelectTaskQuery + ` WHERE deleted_at IS NULL AND owner_id = $1 AND workspace_id = $2 ORDER BY id LIMIT $3`,
+			wantArgs: []interface{}{int64(1), int64(5), 11},
+		},
+		{
+			name:     "case 2 -> status and name filter",
+			filter:   model.TaskFilter{IsDone: &done, Query: "a_b", Limit: 10},
+			want:     model.SelectTaskQuery + ` WHERE deleted_at IS NULL AND owner_id = $1 AND workspace_id = $2 AND is_done = $3 AND task_name ILIKE $4 ESCAPE '\' ORDER BY id LIMIT $5`,
+			wantArgs: []interface{}{int64(1), int64(5), true, `%a\_b%`, 11},
+		},
+		{
+			name:     "case 3 -> descending id needs no tie breaker",
+			filter:   model.TaskFilter{Sort: []model.SortField{{Field: "id", Desc: true}}, Limit: 10},
+			want:     model.SelectTaskQuery + ` WHERE deleted_at IS NULL AND owner_id = $1 AND workspace_id = $2 ORDER BY id DESC LIMIT $3`,
+			wantArgs: []interface{}{int64(1), int64(5), 11},
+		},
+		{
+			name: "case 4 -> cursor with mixed directions",
+			filter: model.TaskFilter{
+				Sort:   []model.SortField{{Field: "is_done"}, {Field: "task_name", Desc: true}},
+				Cursor: encodeCursor([]model.SortField{{Field: "is_done"}, {Field: "task_name", Desc: true}, {Field: "id"}}, model.TaskModel{ID: 4, TaskName: "b"}),
+				Limit:  10,
+			},
+			want:     model.SelectTaskQuery + ` WHERE deleted_at IS NULL AND owner_id = $1 AND workspace_id = $2 AND ((is_done > $3) OR (is_done = $4 AND task_name < $5) OR (is_done = $4 AND task_name = $6 AND id > $7)) ORDER BY is_done, task_name DESC, id LIMIT $8`,
+			wantArgs: []interface{}{int64(1), int64(5), false, false, "b", "b", int64(4), 11},
+		},
+		{
+			name: "case 5 -> cursor from another sort",
+			filter: model.TaskFilter{
+				Sort:   []model.SortField{{Field: "task_name"}},
+				Cursor: encodeCursor(orderOf(model.TaskFilter{}), model.TaskModel{ID: 4}),
+				Limit:  10,
+			},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name: "case 6 -> due window sorted by deadline",
+			filter: model.TaskFilter{
+				DueFrom: &from,
+				DueTo:   &to,
+				Sort:    []model.SortField{{Field: "due_at"}},
+				Cursor:  encodeCursor([]model.SortField{{Field: "due_at"}, {Field: "id"}}, model.TaskModel{ID: 9}),
+				Limit:   10,
+			},
+			want:     model.SelectTaskQuery + ` WHERE deleted_at IS NULL AND owner_id = $1 AND workspace_id = $2 AND due_at >= $3 AND due_at < $4 AND ((COALESCE(due_at, 'infinity') > $5) OR (COALESCE(due_at, 'infinity') = $6 AND id > $7)) ORDER BY COALESCE(due_at, 'infinity'), id LIMIT $8`,
+			wantArgs: []interface{}{int64(1), int64(5), from, to, "infinity", "infinity", int64(9), 11},
+		},
+		{
+			name: "case 7 -> priority and every tag",
+			filter: model.TaskFilter{
+				Priority: &high,
+				Tags:     []string{"home", "work"},
+				Sort:     []model.SortField{{Field: "priority", Desc: true}},
+				Limit:    10,
+			},
+			want: model.SelectTaskQuery + ` WHERE deleted_at IS NULL AND owner_id = $1 AND workspace_id = $2 AND priority = $3` +
+				` AND EXISTS (SELECT 1 FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id AND tags.name = $4)` +
+				` AND EXISTS (SELECT 1 FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id AND tags.name = $5)` +
+				` ORDER BY priority DESC, id LIMIT $6`,
+			wantArgs: []interface{}{int64(1), int64(5), int64(3), "home", "work", 11},
+		},
+		{
+			name:     "case 8 -> tasks of one list",
+			filter:   model.TaskFilter{ListID: &listID, IsDone: &done, Limit: 10},
+			want:     model.SelectTaskQuery + ` WHERE deleted_at IS NULL AND owner_id = $1 AND workspace_id = $2 AND is_done = $3 AND list_id = $4 ORDER BY id LIMIT $5`,
+			wantArgs: []interface{}{int64(1), int64(5), true, int64(2), 11},
+		},
+		{
+			name:    "case 9 -> unknown sort field",
+			filter:  model.TaskFilter{Sort: []model.SortField{{Field: "password"}}, Limit: 10},
+			wantErr: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := buildListQuery(1, 5, tt.filter)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, query)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
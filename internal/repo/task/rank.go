@@ -0,0 +1,175 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/rank"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/lib/pq"
+)
+
+// Reposition puts a top level task right before or right after another one
+// in the manual order. Only the moved task gets a new key, unless its new
+// neighbours leave no room and every key is spread again.
+func (r *Repo) Reposition(ctx context.Context, id int64, place model.RepositionRequest, record model.Record) (model.TaskModel, error) {
+
+	anchor, after := place.Before, false
+	if place.After != nil {
+		anchor, after = place.After, true
+	}
+	if anchor == nil {
+		return model.TaskModel{}, apperror.New(apperror.ErrValidation, "before or after is required")
+	}
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.TaskModel{}, dbError(err, "move task")
+	}
+	defer tx.Rollback()
+
+	owner, workspace := requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)
+
+	var current string
+	err = tx.QueryRowContext(ctx, model.LockRankQuery, id, owner, workspace).Scan(&current)
+	if err == sql.ErrNoRows {
+		return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "task not found")
+	}
+	if err != nil {
+		return model.TaskModel{}, dbError(err, "move task")
+	}
+
+	var before model.TaskModel
+	if record != nil {
+		if before, err = lockTask(ctx, tx, id); err != nil {
+			return model.TaskModel{}, err
+		}
+	}
+
+	var anchorRank string
+	err = tx.QueryRowContext(ctx, model.FetchRankQuery, *anchor, owner, workspace).Scan(&anchorRank)
+	if err == sql.ErrNoRows {
+		return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "anchor task not found")
+	}
+	if err != nil {
+		return model.TaskModel{}, dbError(err, "move task")
+	}
+
+	lower, upper := "", anchorRank
+	if after {
+		lower, upper = anchorRank, ""
+		err = tx.QueryRowContext(ctx, model.RankAfterQuery, anchorRank, id, owner).Scan(&upper)
+	} else {
+		err = tx.QueryRowContext(ctx, model.RankBeforeQuery, anchorRank, id, owner).Scan(&lower)
+	}
+	if err != nil {
+		return model.TaskModel{}, dbError(err, "move task")
+	}
+
+	spread := []int64{}
+	key, err := rank.Between(lower, upper)
+	if err != nil || len(key) > rank.MaxLen {
+		if spread, err = spreadRanks(ctx, tx, id, *anchor, after); err != nil {
+			return model.TaskModel{}, err
+		}
+		key = ""
+	}
+
+	if _, err := tx.ExecContext(ctx, model.SetRankQuery, key, id, owner, workspace); err != nil {
+		fmt.Println(err)
+		return model.TaskModel{}, dbError(err, "move task")
+	}
+
+	if err := recordUpdate(ctx, tx, record, before); err != nil {
+		return model.TaskModel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.TaskModel{}, dbError(err, "move task")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx), append(spread, id)...)
+
+	return r.GetByID(ctx, id)
+}
+
+// Rebalance spreads the keys of the manual order of every user again once
+// one of them is longer than rank.MaxLen, and returns how many tasks got a
+// new key.
+func (r *Repo) Rebalance(ctx context.Context) (int64, error) {
+
+	owners, err := queryIds(ctx, r.Db, model.LongRankOwnersQuery, rank.MaxLen)
+	if err != nil {
+		return 0, dbError(err, "rebalance ranks")
+	}
+
+	var spread int64
+	for _, owner := range owners {
+		count, err := r.rebalance(requestinfo.WithOwnerID(ctx, owner))
+		if err != nil {
+			return spread, err
+		}
+		spread += count
+	}
+
+	return spread, nil
+}
+
+// rebalance spreads the keys of the user of ctx.
+func (r *Repo) rebalance(ctx context.Context) (int64, error) {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, dbError(err, "rebalance ranks")
+	}
+	defer tx.Rollback()
+
+	ids, err := spreadRanks(ctx, tx, 0, 0, false)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError(err, "rebalance ranks")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx), ids...)
+
+	return int64(len(ids)), nil
+}
+
+// spreadRanks gives every task of the user of ctx an evenly spaced key in
+// the current order, with task id taken out and put next to anchor. An id
+// of 0 moves nothing.
+func spreadRanks(ctx context.Context, tx *sql.Tx, id, anchor int64, after bool) ([]int64, error) {
+	owner := requestinfo.OwnerID(ctx)
+
+	ids, err := queryIds(ctx, tx, model.LockRanksQuery, owner)
+	if err != nil {
+		return nil, dbError(err, "rebalance ranks")
+	}
+
+	order := make([]int64, 0, len(ids))
+	for _, other := range ids {
+		if other == id {
+			continue
+		}
+		if other == anchor && !after {
+			order = append(order, id)
+		}
+		order = append(order, other)
+		if other == anchor && after {
+			order = append(order, id)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, model.SpreadRanksQuery, pq.Array(order), pq.Array(rank.Spread(len(order))), owner); err != nil {
+		fmt.Println(err)
+		return nil, dbError(err, "rebalance ranks")
+	}
+
+	return order, nil
+}
@@ -0,0 +1,147 @@
+package task
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/rank"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestRepo_Reposition(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+	before, after := int64(2), int64(3)
+
+	tests := []struct {
+		name    string
+		place   model.RepositionRequest
+		mock    func()
+		want    model.TaskModel
+		wantErr error
+	}{
+		{
+			name:  "case 1 -> move before a task",
+			place: model.RepositionRequest{Before: &before},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT rank FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+				mock.ExpectQuery(`SELECT rank FROM tasks WHERE id=(.*)`).WithArgs(2, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("k"))
+				mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), ''\) FROM tasks WHERE rank < (.*)`).WithArgs("k", 1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("F"))
+				mock.ExpectExec(`UPDATE tasks SET rank = (.*) WHERE id=(.*)`).WithArgs("U", 1, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) AND deleted_at IS NULL`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(taskColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 2, nil, "", "", "U"))
+			},
+			want: model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 2, Rank: "U"},
+		},
+		{
+			name:  "case 2 -> move after the last task",
+			place: model.RepositionRequest{After: &after},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT rank FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+				mock.ExpectQuery(`SELECT rank FROM tasks WHERE id=(.*)`).WithArgs(3, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("k"))
+				mock.ExpectQuery(`SELECT COALESCE\(MIN\(rank\), ''\) FROM tasks WHERE rank > (.*)`).WithArgs("k", 1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow(""))
+				mock.ExpectExec(`UPDATE tasks SET rank = (.*) WHERE id=(.*)`).WithArgs("s", 1, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) AND deleted_at IS NULL`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(taskColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 2, nil, "", "", "s"))
+			},
+			want: model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 2, Rank: "s"},
+		},
+		{
+			name:  "case 3 -> no room between the neighbours spreads every key",
+			place: model.RepositionRequest{Before: &before},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT rank FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+				mock.ExpectQuery(`SELECT rank FROM tasks WHERE id=(.*)`).WithArgs(2, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("k"))
+				mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), ''\) FROM tasks WHERE rank < (.*)`).WithArgs("k", 1, testOwner).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("k"))
+				mock.ExpectQuery(`SELECT id FROM tasks WHERE owner_id=(.*) ORDER BY rank, id FOR UPDATE`).WithArgs(testOwner).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(3).AddRow(2))
+				mock.ExpectExec(`UPDATE tasks SET rank = spread.rank (.*)`).WithArgs(pq.Array([]int64{3, 1, 2}), pq.Array(rank.Spread(3)), testOwner).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(`UPDATE tasks SET rank = (.*) WHERE id=(.*)`).WithArgs("", 1, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) AND deleted_at IS NULL`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(taskColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 2, nil, "", "", rank.Spread(3)[1]))
+			},
+			want: model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 2, Rank: rank.Spread(3)[1]},
+		},
+		{
+			name:  "case 4 -> anchor not found",
+			place: model.RepositionRequest{After: &after},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT rank FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+				mock.ExpectQuery(`SELECT rank FROM tasks WHERE id=(.*)`).WithArgs(3, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"rank"}))
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			result, err := repo.Reposition(ctx, 1, tt.place, nil)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_Rebalance(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	tests := []struct {
+		name string
+		mock func()
+		want int64
+	}{
+		{
+			name: "case 1 -> short keys are left alone",
+			mock: func() {
+				mock.ExpectQuery(`SELECT DISTINCT owner_id FROM tasks (.*)`).WithArgs(rank.MaxLen).WillReturnRows(sqlmock.NewRows([]string{"owner_id"}))
+			},
+		},
+		{
+			name: "case 2 -> long keys are spread again for each of their owners",
+			mock: func() {
+				mock.ExpectQuery(`SELECT DISTINCT owner_id FROM tasks (.*)`).WithArgs(rank.MaxLen).WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow(1).AddRow(2))
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM tasks WHERE owner_id=(.*) ORDER BY rank, id FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(1))
+				mock.ExpectExec(`UPDATE tasks SET rank = spread.rank (.*)`).WithArgs(pq.Array([]int64{2, 1}), pq.Array(rank.Spread(2)), 1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM tasks WHERE owner_id=(.*) ORDER BY rank, id FOR UPDATE`).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectExec(`UPDATE tasks SET rank = spread.rank (.*)`).WithArgs(pq.Array([]int64{5}), pq.Array(rank.Spread(1)), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			result, err := repo.Rebalance(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
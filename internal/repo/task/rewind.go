@@ -0,0 +1,182 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// Rewind applies the steps in order in one transaction and returns the
+// task before and after each of them. A task is only checked against the
+// Expected version of its first step, the later ones find the version the
+// earlier ones left.
+func (r *Repo) Rewind(ctx context.Context, steps []model.RewindStep, record model.Record) ([]model.RewindResult, error) {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, dbError(err, "rewind tasks")
+	}
+	defer tx.Rollback()
+
+	var (
+		results = make([]model.RewindResult, 0, len(steps))
+		checked = map[int64]bool{}
+		changed []int64
+	)
+	for _, step := range steps {
+		if checked[step.TaskID] {
+			step.Expected = 0
+		}
+		checked[step.TaskID] = true
+
+		result, ids, err := rewindTask(ctx, tx, step)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+		changed = append(changed, ids...)
+	}
+
+	if err := RecordChanges(ctx, tx, record, model.RewindChanges(results)...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(err, "rewind tasks")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx), changed...)
+
+	return results, nil
+}
+
+// rewindTask applies one step in tx and returns the ids of every task it
+// changed.
+func rewindTask(ctx context.Context, tx *sql.Tx, step model.RewindStep) (model.RewindResult, []int64, error) {
+
+	result := model.RewindResult{}
+
+	current, found, err := lockAnyTask(ctx, tx, step.TaskID)
+	if err != nil {
+		return result, nil, err
+	}
+
+	var ids []int64
+	switch {
+	case !found && step.State == nil:
+		return result, nil, apperror.New(apperror.ErrConflict, fmt.Sprintf("task %d has been purged", step.TaskID))
+
+	case !found:
+		ids, err = recreateTask(ctx, tx, *step.State)
+
+	case step.Expected != 0 && current.Version != step.Expected:
+		return result, nil, apperror.New(apperror.ErrConflict, fmt.Sprintf("task %d has changed since", step.TaskID))
+
+	case step.State == nil:
+		result.Before = &current
+		if current.DeletedAt == nil {
+			var parentID *int64
+			if parentID, ids, err = trashTask(ctx, tx, current.ID, current.Version); err == nil {
+				ids, err = appendRollUp(ctx, tx, ids, parentID)
+			}
+		}
+
+	default:
+		result.Before = &current
+		ids, err = rewriteTask(ctx, tx, current, *step.State)
+	}
+	if err != nil {
+		return result, nil, err
+	}
+
+	result.After, _, err = lockAnyTask(ctx, tx, step.TaskID)
+	return result, ids, err
+}
+
+// rewriteTask takes current out of the trash when it is there and writes
+// state over it.
+func rewriteTask(ctx context.Context, tx *sql.Tx, current, state model.TaskModel) ([]int64, error) {
+
+	ids := []int64{current.ID}
+	if current.DeletedAt != nil {
+		restored, err := restoreTask(ctx, tx, current.ID)
+		if err != nil {
+			return nil, err
+		}
+		ids = restored
+	}
+
+	if _, err := tx.ExecContext(ctx, model.RewindTaskQuery, state.TaskName, state.IsDone, state.DueAt, state.Timezone, state.RemindAt, state.Priority, state.ListID, state.Position, state.RRule, state.RepeatFrom, state.Rank, current.ID, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)); err != nil {
+		fmt.Println(err)
+		return nil, dbError(err, "rewind task")
+	}
+
+	if _, err := tx.ExecContext(ctx, model.DeleteTaskTagsQuery, current.ID); err != nil {
+		return nil, dbError(err, "update task tags")
+	}
+
+	if err := insertTags(ctx, tx, current.ID, state.Tags); err != nil {
+		return nil, err
+	}
+
+	if current.ParentID == nil {
+		subtaskIds, err := queryIds(ctx, tx, model.MoveSubtasksQuery, state.ListID, current.ID, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+		if err != nil {
+			return nil, dbError(err, "move subtasks")
+		}
+		ids = append(ids, subtaskIds...)
+	}
+
+	return appendRollUp(ctx, tx, ids, current.ParentID)
+}
+
+// recreateTask creates a purged task again with its id. A subtask needs its
+// parent back first.
+func recreateTask(ctx context.Context, tx *sql.Tx, state model.TaskModel) ([]int64, error) {
+
+	if state.ParentID != nil {
+		parent, found, err := lockAnyTask(ctx, tx, *state.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if !found || parent.DeletedAt != nil {
+			return nil, apperror.New(apperror.ErrConflict, "the parent task is gone, bring it back first")
+		}
+		state.ListID = parent.ListID
+	}
+
+	if _, err := tx.ExecContext(ctx, model.InsertTaskWithIdQuery, state.ID, state.TaskName, state.IsDone, state.DueAt, state.Timezone, state.RemindAt, state.Priority, state.ListID, state.ParentID, state.Position, state.RRule, state.RepeatFrom, state.Rank, state.Version+1, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)); err != nil {
+		fmt.Println(err)
+		return nil, dbError(err, "create task")
+	}
+
+	if err := insertTags(ctx, tx, state.ID, state.Tags); err != nil {
+		return nil, err
+	}
+
+	return appendRollUp(ctx, tx, []int64{state.ID}, state.ParentID)
+}
+
+func lockAnyTask(ctx context.Context, tx *sql.Tx, id int64) (model.TaskModel, bool, error) {
+	var deletedAt sql.NullTime
+	task, err := scanTask(tx.QueryRowContext(ctx, model.LockAnyTaskQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)), &deletedAt)
+	if err == sql.ErrNoRows {
+		return task, false, nil
+	}
+	if err != nil {
+		return task, false, dbError(err, "fetch task")
+	}
+	task.DeletedAt = timePtr(deletedAt)
+	return task, true, nil
+}
+
+func appendRollUp(ctx context.Context, tx *sql.Tx, ids []int64, parentID *int64) ([]int64, error) {
+	rolledUp, err := rollUp(ctx, tx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return append(ids, rolledUp...), nil
+}
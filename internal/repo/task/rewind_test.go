@@ -0,0 +1,173 @@
+package task
+
+import (
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestRepo_Rewind(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+	deletedAt := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+	listID := int64(2)
+	anyColumns := append(taskColumns, "deleted_at")
+	state := model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", ListID: &listID, Version: 2, Tags: []string{"home"}}
+
+	tests := []struct {
+		name    string
+		steps   []model.RewindStep
+		mock    func()
+		want    []model.RewindResult
+		wantErr error
+	}{
+		{
+			name:  "case 1 -> write back the state before an update",
+			steps: []model.RewindStep{{TaskID: 1, Expected: 3, State: &state}},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task one", true, nil, "UTC", nil, 0, 2, nil, 0, 3, nil, "", "", "", nil))
+				mock.ExpectExec(`UPDATE tasks SET task_name=(.*), version = version \+ 1 WHERE id=(.*)`).
+					WithArgs("task 1", false, nil, "UTC", nil, 0, 2, 0, "", "", "", 1, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM task_tags WHERE task_id=(.*)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO tags (.*)`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO task_tags (.*)`).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`UPDATE tasks SET list_id=(.*) WHERE parent_id=(.*) RETURNING id`).WithArgs(2, 1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, 2, nil, 0, 4, "{home}", "", "", "", nil))
+				mock.ExpectCommit()
+			},
+			want: []model.RewindResult{{
+				Before: &model.TaskModel{ID: 1, TaskName: "task one", IsDone: true, Timezone: "UTC", ListID: &listID, Version: 3},
+				After:  model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", ListID: &listID, Version: 4, Tags: []string{"home"}},
+			}},
+		},
+		{
+			name:  "case 2 -> bring back a deleted task",
+			steps: []model.RewindStep{{TaskID: 1, Expected: 3, State: &state}},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, 2, nil, 0, 3, "{home}", "", "", "", deletedAt))
+				mock.ExpectQuery(`SELECT parent_id, deleted_at, (.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id", "deleted_at", "exists"}).AddRow(nil, deletedAt, false))
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = NULL(.*) RETURNING id`).WithArgs(1, deletedAt, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(4))
+				mock.ExpectExec(`UPDATE tasks SET task_name=(.*) WHERE id=(.*)`).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM task_tags WHERE task_id=(.*)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO tags (.*)`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO task_tags (.*)`).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`UPDATE tasks SET list_id=(.*) WHERE parent_id=(.*) RETURNING id`).WithArgs(2, 1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, 2, nil, 0, 5, "{home}", "", "", "", nil))
+				mock.ExpectCommit()
+			},
+			want: []model.RewindResult{{
+				Before: &model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", ListID: &listID, Version: 3, Tags: []string{"home"}, DeletedAt: &deletedAt},
+				After:  model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", ListID: &listID, Version: 5, Tags: []string{"home"}},
+			}},
+		},
+		{
+			name:  "case 3 -> create a purged task again with its id",
+			steps: []model.RewindStep{{TaskID: 1, Expected: 3, State: &state}},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns))
+				mock.ExpectExec(`INSERT INTO tasks \(id, (.*)\) VALUES (.*)`).
+					WithArgs(1, "task 1", false, nil, "UTC", nil, 0, 2, nil, 0, "", "", "", 3, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO tags (.*)`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO task_tags (.*)`).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, 2, nil, 0, 3, "{home}", "", "", "", nil))
+				mock.ExpectCommit()
+			},
+			want: []model.RewindResult{{
+				After: model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", ListID: &listID, Version: 3, Tags: []string{"home"}},
+			}},
+		},
+		{
+			name:  "case 4 -> trash a created task, then find it trashed",
+			steps: []model.RewindStep{{TaskID: 1, Expected: 1}, {TaskID: 1, Expected: 1}},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 1, nil, "", "", "", nil))
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) RETURNING parent_id`).WithArgs(1, 1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(nil))
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) WHERE parent_id=(.*)`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 2, nil, "", "", "", deletedAt))
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 2, nil, "", "", "", deletedAt))
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 2, nil, "", "", "", deletedAt))
+				mock.ExpectCommit()
+			},
+			want: []model.RewindResult{
+				{
+					Before: &model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 1},
+					After:  model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 2, DeletedAt: &deletedAt},
+				},
+				{
+					Before: &model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 2, DeletedAt: &deletedAt},
+					After:  model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 2, DeletedAt: &deletedAt},
+				},
+			},
+		},
+		{
+			name:  "case 5 -> task changed since",
+			steps: []model.RewindStep{{TaskID: 1, Expected: 3, State: &state}},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, 2, nil, 0, 4, nil, "", "", "", nil))
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrConflict,
+		},
+		{
+			name:  "case 6 -> purged task cannot be trashed",
+			steps: []model.RewindStep{{TaskID: 1, Expected: 1}},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(anyColumns))
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// copies are cached under the list version, start each case at 0
+			rclient.FlushAll(ctx)
+			rclient.Set(ctx, "ws:5:task:1:v0:1", "{}", 0)
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			result, err := repo.Rewind(ctx, tt.steps, nil)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+
+				_, err = rclient.Get(ctx, "ws:5:task:1:v0:1").Result()
+				assert.Equal(t, redis.Nil, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	model "to-do-list/internal/model/task"
+
+	"github.com/lib/pq"
+)
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// scanTask reads a row selected with model.TaskColumns, followed by the
+// columns read into extra.
+func scanTask(row scanner, extra ...interface{}) (model.TaskModel, error) {
+	var (
+		task     model.TaskModel
+		dueAt    sql.NullTime
+		remindAt sql.NullTime
+		listID   sql.NullInt64
+		parentID sql.NullInt64
+	)
+
+	dest := []interface{}{&task.ID, &task.TaskName, &task.IsDone, &dueAt, &task.Timezone, &remindAt, &task.Priority, &listID, &parentID, &task.Position, &task.Version, (*pq.StringArray)(&task.Tags), &task.RRule, &task.RepeatFrom, &task.Rank}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return task, err
+	}
+
+	if len(task.Tags) == 0 {
+		task.Tags = nil
+	}
+
+	task.DueAt = timePtr(dueAt)
+	task.RemindAt = timePtr(remindAt)
+	task.ListID = int64Ptr(listID)
+	task.ParentID = int64Ptr(parentID)
+
+	return task, nil
+}
+
+// scanListedTask reads a row selected with model.TaskColumns and
+// model.BlockedColumn.
+func scanListedTask(row scanner, extra ...interface{}) (model.TaskModel, error) {
+	var blocked bool
+	task, err := scanTask(row, append([]interface{}{&blocked}, extra...)...)
+	task.Blocked = blocked
+	return task, err
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func int64Ptr(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
+
+// queryIds runs a statement returning the ids of the tasks it changed.
+func queryIds(ctx context.Context, q querier, query string, args ...interface{}) ([]int64, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
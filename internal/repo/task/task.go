@@ -4,12 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"time"
 	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/rank"
+	"to-do-list/pkg/requestinfo"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/lib/pq"
 )
 
 type Repo struct {
@@ -17,10 +19,6 @@ type Repo struct {
 	Redis *redis.Client
 }
 
-const (
-	redisTaskGetAll = "tasks"
-)
-
 func NewTaskRepository(db *sql.DB, redis *redis.Client) *Repo {
 	return &Repo{
 		Db:    db,
@@ -28,101 +26,495 @@ func NewTaskRepository(db *sql.DB, redis *redis.Client) *Repo {
 	}
 }
 
-func (r *Repo) GetAll(ctx context.Context) ([]model.TaskModel, error) {
+func (r *Repo) GetAll(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
 
-	var Tasks = []model.TaskModel{}
-	rdb, err := r.Redis.Get(ctx, redisTaskGetAll).Result()
-	if err != redis.Nil {
+	Page := model.TaskPage{Tasks: []model.TaskModel{}}
+
+	query, args, err := buildListQuery(requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx), filter)
+	if err != nil {
+		return Page, err
+	}
+
+	key := pageKey(ctx, r.listVersion(ctx), filter)
+	rdb, err := r.Redis.Get(ctx, key).Result()
+	if err == nil {
+		if err = json.Unmarshal([]byte(rdb), &Page); err == nil {
+			return Page, nil
+		}
+		fmt.Println(err)
+	} else if err != redis.Nil {
 		fmt.Println(err)
-	} else {
-		err = json.Unmarshal([]byte(rdb), &Tasks)
+	}
+
+	rows, err := r.Db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		fmt.Println("Error on Repo :", err)
+		return Page, dbError(err, "fetch tasks")
+	}
+
+	defer rows.Close()
 
+	for rows.Next() {
+		task_row, err := scanListedTask(rows)
 		if err != nil {
-			fmt.Println(err.Error())
-		} else {
-			if len(Tasks) > 0 {
-				return Tasks, nil
-			}
+			return Page, dbError(err, "scan task")
 		}
+		Page.Tasks = append(Page.Tasks, task_row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return Page, dbError(err, "fetch tasks")
 	}
 
-	rows, err := r.Db.QueryContext(ctx, model.FetchAllTaskQuery)
+	if len(Page.Tasks) > filter.Limit {
+		Page.Tasks = Page.Tasks[:filter.Limit]
+		Page.NextCursor = encodeCursor(orderOf(filter), Page.Tasks[filter.Limit-1])
+	}
+
+	r.cache(ctx, key, Page, redisTaskCacheTTL)
+
+	return Page, nil
+}
+
+func (r *Repo) GetByID(ctx context.Context, id int64) (model.TaskModel, error) {
+
+	task := model.TaskModel{}
+	key := taskKey(ctx, r.listVersion(ctx), id)
+
+	rdb, err := r.Redis.Get(ctx, key).Result()
+	if err == nil {
+		if err = json.Unmarshal([]byte(rdb), &task); err == nil {
+			return task, nil
+		}
+		fmt.Println(err)
+	} else if err != redis.Nil {
+		fmt.Println(err)
+	}
+
+	task, err = scanTask(r.Db.QueryRowContext(ctx, model.FetchTaskByIdQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)))
+	if err != nil {
+		return task, dbError(err, "fetch task")
+	}
+
+	r.cache(ctx, key, task, redisTaskCacheTTL)
+
+	return task, nil
+}
+
+func (r *Repo) Create(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return task, dbError(err, "create task")
+	}
+	defer tx.Rollback()
+
+	task, err = insertTask(ctx, tx, task)
+	if err != nil {
+		return task, err
+	}
+
+	created := task
+	if err := RecordChanges(ctx, tx, record, model.Change{After: &created}); err != nil {
+		return task, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return task, dbError(err, "create task")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx))
+
+	return task, nil
+}
+
+// GetSubtasks returns the subtasks of a task in their order.
+func (r *Repo) GetSubtasks(ctx context.Context, id int64) ([]model.TaskModel, error) {
 
+	Tasks, err := querySubtasks(ctx, r.Db, id)
 	if err != nil {
 		fmt.Println("Error on Repo :", err)
-		return nil, errors.New("database error")
+		return nil, err
 	}
 
-	defer rows.Close()
+	return Tasks, nil
+}
+
+func querySubtasks(ctx context.Context, q querier, id int64) ([]model.TaskModel, error) {
+
+	var Tasks = []model.TaskModel{}
 
-	task_row := model.TaskModel{}
+	rows, err := q.QueryContext(ctx, model.FetchSubtasksQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+
+	if err != nil {
+		return nil, dbError(err, "fetch subtasks")
+	}
+
+	defer rows.Close()
 
 	for rows.Next() {
-		err := rows.Scan(&task_row.ID, &task_row.TaskName, &task_row.IsDone)
+		task_row, err := scanTask(rows)
 		if err != nil {
-			fmt.Println(err)
+			return nil, dbError(err, "scan subtask")
 		}
 		Tasks = append(Tasks, task_row)
 	}
 
-	json_data, err := json.Marshal(Tasks)
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch subtasks")
+	}
+
+	return Tasks, nil
+}
+
+// Reorder numbers the subtasks of a task in the order of ids, which has to
+// list every one of them once. Every subtask is a change to record.
+func (r *Repo) Reorder(ctx context.Context, id int64, ids []int64, record model.Record) error {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(err, "reorder subtasks")
+	}
+	defer tx.Rollback()
 
+	rows, err := tx.QueryContext(ctx, model.LockSubtasksQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
 	if err != nil {
+		return dbError(err, "reorder subtasks")
+	}
+
+	current := map[int64]bool{}
+	for rows.Next() {
+		var subtaskId int64
+		if err := rows.Scan(&subtaskId); err != nil {
+			rows.Close()
+			return dbError(err, "scan subtask")
+		}
+		current[subtaskId] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return dbError(err, "reorder subtasks")
+	}
+
+	seen := map[int64]bool{}
+	for _, subtaskId := range ids {
+		if !current[subtaskId] || seen[subtaskId] {
+			return apperror.New(apperror.ErrValidation, "ids must list every subtask once")
+		}
+		seen[subtaskId] = true
+	}
+	if len(seen) != len(current) {
+		return apperror.New(apperror.ErrValidation, "ids must list every subtask once")
+	}
+
+	var before []model.TaskModel
+	if record != nil {
+		if before, err = querySubtasks(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, model.ReorderSubtasksQuery, id, pq.Array(ids), requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)); err != nil {
 		fmt.Println(err)
-	} else {
-		_ = r.Redis.Set(context.Background(), redisTaskGetAll, json_data, time.Duration(0))
+		return dbError(err, "reorder subtasks")
 	}
 
-	return Tasks, nil
+	if record != nil {
+		after, err := querySubtasks(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := RecordChanges(ctx, tx, record, model.Changes(before, after)...); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError(err, "reorder subtasks")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx), ids...)
+
+	return nil
+}
+
+// SetDone changes only the status of a task.
+func (r *Repo) SetDone(ctx context.Context, id int64, done bool, record model.Record) error {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(err, "update task status")
+	}
+	defer tx.Rollback()
+
+	var before model.TaskModel
+	if record != nil {
+		if before, err = lockTask(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, model.SetTaskDoneQuery, done, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+
+	if err != nil {
+		fmt.Println(err)
+		return dbError(err, "update task status")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return dbError(err, "update task status")
+	}
+	if affected == 0 {
+		return apperror.New(apperror.ErrNotFound, "task not found")
+	}
+
+	if err := recordUpdate(ctx, tx, record, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError(err, "update task status")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx), id)
+
+	return nil
 }
 
-func (r *Repo) Create(ctx context.Context, task model.TaskModel) model.TaskModel {
+func (r *Repo) Update(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
 
-	err := r.Db.QueryRowContext(ctx, model.InsertTaskReturnIdQuery, task.TaskName, task.IsDone).Scan(&task.ID)
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return task, dbError(err, "update task")
+	}
+	defer tx.Rollback()
+
+	var before model.TaskModel
+	if record != nil {
+		if before, err = lockTask(ctx, tx, task.ID); err != nil {
+			return task, err
+		}
+	}
 
+	task, ids, err := updateTask(ctx, tx, task)
+	if err != nil {
+		return task, err
+	}
+
+	after := task
+	if err := RecordChanges(ctx, tx, record, model.Change{Before: &before, After: &after}); err != nil {
+		return task, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return task, dbError(err, "update task")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx), ids...)
+
+	return task, nil
+}
+
+// updateTask saves task in tx and returns it with its new version, together
+// with the ids of the tasks it changed.
+func updateTask(ctx context.Context, tx *sql.Tx, task model.TaskModel) (model.TaskModel, []int64, error) {
+
+	err := tx.QueryRowContext(ctx, model.UpdateTaskQuery, task.TaskName, task.IsDone, task.DueAt, task.Timezone, task.RemindAt, task.Priority, task.ListID, task.RRule, task.RepeatFrom, task.ID, task.Version, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)).Scan(&task.Version)
+
+	if err == sql.ErrNoRows {
+		return task, nil, missingOrChanged(ctx, tx, task.ID)
+	}
 	if err != nil {
 		fmt.Println(err)
+		return task, nil, dbError(err, "update task")
 	}
 
-	_ = r.Redis.Del(ctx, redisTaskGetAll)
+	if _, err := tx.ExecContext(ctx, model.DeleteTaskTagsQuery, task.ID); err != nil {
+		return task, nil, dbError(err, "update task tags")
+	}
+
+	if err := insertTags(ctx, tx, task.ID, task.Tags); err != nil {
+		return task, nil, err
+	}
+
+	// subtasks follow their parent to its list
+	subtaskIds, err := queryIds(ctx, tx, model.MoveSubtasksQuery, task.ListID, task.ID, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		return task, nil, dbError(err, "move subtasks")
+	}
 
-	return task
+	return task, append(subtaskIds, task.ID), nil
 }
 
-func (r *Repo) Update(ctx context.Context, task model.TaskModel) (bool, model.TaskModel) {
+// Move changes the list of a task and its subtasks and returns the moved
+// task.
+func (r *Repo) Move(ctx context.Context, id int64, listID *int64, record model.Record) (model.TaskModel, error) {
 
-	res, err := r.Db.ExecContext(ctx, model.UpdateTaskQuery, task.TaskName, task.IsDone, task.ID)
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.TaskModel{}, dbError(err, "move task")
+	}
+	defer tx.Rollback()
+
+	var before model.TaskModel
+	if record != nil {
+		if before, err = lockTask(ctx, tx, id); err != nil {
+			return model.TaskModel{}, err
+		}
+	}
+
+	ids, err := queryIds(ctx, tx, model.MoveTaskQuery, listID, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
 
 	if err != nil {
 		fmt.Println(err)
+		return model.TaskModel{}, dbError(err, "move task")
+	}
+	if len(ids) == 0 {
+		return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "task not found")
+	}
+
+	if err := recordUpdate(ctx, tx, record, before); err != nil {
+		return model.TaskModel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.TaskModel{}, dbError(err, "move task")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx), ids...)
+
+	return r.GetByID(ctx, id)
+}
+
+// Delete moves a task and its subtasks to the trash.
+func (r *Repo) Delete(ctx context.Context, task model.TaskModel, record model.Record) error {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(err, "delete task")
+	}
+	defer tx.Rollback()
+
+	var before model.TaskModel
+	if record != nil {
+		if before, err = lockTask(ctx, tx, task.ID); err != nil {
+			return err
+		}
+	}
+
+	_, ids, err := trashTask(ctx, tx, task.ID, task.Version)
+	if err != nil {
+		return err
+	}
+
+	if err := RecordChanges(ctx, tx, record, model.Change{Before: &before}); err != nil {
+		return err
 	}
 
-	result := false
-	affected, _ := res.RowsAffected()
-	if affected > 0 {
-		result = true
+	if err := tx.Commit(); err != nil {
+		return dbError(err, "delete task")
 	}
 
-	_ = r.Redis.Del(context.Background(), redisTaskGetAll)
+	r.invalidate(requestinfo.Detach(ctx), ids...)
 
-	return result, task
+	return nil
 }
 
-func (r *Repo) Delete(ctx context.Context, task model.TaskModel) bool {
+// LockListTasks locks the live tasks of a list in tx, where the list repo
+// deletes the list, and returns them by workspace as they are before.
+func LockListTasks(ctx context.Context, tx *sql.Tx, listID int64) (map[int64][]model.TaskModel, error) {
 
-	res, err := r.Db.ExecContext(ctx, model.DeleteTaskQuery, task.ID)
+	tasks := map[int64][]model.TaskModel{}
 
+	rows, err := tx.QueryContext(ctx, model.LockListTasksQuery, listID)
 	if err != nil {
+		fmt.Println("Error on Repo :", err)
+		return nil, dbError(err, "fetch list tasks")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var workspace int64
+		task, err := scanTask(rows, &workspace)
+		if err != nil {
+			return nil, dbError(err, "scan task")
+		}
+		tasks[workspace] = append(tasks[workspace], task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch list tasks")
+	}
+
+	return tasks, nil
+}
+
+// insertTask saves a new task of the user of ctx in its workspace and its
+// tags in tx.
+func insertTask(ctx context.Context, tx *sql.Tx, task model.TaskModel) (model.TaskModel, error) {
+
+	owner := requestinfo.OwnerID(ctx)
+
+	var last string
+	if err := tx.QueryRowContext(ctx, model.LastRankQuery, owner).Scan(&last); err != nil {
 		fmt.Println(err)
+		return task, dbError(err, "create task")
 	}
 
-	result := false
-	affected, _ := res.RowsAffected()
-	if affected > 0 {
-		result = true
+	// two tasks created at once can get the same key, the id orders them
+	key, err := rank.After(last)
+	if err != nil {
+		return task, fmt.Errorf("create task: %w", err)
 	}
+	task.Rank = key
+
+	err = tx.QueryRowContext(ctx, model.InsertTaskReturnIdQuery, task.TaskName, task.IsDone, task.DueAt, task.Timezone, task.RemindAt, task.Priority, task.ListID, task.ParentID, task.RRule, task.RepeatFrom, task.Rank, owner, requestinfo.WorkspaceID(ctx)).Scan(&task.ID, &task.Position, &task.Version)
 
-	_ = r.Redis.Del(ctx, redisTaskGetAll)
+	if err != nil {
+		fmt.Println(err)
+		return task, dbError(err, "create task")
+	}
+
+	if err := insertTags(ctx, tx, task.ID, task.Tags); err != nil {
+		return task, err
+	}
+
+	return task, nil
+}
+
+// missingOrChanged tells why a versioned write matched no row: either the
+// task does not exist or it moved past the expected version.
+func missingOrChanged(ctx context.Context, q rowQuerier, id int64) error {
+	var version int64
+	err := q.QueryRowContext(ctx, model.FetchTaskVersionQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)).Scan(&version)
+	if err == sql.ErrNoRows {
+		return apperror.New(apperror.ErrNotFound, "task not found")
+	}
+	if err != nil {
+		return dbError(err, "fetch task version")
+	}
+	return apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+}
+
+// insertTags creates the tags the user of ctx does not have yet and links
+// all of them to the task.
+func insertTags(ctx context.Context, tx *sql.Tx, id int64, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	owner := requestinfo.OwnerID(ctx)
+
+	if _, err := tx.ExecContext(ctx, model.InsertTagNamesQuery, pq.Array(tags), owner); err != nil {
+		return dbError(err, "create tags")
+	}
+
+	if _, err := tx.ExecContext(ctx, model.InsertTaskTagsQuery, id, pq.Array(tags), owner); err != nil {
+		return dbError(err, "tag task")
+	}
 
-	return result
+	return nil
 }
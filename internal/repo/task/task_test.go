@@ -3,18 +3,30 @@ package task
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"testing"
+	"time"
 	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
 
 	"github.com/alicebob/miniredis"
 	"github.com/go-redis/redis/v8"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
 var (
 	rclient *redis.Client
+
+	taskColumns = []string{"id", "task_name", "is_done", "due_at", "timezone", "remind_at", "priority", "list_id", "parent_id", "position", "version", "tags", "rrule", "repeat_from", "rank"}
+
+	listedColumns = append(taskColumns, "blocked")
+
+	// every request below is made by user testOwner inside workspace testWorkspace
+	testOwner     = int64(1)
+	testWorkspace = int64(5)
+	userCtx       = requestinfo.WithWorkspaceID(requestinfo.WithUserID(context.Background(), testOwner), testWorkspace)
 )
 
 func mockDBAndRedis(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *redis.Client) {
@@ -43,71 +55,171 @@ func TestRepo_GetAll(t *testing.T) {
 
 	db, mock, rclient := mockDBAndRedis(t)
 
-	ctx := context.Background()
+	ctx := userCtx
 
 	type arg struct {
 		ctx_arg context.Context
+		filter  model.TaskFilter
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "task_name", "is_done"}).
-		AddRow(1, "task 1", true).
-		AddRow(2, "task 2", false)
-
 	tests := []struct {
 		name    string
 		arg     arg
-		want    []model.TaskModel
+		mock    func()
+		want    model.TaskPage
 		wantErr error
 	}{
 		{
-			name: "case 1 -> get all data",
-			arg:  arg{ctx_arg: ctx},
-			want: []model.TaskModel{
-				{
-					ID:       1,
-					TaskName: "task 1",
-					IsDone:   true,
+			name: "case 1 -> get first page with next cursor",
+			arg:  arg{ctx_arg: ctx, filter: model.TaskFilter{Limit: 2}},
+			mock: func() {
+				rows := sqlmock.NewRows(listedColumns).
+					AddRow(1, "task 1", true, nil, "", nil, 0, nil, nil, 0, 1, nil, "", "", "", false).
+					AddRow(2, "task 2", false, nil, "", nil, 0, nil, nil, 0, 1, nil, "", "", "", true).
+					AddRow(3, "task 3", false, nil, "", nil, 0, nil, nil, 0, 1, nil, "", "", "", false)
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND owner_id = \$1 AND workspace_id = \$2 ORDER BY id LIMIT \$3`).WithArgs(testOwner, testWorkspace, 3).WillReturnRows(rows)
+			},
+			want: model.TaskPage{
+				Tasks: []model.TaskModel{
+					{
+						ID:       1,
+						Version:  1,
+						TaskName: "task 1",
+						IsDone:   true,
+					},
+					{
+						ID:       2,
+						Version:  1,
+						TaskName: "task 2",
+						IsDone:   false,
+						Blocked:  true,
+					},
 				},
-				{
-					ID:       2,
-					TaskName: "task 2",
-					IsDone:   false,
+				NextCursor: encodeCursor(orderOf(model.TaskFilter{}), model.TaskModel{ID: 2}),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> get last page from cursor",
+			arg:  arg{ctx_arg: ctx, filter: model.TaskFilter{Limit: 2, Cursor: encodeCursor(orderOf(model.TaskFilter{}), model.TaskModel{ID: 2})}},
+			mock: func() {
+				rows := sqlmock.NewRows(listedColumns).
+					AddRow(3, "task 3", false, nil, "", nil, 0, nil, nil, 0, 1, nil, "", "", "", false)
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND owner_id = \$1 AND workspace_id = \$2 AND \(\(id > \$3\)\) ORDER BY id LIMIT \$4`).WithArgs(testOwner, testWorkspace, 2, 3).WillReturnRows(rows)
+			},
+			want: model.TaskPage{
+				Tasks: []model.TaskModel{
+					{
+						ID:       3,
+						Version:  1,
+						TaskName: "task 3",
+						IsDone:   false,
+					},
 				},
 			},
 			wantErr: nil,
 		},
+		{
+			name: "case 3 -> first page served from cache",
+			arg:  arg{ctx_arg: ctx, filter: model.TaskFilter{Limit: 2}},
+			mock: func() {},
+			want: model.TaskPage{
+				Tasks: []model.TaskModel{
+					{
+						ID:       1,
+						Version:  1,
+						TaskName: "task 1",
+						IsDone:   true,
+					},
+					{
+						ID:       2,
+						Version:  1,
+						TaskName: "task 2",
+						IsDone:   false,
+						Blocked:  true,
+					},
+				},
+				NextCursor: encodeCursor(orderOf(model.TaskFilter{}), model.TaskModel{ID: 2}),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "case 4 -> filtered and sorted page",
+			arg: arg{ctx_arg: ctx, filter: model.TaskFilter{
+				IsDone: new(bool),
+				Query:  "50%",
+				Sort:   []model.SortField{{Field: "task_name", Desc: true}},
+				Limit:  1,
+			}},
+			mock: func() {
+				rows := sqlmock.NewRows(listedColumns).
+					AddRow(5, "task 50%", false, nil, "", nil, 0, nil, nil, 0, 1, nil, "", "", "", false).
+					AddRow(6, "task 50% b", false, nil, "", nil, 0, nil, nil, 0, 1, nil, "", "", "", false)
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND owner_id = \$1 AND workspace_id = \$2 AND is_done = \$3 AND task_name ILIKE \$4 ESCAPE '\\' ORDER BY task_name DESC, id LIMIT \$5`).
+					WithArgs(testOwner, testWorkspace, false, `%50\%%`, 2).WillReturnRows(rows)
+			},
+			want: model.TaskPage{
+				Tasks: []model.TaskModel{
+					{
+						ID:       5,
+						Version:  1,
+						TaskName: "task 50%",
+						IsDone:   false,
+					},
+				},
+				NextCursor: encodeCursor([]model.SortField{{Field: "task_name", Desc: true}, {Field: "id"}}, model.TaskModel{ID: 5, TaskName: "task 50%"}),
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "case 5 -> invalid cursor",
+			arg:     arg{ctx_arg: ctx, filter: model.TaskFilter{Limit: 2, Cursor: "not a cursor"}},
+			mock:    func() {},
+			want:    model.TaskPage{Tasks: []model.TaskModel{}},
+			wantErr: apperror.ErrValidation,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
 			repo := NewTaskRepository(db, rclient)
-			mock.ExpectQuery(`SELECT id, task_name, is_done FROM tasks`).WillReturnRows(rows)
-			result, err := repo.GetAll(tt.arg.ctx_arg)
+			result, err := repo.GetAll(tt.arg.ctx_arg, tt.arg.filter)
 
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
 			assert.Equal(t, tt.want, result)
-			assert.Equal(t, tt.wantErr, err)
-
-			str, err := rclient.Get(rclient.Context(), "tasks").Result()
-			var tasks []model.TaskModel
-			assert.NoError(t, err, "error redis")
-
-			err = json.Unmarshal([]byte(str), &tasks)
-			assert.NoError(t, err, "error redis")
-			assert.Equal(t, tt.want, tasks)
-
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 
+	t.Run("case 6 -> create invalidates cached pages", func(t *testing.T) {
+		repo := NewTaskRepository(db, rclient)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), ''\) FROM tasks`).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+		mock.ExpectQuery(`INSERT INTO tasks (.*) RETURNING id, position, version`).WillReturnRows(sqlmock.NewRows([]string{"id", "position", "version"}).AddRow(4, 0, 1))
+		mock.ExpectCommit()
+		_, err := repo.Create(ctx, model.TaskModel{TaskName: "task 4"}, nil)
+		assert.NoError(t, err)
+
+		rows := sqlmock.NewRows(listedColumns).
+			AddRow(1, "task 1", true, nil, "", nil, 0, nil, nil, 0, 1, nil, "", "", "", false)
+		mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND owner_id = \$1 AND workspace_id = \$2 ORDER BY id LIMIT \$3`).WithArgs(testOwner, testWorkspace, 3).WillReturnRows(rows)
+		_, err = repo.GetAll(ctx, model.TaskFilter{Limit: 2})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 }
 
 func TestRepo_Create(t *testing.T) {
 
 	db, mock, rclient := mockDBAndRedis(t)
 
-	rows := sqlmock.NewRows([]string{"id"}).
-		AddRow(1)
-
-	ctx := context.Background()
+	ctx := userCtx
 
 	type args struct {
 		ctx     context.Context
@@ -130,28 +242,117 @@ func TestRepo_Create(t *testing.T) {
 			},
 			want: model.TaskModel{
 				ID:       1,
+				Version:  1,
 				TaskName: "task 1",
+				Rank:     "W",
 				IsDone:   true,
 			},
 		},
+		{
+			name: "case 2 -> create task data with priority and tags",
+			args: args{
+				ctx: ctx,
+				request: model.TaskModel{
+					TaskName: "task 1",
+					Priority: model.PriorityHigh,
+					Tags:     []string{"home", "work"},
+				},
+			},
+			want: model.TaskModel{
+				ID:       1,
+				Version:  1,
+				TaskName: "task 1",
+				Rank:     "W",
+				Priority: model.PriorityHigh,
+				Tags:     []string{"home", "work"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			rows := sqlmock.NewRows([]string{"id", "position", "version"}).
+				AddRow(1, 0, 1)
 			repo := NewTaskRepository(db, rclient)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), ''\) FROM tasks`).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
 			mock.ExpectQuery(`INSERT INTO tasks (.*) RETURNING id`).WillReturnRows(rows)
-			result := repo.Create(tt.args.ctx, tt.args.request)
+			if len(tt.args.request.Tags) > 0 {
+				mock.ExpectExec(`INSERT INTO tags (.*) ON CONFLICT (.*) DO NOTHING`).WithArgs(pq.Array(tt.args.request.Tags), testOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO task_tags (.*)`).WithArgs(1, pq.Array(tt.args.request.Tags), testOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+			result, err := repo.Create(tt.args.ctx, tt.args.request, nil)
+			assert.NoError(t, err)
 			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 
 }
 
+func TestRepo_CreateRecordsEvents(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	record := func(changes []model.Change) []model.TaskEvent {
+		events := make([]model.TaskEvent, len(changes))
+		for i, change := range changes {
+			events[i] = model.TaskEvent{TaskID: change.After.ID, Action: model.EventCreate, After: change.After, Actor: "alice", RequestID: "req-1"}
+		}
+		return events
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "case 1 -> event recorded with the task",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), ''\) FROM tasks`).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+				mock.ExpectQuery(`INSERT INTO tasks (.*) RETURNING id`).WillReturnRows(sqlmock.NewRows([]string{"id", "position", "version"}).AddRow(1, 0, 1))
+				mock.ExpectExec(`INSERT INTO task_events (.*)`).
+					WithArgs(1, "create", nil, sqlmock.AnyArg(), "alice", "req-1", nil, testOwner, testWorkspace, testOwner).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "case 2 -> task not created when its event fails",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT COALESCE\(MAX\(rank\), ''\) FROM tasks`).WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V"))
+				mock.ExpectQuery(`INSERT INTO tasks (.*) RETURNING id`).WillReturnRows(sqlmock.NewRows([]string{"id", "position", "version"}).AddRow(1, 0, 1))
+				mock.ExpectExec(`INSERT INTO task_events (.*)`).WillReturnError(&pq.Error{Code: "08006"})
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			_, err := repo.Create(userCtx, model.TaskModel{TaskName: "task 1"}, record)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestRepo_Update(t *testing.T) {
 
 	db, mock, rclient := mockDBAndRedis(t)
 
-	ctx := context.Background()
+	ctx := userCtx
 
 	type args struct {
 		ctx     context.Context
@@ -159,11 +360,11 @@ func TestRepo_Update(t *testing.T) {
 	}
 
 	tests := []struct {
-		name       string
-		args       args
-		mock       func()
-		want       model.TaskModel
-		wantStatus bool
+		name    string
+		args    args
+		mock    func()
+		want    model.TaskModel
+		wantErr error
 	}{
 		{
 			name: "case 1 -> success update task data",
@@ -173,17 +374,23 @@ func TestRepo_Update(t *testing.T) {
 					ID:       1,
 					TaskName: "task 2",
 					IsDone:   true,
+					Version:  3,
 				},
 			},
 			mock: func() {
-				mock.ExpectExec(`UPDATE tasks SET task_name=(.*), is_done=(.*) WHERE id=(.*)`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET task_name=(.*), is_done=(.*) WHERE id=(.*) RETURNING version`).WithArgs("task 2", true, nil, "", nil, 0, nil, "", "", 1, 3, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+				mock.ExpectExec(`DELETE FROM task_tags WHERE task_id=(.*)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectQuery(`UPDATE tasks SET list_id=(.*) WHERE parent_id=(.*) RETURNING id`).WithArgs(nil, 1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectCommit()
 			},
 			want: model.TaskModel{
 				ID:       1,
 				TaskName: "task 2",
 				IsDone:   true,
+				Version:  4,
 			},
-			wantStatus: true,
+			wantErr: nil,
 		},
 		{
 			name: "case 2 -> no affected update task data",
@@ -196,14 +403,62 @@ func TestRepo_Update(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec(`UPDATE tasks SET task_name=(.*), is_done=(.*) WHERE id=(.*)`).WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET task_name=(.*), is_done=(.*) WHERE id=(.*) RETURNING version`).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(`SELECT version FROM tasks WHERE id=(.*)`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectRollback()
 			},
 			want: model.TaskModel{
 				ID:       1,
 				TaskName: "task 2",
 				IsDone:   true,
 			},
-			wantStatus: false,
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name: "case 3 -> database unavailable on update task data",
+			args: args{
+				ctx: ctx,
+				request: model.TaskModel{
+					ID:       1,
+					TaskName: "task 2",
+					IsDone:   true,
+				},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET task_name=(.*), is_done=(.*) WHERE id=(.*) RETURNING version`).WillReturnError(&pq.Error{Code: "08006"})
+				mock.ExpectRollback()
+			},
+			want: model.TaskModel{
+				ID:       1,
+				TaskName: "task 2",
+				IsDone:   true,
+			},
+			wantErr: apperror.ErrUnavailable,
+		},
+		{
+			name: "case 4 -> task changed since the expected version",
+			args: args{
+				ctx: ctx,
+				request: model.TaskModel{
+					ID:       1,
+					TaskName: "task 2",
+					Version:  3,
+				},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET task_name=(.*), is_done=(.*) WHERE id=(.*) RETURNING version`).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectQuery(`SELECT version FROM tasks WHERE id=(.*)`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+				mock.ExpectRollback()
+			},
+			want: model.TaskModel{
+				ID:       1,
+				TaskName: "task 2",
+				Version:  3,
+			},
+			wantErr: apperror.ErrPreconditionFailed,
 		},
 	}
 
@@ -211,12 +466,16 @@ func TestRepo_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 			repo := NewTaskRepository(db, rclient)
-			status, result := repo.Update(tt.args.ctx, tt.args.request)
+			result, err := repo.Update(tt.args.ctx, tt.args.request, nil)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
 
-			_, err := rclient.Get(rclient.Context(), "tasks").Result()
+			_, err = rclient.Get(rclient.Context(), "ws:5:task:1:v0:1").Result()
 			assert.Equal(t, redis.Nil, err)
-			assert.Equal(t, tt.want, result)
-			assert.Equal(t, tt.wantStatus, status)
 		})
 	}
 
@@ -226,7 +485,7 @@ func TestRepo_Delete(t *testing.T) {
 
 	db, mock, rclient := mockDBAndRedis(t)
 
-	ctx := context.Background()
+	ctx := userCtx
 
 	type args struct {
 		ctx     context.Context
@@ -237,10 +496,10 @@ func TestRepo_Delete(t *testing.T) {
 		name string
 		args args
 		mock func()
-		want bool
+		want error
 	}{
 		{
-			name: "case 1 -> success delete task data",
+			name: "case 1 -> success move task and its subtasks to the trash",
 			args: args{
 				ctx: ctx,
 				request: model.TaskModel{
@@ -251,12 +510,15 @@ func TestRepo_Delete(t *testing.T) {
 			},
 
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM tasks WHERE id=(.*)`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) WHERE id=(.*) RETURNING parent_id`).WithArgs(1, 0, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(nil))
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) WHERE parent_id=(.*) RETURNING id`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectCommit()
 			},
-			want: true,
+			want: nil,
 		},
 		{
-			name: "case 2 -> no affected delete task data",
+			name: "case 2 -> task not found or already in the trash",
 			args: args{
 				ctx: ctx,
 				request: model.TaskModel{
@@ -266,22 +528,436 @@ func TestRepo_Delete(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec(`DELETE FROM tasks WHERE id=(.*)`).WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) WHERE id=(.*) RETURNING parent_id`).WithArgs(1, 0, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"parent_id"}))
+				mock.ExpectQuery(`SELECT version FROM tasks WHERE id=(.*) AND deleted_at IS NULL`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+				mock.ExpectRollback()
+			},
+			want: apperror.ErrNotFound,
+		},
+		{
+			name: "case 3 -> database unavailable on delete task data",
+			args: args{
+				ctx: ctx,
+				request: model.TaskModel{
+					ID: 1,
+				},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) WHERE id=(.*) RETURNING parent_id`).WillReturnError(&pq.Error{Code: "08006"})
+				mock.ExpectRollback()
+			},
+			want: apperror.ErrUnavailable,
+		},
+		{
+			name: "case 4 -> task changed since the expected version",
+			args: args{
+				ctx: ctx,
+				request: model.TaskModel{
+					ID:      1,
+					Version: 2,
+				},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) WHERE id=(.*) AND (.*) RETURNING parent_id`).WithArgs(1, 2, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"parent_id"}))
+				mock.ExpectQuery(`SELECT version FROM tasks WHERE id=(.*)`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+				mock.ExpectRollback()
 			},
-			want: false,
+			want: apperror.ErrPreconditionFailed,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			rclient.Set(ctx, "ws:5:task:1:v0:4", "{}", 0)
 			tt.mock()
 			repo := NewTaskRepository(db, rclient)
-			result := repo.Delete(tt.args.ctx, tt.args.request)
-
-			_, err := rclient.Get(rclient.Context(), "tasks").Result()
+			err := repo.Delete(tt.args.ctx, tt.args.request, nil)
+			if tt.want == nil {
+				assert.NoError(t, err)
+
+				// the subtasks leave the cache with their parent
+				_, err = rclient.Get(ctx, "ws:5:task:1:v0:4").Result()
+				assert.Equal(t, redis.Nil, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+
+			_, err = rclient.Get(rclient.Context(), "ws:5:task:1:v0:1").Result()
 			assert.Equal(t, redis.Nil, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+}
+
+func TestRepo_GetByID(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+	dueAt := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+	remindAt := dueAt.Add(-time.Hour)
+	listID := int64(2)
+
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		mock    func()
+		want    model.TaskModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> get task data from database",
+			args: args{ctx: ctx, id: 1},
+			mock: func() {
+				rows := sqlmock.NewRows(taskColumns).
+					AddRow(1, "task 1", true, nil, "", nil, 0, nil, nil, 0, 1, nil, "", "", "")
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*)`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(rows)
+			},
+			want: model.TaskModel{
+				ID:       1,
+				Version:  1,
+				TaskName: "task 1",
+				IsDone:   true,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> get task data from cache",
+			args: args{ctx: ctx, id: 1},
+			mock: func() {},
+			want: model.TaskModel{
+				ID:       1,
+				Version:  1,
+				TaskName: "task 1",
+				IsDone:   true,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "case 3 -> get task with deadline and reminder",
+			args: args{ctx: ctx, id: 3},
+			mock: func() {
+				rows := sqlmock.NewRows(taskColumns).
+					AddRow(3, "task 3", false, dueAt, "Asia/Jakarta", remindAt, 3, 2, nil, 0, 1, "{home,work}", "", "", "")
+				mock.ExpectQuery(`SELECT (.*) FROM tasks WHERE id=(.*)`).WithArgs(3, testOwner, testWorkspace).WillReturnRows(rows)
+			},
+			want: model.TaskModel{
+				ID:       3,
+				Version:  1,
+				TaskName: "task 3",
+				DueAt:    &dueAt,
+				Timezone: "Asia/Jakarta",
+				RemindAt: &remindAt,
+				Priority: model.PriorityHigh,
+				Tags:     []string{"home", "work"},
+				ListID:   &listID,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "case 4 -> task not found",
+			args: args{ctx: ctx, id: 2},
+			mock: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*)`).WithArgs(2, testOwner, testWorkspace).WillReturnError(sql.ErrNoRows)
+			},
+			want: model.TaskModel{
+				ID: 0,
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			result, err := repo.GetByID(tt.args.ctx, tt.args.id)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
 			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 
+	t.Run("case 5 -> update invalidates the cached task", func(t *testing.T) {
+		repo := NewTaskRepository(db, rclient)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE tasks SET task_name=(.*), is_done=(.*) WHERE id=(.*) RETURNING version`).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+		mock.ExpectExec(`DELETE FROM task_tags WHERE task_id=(.*)`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`UPDATE tasks SET list_id=(.*) WHERE parent_id=(.*) RETURNING id`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+		_, err := repo.Update(ctx, model.TaskModel{ID: 1, TaskName: "task 1 updated"}, nil)
+		assert.NoError(t, err)
+
+		_, err = rclient.Get(rclient.Context(), "ws:5:task:1:v0:1").Result()
+		assert.Equal(t, redis.Nil, err)
+	})
+
+}
+
+func TestRepo_GetByIDRacingAWrite(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+	repo := NewTaskRepository(db, rclient)
+
+	// a read takes the list version, an update commits and invalidates,
+	// then the read caches the copy it found before the update
+	version := repo.listVersion(ctx)
+	repo.invalidate(ctx, 1)
+	repo.cache(ctx, taskKey(ctx, version, 1), model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}, redisTaskCacheTTL)
+
+	rows := sqlmock.NewRows(taskColumns).
+		AddRow(1, "task 1 updated", false, nil, "", nil, 0, nil, nil, 0, 2, nil, "", "", "")
+	mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*)`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(rows)
+
+	task, err := repo.GetByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), task.Version)
+
+	// the stale copy is never read again and expires on its own
+	ttl, err := rclient.TTL(ctx, taskKey(ctx, version, 1)).Result()
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_Move(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+	listID := int64(2)
+
+	tests := []struct {
+		name    string
+		listID  *int64
+		mock    func()
+		want    model.TaskModel
+		wantErr error
+	}{
+		{
+			name:   "case 1 -> move task to another list",
+			listID: &listID,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET list_id=(.*) WHERE \(id=(.*) OR parent_id=(.*)\) AND owner_id=(.*) AND deleted_at IS NULL RETURNING id`).WithArgs(&listID, 1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(4))
+				mock.ExpectCommit()
+				rows := sqlmock.NewRows(taskColumns).
+					AddRow(1, "task 1", false, nil, "UTC", nil, 0, 2, nil, 0, 1, nil, "", "", "")
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*)`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(rows)
+			},
+			want:    model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", ListID: &listID, Version: 1},
+			wantErr: nil,
+		},
+		{
+			name:   "case 2 -> list does not exist",
+			listID: &listID,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET list_id=(.*) WHERE \(id=(.*) OR parent_id=(.*)\) AND owner_id=(.*) AND deleted_at IS NULL RETURNING id`).WithArgs(&listID, 1, testOwner, testWorkspace).WillReturnError(&pq.Error{Code: "23503", Constraint: "tasks_list_id_fkey"})
+				mock.ExpectRollback()
+			},
+			want:    model.TaskModel{},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:   "case 3 -> task not found",
+			listID: nil,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE tasks SET list_id=(.*) WHERE \(id=(.*) OR parent_id=(.*)\) AND owner_id=(.*) AND deleted_at IS NULL RETURNING id`).WithArgs(nil, 1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			want:    model.TaskModel{},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			result, err := repo.Move(ctx, 1, tt.listID, nil)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_GetSubtasks(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	parentID := int64(1)
+	rows := sqlmock.NewRows(taskColumns).
+		AddRow(2, "step 1", true, nil, "UTC", nil, 0, nil, 1, 0, 1, nil, "", "", "").
+		AddRow(3, "step 2", false, nil, "UTC", nil, 0, nil, 1, 1, 1, nil, "", "", "")
+	mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE parent_id=(.*) ORDER BY position, id`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(rows)
+
+	repo := NewTaskRepository(db, rclient)
+	result, err := repo.GetSubtasks(userCtx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TaskModel{
+		{ID: 2, TaskName: "step 1", IsDone: true, Timezone: "UTC", ParentID: &parentID, Position: 0, Version: 1},
+		{ID: 3, TaskName: "step 2", Timezone: "UTC", ParentID: &parentID, Position: 1, Version: 1},
+	}, result)
+}
+
+func TestRepo_Reorder(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+
+	tests := []struct {
+		name    string
+		ids     []int64
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "case 1 -> reorder every subtask",
+			ids:  []int64{3, 2},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM tasks WHERE parent_id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3))
+				mock.ExpectExec(`UPDATE tasks SET position = array_position\((.*), id\) - 1, version = version \+ 1 WHERE parent_id=(.*)`).WithArgs(1, pq.Array([]int64{3, 2}), testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> a subtask is missing",
+			ids:  []int64{3},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM tasks WHERE parent_id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3))
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name: "case 3 -> a subtask is listed twice",
+			ids:  []int64{3, 3, 2},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT id FROM tasks WHERE parent_id=(.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3))
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			err := repo.Reorder(ctx, 1, tt.ids, nil)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_SetDone(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+
+	rclient.Set(ctx, "ws:5:task:1:v0:1", "{}", 0)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tasks SET is_done=(.*) WHERE id=(.*)`).WithArgs(true, 1, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tasks SET is_done=(.*) WHERE id=(.*)`).WithArgs(true, 2, testOwner, testWorkspace).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	repo := NewTaskRepository(db, rclient)
+
+	assert.NoError(t, repo.SetDone(ctx, 1, true, nil))
+	_, err := rclient.Get(ctx, "ws:5:task:1:v0:1").Result()
+	assert.Equal(t, redis.Nil, err)
+
+	assert.ErrorIs(t, repo.SetDone(ctx, 2, true, nil), apperror.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_GetByIDOfAnotherUser(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	// user 2 has the task cached, user 1 only finds what the database lets
+	// them see
+	rclient.Set(userCtx, "ws:5:task:2:v0:1", `{"id":1,"task_name":"task 1"}`, 0)
+	mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) AND owner_id=(.*)`).WithArgs(1, testOwner, testWorkspace).WillReturnError(sql.ErrNoRows)
+
+	repo := NewTaskRepository(db, rclient)
+	_, err := repo.GetByID(userCtx, 1)
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_AnotherWorkspace(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	// the same user caches task 1 in workspace testWorkspace, then works
+	// in workspace 6 where the database knows no such task
+	other := requestinfo.WithWorkspaceID(userCtx, 6)
+	rclient.Set(userCtx, "ws:5:task:1:v0:1", `{"id":1,"task_name":"task 1"}`, 0)
+
+	mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) AND owner_id=(.*) AND workspace_id=(.*)`).WithArgs(1, testOwner, 6).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND owner_id = \$1 AND workspace_id = \$2 ORDER BY id LIMIT \$3`).WithArgs(testOwner, 6, 3).WillReturnRows(sqlmock.NewRows(listedColumns))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tasks SET is_done=(.*) WHERE id=(.*)`).WithArgs(true, 1, testOwner, 6).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE tasks SET deleted_at = now\(\)(.*) WHERE id=(.*) RETURNING parent_id`).WithArgs(1, 0, testOwner, 6).WillReturnRows(sqlmock.NewRows([]string{"parent_id"}))
+	mock.ExpectQuery(`SELECT version FROM tasks WHERE id=(.*) AND deleted_at IS NULL`).WithArgs(1, testOwner, 6).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+	mock.ExpectRollback()
+
+	repo := NewTaskRepository(db, rclient)
+
+	_, err := repo.GetByID(other, 1)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	page, err := repo.GetAll(other, model.TaskFilter{Limit: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Tasks)
+
+	assert.ErrorIs(t, repo.SetDone(other, 1, true, nil), apperror.ErrNotFound)
+
+	assert.ErrorIs(t, repo.Delete(other, model.TaskModel{ID: 1}, nil), apperror.ErrNotFound)
+
+	// the copy of the first workspace is still there, untouched
+	cached, err := rclient.Get(userCtx, "ws:5:task:1:v0:1").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"task_name":"task 1"}`, cached)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,148 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// GetTrash returns the trashed tasks, most recently trashed first. The trash
+// is not cached.
+func (r *Repo) GetTrash(ctx context.Context) ([]model.TaskModel, error) {
+
+	var Tasks = []model.TaskModel{}
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchTrashQuery, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+
+	if err != nil {
+		fmt.Println("Error on Repo :", err)
+		return nil, dbError(err, "fetch trash")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var deletedAt sql.NullTime
+		task_row, err := scanTask(rows, &deletedAt)
+		if err != nil {
+			return nil, dbError(err, "scan task")
+		}
+		task_row.DeletedAt = timePtr(deletedAt)
+		Tasks = append(Tasks, task_row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch trash")
+	}
+
+	return Tasks, nil
+}
+
+// Restore takes a task out of the trash together with the subtasks that
+// were trashed with it and returns the restored task. A subtask cannot come
+// back while its parent is in the trash.
+func (r *Repo) Restore(ctx context.Context, id int64, record model.Record) (model.TaskModel, error) {
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.TaskModel{}, dbError(err, "restore task")
+	}
+	defer tx.Rollback()
+
+	ids, err := restoreTask(ctx, tx, id)
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+
+	if record != nil {
+		restored, err := lockTask(ctx, tx, id)
+		if err != nil {
+			return model.TaskModel{}, err
+		}
+		if err := RecordChanges(ctx, tx, record, model.Change{After: &restored}); err != nil {
+			return model.TaskModel{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.TaskModel{}, dbError(err, "restore task")
+	}
+
+	r.invalidate(requestinfo.Detach(ctx), ids...)
+
+	return r.GetByID(ctx, id)
+}
+
+// Purge removes the tasks of every user trashed before the given time for
+// good and returns how many it removed.
+func (r *Repo) Purge(ctx context.Context, before time.Time) (int64, error) {
+
+	res, err := r.Db.ExecContext(ctx, model.PurgeTrashQuery, before)
+
+	if err != nil {
+		fmt.Println(err)
+		return 0, dbError(err, "purge trash")
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, dbError(err, "purge trash")
+	}
+
+	return purged, nil
+}
+
+// restoreTask takes a trashed task out of the trash in tx, with the subtasks
+// trashed together with it, and returns their ids.
+func restoreTask(ctx context.Context, tx *sql.Tx, id int64) ([]int64, error) {
+
+	var (
+		parentID      sql.NullInt64
+		deletedAt     time.Time
+		parentInTrash bool
+	)
+	err := tx.QueryRowContext(ctx, model.LockTrashedTaskQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)).Scan(&parentID, &deletedAt, &parentInTrash)
+	if err == sql.ErrNoRows {
+		return nil, apperror.New(apperror.ErrNotFound, "task not found in trash")
+	}
+	if err != nil {
+		return nil, dbError(err, "restore task")
+	}
+	if parentInTrash {
+		return nil, apperror.New(apperror.ErrConflict, "the parent task is in the trash, restore it first")
+	}
+
+	ids, err := queryIds(ctx, tx, model.RestoreTaskQuery, id, deletedAt, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		fmt.Println(err)
+		return nil, dbError(err, "restore task")
+	}
+
+	return ids, nil
+}
+
+// trashTask moves a task and its subtasks to the trash in tx and returns the
+// parent of the task with the ids of every trashed task.
+func trashTask(ctx context.Context, tx *sql.Tx, id, version int64) (*int64, []int64, error) {
+
+	var parentID sql.NullInt64
+	err := tx.QueryRowContext(ctx, model.TrashTaskQuery, id, version, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx)).Scan(&parentID)
+	if err == sql.ErrNoRows {
+		return nil, nil, missingOrChanged(ctx, tx, id)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return nil, nil, dbError(err, "delete task")
+	}
+
+	ids, err := queryIds(ctx, tx, model.TrashSubtasksQuery, id, requestinfo.OwnerID(ctx), requestinfo.WorkspaceID(ctx))
+	if err != nil {
+		return nil, nil, dbError(err, "delete subtasks")
+	}
+
+	return int64Ptr(parentID), append(ids, id), nil
+}
@@ -0,0 +1,149 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestRepo_GetTrash(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	deletedAt := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+	parentID := int64(1)
+	rows := sqlmock.NewRows(append(taskColumns, "deleted_at")).
+		AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 2, nil, "", "", "", deletedAt).
+		AddRow(2, "step 1", true, nil, "UTC", nil, 0, nil, 1, 0, 2, nil, "", "", "", deletedAt)
+	mock.ExpectQuery(`SELECT (.+), deleted_at FROM tasks WHERE owner_id=(.*) AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id`).WithArgs(testOwner, testWorkspace).WillReturnRows(rows)
+
+	repo := NewTaskRepository(db, rclient)
+	result, err := repo.GetTrash(userCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TaskModel{
+		{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 2, DeletedAt: &deletedAt},
+		{ID: 2, TaskName: "step 1", IsDone: true, Timezone: "UTC", ParentID: &parentID, Version: 2, DeletedAt: &deletedAt},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_Restore(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	ctx := userCtx
+	deletedAt := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.TaskModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> restore task with the subtasks trashed with it",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT parent_id, deleted_at, (.*) FROM tasks WHERE id=(.*) AND deleted_at IS NOT NULL FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id", "deleted_at", "exists"}).AddRow(nil, deletedAt, false))
+				mock.ExpectQuery(`UPDATE tasks SET deleted_at = NULL(.*) WHERE \(id=(.*) OR parent_id=(.*)\) AND owner_id=(.*) AND deleted_at=(.*) RETURNING id`).WithArgs(1, deletedAt, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(4))
+				mock.ExpectCommit()
+				mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE id=(.*) AND deleted_at IS NULL`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows(taskColumns).AddRow(1, "task 1", false, nil, "UTC", nil, 0, nil, nil, 0, 3, nil, "", "", ""))
+			},
+			want: model.TaskModel{ID: 1, TaskName: "task 1", Timezone: "UTC", Version: 3},
+		},
+		{
+			name: "case 2 -> task not in the trash",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT parent_id, deleted_at, (.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id", "deleted_at", "exists"}))
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name: "case 3 -> parent still in the trash",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT parent_id, deleted_at, (.*) FOR UPDATE`).WithArgs(1, testOwner, testWorkspace).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id", "deleted_at", "exists"}).AddRow(3, deletedAt, true))
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rclient.Set(ctx, "ws:5:task:1:v0:4", "{}", 0)
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			result, err := repo.Restore(ctx, 1, nil)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+
+				_, err = rclient.Get(ctx, "ws:5:task:1:v0:4").Result()
+				assert.Equal(t, redis.Nil, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_Purge(t *testing.T) {
+
+	db, mock, rclient := mockDBAndRedis(t)
+
+	before := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "case 1 -> purge old trash",
+			mock: func() {
+				mock.ExpectExec(`DELETE FROM tasks WHERE deleted_at < (.*)`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			want: 3,
+		},
+		{
+			name: "case 2 -> database unavailable",
+			mock: func() {
+				mock.ExpectExec(`DELETE FROM tasks WHERE deleted_at < (.*)`).WithArgs(before).WillReturnError(&pq.Error{Code: "08006"})
+			},
+			wantErr: apperror.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewTaskRepository(db, rclient)
+			purged, err := repo.Purge(context.Background(), before)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, purged)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
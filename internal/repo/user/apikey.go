@@ -0,0 +1,111 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// CreateAPIKey stores a key of the signed in user, limited to the workspace
+// of the request. Only its hash is kept.
+func (r *Repo) CreateAPIKey(ctx context.Context, key model.APIKeyModel) (model.APIKeyModel, error) {
+
+	if workspace := requestinfo.WorkspaceID(ctx); workspace != 0 {
+		key.WorkspaceID = &workspace
+	}
+
+	err := r.Db.QueryRowContext(ctx, model.InsertAPIKeyReturnIdQuery, key.Name, key.Prefix, key.Hash, key.ReadOnly, key.WorkspaceID, requestinfo.UserID(ctx)).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		fmt.Println(err)
+		return key, keyError(err, "create api key")
+	}
+
+	return key, nil
+}
+
+func (r *Repo) GetAPIKeys(ctx context.Context) ([]model.APIKeyModel, error) {
+
+	keys := []model.APIKeyModel{}
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchAPIKeysQuery, requestinfo.UserID(ctx))
+	if err != nil {
+		fmt.Println(err)
+		return nil, keyError(err, "fetch api keys")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key         model.APIKeyModel
+			workspaceID sql.NullInt64
+			lastUsedAt  sql.NullTime
+		)
+		if err := rows.Scan(&key.ID, &key.Name, &key.Prefix, &key.ReadOnly, &workspaceID, &key.CreatedAt, &lastUsedAt); err != nil {
+			return nil, keyError(err, "scan api key")
+		}
+		if workspaceID.Valid {
+			key.WorkspaceID = &workspaceID.Int64
+		}
+		if lastUsedAt.Valid {
+			key.LastUsedAt = &lastUsedAt.Time
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, keyError(err, "fetch api keys")
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey deletes a key of the signed in user, requests made with it
+// fail from then on.
+func (r *Repo) RevokeAPIKey(ctx context.Context, id int64) error {
+
+	res, err := r.Db.ExecContext(ctx, model.DeleteAPIKeyQuery, id, requestinfo.UserID(ctx))
+	if err != nil {
+		fmt.Println(err)
+		return keyError(err, "revoke api key")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return keyError(err, "revoke api key")
+	}
+	if affected == 0 {
+		return apperror.New(apperror.ErrNotFound, "api key not found")
+	}
+
+	return nil
+}
+
+// UseAPIKey returns who the key with the given hash belongs to and records
+// that it was used.
+func (r *Repo) UseAPIKey(ctx context.Context, hash string) (model.Principal, error) {
+
+	var (
+		principal   model.Principal
+		id          int64
+		workspaceID sql.NullInt64
+	)
+
+	err := r.Db.QueryRowContext(ctx, model.FetchAPIKeyByHashQuery, hash).Scan(&id, &principal.UserID, &principal.Email, &principal.ReadOnly, &workspaceID)
+	if err != nil {
+		return principal, keyError(err, "fetch api key")
+	}
+	principal.WorkspaceID = workspaceID.Int64
+
+	// the request goes on when the timestamp cannot be written, it is only
+	// informative
+	if _, err := r.Db.ExecContext(ctx, model.TouchAPIKeyQuery, id); err != nil {
+		fmt.Println(err)
+	}
+
+	return principal, nil
+}
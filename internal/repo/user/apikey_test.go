@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var (
+	testOwner = int64(1)
+	userCtx   = requestinfo.WithUserID(context.Background(), testOwner)
+)
+
+func TestRepo_CreateAPIKey(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	workspaceID := int64(2)
+
+	// the key is limited to the workspace it is created in
+	mock.ExpectQuery(`INSERT INTO api_keys (.*) RETURNING id, created_at`).WithArgs("backup", "tdl_abcdefgh", "hash", true, &workspaceID, testOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+
+	repo := NewUserRepository(db)
+	result, err := repo.CreateAPIKey(requestinfo.WithWorkspaceID(userCtx, workspaceID), model.APIKeyModel{Name: "backup", Prefix: "tdl_abcdefgh", Hash: "hash", ReadOnly: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.APIKeyModel{ID: 3, Name: "backup", Prefix: "tdl_abcdefgh", Hash: "hash", ReadOnly: true, WorkspaceID: &workspaceID, CreatedAt: createdAt}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_GetAPIKeys(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	lastUsedAt := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	workspaceID := int64(2)
+
+	mock.ExpectQuery(`SELECT (.+) FROM api_keys WHERE user_id=(.*) ORDER BY id`).WithArgs(testOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "prefix", "read_only", "workspace_id", "created_at", "last_used_at"}).
+			AddRow(1, "backup", "tdl_abcdefgh", true, 2, createdAt, lastUsedAt).
+			AddRow(2, "sync", "tdl_ijklmnop", false, nil, createdAt, nil))
+
+	repo := NewUserRepository(db)
+	result, err := repo.GetAPIKeys(userCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.APIKeyModel{
+		{ID: 1, Name: "backup", Prefix: "tdl_abcdefgh", ReadOnly: true, WorkspaceID: &workspaceID, CreatedAt: createdAt, LastUsedAt: &lastUsedAt},
+		{ID: 2, Name: "sync", Prefix: "tdl_ijklmnop", CreatedAt: createdAt},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_RevokeAPIKey(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "case 1 -> revoke key",
+			mock: func() {
+				mock.ExpectExec(`DELETE FROM api_keys WHERE id=(.*) AND user_id=(.*)`).WithArgs(1, testOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "case 2 -> key of another user",
+			mock: func() {
+				mock.ExpectExec(`DELETE FROM api_keys WHERE id=(.*) AND user_id=(.*)`).WithArgs(1, testOwner).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewUserRepository(db)
+			err := repo.RevokeAPIKey(userCtx, 1)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_UseAPIKey(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.Principal
+		wantErr error
+	}{
+		{
+			name: "case 1 -> key is found and its use recorded",
+			mock: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM api_keys JOIN users (.+) WHERE api_keys.key_hash=(.*)`).WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "email", "read_only", "workspace_id"}).AddRow(3, 1, "alice@example.com", true, 2))
+				mock.ExpectExec(`UPDATE api_keys SET last_used_at=now\(\) WHERE id=(.*)`).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: model.Principal{UserID: 1, Email: "alice@example.com", ReadOnly: true, WorkspaceID: 2},
+		},
+		{
+			name: "case 2 -> failing to record the use does not fail the request",
+			mock: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM api_keys JOIN users (.+) WHERE api_keys.key_hash=(.*)`).WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "email", "read_only", "workspace_id"}).AddRow(3, 1, "alice@example.com", false, nil))
+				mock.ExpectExec(`UPDATE api_keys SET last_used_at=now\(\) WHERE id=(.*)`).WithArgs(3).WillReturnError(errors.New("lock timeout"))
+			},
+			want: model.Principal{UserID: 1, Email: "alice@example.com"},
+		},
+		{
+			name: "case 3 -> unknown or revoked key",
+			mock: func() {
+				mock.ExpectQuery(`SELECT (.+) FROM api_keys JOIN users (.+) WHERE api_keys.key_hash=(.*)`).WithArgs("hash").WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewUserRepository(db)
+			result, err := repo.UseAPIKey(context.Background(), "hash")
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package user
+
+import "to-do-list/internal/repo/dberror"
+
+func dbError(err error, message string) error {
+	return dberror.Wrap(err, "user", message)
+}
+
+func keyError(err error, message string) error {
+	return dberror.Wrap(err, "api key", message)
+}
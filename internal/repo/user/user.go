@@ -0,0 +1,84 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+)
+
+type Repo struct {
+	Db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *Repo {
+	return &Repo{
+		Db: db,
+	}
+}
+
+func (r *Repo) Create(ctx context.Context, user model.UserModel) (model.UserModel, error) {
+
+	err := r.Db.QueryRowContext(ctx, model.InsertUserReturnIdQuery, user.Email, user.PasswordHash).Scan(&user.ID, &user.CreatedAt)
+	if err != nil {
+		fmt.Println(err)
+		return user, dbError(err, "create user")
+	}
+
+	return user, nil
+}
+
+func (r *Repo) GetByEmail(ctx context.Context, email string) (model.UserModel, error) {
+	return r.fetch(ctx, model.FetchUserByEmailQuery, email)
+}
+
+func (r *Repo) GetByID(ctx context.Context, id int64) (model.UserModel, error) {
+	return r.fetch(ctx, model.FetchUserByIdQuery, id)
+}
+
+func (r *Repo) fetch(ctx context.Context, query string, arg interface{}) (model.UserModel, error) {
+
+	user := model.UserModel{}
+
+	err := r.Db.QueryRowContext(ctx, query, arg).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt)
+	if err != nil {
+		return user, dbError(err, "fetch user")
+	}
+
+	return user, nil
+}
+
+// SaveRefreshToken stores a new refresh token and drops the expired ones of
+// the same user.
+func (r *Repo) SaveRefreshToken(ctx context.Context, token model.RefreshToken) error {
+
+	if _, err := r.Db.ExecContext(ctx, model.DeleteExpiredRefreshTokensQuery, token.UserID); err != nil {
+		fmt.Println(err)
+		return dbError(err, "save refresh token")
+	}
+
+	if _, err := r.Db.ExecContext(ctx, model.InsertRefreshTokenQuery, token.Hash, token.UserID, token.ExpiresAt); err != nil {
+		fmt.Println(err)
+		return dbError(err, "save refresh token")
+	}
+
+	return nil
+}
+
+// TakeRefreshToken removes the refresh token with the given hash and
+// returns it. Expired tokens are returned too, the caller checks.
+func (r *Repo) TakeRefreshToken(ctx context.Context, hash string) (model.RefreshToken, error) {
+
+	token := model.RefreshToken{Hash: hash}
+
+	err := r.Db.QueryRowContext(ctx, model.TakeRefreshTokenQuery, hash).Scan(&token.UserID, &token.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return token, apperror.New(apperror.ErrNotFound, "refresh token not found")
+	}
+	if err != nil {
+		return token, dbError(err, "take refresh token")
+	}
+
+	return token, nil
+}
@@ -0,0 +1,156 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var userColumns = []string{"id", "email", "password_hash", "created_at"}
+
+func mockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func TestRepo_Create(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.UserModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> create user",
+			mock: func() {
+				mock.ExpectQuery(`INSERT INTO users (.*) RETURNING id, created_at`).WithArgs("alice@example.com", "hash").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, createdAt))
+			},
+			want: model.UserModel{ID: 1, Email: "alice@example.com", PasswordHash: "hash", CreatedAt: createdAt},
+		},
+		{
+			name: "case 2 -> email already used",
+			mock: func() {
+				mock.ExpectQuery(`INSERT INTO users (.*) RETURNING id, created_at`).WithArgs("alice@example.com", "hash").WillReturnError(&pq.Error{Code: "23505"})
+			},
+			want:    model.UserModel{Email: "alice@example.com", PasswordHash: "hash"},
+			wantErr: apperror.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewUserRepository(db)
+			result, err := repo.Create(context.Background(), model.UserModel{Email: "alice@example.com", PasswordHash: "hash"})
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_GetByEmail(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT (.+) FROM users WHERE email=(.*)`).WithArgs("alice@example.com").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "alice@example.com", "hash", createdAt))
+	mock.ExpectQuery(`SELECT (.+) FROM users WHERE email=(.*)`).WithArgs("bob@example.com").WillReturnError(sql.ErrNoRows)
+
+	repo := NewUserRepository(db)
+
+	result, err := repo.GetByEmail(context.Background(), "alice@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, model.UserModel{ID: 1, Email: "alice@example.com", PasswordHash: "hash", CreatedAt: createdAt}, result)
+
+	_, err = repo.GetByEmail(context.Background(), "bob@example.com")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_SaveRefreshToken(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	expiresAt := time.Date(2026, 4, 8, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`DELETE FROM refresh_tokens WHERE user_id=(.*) AND expires_at < now\(\)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO refresh_tokens (.*)`).WithArgs("hash", 1, expiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewUserRepository(db)
+	err := repo.SaveRefreshToken(context.Background(), model.RefreshToken{Hash: "hash", UserID: 1, ExpiresAt: expiresAt})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_TakeRefreshToken(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	expiresAt := time.Date(2026, 4, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    model.RefreshToken
+		wantErr error
+	}{
+		{
+			name: "case 1 -> take token",
+			mock: func() {
+				mock.ExpectQuery(`DELETE FROM refresh_tokens WHERE token_hash=(.*) RETURNING user_id, expires_at`).WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "expires_at"}).AddRow(1, expiresAt))
+			},
+			want: model.RefreshToken{Hash: "hash", UserID: 1, ExpiresAt: expiresAt},
+		},
+		{
+			name: "case 2 -> token already used",
+			mock: func() {
+				mock.ExpectQuery(`DELETE FROM refresh_tokens WHERE token_hash=(.*) RETURNING user_id, expires_at`).WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "expires_at"}))
+			},
+			want:    model.RefreshToken{Hash: "hash"},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			repo := NewUserRepository(db)
+			result, err := repo.TakeRefreshToken(context.Background(), "hash")
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
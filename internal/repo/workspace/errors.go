@@ -0,0 +1,11 @@
+package workspace
+
+import "to-do-list/internal/repo/dberror"
+
+func dbError(err error, message string) error {
+	return dberror.Wrap(err, "workspace", message)
+}
+
+func memberError(err error, message string) error {
+	return dberror.Wrap(err, "member", message)
+}
@@ -0,0 +1,201 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	model "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+type Repo struct {
+	Db *sql.DB
+}
+
+func NewWorkspaceRepository(db *sql.DB) *Repo {
+	return &Repo{
+		Db: db,
+	}
+}
+
+// GetAll returns the workspaces of the signed in user, the default one
+// first.
+func (r *Repo) GetAll(ctx context.Context) ([]model.WorkspaceModel, error) {
+
+	workspaces := []model.WorkspaceModel{}
+
+	user := requestinfo.UserID(ctx)
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchWorkspacesQuery, user)
+	if err != nil {
+		fmt.Println(err)
+		return nil, dbError(err, "fetch workspaces")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		workspace, err := scanWorkspace(rows, user)
+		if err != nil {
+			return nil, dbError(err, "scan workspace")
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(err, "fetch workspaces")
+	}
+
+	return workspaces, nil
+}
+
+// GetByID returns a workspace of the signed in user. The others are not
+// found.
+func (r *Repo) GetByID(ctx context.Context, id int64) (model.WorkspaceModel, error) {
+	return r.fetch(ctx, model.FetchWorkspaceByIdQuery, id)
+}
+
+// GetBySlug is GetByID for the slug of the workspace.
+func (r *Repo) GetBySlug(ctx context.Context, slug string) (model.WorkspaceModel, error) {
+	return r.fetch(ctx, model.FetchWorkspaceBySlugQuery, slug)
+}
+
+func (r *Repo) fetch(ctx context.Context, query string, arg interface{}) (model.WorkspaceModel, error) {
+
+	user := requestinfo.UserID(ctx)
+
+	workspace, err := scanWorkspace(r.Db.QueryRowContext(ctx, query, arg, user), user)
+	if err != nil {
+		return workspace, dbError(err, "fetch workspace")
+	}
+
+	return workspace, nil
+}
+
+// Create stores a workspace owned by the signed in user, who becomes its
+// first member.
+func (r *Repo) Create(ctx context.Context, workspace model.WorkspaceModel) (model.WorkspaceModel, error) {
+
+	user := requestinfo.UserID(ctx)
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return workspace, dbError(err, "create workspace")
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, model.InsertWorkspaceReturnIdQuery, workspace.Name, workspace.Slug, user).Scan(&workspace.ID, &workspace.CreatedAt)
+	if err != nil {
+		fmt.Println(err)
+		return workspace, dbError(err, "create workspace")
+	}
+
+	var joinedAt sql.NullTime
+	if err := tx.QueryRowContext(ctx, model.InsertMemberQuery, workspace.ID, user).Scan(&joinedAt); err != nil {
+		fmt.Println(err)
+		return workspace, dbError(err, "create workspace")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return workspace, dbError(err, "create workspace")
+	}
+
+	workspace.Role = model.RoleOwner
+	return workspace, nil
+}
+
+func (r *Repo) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+
+	members := []model.MemberModel{}
+
+	rows, err := r.Db.QueryContext(ctx, model.FetchMembersQuery, id)
+	if err != nil {
+		fmt.Println(err)
+		return nil, memberError(err, "fetch members")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			member  model.MemberModel
+			ownerID sql.NullInt64
+		)
+		if err := rows.Scan(&member.WorkspaceID, &member.UserID, &member.Email, &ownerID, &member.JoinedAt); err != nil {
+			return nil, memberError(err, "scan member")
+		}
+		member.Role = roleOf(ownerID, member.UserID)
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, memberError(err, "fetch members")
+	}
+
+	return members, nil
+}
+
+// AddMember adds the user with an email to a workspace.
+func (r *Repo) AddMember(ctx context.Context, id int64, email string) (model.MemberModel, error) {
+
+	member := model.MemberModel{WorkspaceID: id, Email: email, Role: model.RoleMember}
+
+	err := r.Db.QueryRowContext(ctx, model.AddMemberQuery, id, email).Scan(&member.UserID, &member.JoinedAt)
+	if err == sql.ErrNoRows {
+		return member, apperror.New(apperror.ErrNotFound, "no user with this email")
+	}
+	if err != nil {
+		fmt.Println(err)
+		return member, memberError(err, "add member")
+	}
+
+	return member, nil
+}
+
+// RemoveMember takes a user out of a workspace. Their tasks stay in it,
+// out of their reach until they are added again.
+func (r *Repo) RemoveMember(ctx context.Context, id int64, userID int64) error {
+
+	res, err := r.Db.ExecContext(ctx, model.DeleteMemberQuery, id, userID)
+	if err != nil {
+		fmt.Println(err)
+		return memberError(err, "remove member")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return memberError(err, "remove member")
+	}
+	if affected == 0 {
+		return apperror.New(apperror.ErrNotFound, "member not found")
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWorkspace(row scanner, user int64) (model.WorkspaceModel, error) {
+
+	var (
+		workspace model.WorkspaceModel
+		ownerID   sql.NullInt64
+	)
+
+	if err := row.Scan(&workspace.ID, &workspace.Name, &workspace.Slug, &ownerID, &workspace.CreatedAt); err != nil {
+		return workspace, err
+	}
+	workspace.Role = roleOf(ownerID, user)
+
+	return workspace, nil
+}
+
+func roleOf(ownerID sql.NullInt64, user int64) model.Role {
+	if ownerID.Valid && ownerID.Int64 == user {
+		return model.RoleOwner
+	}
+	return model.RoleMember
+}
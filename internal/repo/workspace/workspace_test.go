@@ -0,0 +1,240 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var (
+	testUser = int64(1)
+	userCtx  = requestinfo.WithUserID(context.Background(), testUser)
+
+	workspaceColumns = []string{"id", "name", "slug", "owner_id", "created_at"}
+)
+
+func mockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func TestRepo_GetAll(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT (.+) FROM workspaces (.+) ORDER BY workspaces.id`).WithArgs(testUser).
+		WillReturnRows(sqlmock.NewRows(workspaceColumns).
+			AddRow(1, "Default", "default", nil, createdAt).
+			AddRow(2, "Acme", "acme", testUser, createdAt).
+			AddRow(3, "Globex", "globex", 4, createdAt))
+
+	repo := NewWorkspaceRepository(db)
+	result, err := repo.GetAll(userCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.WorkspaceModel{
+		{ID: 1, Name: "Default", Slug: "default", Role: model.RoleMember, CreatedAt: createdAt},
+		{ID: 2, Name: "Acme", Slug: "acme", Role: model.RoleOwner, CreatedAt: createdAt},
+		{ID: 3, Name: "Globex", Slug: "globex", Role: model.RoleMember, CreatedAt: createdAt},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_GetBySlug(t *testing.T) {
+
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func(mock sqlmock.Sqlmock)
+		want    model.WorkspaceModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> workspace of a member",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM workspaces (.+) WHERE workspaces.slug=(.*)`).WithArgs("acme", testUser).
+					WillReturnRows(sqlmock.NewRows(workspaceColumns).AddRow(2, "Acme", "acme", 4, createdAt))
+			},
+			want: model.WorkspaceModel{ID: 2, Name: "Acme", Slug: "acme", Role: model.RoleMember, CreatedAt: createdAt},
+		},
+		{
+			name: "case 2 -> workspace the user is not a member of is not found",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM workspaces (.+) WHERE workspaces.slug=(.*)`).WithArgs("acme", testUser).
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := mockDB(t)
+			tt.mock(mock)
+
+			repo := NewWorkspaceRepository(db)
+			result, err := repo.GetBySlug(userCtx, "acme")
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_Create(t *testing.T) {
+
+	createdAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func(mock sqlmock.Sqlmock)
+		want    model.WorkspaceModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> creator becomes the owner and first member",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO workspaces (.*) RETURNING id, created_at`).WithArgs("Acme", "acme", testUser).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, createdAt))
+				mock.ExpectQuery(`INSERT INTO workspace_members (.*) RETURNING created_at`).WithArgs(2, testUser).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+				mock.ExpectCommit()
+			},
+			want: model.WorkspaceModel{ID: 2, Name: "Acme", Slug: "acme", Role: model.RoleOwner, CreatedAt: createdAt},
+		},
+		{
+			name: "case 2 -> slug already taken",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO workspaces (.*) RETURNING id, created_at`).WithArgs("Acme", "acme", testUser).
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "workspaces_slug_key"})
+				mock.ExpectRollback()
+			},
+			wantErr: apperror.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := mockDB(t)
+			tt.mock(mock)
+
+			repo := NewWorkspaceRepository(db)
+			result, err := repo.Create(userCtx, model.WorkspaceModel{Name: "Acme", Slug: "acme"})
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_GetMembers(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	joinedAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT (.+) FROM workspace_members (.+) WHERE workspace_members.workspace_id=(.*)`).WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "email", "owner_id", "created_at"}).
+			AddRow(2, testUser, "alice@example.com", testUser, joinedAt).
+			AddRow(2, 4, "bob@example.com", testUser, joinedAt))
+
+	repo := NewWorkspaceRepository(db)
+	result, err := repo.GetMembers(userCtx, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.MemberModel{
+		{WorkspaceID: 2, UserID: testUser, Email: "alice@example.com", Role: model.RoleOwner, JoinedAt: joinedAt},
+		{WorkspaceID: 2, UserID: 4, Email: "bob@example.com", Role: model.RoleMember, JoinedAt: joinedAt},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepo_AddMember(t *testing.T) {
+
+	joinedAt := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func(mock sqlmock.Sqlmock)
+		want    model.MemberModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> add a user by email",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO workspace_members (.+) RETURNING user_id, created_at`).WithArgs(2, "bob@example.com").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "created_at"}).AddRow(4, joinedAt))
+			},
+			want: model.MemberModel{WorkspaceID: 2, UserID: 4, Email: "bob@example.com", Role: model.RoleMember, JoinedAt: joinedAt},
+		},
+		{
+			name: "case 2 -> no user with the email",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO workspace_members (.+) RETURNING user_id, created_at`).WithArgs(2, "bob@example.com").
+					WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := mockDB(t)
+			tt.mock(mock)
+
+			repo := NewWorkspaceRepository(db)
+			result, err := repo.AddMember(userCtx, 2, "bob@example.com")
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepo_RemoveMember(t *testing.T) {
+
+	db, mock := mockDB(t)
+
+	mock.ExpectExec(`DELETE FROM workspace_members WHERE workspace_id=(.*) AND user_id=(.*)`).WithArgs(2, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM workspace_members WHERE workspace_id=(.*) AND user_id=(.*)`).WithArgs(2, testUser).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewWorkspaceRepository(db)
+
+	assert.NoError(t, repo.RemoveMember(userCtx, 2, 4))
+	// the owner is never removed
+	assert.ErrorIs(t, repo.RemoveMember(userCtx, 2, testUser), apperror.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,126 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+type Usecase struct {
+	listRepo Repo
+	tasks    Tasks
+}
+
+func NewUseCase(repo Repo, tasks Tasks) *Usecase {
+	return &Usecase{
+		listRepo: repo,
+		tasks:    tasks,
+	}
+}
+
+type Repo interface {
+	GetAll(ctx context.Context) ([]model.ListModel, error)
+	GetByID(ctx context.Context, id int64) (model.ListModel, error)
+	Create(ctx context.Context, list model.ListModel) (model.ListModel, error)
+	Update(ctx context.Context, list model.ListModel) (model.ListModel, error)
+	// Delete returns the live tasks of the list by workspace, as they were
+	// before, and records what it did to them with record
+	Delete(ctx context.Context, id int64, opts model.DeleteOptions, record taskmodel.Record) (map[int64][]taskmodel.TaskModel, error)
+	ListAccess(ctx context.Context, id int64) (model.Access, error)
+	GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error)
+	Invite(ctx context.Context, id int64, invite model.InviteRequest) (model.MemberModel, error)
+	Accept(ctx context.Context, id int64) (model.MemberModel, error)
+	RemoveMember(ctx context.Context, id int64, userID int64) error
+	GetInvitations(ctx context.Context) ([]model.MemberModel, error)
+}
+
+// Tasks pages through tasks and records what deleting a list does to them,
+// the task usecase implements it.
+type Tasks interface {
+	GetAllTask(ctx context.Context, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error)
+	RecordListDelete(ctx context.Context) taskmodel.Record
+}
+
+// GetAllList returns the lists of the user and those shared with them.
+func (u *Usecase) GetAllList(ctx context.Context) ([]model.ListModel, error) {
+	return u.listRepo.GetAll(ctx)
+}
+
+func (u *Usecase) GetList(ctx context.Context, id int64) (model.ListModel, error) {
+	ctx, access, err := u.access(ctx, id, model.RoleViewer)
+	if err != nil {
+		return model.ListModel{}, err
+	}
+	list, err := u.listRepo.GetByID(ctx, id)
+	list.Role = access.Role
+	return list, err
+}
+
+func (u *Usecase) CreateList(ctx context.Context, r model.ListModel) (model.ListModel, error) {
+	r.Name = strings.TrimSpace(r.Name)
+	return u.listRepo.Create(ctx, r)
+}
+
+func (u *Usecase) UpdateList(ctx context.Context, r model.ListModel) (model.ListModel, error) {
+	r.Name = strings.TrimSpace(r.Name)
+	ctx, access, err := u.access(ctx, r.ID, model.RoleOwner)
+	if err != nil {
+		return r, err
+	}
+	list, err := u.listRepo.Update(ctx, r)
+	list.Role = access.Role
+	return list, err
+}
+
+// GetListTasks pages through the tasks of a list. An unknown list is not
+// found rather than an empty page.
+func (u *Usecase) GetListTasks(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+	if _, _, err := u.access(ctx, id, model.RoleViewer); err != nil {
+		return taskmodel.TaskPage{}, err
+	}
+	filter.ListID = &id
+	return u.tasks.GetAllTask(ctx, filter)
+}
+
+// DeleteList is left to the user who created the list, members cannot
+// delete it even as owners. What it does to the tasks of the list goes to
+// their history like any other change.
+func (u *Usecase) DeleteList(ctx context.Context, id int64, opts model.DeleteOptions) error {
+	ctx, access, err := u.access(ctx, id, model.RoleOwner)
+	if err != nil {
+		return err
+	}
+	if access.OwnerID != requestinfo.UserID(ctx) {
+		return apperror.New(apperror.ErrForbidden, "only the user who created the list deletes it")
+	}
+
+	switch opts.Mode {
+	case model.DeleteCascade:
+		opts.To = nil
+	case model.DeleteReassign:
+		if opts.To != nil && *opts.To == id {
+			return apperror.New(apperror.ErrValidation, "cannot reassign tasks to the deleted list")
+		}
+	default:
+		return apperror.New(apperror.ErrValidation, "mode must be cascade or reassign")
+	}
+	_, err = u.listRepo.Delete(ctx, id, opts, u.tasks.RecordListDelete(ctx))
+	return err
+}
+
+// access checks that the signed in user has at least the role need on the
+// list and returns a context reaching the data of its owner.
+func (u *Usecase) access(ctx context.Context, id int64, need model.Role) (context.Context, model.Access, error) {
+	access, err := u.listRepo.ListAccess(ctx, id)
+	if err != nil {
+		return ctx, access, err
+	}
+	if !access.Role.Allows(need) {
+		return ctx, access, apperror.New(apperror.ErrForbidden, fmt.Sprintf("the %s role on the list is needed, you are %s", need, access.Role))
+	}
+	return requestinfo.WithOwnerID(ctx, access.OwnerID), access, nil
+}
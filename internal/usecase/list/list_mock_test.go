@@ -0,0 +1,85 @@
+package list
+
+import (
+	"context"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/requestinfo"
+)
+
+type ListRepositoryMock struct {
+	GetAllFunc  func(ctx context.Context) ([]model.ListModel, error)
+	GetByIDFunc func(ctx context.Context, id int64) (model.ListModel, error)
+	CreateFunc  func(ctx context.Context, list model.ListModel) (model.ListModel, error)
+	UpdateFunc  func(ctx context.Context, list model.ListModel) (model.ListModel, error)
+	DeleteFunc  func(ctx context.Context, id int64, opts model.DeleteOptions, record taskmodel.Record) (map[int64][]taskmodel.TaskModel, error)
+
+	ListAccessFunc     func(ctx context.Context, id int64) (model.Access, error)
+	GetMembersFunc     func(ctx context.Context, id int64) ([]model.MemberModel, error)
+	InviteFunc         func(ctx context.Context, id int64, invite model.InviteRequest) (model.MemberModel, error)
+	AcceptFunc         func(ctx context.Context, id int64) (model.MemberModel, error)
+	RemoveMemberFunc   func(ctx context.Context, id int64, userID int64) error
+	GetInvitationsFunc func(ctx context.Context) ([]model.MemberModel, error)
+}
+
+func (repository *ListRepositoryMock) GetAll(ctx context.Context) ([]model.ListModel, error) {
+	return repository.GetAllFunc(ctx)
+}
+
+func (repository *ListRepositoryMock) GetByID(ctx context.Context, id int64) (model.ListModel, error) {
+	return repository.GetByIDFunc(ctx, id)
+}
+
+func (repository *ListRepositoryMock) Create(ctx context.Context, list model.ListModel) (model.ListModel, error) {
+	return repository.CreateFunc(ctx, list)
+}
+
+func (repository *ListRepositoryMock) Update(ctx context.Context, list model.ListModel) (model.ListModel, error) {
+	return repository.UpdateFunc(ctx, list)
+}
+
+func (repository *ListRepositoryMock) Delete(ctx context.Context, id int64, opts model.DeleteOptions, record taskmodel.Record) (map[int64][]taskmodel.TaskModel, error) {
+	return repository.DeleteFunc(ctx, id, opts, record)
+}
+
+func (repository *ListRepositoryMock) ListAccess(ctx context.Context, id int64) (model.Access, error) {
+	return repository.ListAccessFunc(ctx, id)
+}
+
+func (repository *ListRepositoryMock) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	return repository.GetMembersFunc(ctx, id)
+}
+
+func (repository *ListRepositoryMock) Invite(ctx context.Context, id int64, invite model.InviteRequest) (model.MemberModel, error) {
+	return repository.InviteFunc(ctx, id, invite)
+}
+
+func (repository *ListRepositoryMock) Accept(ctx context.Context, id int64) (model.MemberModel, error) {
+	return repository.AcceptFunc(ctx, id)
+}
+
+func (repository *ListRepositoryMock) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	return repository.RemoveMemberFunc(ctx, id, userID)
+}
+
+func (repository *ListRepositoryMock) GetInvitations(ctx context.Context) ([]model.MemberModel, error) {
+	return repository.GetInvitationsFunc(ctx)
+}
+
+// ownAccess answers like the repo for lists of the signed in user.
+func ownAccess(ctx context.Context, id int64) (model.Access, error) {
+	return model.Access{OwnerID: requestinfo.UserID(ctx), Role: model.RoleOwner}, nil
+}
+
+type TasksMock struct {
+	GetAllTaskFunc       func(ctx context.Context, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error)
+	RecordListDeleteFunc func(ctx context.Context) taskmodel.Record
+}
+
+func (mock *TasksMock) GetAllTask(ctx context.Context, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+	return mock.GetAllTaskFunc(ctx, filter)
+}
+
+func (mock *TasksMock) RecordListDelete(ctx context.Context) taskmodel.Record {
+	return mock.RecordListDeleteFunc(ctx)
+}
@@ -0,0 +1,133 @@
+package list
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseCase_GetListTasks(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		repo    *ListRepositoryMock
+		want    taskmodel.TaskPage
+		wantErr error
+	}{
+		{
+			name: "case 1 -> tasks are filtered by the list",
+			repo: &ListRepositoryMock{
+				ListAccessFunc: ownAccess,
+			},
+			want:    taskmodel.TaskPage{Tasks: []taskmodel.TaskModel{{ID: 1, TaskName: "task 1"}}},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> unknown list is not found",
+			repo: &ListRepositoryMock{
+				ListAccessFunc: func(ctx context.Context, id int64) (model.Access, error) {
+					return model.Access{}, apperror.New(apperror.ErrNotFound, "list not found")
+				},
+			},
+			want:    taskmodel.TaskPage{},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := &TasksMock{
+				GetAllTaskFunc: func(ctx context.Context, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+					if filter.ListID == nil || *filter.ListID != 2 || filter.Limit != 5 {
+						return taskmodel.TaskPage{}, apperror.New(apperror.ErrValidation, "unexpected filter")
+					}
+					return taskmodel.TaskPage{Tasks: []taskmodel.TaskModel{{ID: 1, TaskName: "task 1"}}}, nil
+				},
+			}
+			got, err := NewUseCase(tt.repo, tasks).GetListTasks(ctx, 2, taskmodel.TaskFilter{Limit: 5})
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUseCase_DeleteList(t *testing.T) {
+	ctx := context.Background()
+	to := int64(2)
+	self := int64(1)
+
+	tests := []struct {
+		name     string
+		opts     model.DeleteOptions
+		wantOpts model.DeleteOptions
+		wantErr  error
+	}{
+		{
+			name:     "case 1 -> cascade ignores the target list",
+			opts:     model.DeleteOptions{Mode: model.DeleteCascade, To: &to},
+			wantOpts: model.DeleteOptions{Mode: model.DeleteCascade},
+			wantErr:  nil,
+		},
+		{
+			name:     "case 2 -> reassign to another list",
+			opts:     model.DeleteOptions{Mode: model.DeleteReassign, To: &to},
+			wantOpts: model.DeleteOptions{Mode: model.DeleteReassign, To: &to},
+			wantErr:  nil,
+		},
+		{
+			name:    "case 3 -> reassign to the deleted list",
+			opts:    model.DeleteOptions{Mode: model.DeleteReassign, To: &self},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 4 -> missing mode",
+			opts:    model.DeleteOptions{},
+			wantErr: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got      model.DeleteOptions
+				recorded []taskmodel.Change
+			)
+			changes := []taskmodel.Change{{Before: &taskmodel.TaskModel{ID: 7, TaskName: "task 7", ListID: &self, Version: 1}}}
+			repo := &ListRepositoryMock{
+				ListAccessFunc: ownAccess,
+				DeleteFunc: func(ctx context.Context, id int64, opts model.DeleteOptions, record taskmodel.Record) (map[int64][]taskmodel.TaskModel, error) {
+					got = opts
+					record(changes)
+					return map[int64][]taskmodel.TaskModel{1: {*changes[0].Before}}, nil
+				},
+			}
+			tasks := &TasksMock{
+				RecordListDeleteFunc: func(ctx context.Context) taskmodel.Record {
+					return func(changes []taskmodel.Change) []taskmodel.TaskEvent {
+						recorded = changes
+						return nil
+					}
+				},
+			}
+			err := NewUseCase(repo, tasks).DeleteList(ctx, 1, tt.opts)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantOpts, got)
+				// the tasks of the list go to their history
+				assert.Equal(t, changes, recorded)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, recorded)
+			}
+		})
+	}
+}
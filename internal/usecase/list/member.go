@@ -0,0 +1,52 @@
+package list
+
+import (
+	"context"
+	"strings"
+	model "to-do-list/internal/model/list"
+	"to-do-list/pkg/requestinfo"
+)
+
+// Sharing a list goes through an invitation the invited user accepts. Until
+// then the list stays hidden from them.
+
+// GetMembers returns the members of a list and the pending invitations,
+// without its owner.
+func (u *Usecase) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	if _, _, err := u.access(ctx, id, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return u.listRepo.GetMembers(ctx, id)
+}
+
+// InviteMember shares a list, or changes the role of a member.
+func (u *Usecase) InviteMember(ctx context.Context, id int64, r model.InviteRequest) (model.MemberModel, error) {
+	if _, _, err := u.access(ctx, id, model.RoleOwner); err != nil {
+		return model.MemberModel{}, err
+	}
+	r.Email = strings.ToLower(strings.TrimSpace(r.Email))
+	return u.listRepo.Invite(ctx, id, r)
+}
+
+// AcceptInvitation makes the signed in user a member of the list they were
+// invited to.
+func (u *Usecase) AcceptInvitation(ctx context.Context, id int64) (model.MemberModel, error) {
+	return u.listRepo.Accept(ctx, id)
+}
+
+// RemoveMember revokes a membership or an invitation. Owners of the list
+// remove anyone, other members only themselves, to leave or to decline.
+func (u *Usecase) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	if userID != requestinfo.UserID(ctx) {
+		if _, _, err := u.access(ctx, id, model.RoleOwner); err != nil {
+			return err
+		}
+	}
+	return u.listRepo.RemoveMember(ctx, id, userID)
+}
+
+// GetInvitations returns the invitations the signed in user has not
+// accepted yet.
+func (u *Usecase) GetInvitations(ctx context.Context) ([]model.MemberModel, error) {
+	return u.listRepo.GetInvitations(ctx)
+}
@@ -0,0 +1,130 @@
+package list
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/list"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sharedAccess answers like the repo for a list of user 1 shared with the
+// signed in user as role.
+func sharedAccess(role model.Role) func(ctx context.Context, id int64) (model.Access, error) {
+	return func(ctx context.Context, id int64) (model.Access, error) {
+		return model.Access{OwnerID: 1, Role: role}, nil
+	}
+}
+
+func TestUseCase_SharedListRoles(t *testing.T) {
+	ctx := requestinfo.WithUserID(context.Background(), 2)
+
+	tests := []struct {
+		name       string
+		role       model.Role
+		wantRename error
+		wantInvite error
+		wantDelete error
+	}{
+		{
+			name:       "case 1 -> viewer only reads",
+			role:       model.RoleViewer,
+			wantRename: apperror.ErrForbidden,
+			wantInvite: apperror.ErrForbidden,
+			wantDelete: apperror.ErrForbidden,
+		},
+		{
+			name:       "case 2 -> editor only changes tasks",
+			role:       model.RoleEditor,
+			wantRename: apperror.ErrForbidden,
+			wantInvite: apperror.ErrForbidden,
+			wantDelete: apperror.ErrForbidden,
+		},
+		{
+			name:       "case 3 -> owner shares and renames but does not delete",
+			role:       model.RoleOwner,
+			wantDelete: apperror.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ListRepositoryMock{
+				ListAccessFunc: sharedAccess(tt.role),
+				GetByIDFunc: func(ctx context.Context, id int64) (model.ListModel, error) {
+					assert.Equal(t, int64(1), requestinfo.OwnerID(ctx), "queries reach the data of the owner")
+					return model.ListModel{ID: id, Name: "Team"}, nil
+				},
+				UpdateFunc: func(ctx context.Context, list model.ListModel) (model.ListModel, error) {
+					return list, nil
+				},
+				InviteFunc: func(ctx context.Context, id int64, invite model.InviteRequest) (model.MemberModel, error) {
+					assert.Equal(t, "carol@example.com", invite.Email)
+					return model.MemberModel{ListID: id, Email: invite.Email, Role: invite.Role}, nil
+				},
+				DeleteFunc: func(ctx context.Context, id int64, opts model.DeleteOptions, record taskmodel.Record) (map[int64][]taskmodel.TaskModel, error) {
+					return nil, nil
+				},
+			}
+			u := NewUseCase(repo, &TasksMock{RecordListDeleteFunc: func(ctx context.Context) taskmodel.Record { return nil }})
+
+			list, err := u.GetList(ctx, 3)
+			assert.NoError(t, err)
+			assert.Equal(t, model.ListModel{ID: 3, Name: "Team", Role: tt.role}, list)
+
+			_, err = u.UpdateList(ctx, model.ListModel{ID: 3, Name: "Renamed"})
+			assertErr(t, tt.wantRename, err)
+
+			_, err = u.InviteMember(ctx, 3, model.InviteRequest{Email: " Carol@Example.com", Role: model.RoleViewer})
+			assertErr(t, tt.wantInvite, err)
+
+			err = u.DeleteList(ctx, 3, model.DeleteOptions{Mode: model.DeleteCascade})
+			assertErr(t, tt.wantDelete, err)
+		})
+	}
+}
+
+func TestUseCase_RemoveMember(t *testing.T) {
+	ctx := requestinfo.WithUserID(context.Background(), 2)
+
+	tests := []struct {
+		name    string
+		userID  int64
+		wantErr error
+	}{
+		{
+			name:   "case 1 -> member leaves the list",
+			userID: 2,
+		},
+		{
+			name:    "case 2 -> editor cannot remove another member",
+			userID:  3,
+			wantErr: apperror.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ListRepositoryMock{
+				ListAccessFunc: sharedAccess(model.RoleEditor),
+				RemoveMemberFunc: func(ctx context.Context, id int64, userID int64) error {
+					return nil
+				},
+			}
+			err := NewUseCase(repo, &TasksMock{}).RemoveMember(ctx, 3, tt.userID)
+			assertErr(t, tt.wantErr, err)
+		})
+	}
+}
+
+func assertErr(t *testing.T, want error, err error) {
+	t.Helper()
+	if want == nil {
+		assert.NoError(t, err)
+	} else {
+		assert.ErrorIs(t, err, want)
+	}
+}
@@ -0,0 +1,233 @@
+package share
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/share"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// PathPrefix is where the public pages of links are served.
+const PathPrefix = "/api/shared/"
+
+// A link reads as the user who created it, through the list and task
+// usecases, so visitors see what that user sees of the list or the task and
+// nothing else. The creator has to stay an owner of it for the link to work.
+
+type Usecase struct {
+	linkRepo Repo
+	sharing  Sharing
+	lists    Lists
+	tasks    Tasks
+	key      []byte
+	now      func() time.Time
+}
+
+// NewUseCase signs links with a key derived from secret, so a token of a
+// link is never mistaken for an access token signed with secret itself.
+func NewUseCase(repo Repo, sharing Sharing, lists Lists, tasks Tasks, secret []byte) *Usecase {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("share links"))
+	return &Usecase{
+		linkRepo: repo,
+		sharing:  sharing,
+		lists:    lists,
+		tasks:    tasks,
+		key:      mac.Sum(nil),
+		now:      time.Now,
+	}
+}
+
+type Repo interface {
+	Create(ctx context.Context, link model.LinkModel) (model.LinkModel, error)
+	GetAll(ctx context.Context) ([]model.LinkModel, error)
+	GetByID(ctx context.Context, id int64) (model.LinkModel, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// Sharing tells what a user may do with a list or a task, the list repo
+// implements it.
+type Sharing interface {
+	ListAccess(ctx context.Context, id int64) (listmodel.Access, error)
+	TaskAccess(ctx context.Context, id int64) (listmodel.Access, error)
+}
+
+// Lists reads lists, the list usecase implements it.
+type Lists interface {
+	GetList(ctx context.Context, id int64) (listmodel.ListModel, error)
+	GetListTasks(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error)
+}
+
+// Tasks reads tasks, the task usecase implements it.
+type Tasks interface {
+	GetTask(ctx context.Context, id int64) (taskmodel.TaskModel, error)
+}
+
+// CreateLink shares a list or a task the signed in user owns with anyone
+// holding the link, until it expires or is revoked.
+func (u *Usecase) CreateLink(ctx context.Context, r model.LinkRequest) (model.LinkModel, error) {
+	if (r.ListID == nil) == (r.TaskID == nil) {
+		return model.LinkModel{}, apperror.New(apperror.ErrValidation, "a link is to a list or to a task, set one of list_id and task_id")
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(u.now()) {
+		return model.LinkModel{}, apperror.New(apperror.ErrValidation, "expires_at must be in the future")
+	}
+
+	link := model.LinkModel{ListID: r.ListID, TaskID: r.TaskID, ExpiresAt: r.ExpiresAt}
+	if err := u.checkOwner(ctx, link); err != nil {
+		return model.LinkModel{}, err
+	}
+
+	link, err := u.linkRepo.Create(ctx, link)
+	if err != nil {
+		return model.LinkModel{}, err
+	}
+	link.URL = PathPrefix + u.token(link)
+	return link, nil
+}
+
+// GetLinks returns the links the signed in user created. The token of a
+// link is derived from it, so their URLs can be read again.
+func (u *Usecase) GetLinks(ctx context.Context) ([]model.LinkModel, error) {
+	links, err := u.linkRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		links[i].URL = PathPrefix + u.token(links[i])
+	}
+	return links, nil
+}
+
+// RevokeLink stops a link of the signed in user from working.
+func (u *Usecase) RevokeLink(ctx context.Context, id int64) error {
+	return u.linkRepo.Delete(ctx, id)
+}
+
+// GetShared returns what the link with the given token shows.
+func (u *Usecase) GetShared(ctx context.Context, token string) (model.SharedModel, error) {
+	ctx, link, err := u.open(ctx, token)
+	if err != nil {
+		return model.SharedModel{}, err
+	}
+
+	if link.ListID != nil {
+		list, err := u.lists.GetList(ctx, *link.ListID)
+		if err != nil {
+			return model.SharedModel{}, err
+		}
+		// the role is that of the creator, not of the visitor
+		list.Role = ""
+		return model.SharedModel{List: &list}, nil
+	}
+
+	task, err := u.tasks.GetTask(ctx, *link.TaskID)
+	if err != nil {
+		return model.SharedModel{}, err
+	}
+	return model.SharedModel{Task: &task}, nil
+}
+
+// GetSharedTasks pages through the tasks of the list a link shows.
+func (u *Usecase) GetSharedTasks(ctx context.Context, token string, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+	ctx, link, err := u.open(ctx, token)
+	if err != nil {
+		return taskmodel.TaskPage{}, err
+	}
+	if link.ListID == nil {
+		return taskmodel.TaskPage{}, apperror.New(apperror.ErrNotFound, "the share link is to a task, not to a list")
+	}
+	return u.lists.GetListTasks(ctx, *link.ListID, filter)
+}
+
+// open checks the token and returns a context reading as the creator of
+// the link. Forged, revoked, expired and orphaned links answer the same.
+func (u *Usecase) open(ctx context.Context, token string) (context.Context, model.LinkModel, error) {
+	notFound := apperror.New(apperror.ErrNotFound, "share link not found")
+
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ctx, model.LinkModel{}, notFound
+	}
+	linkID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || linkID <= 0 {
+		return ctx, model.LinkModel{}, notFound
+	}
+
+	link, err := u.linkRepo.GetByID(ctx, linkID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return ctx, model.LinkModel{}, notFound
+	}
+	if err != nil {
+		return ctx, model.LinkModel{}, err
+	}
+	if !hmac.Equal([]byte(sig), []byte(u.signature(link))) {
+		return ctx, model.LinkModel{}, notFound
+	}
+	if link.ExpiresAt != nil && !u.now().Before(*link.ExpiresAt) {
+		return ctx, model.LinkModel{}, notFound
+	}
+
+	ctx = requestinfo.WithWorkspaceID(requestinfo.WithUserID(ctx, link.UserID), link.WorkspaceID)
+	err = u.checkOwner(ctx, link)
+	if errors.Is(err, apperror.ErrNotFound) || errors.Is(err, apperror.ErrForbidden) {
+		return ctx, model.LinkModel{}, notFound
+	}
+	if err != nil {
+		return ctx, model.LinkModel{}, err
+	}
+	return ctx, link, nil
+}
+
+// checkOwner checks that the signed in user owns what the link shows.
+func (u *Usecase) checkOwner(ctx context.Context, link model.LinkModel) error {
+	var (
+		access listmodel.Access
+		err    error
+	)
+	if link.ListID != nil {
+		access, err = u.sharing.ListAccess(ctx, *link.ListID)
+	} else {
+		access, err = u.sharing.TaskAccess(ctx, *link.TaskID)
+	}
+	if err != nil {
+		return err
+	}
+	if !access.Role.Allows(listmodel.RoleOwner) {
+		return apperror.New(apperror.ErrForbidden, fmt.Sprintf("the owner role is needed to share, you are %s", access.Role))
+	}
+	return nil
+}
+
+// token is the id of the link and its signature, which covers what the
+// link shows and when it was created so it cannot be pointed elsewhere.
+func (u *Usecase) token(link model.LinkModel) string {
+	return strconv.FormatInt(link.ID, 10) + "." + u.signature(link)
+}
+
+func (u *Usecase) signature(link model.LinkModel) string {
+	target := "task:" + strconv.FormatInt(deref(link.TaskID), 10)
+	if link.ListID != nil {
+		target = "list:" + strconv.FormatInt(*link.ListID, 10)
+	}
+	mac := hmac.New(sha256.New, u.key)
+	fmt.Fprintf(mac, "%d.%s.%d.%d", link.ID, target, link.UserID, link.CreatedAt.Unix())
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func deref(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -0,0 +1,65 @@
+package share
+
+import (
+	"context"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/share"
+	taskmodel "to-do-list/internal/model/task"
+)
+
+type ShareRepositoryMock struct {
+	CreateFunc  func(ctx context.Context, link model.LinkModel) (model.LinkModel, error)
+	GetAllFunc  func(ctx context.Context) ([]model.LinkModel, error)
+	GetByIDFunc func(ctx context.Context, id int64) (model.LinkModel, error)
+	DeleteFunc  func(ctx context.Context, id int64) error
+}
+
+func (repository *ShareRepositoryMock) Create(ctx context.Context, link model.LinkModel) (model.LinkModel, error) {
+	return repository.CreateFunc(ctx, link)
+}
+
+func (repository *ShareRepositoryMock) GetAll(ctx context.Context) ([]model.LinkModel, error) {
+	return repository.GetAllFunc(ctx)
+}
+
+func (repository *ShareRepositoryMock) GetByID(ctx context.Context, id int64) (model.LinkModel, error) {
+	return repository.GetByIDFunc(ctx, id)
+}
+
+func (repository *ShareRepositoryMock) Delete(ctx context.Context, id int64) error {
+	return repository.DeleteFunc(ctx, id)
+}
+
+type SharingMock struct {
+	ListAccessFunc func(ctx context.Context, id int64) (listmodel.Access, error)
+	TaskAccessFunc func(ctx context.Context, id int64) (listmodel.Access, error)
+}
+
+func (m *SharingMock) ListAccess(ctx context.Context, id int64) (listmodel.Access, error) {
+	return m.ListAccessFunc(ctx, id)
+}
+
+func (m *SharingMock) TaskAccess(ctx context.Context, id int64) (listmodel.Access, error) {
+	return m.TaskAccessFunc(ctx, id)
+}
+
+type ListsMock struct {
+	GetListFunc      func(ctx context.Context, id int64) (listmodel.ListModel, error)
+	GetListTasksFunc func(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error)
+}
+
+func (m *ListsMock) GetList(ctx context.Context, id int64) (listmodel.ListModel, error) {
+	return m.GetListFunc(ctx, id)
+}
+
+func (m *ListsMock) GetListTasks(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+	return m.GetListTasksFunc(ctx, id, filter)
+}
+
+type TasksMock struct {
+	GetTaskFunc func(ctx context.Context, id int64) (taskmodel.TaskModel, error)
+}
+
+func (m *TasksMock) GetTask(ctx context.Context, id int64) (taskmodel.TaskModel, error) {
+	return m.GetTaskFunc(ctx, id)
+}
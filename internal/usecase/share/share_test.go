@@ -0,0 +1,221 @@
+package share
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/share"
+	taskmodel "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now       = time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	createdAt = now.Add(-time.Hour)
+	alice     = int64(1)
+	aliceCtx  = requestinfo.WithUserID(context.Background(), alice)
+
+	// links are created in workspace 2, where list 4 and task 7 are
+	workspace = int64(2)
+)
+
+// owners answers that alice owns list 4 and task 7 and nothing else.
+func owners() *SharingMock {
+	access := func(ctx context.Context, owned bool) (listmodel.Access, error) {
+		if requestinfo.UserID(ctx) != alice || !owned {
+			return listmodel.Access{}, apperror.New(apperror.ErrNotFound, "list not found")
+		}
+		return listmodel.Access{OwnerID: alice, Role: listmodel.RoleOwner}, nil
+	}
+	return &SharingMock{
+		ListAccessFunc: func(ctx context.Context, id int64) (listmodel.Access, error) { return access(ctx, id == 4) },
+		TaskAccessFunc: func(ctx context.Context, id int64) (listmodel.Access, error) { return access(ctx, id == 7) },
+	}
+}
+
+func newUseCase(repo Repo, sharing Sharing) *Usecase {
+	u := NewUseCase(repo, sharing,
+		&ListsMock{
+			GetListFunc: func(ctx context.Context, id int64) (listmodel.ListModel, error) {
+				if requestinfo.WorkspaceID(ctx) != workspace {
+					return listmodel.ListModel{}, apperror.New(apperror.ErrNotFound, "list not found")
+				}
+				return listmodel.ListModel{ID: id, Name: "Work", Role: listmodel.RoleOwner}, nil
+			},
+			GetListTasksFunc: func(ctx context.Context, id int64, filter taskmodel.TaskFilter) (taskmodel.TaskPage, error) {
+				if requestinfo.WorkspaceID(ctx) != workspace {
+					return taskmodel.TaskPage{}, nil
+				}
+				return taskmodel.TaskPage{Tasks: []taskmodel.TaskModel{{ID: 7, TaskName: "Report", ListID: &id}}}, nil
+			},
+		},
+		&TasksMock{
+			GetTaskFunc: func(ctx context.Context, id int64) (taskmodel.TaskModel, error) {
+				if requestinfo.WorkspaceID(ctx) != workspace {
+					return taskmodel.TaskModel{}, apperror.New(apperror.ErrNotFound, "task not found")
+				}
+				return taskmodel.TaskModel{ID: id, TaskName: "Report"}, nil
+			},
+		},
+		[]byte("secret"))
+	u.now = func() time.Time { return now }
+	return u
+}
+
+func TestUseCase_CreateLink(t *testing.T) {
+	listID, taskID, otherID := int64(4), int64(7), int64(5)
+	past := now.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		request model.LinkRequest
+		wantErr error
+	}{
+		{name: "case 1 -> link to an owned list", request: model.LinkRequest{ListID: &listID}},
+		{name: "case 2 -> link to an owned task", request: model.LinkRequest{TaskID: &taskID}},
+		{name: "case 3 -> neither a list nor a task", request: model.LinkRequest{}, wantErr: apperror.ErrValidation},
+		{name: "case 4 -> both a list and a task", request: model.LinkRequest{ListID: &listID, TaskID: &taskID}, wantErr: apperror.ErrValidation},
+		{name: "case 5 -> already expired", request: model.LinkRequest{ListID: &listID, ExpiresAt: &past}, wantErr: apperror.ErrValidation},
+		{name: "case 6 -> list of another user", request: model.LinkRequest{ListID: &otherID}, wantErr: apperror.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ShareRepositoryMock{
+				CreateFunc: func(ctx context.Context, link model.LinkModel) (model.LinkModel, error) {
+					link.ID, link.UserID, link.CreatedAt = 3, requestinfo.UserID(ctx), createdAt
+					return link, nil
+				},
+			}
+
+			got, err := newUseCase(repo, owners()).CreateLink(aliceCtx, tt.request)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(got.URL, PathPrefix+"3."), got.URL)
+		})
+	}
+}
+
+func TestUseCase_GetShared(t *testing.T) {
+	listID, taskID := int64(4), int64(7)
+	expired := now.Add(-time.Minute)
+
+	listLink := model.LinkModel{ID: 3, ListID: &listID, UserID: alice, WorkspaceID: workspace, CreatedAt: createdAt}
+	taskLink := model.LinkModel{ID: 3, TaskID: &taskID, UserID: alice, WorkspaceID: workspace, CreatedAt: createdAt}
+	expiredLink := listLink
+	expiredLink.ExpiresAt = &expired
+
+	signer := newUseCase(nil, nil)
+
+	tests := []struct {
+		name    string
+		stored  model.LinkModel
+		token   string
+		sharing *SharingMock
+		want    model.SharedModel
+		wantErr error
+	}{
+		{
+			name:    "case 1 -> list link shows the list without the role of its creator",
+			stored:  listLink,
+			token:   signer.token(listLink),
+			sharing: owners(),
+			want:    model.SharedModel{List: &listmodel.ListModel{ID: 4, Name: "Work"}},
+		},
+		{
+			name:    "case 2 -> task link shows the task",
+			stored:  taskLink,
+			token:   signer.token(taskLink),
+			sharing: owners(),
+			want:    model.SharedModel{Task: &taskmodel.TaskModel{ID: 7, TaskName: "Report"}},
+		},
+		{
+			name:    "case 3 -> signature of a task link does not open a list",
+			stored:  listLink,
+			token:   signer.token(taskLink),
+			sharing: owners(),
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name:    "case 4 -> guessed token",
+			stored:  listLink,
+			token:   "3.abc",
+			sharing: owners(),
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name:    "case 5 -> expired link",
+			stored:  expiredLink,
+			token:   signer.token(expiredLink),
+			sharing: owners(),
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name:   "case 6 -> creator is no longer an owner of the list",
+			stored: listLink,
+			token:  signer.token(listLink),
+			sharing: &SharingMock{
+				ListAccessFunc: func(ctx context.Context, id int64) (listmodel.Access, error) {
+					return listmodel.Access{OwnerID: 2, Role: listmodel.RoleViewer}, nil
+				},
+			},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ShareRepositoryMock{
+				GetByIDFunc: func(ctx context.Context, id int64) (model.LinkModel, error) {
+					if id != tt.stored.ID {
+						return model.LinkModel{}, apperror.New(apperror.ErrNotFound, "share link not found")
+					}
+					return tt.stored, nil
+				},
+			}
+
+			// visitors are not signed in
+			got, err := newUseCase(repo, tt.sharing).GetShared(context.Background(), tt.token)
+
+			if tt.wantErr != nil {
+				// no failure tells whether the link exists
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.EqualError(t, err, "share link not found")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUseCase_GetSharedTasks(t *testing.T) {
+	listID, taskID := int64(4), int64(7)
+	taskLink := model.LinkModel{ID: 3, TaskID: &taskID, UserID: alice, WorkspaceID: workspace, CreatedAt: createdAt}
+	listLink := model.LinkModel{ID: 3, ListID: &listID, UserID: alice, WorkspaceID: workspace, CreatedAt: createdAt}
+
+	for _, link := range []model.LinkModel{listLink, taskLink} {
+		repo := &ShareRepositoryMock{
+			GetByIDFunc: func(ctx context.Context, id int64) (model.LinkModel, error) { return link, nil },
+		}
+		u := newUseCase(repo, owners())
+
+		page, err := u.GetSharedTasks(context.Background(), u.token(link), taskmodel.TaskFilter{})
+
+		if link.ListID == nil {
+			assert.ErrorIs(t, err, apperror.ErrNotFound, "a task link has no tasks to page through")
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Len(t, page.Tasks, 1)
+	}
+}
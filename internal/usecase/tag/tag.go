@@ -0,0 +1,56 @@
+package tag
+
+import (
+	"context"
+	"strings"
+	model "to-do-list/internal/model/tag"
+	"to-do-list/pkg/apperror"
+)
+
+type Usecase struct {
+	tagRepo Repo
+}
+
+func NewUseCase(repo Repo) *Usecase {
+	return &Usecase{
+		tagRepo: repo,
+	}
+}
+
+type Repo interface {
+	GetAll(ctx context.Context) ([]model.TagModel, error)
+	Create(ctx context.Context, tag model.TagModel) (model.TagModel, error)
+	Rename(ctx context.Context, tag model.TagModel) (model.TagModel, error)
+	Merge(ctx context.Context, from int64, into int64) (model.TagModel, error)
+	Delete(ctx context.Context, tag model.TagModel) error
+}
+
+func (u *Usecase) GetAllTag(ctx context.Context) ([]model.TagModel, error) {
+	return u.tagRepo.GetAll(ctx)
+}
+
+func (u *Usecase) CreateTag(ctx context.Context, r model.TagModel) (model.TagModel, error) {
+	r.Name = normalize(r.Name)
+	return u.tagRepo.Create(ctx, r)
+}
+
+func (u *Usecase) RenameTag(ctx context.Context, r model.TagModel) (model.TagModel, error) {
+	r.Name = normalize(r.Name)
+	return u.tagRepo.Rename(ctx, r)
+}
+
+func (u *Usecase) MergeTag(ctx context.Context, from int64, into int64) (model.TagModel, error) {
+	if from == into {
+		return model.TagModel{}, apperror.New(apperror.ErrValidation, "cannot merge a tag into itself")
+	}
+	return u.tagRepo.Merge(ctx, from, into)
+}
+
+func (u *Usecase) DeleteTag(ctx context.Context, r model.TagModel) error {
+	return u.tagRepo.Delete(ctx, r)
+}
+
+// normalize matches the task usecase, which stores tag names lower case.
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
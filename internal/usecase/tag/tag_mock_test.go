@@ -0,0 +1,34 @@
+package tag
+
+import (
+	"context"
+	model "to-do-list/internal/model/tag"
+)
+
+type TagRepositoryMock struct {
+	GetAllFunc func(ctx context.Context) ([]model.TagModel, error)
+	CreateFunc func(ctx context.Context, tag model.TagModel) (model.TagModel, error)
+	RenameFunc func(ctx context.Context, tag model.TagModel) (model.TagModel, error)
+	MergeFunc  func(ctx context.Context, from int64, into int64) (model.TagModel, error)
+	DeleteFunc func(ctx context.Context, tag model.TagModel) error
+}
+
+func (repository *TagRepositoryMock) GetAll(ctx context.Context) ([]model.TagModel, error) {
+	return repository.GetAllFunc(ctx)
+}
+
+func (repository *TagRepositoryMock) Create(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+	return repository.CreateFunc(ctx, tag)
+}
+
+func (repository *TagRepositoryMock) Rename(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+	return repository.RenameFunc(ctx, tag)
+}
+
+func (repository *TagRepositoryMock) Merge(ctx context.Context, from int64, into int64) (model.TagModel, error) {
+	return repository.MergeFunc(ctx, from, into)
+}
+
+func (repository *TagRepositoryMock) Delete(ctx context.Context, tag model.TagModel) error {
+	return repository.DeleteFunc(ctx, tag)
+}
@@ -0,0 +1,115 @@
+package tag
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/tag"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseCase_CreateTag(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		arg     model.TagModel
+		repo    *TagRepositoryMock
+		want    model.TagModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> name is normalized before it is stored",
+			arg:  model.TagModel{Name: "  Home "},
+			repo: &TagRepositoryMock{
+				CreateFunc: func(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+					tag.ID = 1
+					return tag, nil
+				},
+			},
+			want:    model.TagModel{ID: 1, Name: "home"},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> repo conflict is returned",
+			arg:  model.TagModel{Name: "home"},
+			repo: &TagRepositoryMock{
+				CreateFunc: func(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+					return tag, apperror.New(apperror.ErrConflict, "tag already exists")
+				},
+			},
+			want:    model.TagModel{Name: "home"},
+			wantErr: apperror.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewUseCase(tt.repo).CreateTag(ctx, tt.arg)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUseCase_RenameTag(t *testing.T) {
+	repo := &TagRepositoryMock{
+		RenameFunc: func(ctx context.Context, tag model.TagModel) (model.TagModel, error) {
+			return tag, nil
+		},
+	}
+
+	got, err := NewUseCase(repo).RenameTag(context.Background(), model.TagModel{ID: 1, Name: " WORK"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.TagModel{ID: 1, Name: "work"}, got)
+}
+
+func TestUseCase_MergeTag(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		from    int64
+		into    int64
+		want    model.TagModel
+		wantErr error
+	}{
+		{
+			name:    "case 1 -> merge into another tag",
+			from:    1,
+			into:    2,
+			want:    model.TagModel{ID: 2, Name: "house"},
+			wantErr: nil,
+		},
+		{
+			name:    "case 2 -> merge into itself is rejected",
+			from:    1,
+			into:    1,
+			want:    model.TagModel{},
+			wantErr: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &TagRepositoryMock{
+				MergeFunc: func(ctx context.Context, from int64, into int64) (model.TagModel, error) {
+					return model.TagModel{ID: into, Name: "house"}, nil
+				},
+			}
+			got, err := NewUseCase(repo).MergeTag(ctx, tt.from, tt.into)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// Batch checks the operations like the single task methods do and runs them
+// in one transaction. The error is only set when the batch could not run,
+// failed operations are reported in their result.
+func (u *Usecase) Batch(ctx context.Context, r model.BatchRequest) ([]model.BatchResult, error) {
+	switch r.Mode {
+	case "":
+		r.Mode = model.BatchAllOrNothing
+	case model.BatchAllOrNothing, model.BatchBestEffort:
+	default:
+		return nil, apperror.New(apperror.ErrValidation, "mode must be all_or_nothing or best_effort")
+	}
+	if len(r.Operations) == 0 {
+		return nil, apperror.New(apperror.ErrValidation, "operations is required")
+	}
+	if len(r.Operations) > model.MaxBatchOperations {
+		return nil, apperror.New(apperror.ErrValidation, fmt.Sprintf("a batch takes up to %d operations", model.MaxBatchOperations))
+	}
+
+	var (
+		opts    = model.BatchOptions{AllOrNothing: r.Mode == model.BatchAllOrNothing, BlockOpenSubtasks: u.blockOpenSubtasks}
+		results = make([]model.BatchResult, len(r.Operations))
+		ops     = []model.BatchOperation{}
+		index   = []int{}
+		failed  = false
+	)
+	for i, op := range r.Operations {
+		results[i] = model.BatchResult{Op: op.Op, ID: op.ID}
+		op, err := u.prepareOperation(op)
+		if err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+		ops = append(ops, op)
+		index = append(index, i)
+	}
+
+	if !(failed && opts.AllOrNothing) && len(ops) > 0 {
+		done, err := u.taskRepo.Batch(ctx, ops, opts, u.record(ctx, batchEvents))
+		if err != nil {
+			return nil, err
+		}
+		for i, result := range done {
+			if result.Err != nil {
+				failed = true
+			}
+			results[index[i]] = result
+		}
+	}
+
+	if failed && opts.AllOrNothing {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Task = nil
+				results[i].RolledBack = true
+			}
+		}
+		return results, nil
+	}
+
+	// the batch is saved, a failed repeat is logged
+	for i, result := range results {
+		if !completesRecurring(result) {
+			continue
+		}
+		task, err := u.repeat(ctx, *result.Task)
+		if err != nil {
+			fmt.Println("[Repeat] Next occurrence error :", err)
+			continue
+		}
+		results[i].Task = &task
+	}
+
+	return results, nil
+}
+
+func completesRecurring(result model.BatchResult) bool {
+	return result.Err == nil && result.Before != nil && !result.Before.IsDone &&
+		result.Task != nil && result.Task.IsDone && result.Task.RRule != ""
+}
+
+// batchEvents describes the operations that changed a task.
+func batchEvents(changes []model.Change) []model.TaskEvent {
+	return describeChanges(model.EventCreate, changes)
+}
+
+// prepareOperation applies the defaults and checks of the single task
+// methods to an operation.
+func (u *Usecase) prepareOperation(op model.BatchOperation) (model.BatchOperation, error) {
+	switch op.Op {
+	case model.BatchCreate, model.BatchUpdate:
+		if op.Task == nil {
+			return op, apperror.New(apperror.ErrValidation, "task is required")
+		}
+		if op.Op == model.BatchUpdate && op.ID == 0 {
+			return op, apperror.New(apperror.ErrValidation, "id is required")
+		}
+		task := *op.Task
+		if task.Timezone == "" {
+			task.Timezone = model.DefaultTimezone
+		}
+		task.Tags = NormalizeTags(task.Tags)
+		task.ParentID = nil
+		task = withRepeatDefaults(task)
+		if u.validate != nil {
+			if fields := u.validate(task); fields != nil {
+				return op, apperror.Wrap(apperror.ErrValidation, "Invalid Request Data", model.FieldErrors(fields))
+			}
+		}
+		op.Task = &task
+	case model.BatchDelete, model.BatchComplete:
+		if op.ID == 0 {
+			return op, apperror.New(apperror.ErrValidation, "id is required")
+		}
+	default:
+		return op, apperror.New(apperror.ErrValidation, "op must be create, update, delete or complete")
+	}
+	return op, nil
+}
@@ -0,0 +1,133 @@
+package task
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseCase_Batch(t *testing.T) {
+	ctx := context.Background()
+
+	validate := func(task model.TaskModel) []model.ErrorField {
+		if task.TaskName == "" {
+			return []model.ErrorField{{FieldName: "TaskName", Message: "TaskName is required"}}
+		}
+		return nil
+	}
+
+	// the mock repo applies every operation it gets
+	var got []model.BatchOperation
+	apply := func(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error) {
+		got = ops
+		results := make([]model.BatchResult, len(ops))
+		for i, op := range ops {
+			results[i] = model.BatchResult{Op: op.Op, ID: op.ID, Task: op.Task}
+		}
+		return results, nil
+	}
+
+	create := model.BatchOperation{Op: model.BatchCreate, Task: &model.TaskModel{TaskName: "task 1", Tags: []string{" Home"}}}
+	created := &model.TaskModel{TaskName: "task 1", Timezone: "UTC", Tags: []string{"home"}}
+	invalid := model.BatchOperation{Op: model.BatchCreate, Task: &model.TaskModel{}}
+
+	tests := []struct {
+		name     string
+		request  model.BatchRequest
+		batch    func(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error)
+		want     []model.BatchResult
+		wantOps  []model.BatchOperation
+		wantErrs []error
+		wantErr  error
+	}{
+		{
+			name:    "case 1 -> unknown mode",
+			request: model.BatchRequest{Mode: "some", Operations: []model.BatchOperation{create}},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 2 -> no operations",
+			request: model.BatchRequest{},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name: "case 3 -> all or nothing batch with an invalid operation does not run",
+			request: model.BatchRequest{Operations: []model.BatchOperation{
+				create,
+				invalid,
+			}},
+			want: []model.BatchResult{
+				{Op: model.BatchCreate, RolledBack: true},
+				{Op: model.BatchCreate},
+			},
+			wantErrs: []error{nil, apperror.ErrValidation},
+		},
+		{
+			name: "case 4 -> best effort batch runs the valid operations",
+			request: model.BatchRequest{Mode: model.BatchBestEffort, Operations: []model.BatchOperation{
+				{Op: "archive", ID: 1},
+				create,
+				{Op: model.BatchDelete},
+				{Op: model.BatchComplete, ID: 2},
+			}},
+			batch: apply,
+			want: []model.BatchResult{
+				{Op: "archive", ID: 1},
+				{Op: model.BatchCreate, Task: created},
+				{Op: model.BatchDelete},
+				{Op: model.BatchComplete, ID: 2},
+			},
+			wantOps: []model.BatchOperation{
+				{Op: model.BatchCreate, Task: created},
+				{Op: model.BatchComplete, ID: 2},
+			},
+			wantErrs: []error{apperror.ErrValidation, nil, apperror.ErrValidation, nil},
+		},
+		{
+			name: "case 5 -> failure in the repo rolls back the others",
+			request: model.BatchRequest{Mode: model.BatchAllOrNothing, Operations: []model.BatchOperation{
+				create,
+				{Op: model.BatchComplete, ID: 2},
+			}},
+			batch: func(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error) {
+				return []model.BatchResult{
+					{Op: model.BatchCreate, ID: 4, Task: &model.TaskModel{ID: 4}},
+					{Op: model.BatchComplete, ID: 2, Err: apperror.New(apperror.ErrNotFound, "task not found")},
+				}, nil
+			},
+			want: []model.BatchResult{
+				{Op: model.BatchCreate, ID: 4, RolledBack: true},
+				{Op: model.BatchComplete, ID: 2},
+			},
+			wantErrs: []error{nil, apperror.ErrNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			u := NewUseCase(&TaskRepositoryMock{BatchFunc: tt.batch}, WithValidator(validate))
+
+			result, err := u.Batch(ctx, tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			for i := range result {
+				if tt.wantErrs[i] == nil {
+					assert.NoError(t, result[i].Err)
+				} else {
+					assert.ErrorIs(t, result[i].Err, tt.wantErrs[i])
+				}
+				result[i].Err = nil
+			}
+			assert.Equal(t, tt.want, result)
+			assert.Equal(t, tt.wantOps, got)
+		})
+	}
+}
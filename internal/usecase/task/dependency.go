@@ -0,0 +1,176 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// AddDependency blocks a task by another one, unless the blocker already
+// waits for the task, directly or through other tasks.
+func (u *Usecase) AddDependency(ctx context.Context, dependency model.Dependency) error {
+	if dependency.TaskID == dependency.BlockedBy {
+		return apperror.New(apperror.ErrValidation, "a task cannot block itself")
+	}
+
+	// the blocker has to be a task of the same owner the user sees
+	ctx, err := u.forTask(ctx, dependency.TaskID, listmodel.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if _, err := u.forTask(ctx, dependency.BlockedBy, listmodel.RoleViewer); err != nil {
+		return err
+	}
+	for _, id := range []int64{dependency.TaskID, dependency.BlockedBy} {
+		if _, err := u.taskRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	// the repo checks the dependencies while no other request changes them
+	return u.taskRepo.AddDependency(ctx, dependency, func(dependencies []model.Dependency) error {
+		if path := findCycle(dependencies, dependency); path != nil {
+			return apperror.New(apperror.ErrConflict, fmt.Sprintf("dependency would create a cycle: %s", formatPath(path)))
+		}
+		return nil
+	})
+}
+
+func (u *Usecase) RemoveDependency(ctx context.Context, dependency model.Dependency) error {
+	ctx, err := u.forTask(ctx, dependency.TaskID, listmodel.RoleEditor)
+	if err != nil {
+		return err
+	}
+	return u.taskRepo.RemoveDependency(ctx, dependency)
+}
+
+// GetBlockers returns the tasks the task waits for, done or not.
+func (u *Usecase) GetBlockers(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	ctx, err := u.forTask(ctx, id, listmodel.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.taskRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.taskRepo.GetBlockers(ctx, id)
+}
+
+// Plan orders the open tasks so that every task comes after the open tasks
+// blocking it. Among the tasks that can be done next, the earliest due
+// comes first.
+func (u *Usecase) Plan(ctx context.Context) ([]model.TaskModel, error) {
+	tasks, err := u.taskRepo.GetOpenTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := u.taskRepo.GetDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[int64]model.TaskModel, len(tasks))
+	for _, task := range tasks {
+		open[task.ID] = task
+	}
+
+	// done and trashed blockers do not hold anything back
+	waiting := map[int64]int{}
+	blocks := map[int64][]int64{}
+	for _, dependency := range dependencies {
+		_, isOpen := open[dependency.TaskID]
+		_, blockerOpen := open[dependency.BlockedBy]
+		if !isOpen || !blockerOpen {
+			continue
+		}
+		waiting[dependency.TaskID]++
+		blocks[dependency.BlockedBy] = append(blocks[dependency.BlockedBy], dependency.TaskID)
+	}
+
+	ready := []model.TaskModel{}
+	for _, task := range tasks {
+		if waiting[task.ID] == 0 {
+			ready = append(ready, task)
+		}
+	}
+
+	plan := make([]model.TaskModel, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.SliceStable(ready, func(i, j int) bool {
+			return plansBefore(ready[i], ready[j])
+		})
+
+		task := ready[0]
+		ready = ready[1:]
+		plan = append(plan, task)
+
+		for _, id := range blocks[task.ID] {
+			waiting[id]--
+			if waiting[id] == 0 {
+				ready = append(ready, open[id])
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+func plansBefore(a, b model.TaskModel) bool {
+	switch {
+	case a.DueAt != nil && b.DueAt != nil && !a.DueAt.Equal(*b.DueAt):
+		return a.DueAt.Before(*b.DueAt)
+	case a.DueAt != nil && b.DueAt == nil:
+		return true
+	case a.DueAt == nil && b.DueAt != nil:
+		return false
+	}
+	return a.ID < b.ID
+}
+
+// findCycle returns the path from the task back to itself that adding the
+// dependency would close, or nil when there is none.
+func findCycle(dependencies []model.Dependency, dependency model.Dependency) []int64 {
+	blockedBy := map[int64][]int64{}
+	for _, d := range dependencies {
+		blockedBy[d.TaskID] = append(blockedBy[d.TaskID], d.BlockedBy)
+	}
+
+	// the new edge makes TaskID wait for BlockedBy, so a loop exists when
+	// BlockedBy already waits for TaskID
+	visited := map[int64]bool{}
+	var walk func(id int64) []int64
+	walk = func(id int64) []int64 {
+		if id == dependency.TaskID {
+			return []int64{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+		for _, next := range blockedBy[id] {
+			if path := walk(next); path != nil {
+				return append([]int64{id}, path...)
+			}
+		}
+		return nil
+	}
+
+	path := walk(dependency.BlockedBy)
+	if path == nil {
+		return nil
+	}
+	return append([]int64{dependency.TaskID}, path...)
+}
+
+func formatPath(path []int64) string {
+	ids := make([]string, len(path))
+	for i, id := range path {
+		ids[i] = fmt.Sprint(id)
+	}
+	return strings.Join(ids, " -> ")
+}
@@ -0,0 +1,146 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dependencyRepository(dependencies []model.Dependency, added *[]model.Dependency) *TaskRepositoryMock {
+	return &TaskRepositoryMock{
+		GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+			if id > 5 {
+				return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "task not found")
+			}
+			return model.TaskModel{ID: id}, nil
+		},
+		AddDependencyFunc: func(ctx context.Context, dependency model.Dependency, check func([]model.Dependency) error) error {
+			if err := check(dependencies); err != nil {
+				return err
+			}
+			*added = append(*added, dependency)
+			return nil
+		},
+	}
+}
+
+func TestUseCase_AddDependency(t *testing.T) {
+	ctx := context.Background()
+
+	// 3 waits for 2, which waits for 1
+	dependencies := []model.Dependency{{TaskID: 2, BlockedBy: 1}, {TaskID: 3, BlockedBy: 2}}
+
+	tests := []struct {
+		name       string
+		dependency model.Dependency
+		wantErr    error
+		wantMsg    string
+	}{
+		{
+			name:       "case 1 -> add dependency",
+			dependency: model.Dependency{TaskID: 4, BlockedBy: 3},
+		},
+		{
+			name:       "case 2 -> add existing dependency",
+			dependency: model.Dependency{TaskID: 3, BlockedBy: 2},
+		},
+		{
+			name:       "case 3 -> task blocking itself",
+			dependency: model.Dependency{TaskID: 2, BlockedBy: 2},
+			wantErr:    apperror.ErrValidation,
+			wantMsg:    "a task cannot block itself",
+		},
+		{
+			name:       "case 4 -> blocker not found",
+			dependency: model.Dependency{TaskID: 2, BlockedBy: 9},
+			wantErr:    apperror.ErrNotFound,
+			wantMsg:    "task not found",
+		},
+		{
+			name:       "case 5 -> direct cycle",
+			dependency: model.Dependency{TaskID: 1, BlockedBy: 2},
+			wantErr:    apperror.ErrConflict,
+			wantMsg:    "dependency would create a cycle: 1 -> 2 -> 1",
+		},
+		{
+			name:       "case 6 -> cycle through another task",
+			dependency: model.Dependency{TaskID: 1, BlockedBy: 3},
+			wantErr:    apperror.ErrConflict,
+			wantMsg:    "dependency would create a cycle: 1 -> 3 -> 2 -> 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added := []model.Dependency{}
+			repo := dependencyRepository(dependencies, &added)
+
+			err := NewUseCase(repo).AddDependency(ctx, tt.dependency)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.EqualError(t, err, tt.wantMsg)
+				assert.Empty(t, added)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []model.Dependency{tt.dependency}, added)
+			}
+		})
+	}
+}
+
+func TestUseCase_Plan(t *testing.T) {
+	ctx := context.Background()
+	early := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	late := time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		tasks        []model.TaskModel
+		dependencies []model.Dependency
+		want         []int64
+	}{
+		{
+			name:  "case 1 -> no dependencies sorts by due date then id",
+			tasks: []model.TaskModel{{ID: 1}, {ID: 2, DueAt: &late}, {ID: 3, DueAt: &early}},
+			want:  []int64{3, 2, 1},
+		},
+		{
+			name:         "case 2 -> blockers come first",
+			tasks:        []model.TaskModel{{ID: 1}, {ID: 2, DueAt: &early}, {ID: 3, DueAt: &late}},
+			dependencies: []model.Dependency{{TaskID: 2, BlockedBy: 1}, {TaskID: 3, BlockedBy: 2}},
+			want:         []int64{1, 2, 3},
+		},
+		{
+			name:         "case 3 -> done blockers are ignored",
+			tasks:        []model.TaskModel{{ID: 2}, {ID: 3, DueAt: &early}},
+			dependencies: []model.Dependency{{TaskID: 3, BlockedBy: 1}},
+			want:         []int64{3, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &TaskRepositoryMock{
+				GetOpenTasksFunc: func(ctx context.Context) ([]model.TaskModel, error) {
+					return tt.tasks, nil
+				},
+				GetDependenciesFunc: func(ctx context.Context) ([]model.Dependency, error) {
+					return tt.dependencies, nil
+				},
+			}
+
+			result, err := NewUseCase(repo).Plan(ctx)
+			assert.NoError(t, err)
+
+			ids := []int64{}
+			for _, task := range result {
+				ids = append(ids, task.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package task
+
+import (
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+// resolveDue turns the relative Due window of a filter into absolute bounds
+// using calendar days of the filter timezone, so "today" follows the
+// client's midnight and DST shifts rather than UTC's.
+func resolveDue(filter model.TaskFilter) (model.TaskFilter, error) {
+	if filter.Due == "" {
+		return filter, nil
+	}
+
+	if filter.Timezone == "" {
+		filter.Timezone = model.DefaultTimezone
+	}
+	loc, err := time.LoadLocation(filter.Timezone)
+	if err != nil {
+		return filter, apperror.Wrap(apperror.ErrValidation, "unknown timezone "+filter.Timezone, err)
+	}
+
+	var (
+		current  = now().In(loc)
+		midnight = time.Date(current.Year(), current.Month(), current.Day(), 0, 0, 0, 0, loc)
+		from, to time.Time
+	)
+
+	switch filter.Due {
+	case model.DueOverdue:
+		// minute precision keeps the cached page reusable for a while
+		to = current.Truncate(time.Minute)
+		filter.DueTo = &to
+		if filter.IsDone == nil {
+			open := false
+			filter.IsDone = &open
+		}
+		return filter, nil
+	case model.DueToday:
+		from = midnight
+		to = time.Date(current.Year(), current.Month(), current.Day()+1, 0, 0, 0, 0, loc)
+	case model.DueWeek:
+		// weeks start on Monday
+		offset := (int(current.Weekday()) + 6) % 7
+		from = time.Date(current.Year(), current.Month(), current.Day()-offset, 0, 0, 0, 0, loc)
+		to = time.Date(current.Year(), current.Month(), current.Day()-offset+7, 0, 0, 0, 0, loc)
+	default:
+		return filter, apperror.New(apperror.ErrValidation, "unknown due window "+filter.Due)
+	}
+
+	filter.DueFrom = &from
+	filter.DueTo = &to
+	return filter, nil
+}
@@ -0,0 +1,111 @@
+package task
+
+import (
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveDue(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	open := false
+
+	date := func(loc *time.Location, y int, m time.Month, d, h, min int) *time.Time {
+		t := time.Date(y, m, d, h, min, 0, 0, loc)
+		return &t
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		filter  model.TaskFilter
+		want    model.TaskFilter
+		wantErr error
+	}{
+		{
+			name:   "case 1 -> no due window",
+			now:    time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC),
+			filter: model.TaskFilter{Limit: 10},
+			want:   model.TaskFilter{Limit: 10},
+		},
+		{
+			name:   "case 2 -> overdue only open tasks",
+			now:    time.Date(2026, 3, 8, 12, 30, 45, 0, time.UTC),
+			filter: model.TaskFilter{Due: model.DueOverdue},
+			want: model.TaskFilter{
+				Due:      model.DueOverdue,
+				Timezone: "UTC",
+				IsDone:   &open,
+				DueTo:    date(time.UTC, 2026, 3, 8, 12, 30),
+			},
+		},
+		{
+			name:   "case 3 -> today follows the client midnight",
+			now:    time.Date(2026, 3, 8, 20, 0, 0, 0, time.UTC),
+			filter: model.TaskFilter{Due: model.DueToday, Timezone: "Asia/Jakarta"},
+			want: model.TaskFilter{
+				Due:      model.DueToday,
+				Timezone: "Asia/Jakarta",
+				DueFrom:  date(jakarta, 2026, 3, 9, 0, 0),
+				DueTo:    date(jakarta, 2026, 3, 10, 0, 0),
+			},
+		},
+		{
+			name:   "case 4 -> today is 23 hours long when DST starts",
+			now:    time.Date(2026, 3, 8, 15, 0, 0, 0, time.UTC),
+			filter: model.TaskFilter{Due: model.DueToday, Timezone: "America/New_York"},
+			want: model.TaskFilter{
+				Due:      model.DueToday,
+				Timezone: "America/New_York",
+				DueFrom:  date(newYork, 2026, 3, 8, 0, 0),
+				DueTo:    date(newYork, 2026, 3, 9, 0, 0),
+			},
+		},
+		{
+			name:   "case 5 -> week runs from Monday to Monday",
+			now:    time.Date(2026, 3, 8, 15, 0, 0, 0, time.UTC),
+			filter: model.TaskFilter{Due: model.DueWeek, Timezone: "America/New_York"},
+			want: model.TaskFilter{
+				Due:      model.DueWeek,
+				Timezone: "America/New_York",
+				DueFrom:  date(newYork, 2026, 3, 2, 0, 0),
+				DueTo:    date(newYork, 2026, 3, 9, 0, 0),
+			},
+		},
+		{
+			name:    "case 6 -> unknown timezone",
+			now:     time.Date(2026, 3, 8, 15, 0, 0, 0, time.UTC),
+			filter:  model.TaskFilter{Due: model.DueToday, Timezone: "Mars/Olympus"},
+			wantErr: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = func() time.Time { return tt.now }
+			defer func() { now = time.Now }()
+
+			got, err := resolveDue(tt.filter)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("case 7 -> DST day spans 23 hours", func(t *testing.T) {
+		now = func() time.Time { return time.Date(2026, 3, 8, 15, 0, 0, 0, time.UTC) }
+		defer func() { now = time.Now }()
+
+		got, err := resolveDue(model.TaskFilter{Due: model.DueToday, Timezone: "America/New_York"})
+		assert.NoError(t, err)
+		assert.Equal(t, 23*time.Hour, got.DueTo.Sub(*got.DueFrom))
+	})
+}
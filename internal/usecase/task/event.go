@@ -0,0 +1,127 @@
+package task
+
+import (
+	"context"
+	"errors"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// EventRepo reads the audit log. Events are only ever appended, by the
+// writes of Repo that take a model.Record.
+type EventRepo interface {
+	GetEvents(ctx context.Context, filter model.EventFilter) (model.EventPage, error)
+	GetUndoableEvents(ctx context.Context, user int64) ([]model.TaskEvent, error)
+	GetSnapshot(ctx context.Context, id, version int64) (model.TaskModel, error)
+}
+
+// Every create, update, delete and restore made through the Usecase is
+// recorded in the transaction that makes it, with the actor and request id
+// of ctx: a change whose events cannot be saved fails. Changes
+// that follow from a recorded one are not recorded on their own: subtasks
+// moving or going to the trash with their parent, and status roll-ups to
+// the parent.
+
+// GetHistory returns the events of one task, newest first. The history
+// outlives the task.
+func (u *Usecase) GetHistory(ctx context.Context, id int64, filter model.EventFilter) (model.EventPage, error) {
+	// once the task is gone only its owner reads the history
+	ctx, err := u.forTask(ctx, id, listmodel.RoleViewer)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return model.EventPage{}, err
+	}
+	filter.TaskID = &id
+	return u.GetAudit(ctx, filter)
+}
+
+// GetAudit returns the events of every task, newest first.
+func (u *Usecase) GetAudit(ctx context.Context, filter model.EventFilter) (model.EventPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = model.DefaultPageLimit
+	}
+	if filter.Limit > model.MaxPageLimit {
+		filter.Limit = model.MaxPageLimit
+	}
+	if u.events == nil {
+		return model.EventPage{Events: []model.TaskEvent{}}, nil
+	}
+	return u.events.GetEvents(ctx, filter)
+}
+
+// RecordListDelete returns the Record of deleting a list in ctx: a cascade
+// deletes the tasks of the list, a reassign moves them to another list.
+// Subtasks follow their parent.
+func (u *Usecase) RecordListDelete(ctx context.Context) model.Record {
+	return u.record(ctx, func(changes []model.Change) []model.TaskEvent {
+		events := []model.TaskEvent{}
+		for _, change := range changes {
+			if change.Before.ParentID != nil {
+				continue
+			}
+			if change.After == nil {
+				events = append(events, newEvent(model.EventDelete, change.Before, nil))
+			} else {
+				events = append(events, newEvent(model.EventUpdate, change.Before, change.After))
+			}
+		}
+		return events
+	})
+}
+
+func newEvent(action string, before, after *model.TaskModel) model.TaskEvent {
+	event := model.TaskEvent{Action: action, Before: before, After: after}
+	if after != nil {
+		event.TaskID = after.ID
+	} else if before != nil {
+		event.TaskID = before.ID
+	}
+	return event
+}
+
+// record returns the Record of a write made in ctx, which describe turns
+// into events with the actor and request id of ctx. It is nil without an
+// audit log.
+func (u *Usecase) record(ctx context.Context, describe func(changes []model.Change) []model.TaskEvent) model.Record {
+	if u.events == nil {
+		return nil
+	}
+
+	return func(changes []model.Change) []model.TaskEvent {
+		events := describe(changes)
+		for i := range events {
+			events[i].Actor = requestinfo.Actor(ctx)
+			events[i].RequestID = requestinfo.RequestID(ctx)
+		}
+		return events
+	}
+}
+
+// as describes every change as action.
+func as(action string) func(changes []model.Change) []model.TaskEvent {
+	return func(changes []model.Change) []model.TaskEvent {
+		events := make([]model.TaskEvent, len(changes))
+		for i, change := range changes {
+			events[i] = newEvent(action, change.Before, change.After)
+		}
+		return events
+	}
+}
+
+// describeChanges describes each change by what it did to its task. A task
+// that was not there before arrived by the action arrived.
+func describeChanges(arrived string, changes []model.Change) []model.TaskEvent {
+	events := make([]model.TaskEvent, len(changes))
+	for i, change := range changes {
+		switch {
+		case change.After == nil:
+			events[i] = newEvent(model.EventDelete, change.Before, nil)
+		case change.Before == nil:
+			events[i] = newEvent(arrived, nil, change.After)
+		default:
+			events[i] = newEvent(model.EventUpdate, change.Before, change.After)
+		}
+	}
+	return events
+}
@@ -0,0 +1,203 @@
+package task
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseCase_RecordsEvents(t *testing.T) {
+	ctx := requestinfo.WithRequestID(requestinfo.WithActor(context.Background(), "alice"), "req-1")
+	parentID := int64(1)
+
+	tests := []struct {
+		name   string
+		change func(u *Usecase) error
+		want   []model.TaskEvent
+	}{
+		{
+			name: "case 1 -> create",
+			change: func(u *Usecase) error {
+				_, err := u.CreateTask(ctx, model.TaskModel{TaskName: "task 3"})
+				return err
+			},
+			want: []model.TaskEvent{
+				{TaskID: 3, Action: model.EventCreate, After: &model.TaskModel{ID: 3, TaskName: "task 3", Timezone: "UTC", Version: 1}, Actor: "alice", RequestID: "req-1"},
+			},
+		},
+		{
+			name: "case 2 -> update with the task before and after",
+			change: func(u *Usecase) error {
+				_, err := u.UpdateTask(ctx, model.TaskModel{ID: 1, TaskName: "task one", IsDone: true})
+				return err
+			},
+			want: []model.TaskEvent{
+				{TaskID: 1, Action: model.EventUpdate, Before: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}, After: &model.TaskModel{ID: 1, TaskName: "task one", IsDone: true, Timezone: "UTC", Version: 2}, Actor: "alice", RequestID: "req-1"},
+			},
+		},
+		{
+			name: "case 3 -> completing a subtask does not record the roll-up",
+			change: func(u *Usecase) error {
+				_, err := u.SetTaskDone(ctx, 2, true)
+				return err
+			},
+			want: []model.TaskEvent{
+				{TaskID: 2, Action: model.EventUpdate, Before: &model.TaskModel{ID: 2, TaskName: "step 1", ParentID: &parentID, Version: 1}, After: &model.TaskModel{ID: 2, TaskName: "step 1", IsDone: true, ParentID: &parentID, Version: 2}, Actor: "alice", RequestID: "req-1"},
+			},
+		},
+		{
+			name: "case 4 -> delete",
+			change: func(u *Usecase) error {
+				return u.DeleteTask(ctx, model.TaskModel{ID: 1})
+			},
+			want: []model.TaskEvent{
+				{TaskID: 1, Action: model.EventDelete, Before: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}, Actor: "alice", RequestID: "req-1"},
+			},
+		},
+		{
+			name: "case 5 -> failed change records nothing",
+			change: func(u *Usecase) error {
+				_, err := u.UpdateTask(ctx, model.TaskModel{ID: 1, TaskName: "task one", Version: 5})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := map[int64]*model.TaskModel{
+				1: {ID: 1, TaskName: "task 1", Version: 1},
+				2: {ID: 2, TaskName: "step 1", ParentID: &parentID, Version: 1},
+			}
+			repo := subtaskRepository(tasks)
+			var got []model.TaskEvent
+			// the writes record their changes as a repo does, before they commit
+			write := func(record model.Record, before, after *model.TaskModel) {
+				if record != nil {
+					got = append(got, record([]model.Change{{Before: before, After: after}})...)
+				}
+			}
+			repo.CreateFunc = func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+				task.ID = 3
+				task.Version = 1
+				write(record, nil, &task)
+				return task, nil
+			}
+			repo.UpdateFunc = func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+				before := *tasks[task.ID]
+				task.Version = before.Version + 1
+				write(record, &before, &task)
+				return task, nil
+			}
+			repo.SetDoneFunc = func(ctx context.Context, id int64, done bool, record model.Record) error {
+				before := *tasks[id]
+				tasks[id].IsDone = done
+				tasks[id].Version++
+				after := *tasks[id]
+				write(record, &before, &after)
+				return nil
+			}
+			repo.DeleteFunc = func(ctx context.Context, task model.TaskModel, record model.Record) error {
+				before := *tasks[task.ID]
+				write(record, &before, nil)
+				return nil
+			}
+
+			err := tt.change(NewUseCase(repo, WithEvents(&EventRepositoryMock{})))
+			if tt.want == nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUseCase_RecordFailureFailsTheChange(t *testing.T) {
+	repo := &TaskRepositoryMock{CreateFunc: func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+		// the events go in the transaction of the task, which rolls back
+		record([]model.Change{{After: &task}})
+		return model.TaskModel{}, apperror.New(apperror.ErrUnavailable, "record task event")
+	}}
+
+	_, err := NewUseCase(repo, WithEvents(&EventRepositoryMock{})).CreateTask(context.Background(), model.TaskModel{TaskName: "task 1"})
+
+	assert.ErrorIs(t, err, apperror.ErrUnavailable)
+}
+
+func TestUseCase_NoRecordWithoutEvents(t *testing.T) {
+	var got model.Record
+	repo := &TaskRepositoryMock{CreateFunc: func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+		got = record
+		return task, nil
+	}}
+
+	_, err := NewUseCase(repo).CreateTask(context.Background(), model.TaskModel{TaskName: "task 1"})
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestUseCase_RecordListDelete(t *testing.T) {
+	ctx := requestinfo.WithRequestID(requestinfo.WithActor(context.Background(), "alice"), "req-1")
+	listID, to, parentID := int64(3), int64(4), int64(1)
+
+	// the tasks of the list, a subtask follows its parent
+	tasks := []model.TaskModel{
+		{ID: 1, TaskName: "task 1", ListID: &listID, Version: 1},
+		{ID: 2, TaskName: "step 1", ListID: &listID, ParentID: &parentID, Version: 1},
+	}
+	moved := []model.TaskModel{
+		{ID: 1, TaskName: "task 1", ListID: &to, Version: 2},
+		{ID: 2, TaskName: "step 1", ListID: &to, ParentID: &parentID, Version: 2},
+	}
+
+	tests := []struct {
+		name    string
+		changes []model.Change
+		want    []model.TaskEvent
+	}{
+		{
+			name:    "case 1 -> cascade deletes the tasks",
+			changes: []model.Change{{Before: &tasks[0]}, {Before: &tasks[1]}},
+			want: []model.TaskEvent{
+				{TaskID: 1, Action: model.EventDelete, Before: &tasks[0], Actor: "alice", RequestID: "req-1"},
+			},
+		},
+		{
+			name:    "case 2 -> reassign moves the tasks to another list",
+			changes: []model.Change{{Before: &tasks[0], After: &moved[0]}, {Before: &tasks[1], After: &moved[1]}},
+			want: []model.TaskEvent{
+				{TaskID: 1, Action: model.EventUpdate, Before: &tasks[0], After: &moved[0], Actor: "alice", RequestID: "req-1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := NewUseCase(&TaskRepositoryMock{}, WithEvents(&EventRepositoryMock{})).RecordListDelete(ctx)
+
+			assert.Equal(t, tt.want, record(tt.changes))
+		})
+	}
+}
+
+func TestUseCase_GetHistory(t *testing.T) {
+	var got model.EventFilter
+	events := &EventRepositoryMock{GetEventsFunc: func(ctx context.Context, filter model.EventFilter) (model.EventPage, error) {
+		got = filter
+		return model.EventPage{Events: []model.TaskEvent{}}, nil
+	}}
+	u := NewUseCase(&TaskRepositoryMock{}, WithEvents(events))
+
+	_, err := u.GetHistory(context.Background(), 4, model.EventFilter{Limit: 500, Actor: "alice"})
+
+	taskID := int64(4)
+	assert.NoError(t, err)
+	assert.Equal(t, model.EventFilter{TaskID: &taskID, Actor: "alice", Limit: model.MaxPageLimit}, got)
+}
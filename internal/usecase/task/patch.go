@@ -0,0 +1,63 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/jsonpatch"
+)
+
+// PatchTask applies the patch to the stored task and saves the result like
+// UpdateTask, so only the fields the patch touches change. id, parent_id,
+// position, rank and version cannot be patched. The result is only saved over
+// the version the patch was applied to, which has to be version unless
+// that is 0.
+func (u *Usecase) PatchTask(ctx context.Context, id int64, version int64, patch model.TaskPatch) (model.TaskModel, error) {
+	scopedCtx, err := u.forTask(ctx, id, listmodel.RoleEditor)
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+	current, err := u.taskRepo.GetByID(scopedCtx, id)
+	if err != nil {
+		return current, err
+	}
+	if version != 0 && version != current.Version {
+		return current, apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+	}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return current, err
+	}
+
+	patched, err := patch.Apply(doc)
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return current, apperror.Wrap(apperror.ErrConflict, "task does not match the patch test", err)
+	}
+	if err != nil {
+		return current, apperror.Wrap(apperror.ErrValidation, "invalid patch: "+err.Error(), err)
+	}
+
+	var task model.TaskModel
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&task); err != nil {
+		return current, apperror.Wrap(apperror.ErrValidation, "invalid patched task: "+err.Error(), err)
+	}
+
+	if task.ID != current.ID || !sameID(task.ParentID, current.ParentID) || task.Position != current.Position || task.Rank != current.Rank || task.Version != current.Version {
+		return current, apperror.New(apperror.ErrValidation, "id, parent_id, position, rank and version cannot be patched")
+	}
+
+	if u.validate != nil {
+		if fields := u.validate(task); fields != nil {
+			return current, apperror.Wrap(apperror.ErrValidation, "Invalid Request Data", model.FieldErrors(fields))
+		}
+	}
+
+	return u.UpdateTask(ctx, task)
+}
@@ -0,0 +1,166 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseCase_PatchTask(t *testing.T) {
+	ctx := context.Background()
+
+	validate := func(task model.TaskModel) []model.ErrorField {
+		if task.TaskName == "" {
+			return []model.ErrorField{{FieldName: "TaskName", Message: "TaskName is required"}}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		version int64
+		patch   model.TaskPatch
+		want    model.TaskModel
+		wantErr error
+	}{
+		{
+			name:  "case 1 -> merge patch only changes the given fields",
+			patch: model.MergePatch(`{"is_done":true}`),
+			want: model.TaskModel{
+				ID:       1,
+				TaskName: "task 1",
+				IsDone:   true,
+				Timezone: "Asia/Jakarta",
+				Priority: model.PriorityHigh,
+				Tags:     []string{"home"},
+				Version:  3,
+			},
+		},
+		{
+			name:  "case 2 -> merge patch null removes a field",
+			patch: model.MergePatch(`{"tags":null,"priority":"low"}`),
+			want: model.TaskModel{
+				ID:       1,
+				TaskName: "task 1",
+				Timezone: "Asia/Jakarta",
+				Priority: model.PriorityLow,
+				Version:  3,
+			},
+		},
+		{
+			name: "case 3 -> json patch",
+			patch: model.JSONPatch{
+				{Op: "add", Path: "/tags/-", Value: raw(`"Work"`)},
+				{Op: "replace", Path: "/task_name", Value: raw(`"renamed"`)},
+			},
+			want: model.TaskModel{
+				ID:       1,
+				TaskName: "renamed",
+				Timezone: "Asia/Jakarta",
+				Priority: model.PriorityHigh,
+				Tags:     []string{"home", "work"},
+				Version:  3,
+			},
+		},
+		{
+			name:    "case 4 -> json patch test does not match",
+			patch:   model.JSONPatch{{Op: "test", Path: "/is_done", Value: raw(`true`)}},
+			wantErr: apperror.ErrConflict,
+		},
+		{
+			name:    "case 5 -> patched task fails validation",
+			patch:   model.MergePatch(`{"task_name":""}`),
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 6 -> id cannot be patched",
+			patch:   model.MergePatch(`{"id":2}`),
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 7 -> unknown field",
+			patch:   model.MergePatch(`{"owner":"me"}`),
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 8 -> invalid priority",
+			patch:   model.MergePatch(`{"priority":"urgent"}`),
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 9 -> patch at the current version",
+			version: 3,
+			patch:   model.MergePatch(`{"task_name":"renamed"}`),
+			want: model.TaskModel{
+				ID:       1,
+				TaskName: "renamed",
+				Timezone: "Asia/Jakarta",
+				Priority: model.PriorityHigh,
+				Tags:     []string{"home"},
+				Version:  3,
+			},
+		},
+		{
+			name:    "case 10 -> task changed since the expected version",
+			version: 2,
+			patch:   model.MergePatch(`{"task_name":"renamed"}`),
+			wantErr: apperror.ErrPreconditionFailed,
+		},
+		{
+			name:    "case 11 -> version cannot be patched",
+			patch:   model.MergePatch(`{"version":7}`),
+			wantErr: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &TaskRepositoryMock{
+				GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{
+						ID:       id,
+						TaskName: "task 1",
+						Timezone: "Asia/Jakarta",
+						Priority: model.PriorityHigh,
+						Tags:     []string{"home"},
+						Version:  3,
+					}, nil
+				},
+				UpdateFunc: func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+					return task, nil
+				},
+			}
+			u := NewUseCase(repo, WithValidator(validate))
+
+			got, err := u.PatchTask(ctx, 1, tt.version, tt.patch)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("case 12 -> validation errors carry the fields", func(t *testing.T) {
+		repo := &TaskRepositoryMock{
+			GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+				return model.TaskModel{ID: id, TaskName: "task 1"}, nil
+			},
+		}
+		_, err := NewUseCase(repo, WithValidator(validate)).PatchTask(ctx, 1, 0, model.MergePatch(`{"task_name":null}`))
+
+		var fields model.FieldErrors
+		assert.ErrorAs(t, err, &fields)
+		assert.Equal(t, model.FieldErrors{{FieldName: "TaskName", Message: "TaskName is required"}}, fields)
+	})
+}
+
+func raw(s string) *json.RawMessage {
+	v := json.RawMessage(s)
+	return &v
+}
@@ -0,0 +1,51 @@
+package task
+
+import (
+	"context"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// RepositionTask moves a top level task right before or right after another
+// top level task of the manual order. Subtasks have ReorderSubtasks.
+func (u *Usecase) RepositionTask(ctx context.Context, id int64, place model.RepositionRequest) (model.TaskModel, error) {
+	anchor := place.Before
+	if place.After != nil {
+		anchor = place.After
+	}
+	if anchor == nil || (place.Before != nil && place.After != nil) {
+		return model.TaskModel{}, apperror.New(apperror.ErrValidation, "exactly one of before and after is required")
+	}
+	if *anchor == id {
+		return model.TaskModel{}, apperror.New(apperror.ErrValidation, "a task cannot move next to itself")
+	}
+
+	ctx, err := u.forTask(ctx, id, listmodel.RoleEditor)
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+	current, err := u.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return current, err
+	}
+	if current.ParentID != nil {
+		return current, apperror.New(apperror.ErrValidation, "subtasks are ordered within their parent")
+	}
+
+	other, err := u.taskRepo.GetByID(ctx, *anchor)
+	if err != nil {
+		return current, err
+	}
+	if other.ParentID != nil {
+		return current, apperror.New(apperror.ErrValidation, "a task can only move next to a top level task")
+	}
+
+	return u.taskRepo.Reposition(ctx, id, place, u.record(ctx, as(model.EventUpdate)))
+}
+
+// RebalanceRanks spreads the keys of the manual order again when moves made
+// them too long, and returns how many tasks got a new key.
+func (u *Usecase) RebalanceRanks(ctx context.Context) (int64, error) {
+	return u.taskRepo.Rebalance(ctx)
+}
@@ -0,0 +1,94 @@
+package task
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseCase_RepositionTask(t *testing.T) {
+	ctx := context.Background()
+	parentID := int64(1)
+	one, two, three, nine := int64(1), int64(2), int64(3), int64(9)
+
+	tests := []struct {
+		name    string
+		id      int64
+		place   model.RepositionRequest
+		want    model.TaskModel
+		wantErr error
+	}{
+		{
+			name:  "case 1 -> move after a task",
+			id:    1,
+			place: model.RepositionRequest{After: &three},
+			want:  model.TaskModel{ID: 1, TaskName: "task 1", Rank: "s", Version: 2},
+		},
+		{
+			name:    "case 2 -> neither before nor after",
+			id:      1,
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 3 -> both before and after",
+			id:      1,
+			place:   model.RepositionRequest{Before: &three, After: &three},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 4 -> next to itself",
+			id:      1,
+			place:   model.RepositionRequest{Before: &one},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 5 -> subtask",
+			id:      2,
+			place:   model.RepositionRequest{Before: &three},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 6 -> next to a subtask",
+			id:      3,
+			place:   model.RepositionRequest{After: &two},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 7 -> anchor not found",
+			id:      1,
+			place:   model.RepositionRequest{Before: &nine},
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := map[int64]*model.TaskModel{
+				1: {ID: 1, TaskName: "task 1", Rank: "V", Version: 1},
+				2: {ID: 2, TaskName: "step 1", ParentID: &parentID, Rank: "W", Version: 1},
+				3: {ID: 3, TaskName: "task 3", Rank: "k", Version: 1},
+			}
+			repo := subtaskRepository(tasks)
+			repositioned := false
+			repo.RepositionFunc = func(ctx context.Context, id int64, place model.RepositionRequest, record model.Record) (model.TaskModel, error) {
+				repositioned = true
+				task := *tasks[id]
+				task.Rank = "s"
+				task.Version++
+				return task, nil
+			}
+
+			result, err := NewUseCase(repo).RepositionTask(ctx, tt.id, tt.place)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, repositioned)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
+			}
+		})
+	}
+}
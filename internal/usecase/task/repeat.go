@@ -0,0 +1,113 @@
+package task
+
+import (
+	"context"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/rrule"
+)
+
+// Completing a task with an RRule creates its next occurrence: a copy of the
+// task, open, due at the next time of the rule. The rule moves over to the
+// new task, so reopening and completing the old one again does not repeat
+// it twice. Subtasks are not copied and cannot repeat themselves.
+
+// withRepeatDefaults makes RepeatFrom match RRule.
+func withRepeatDefaults(task model.TaskModel) model.TaskModel {
+	switch {
+	case task.RRule == "":
+		task.RepeatFrom = ""
+	case task.RepeatFrom == "":
+		task.RepeatFrom = model.RepeatFromDue
+	}
+	return task
+}
+
+// nextOccurrence returns the task following task, completed at doneAt, or
+// false when its rule has ended. On the due schedule the next due date is
+// the first one of the rule after DueAt, late completions included. From
+// completion the rule starts again on the day of doneAt, at the time of day
+// of DueAt. Dates are those of the task timezone, so the time of day stays
+// the same across DST changes.
+func nextOccurrence(task model.TaskModel, doneAt time.Time) (model.TaskModel, bool, error) {
+	rule, err := rrule.Parse(task.RRule)
+	if err != nil {
+		return model.TaskModel{}, false, apperror.Wrap(apperror.ErrValidation, "invalid rrule: "+err.Error(), err)
+	}
+	if task.DueAt == nil {
+		return model.TaskModel{}, false, apperror.New(apperror.ErrValidation, "a recurring task needs due_at")
+	}
+	loc, err := time.LoadLocation(task.Timezone)
+	if err != nil {
+		return model.TaskModel{}, false, apperror.Wrap(apperror.ErrValidation, "unknown timezone "+task.Timezone, err)
+	}
+
+	due := task.DueAt.In(loc)
+	start := due
+	if task.RepeatFrom == model.RepeatFromCompletion {
+		done := doneAt.In(loc)
+		start = time.Date(done.Year(), done.Month(), done.Day(), due.Hour(), due.Minute(), due.Second(), 0, loc)
+	}
+
+	nextDue, ok := rule.Next(start, start)
+	if !ok {
+		return model.TaskModel{}, false, nil
+	}
+
+	next := model.TaskModel{
+		TaskName:   task.TaskName,
+		DueAt:      utc(nextDue),
+		Timezone:   task.Timezone,
+		RRule:      task.RRule,
+		RepeatFrom: task.RepeatFrom,
+		Priority:   task.Priority,
+		Tags:       task.Tags,
+		ListID:     task.ListID,
+	}
+	if rule.Count > 0 {
+		// the completed task was one of them
+		rule.Count--
+		next.RRule = rule.String()
+	}
+	if task.RemindAt != nil {
+		// same number of days before, at the same time of day
+		remind := task.RemindAt.In(loc)
+		days := int(calendarDay(due).Sub(calendarDay(remind)).Hours() / 24)
+		next.RemindAt = utc(time.Date(nextDue.Year(), nextDue.Month(), nextDue.Day()-days, remind.Hour(), remind.Minute(), remind.Second(), 0, loc))
+	}
+
+	return next, true, nil
+}
+
+// repeat creates the next occurrence of a recurring task that was completed
+// without UpdateTask, and takes the rule off the completed task.
+func (u *Usecase) repeat(ctx context.Context, done model.TaskModel) (model.TaskModel, error) {
+	next, ok, err := nextOccurrence(done, now())
+	if err != nil {
+		return done, err
+	}
+
+	before := done
+	done.RRule, done.RepeatFrom = "", ""
+	updated, err := u.taskRepo.Update(ctx, done, u.record(ctx, as(model.EventUpdate)))
+	if err != nil {
+		return before, err
+	}
+
+	if ok {
+		if _, err := u.CreateTask(ctx, next); err != nil {
+			return updated, err
+		}
+	}
+	return updated, nil
+}
+
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func utc(t time.Time) *time.Time {
+	t = t.UTC()
+	return &t
+}
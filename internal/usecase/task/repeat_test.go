@@ -0,0 +1,171 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	at := func(s string) *time.Time {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic(err)
+		}
+		t = t.UTC()
+		return &t
+	}
+	listID := int64(2)
+
+	tests := []struct {
+		name    string
+		task    model.TaskModel
+		doneAt  time.Time
+		want    model.TaskModel
+		wantOK  bool
+		wantErr error
+	}{
+		{
+			name:   "case 1 -> rent on the 1st keeps its schedule when paid late",
+			task:   model.TaskModel{TaskName: "pay rent", DueAt: at("2026-03-01T09:00:00Z"), Timezone: "UTC", RRule: "FREQ=MONTHLY;BYMONTHDAY=1", RepeatFrom: model.RepeatFromDue, Priority: model.PriorityHigh, Tags: []string{"home"}, ListID: &listID},
+			doneAt: *at("2026-03-04T18:00:00Z"),
+			want:   model.TaskModel{TaskName: "pay rent", DueAt: at("2026-04-01T09:00:00Z"), Timezone: "UTC", RRule: "FREQ=MONTHLY;BYMONTHDAY=1", RepeatFrom: model.RepeatFromDue, Priority: model.PriorityHigh, Tags: []string{"home"}, ListID: &listID},
+			wantOK: true,
+		},
+		{
+			name:   "case 2 -> watering every 3 days counts from completion",
+			task:   model.TaskModel{TaskName: "water plants", DueAt: at("2026-03-01T08:00:00Z"), Timezone: "UTC", RRule: "FREQ=DAILY;INTERVAL=3", RepeatFrom: model.RepeatFromCompletion},
+			doneAt: *at("2026-03-02T19:30:00Z"),
+			want:   model.TaskModel{TaskName: "water plants", DueAt: at("2026-03-05T08:00:00Z"), Timezone: "UTC", RRule: "FREQ=DAILY;INTERVAL=3", RepeatFrom: model.RepeatFromCompletion},
+			wantOK: true,
+		},
+		{
+			name:   "case 3 -> 09:00 in New York stays 09:00 after the DST change",
+			task:   model.TaskModel{TaskName: "stand-up", DueAt: at("2026-03-06T09:00:00-05:00"), Timezone: "America/New_York", RRule: "FREQ=WEEKLY;BYDAY=MO,FR", RepeatFrom: model.RepeatFromDue, RemindAt: at("2026-03-05T20:00:00-05:00")},
+			doneAt: *at("2026-03-06T15:00:00Z"),
+			want:   model.TaskModel{TaskName: "stand-up", DueAt: at("2026-03-09T09:00:00-04:00"), Timezone: "America/New_York", RRule: "FREQ=WEEKLY;BYDAY=MO,FR", RepeatFrom: model.RepeatFromDue, RemindAt: at("2026-03-08T20:00:00-04:00")},
+			wantOK: true,
+		},
+		{
+			name:   "case 4 -> completion day is the day of the task timezone",
+			task:   model.TaskModel{TaskName: "water plants", DueAt: at("2026-03-01T08:00:00+07:00"), Timezone: "Asia/Jakarta", RRule: "FREQ=DAILY;INTERVAL=3", RepeatFrom: model.RepeatFromCompletion},
+			doneAt: *at("2026-03-02T20:00:00Z"),
+			want:   model.TaskModel{TaskName: "water plants", DueAt: at("2026-03-06T08:00:00+07:00"), Timezone: "Asia/Jakarta", RRule: "FREQ=DAILY;INTERVAL=3", RepeatFrom: model.RepeatFromCompletion},
+			wantOK: true,
+		},
+		{
+			name:   "case 5 -> count goes down with each occurrence",
+			task:   model.TaskModel{TaskName: "physio", DueAt: at("2026-03-02T10:00:00Z"), Timezone: "UTC", RRule: "FREQ=WEEKLY;COUNT=3", RepeatFrom: model.RepeatFromDue},
+			doneAt: *at("2026-03-02T11:00:00Z"),
+			want:   model.TaskModel{TaskName: "physio", DueAt: at("2026-03-09T10:00:00Z"), Timezone: "UTC", RRule: "FREQ=WEEKLY;COUNT=2", RepeatFrom: model.RepeatFromDue},
+			wantOK: true,
+		},
+		{
+			name:   "case 6 -> last occurrence",
+			task:   model.TaskModel{TaskName: "physio", DueAt: at("2026-03-16T10:00:00Z"), Timezone: "UTC", RRule: "FREQ=WEEKLY;COUNT=1", RepeatFrom: model.RepeatFromDue},
+			doneAt: *at("2026-03-16T11:00:00Z"),
+		},
+		{
+			name:    "case 7 -> rule without a due date",
+			task:    model.TaskModel{TaskName: "physio", Timezone: "UTC", RRule: "FREQ=WEEKLY"},
+			wantErr: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok, err := nextOccurrence(tt.task, tt.doneAt)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, next)
+		})
+	}
+}
+
+func TestUseCase_CompleteRecurringTask(t *testing.T) {
+	dueAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	nextDue := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return time.Date(2026, 3, 3, 15, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		name     string
+		complete func(u *Usecase) (model.TaskModel, error)
+	}{
+		{
+			name: "case 1 -> set done",
+			complete: func(u *Usecase) (model.TaskModel, error) {
+				return u.SetTaskDone(context.Background(), 1, true)
+			},
+		},
+		{
+			name: "case 2 -> update",
+			complete: func(u *Usecase) (model.TaskModel, error) {
+				return u.UpdateTask(context.Background(), model.TaskModel{ID: 1, TaskName: "pay rent", IsDone: true, DueAt: &dueAt, RRule: "FREQ=MONTHLY"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := map[int64]*model.TaskModel{
+				1: {ID: 1, TaskName: "pay rent", DueAt: &dueAt, Timezone: "UTC", RRule: "FREQ=MONTHLY", RepeatFrom: model.RepeatFromDue, Version: 1},
+			}
+			repo := subtaskRepository(tasks)
+			repo.UpdateFunc = func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+				task.Version = tasks[task.ID].Version + 1
+				tasks[task.ID] = &task
+				return task, nil
+			}
+
+			done, err := tt.complete(NewUseCase(repo))
+
+			assert.NoError(t, err)
+			assert.Equal(t, model.TaskModel{ID: 1, TaskName: "pay rent", IsDone: true, DueAt: &dueAt, Timezone: "UTC", Version: 2}, done)
+			assert.Equal(t, &model.TaskModel{ID: 2, TaskName: "pay rent", DueAt: &nextDue, Timezone: "UTC", RRule: "FREQ=MONTHLY", RepeatFrom: model.RepeatFromDue}, tasks[2])
+		})
+	}
+}
+
+func TestUseCase_SubtaskCannotRepeat(t *testing.T) {
+	tasks := map[int64]*model.TaskModel{1: {ID: 1, TaskName: "task 1"}}
+	dueAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := NewUseCase(subtaskRepository(tasks)).CreateSubtask(context.Background(), 1, model.TaskModel{TaskName: "step 1", DueAt: &dueAt, RRule: "FREQ=DAILY"})
+
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+}
+
+func TestUseCase_BatchRepeatsCompletedTask(t *testing.T) {
+	dueAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	nextDue := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	before := model.TaskModel{ID: 1, TaskName: "stretch", DueAt: &dueAt, Timezone: "UTC", RRule: "FREQ=DAILY", RepeatFrom: model.RepeatFromDue, Version: 1}
+	after := before
+	after.IsDone = true
+	after.Version = 2
+
+	tasks := map[int64]*model.TaskModel{1: &after}
+	repo := subtaskRepository(tasks)
+	repo.BatchFunc = func(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error) {
+		return []model.BatchResult{{Op: model.BatchComplete, ID: 1, Task: &after, Before: &before}}, nil
+	}
+	repo.UpdateFunc = func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+		assert.Equal(t, int64(2), task.Version)
+		task.Version++
+		return task, nil
+	}
+
+	results, err := NewUseCase(repo).Batch(context.Background(), model.BatchRequest{Operations: []model.BatchOperation{{Op: model.BatchComplete, ID: 1}}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.TaskModel{ID: 1, TaskName: "stretch", IsDone: true, DueAt: &dueAt, Timezone: "UTC", Version: 3}, results[0].Task)
+	assert.Equal(t, &model.TaskModel{ID: 2, TaskName: "stretch", DueAt: &nextDue, Timezone: "UTC", RRule: "FREQ=DAILY", RepeatFrom: model.RepeatFromDue}, tasks[2])
+}
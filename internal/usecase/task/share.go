@@ -0,0 +1,86 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	listmodel "to-do-list/internal/model/list"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// Members of a shared list reach its tasks as the owner of the list, once
+// their role allows what they ask. Every member then reads and invalidates
+// the same cached copies, those of the owner.
+
+// Sharing tells what the signed in user may do with a list or a task, the
+// list repo implements it.
+type Sharing interface {
+	ListAccess(ctx context.Context, id int64) (listmodel.Access, error)
+	TaskAccess(ctx context.Context, id int64) (listmodel.Access, error)
+}
+
+// WithSharing lets members of shared lists reach their tasks. Without it
+// users only reach their own tasks.
+func WithSharing(sharing Sharing) Option {
+	return func(u *Usecase) {
+		u.sharing = sharing
+	}
+}
+
+// forTask checks that the signed in user has at least the role need on the
+// task and returns a context reaching the data of its owner.
+func (u *Usecase) forTask(ctx context.Context, id int64, need listmodel.Role) (context.Context, error) {
+	if u.sharing == nil {
+		return ctx, nil
+	}
+	access, err := u.sharing.TaskAccess(ctx, id)
+	if err != nil {
+		return ctx, err
+	}
+	return scoped(ctx, access, need)
+}
+
+// forList is forTask for the tasks of a list.
+func (u *Usecase) forList(ctx context.Context, id int64, need listmodel.Role) (context.Context, error) {
+	if u.sharing == nil {
+		return ctx, nil
+	}
+	access, err := u.sharing.ListAccess(ctx, id)
+	if err != nil {
+		return ctx, err
+	}
+	return scoped(ctx, access, need)
+}
+
+func scoped(ctx context.Context, access listmodel.Access, need listmodel.Role) (context.Context, error) {
+	if !access.Role.Allows(need) {
+		return ctx, apperror.New(apperror.ErrForbidden, fmt.Sprintf("the %s role on the list is needed, you are %s", need, access.Role))
+	}
+	return requestinfo.WithOwnerID(ctx, access.OwnerID), nil
+}
+
+// checkListChange lets a task go to another list the signed in user edits,
+// of the same owner. Only the owner takes a task out of every list, where
+// members would lose it.
+func (u *Usecase) checkListChange(ctx context.Context, from, to *int64) error {
+	if u.sharing == nil || sameID(from, to) {
+		return nil
+	}
+	if to == nil {
+		if requestinfo.OwnerID(ctx) != requestinfo.UserID(ctx) {
+			return apperror.New(apperror.ErrForbidden, "only the owner of the list takes tasks out of it")
+		}
+		return nil
+	}
+	access, err := u.sharing.ListAccess(ctx, *to)
+	if err != nil {
+		return err
+	}
+	if access.OwnerID != requestinfo.OwnerID(ctx) {
+		return apperror.New(apperror.ErrValidation, "a task only moves to another list of the same owner")
+	}
+	if !access.Role.Allows(listmodel.RoleEditor) {
+		return apperror.New(apperror.ErrForbidden, fmt.Sprintf("the editor role on the list is needed, you are %s", access.Role))
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package task
+
+import (
+	"context"
+	"testing"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// user 2 is a member of list 10 and list 11 of user 1, list 12 of user 1
+// is not shared with them
+func newSharing(role listmodel.Role) *SharingMock {
+	return &SharingMock{
+		TaskAccessFunc: func(ctx context.Context, id int64) (listmodel.Access, error) {
+			return listmodel.Access{OwnerID: 1, Role: role}, nil
+		},
+		ListAccessFunc: func(ctx context.Context, id int64) (listmodel.Access, error) {
+			switch id {
+			case 10, 11:
+				return listmodel.Access{OwnerID: 1, Role: role}, nil
+			case 20:
+				return listmodel.Access{OwnerID: 2, Role: listmodel.RoleOwner}, nil
+			}
+			return listmodel.Access{}, apperror.New(apperror.ErrNotFound, "list not found")
+		},
+	}
+}
+
+func sharedRepo(t *testing.T) *TaskRepositoryMock {
+	listID := int64(10)
+	task := model.TaskModel{ID: 1, TaskName: "shared", ListID: &listID, Version: 1}
+	return &TaskRepositoryMock{
+		GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+			assert.Equal(t, int64(1), requestinfo.OwnerID(ctx), "queries reach the data of the owner")
+			return task, nil
+		},
+		UpdateFunc: func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+			assert.Equal(t, int64(1), requestinfo.OwnerID(ctx))
+			assert.Equal(t, int64(2), requestinfo.UserID(ctx))
+			return task, nil
+		},
+		DeleteFunc: func(ctx context.Context, task model.TaskModel, record model.Record) error {
+			assert.Equal(t, int64(1), requestinfo.OwnerID(ctx))
+			return nil
+		},
+	}
+}
+
+func TestUseCase_SharedTaskRoles(t *testing.T) {
+	ctx := requestinfo.WithUserID(context.Background(), 2)
+
+	tests := []struct {
+		name    string
+		role    listmodel.Role
+		wantErr error
+	}{
+		{
+			name:    "case 1 -> viewer cannot change a task",
+			role:    listmodel.RoleViewer,
+			wantErr: apperror.ErrForbidden,
+		},
+		{
+			name: "case 2 -> editor changes a task",
+			role: listmodel.RoleEditor,
+		},
+		{
+			name: "case 3 -> owner changes a task",
+			role: listmodel.RoleOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUseCase(sharedRepo(t), WithSharing(newSharing(tt.role)))
+
+			listID := int64(10)
+			_, err := u.UpdateTask(ctx, model.TaskModel{ID: 1, TaskName: "renamed", ListID: &listID})
+			assertErr(t, tt.wantErr, err)
+
+			err = u.DeleteTask(ctx, model.TaskModel{ID: 1})
+			assertErr(t, tt.wantErr, err)
+
+			_, err = u.GetTask(ctx, 1)
+			assert.NoError(t, err, "every member reads")
+		})
+	}
+}
+
+func TestUseCase_SharedTaskListChange(t *testing.T) {
+	ctx := requestinfo.WithUserID(context.Background(), 2)
+
+	tests := []struct {
+		name    string
+		listID  *int64
+		wantErr error
+	}{
+		{
+			name:   "case 1 -> editor moves a task to another shared list",
+			listID: func() *int64 { id := int64(11); return &id }(),
+		},
+		{
+			name:    "case 2 -> list of the owner not shared with the member",
+			listID:  func() *int64 { id := int64(12); return &id }(),
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name:    "case 3 -> list of the member",
+			listID:  func() *int64 { id := int64(20); return &id }(),
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 4 -> members do not take tasks out of every list",
+			wantErr: apperror.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUseCase(sharedRepo(t), WithSharing(newSharing(listmodel.RoleEditor)))
+
+			_, err := u.UpdateTask(ctx, model.TaskModel{ID: 1, TaskName: "shared", ListID: tt.listID})
+			assertErr(t, tt.wantErr, err)
+		})
+	}
+}
+
+func assertErr(t *testing.T, want error, err error) {
+	t.Helper()
+	if want == nil {
+		assert.NoError(t, err)
+	} else {
+		assert.ErrorIs(t, err, want)
+	}
+}
@@ -0,0 +1,160 @@
+package task
+
+import (
+	"context"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+// A task with subtasks is done exactly when all of them are: completing the
+// last open subtask completes the parent, reopening one or adding an open
+// one reopens it. Completing the parent itself leaves its subtasks alone,
+// unless the usecase was built WithBlockOpenSubtasks.
+
+func (u *Usecase) GetSubtasks(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	ctx, err := u.forTask(ctx, id, listmodel.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.taskRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.taskRepo.GetSubtasks(ctx, id)
+}
+
+// CreateSubtask appends a subtask to a task. Subtasks live in the list of
+// their parent and cannot have subtasks themselves.
+func (u *Usecase) CreateSubtask(ctx context.Context, parentID int64, r model.TaskModel) (model.TaskModel, error) {
+	ctx, err := u.forTask(ctx, parentID, listmodel.RoleEditor)
+	if err != nil {
+		return r, err
+	}
+	parent, err := u.taskRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return r, err
+	}
+	if parent.ParentID != nil {
+		return r, apperror.New(apperror.ErrValidation, "a subtask cannot have subtasks")
+	}
+	if r.RRule != "" {
+		return r, apperror.New(apperror.ErrValidation, "a subtask cannot repeat")
+	}
+
+	if r.Timezone == "" {
+		r.Timezone = parent.Timezone
+	}
+	r.Tags = NormalizeTags(r.Tags)
+	r.ListID = parent.ListID
+	r.ParentID = &parent.ID
+
+	subtask, err := u.taskRepo.Create(ctx, r, u.record(ctx, as(model.EventCreate)))
+	if err != nil {
+		return subtask, err
+	}
+	return subtask, u.rollUp(ctx, r.ParentID)
+}
+
+func (u *Usecase) ReorderSubtasks(ctx context.Context, id int64, ids []int64) ([]model.TaskModel, error) {
+	ctx, err := u.forTask(ctx, id, listmodel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.taskRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	// only the subtasks that changed place are recorded
+	record := u.record(ctx, func(changes []model.Change) []model.TaskEvent {
+		moved := []model.Change{}
+		for _, change := range changes {
+			if change.Before.Position != change.After.Position {
+				moved = append(moved, change)
+			}
+		}
+		return as(model.EventUpdate)(moved)
+	})
+	if err := u.taskRepo.Reorder(ctx, id, ids, record); err != nil {
+		return nil, err
+	}
+	return u.taskRepo.GetSubtasks(ctx, id)
+}
+
+// SetTaskDone completes or reopens a task and rolls the change up to its
+// parent.
+func (u *Usecase) SetTaskDone(ctx context.Context, id int64, done bool) (model.TaskModel, error) {
+	ctx, err := u.forTask(ctx, id, listmodel.RoleEditor)
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+	task, err := u.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return task, err
+	}
+	if task.IsDone == done {
+		return task, nil
+	}
+	if done && task.RRule != "" {
+		task.IsDone = true
+		return u.UpdateTask(ctx, task)
+	}
+	if err := u.checkDone(ctx, task, done); err != nil {
+		return task, err
+	}
+
+	if err := u.taskRepo.SetDone(ctx, id, done, u.record(ctx, as(model.EventUpdate))); err != nil {
+		return task, err
+	}
+	task.IsDone = done
+	task.Version++
+
+	return task, u.rollUp(ctx, task.ParentID)
+}
+
+// checkDone enforces WithBlockOpenSubtasks when task is being completed.
+func (u *Usecase) checkDone(ctx context.Context, task model.TaskModel, done bool) error {
+	if !u.blockOpenSubtasks || !done || task.IsDone {
+		return nil
+	}
+
+	subtasks, err := u.taskRepo.GetSubtasks(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	for _, subtask := range subtasks {
+		if !subtask.IsDone {
+			return apperror.New(apperror.ErrConflict, "task has open subtasks")
+		}
+	}
+	return nil
+}
+
+// rollUp makes the status of the parent match its subtasks after one of them
+// changed.
+func (u *Usecase) rollUp(ctx context.Context, parentID *int64) error {
+	if parentID == nil {
+		return nil
+	}
+
+	subtasks, err := u.taskRepo.GetSubtasks(ctx, *parentID)
+	if err != nil {
+		return err
+	}
+	if len(subtasks) == 0 {
+		return nil
+	}
+
+	done := true
+	for _, subtask := range subtasks {
+		done = done && subtask.IsDone
+	}
+
+	parent, err := u.taskRepo.GetByID(ctx, *parentID)
+	if err != nil {
+		return err
+	}
+	if parent.IsDone == done {
+		return nil
+	}
+	// the roll-up follows from a recorded change
+	return u.taskRepo.SetDone(ctx, parent.ID, done, nil)
+}
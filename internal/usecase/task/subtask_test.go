@@ -0,0 +1,182 @@
+package task
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// subtaskRepository keeps tasks in a map so the roll-up can be followed
+// through several calls.
+func subtaskRepository(tasks map[int64]*model.TaskModel) *TaskRepositoryMock {
+	return &TaskRepositoryMock{
+		GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+			task, ok := tasks[id]
+			if !ok {
+				return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "task not found")
+			}
+			return *task, nil
+		},
+		GetSubtasksFunc: func(ctx context.Context, id int64) ([]model.TaskModel, error) {
+			subtasks := []model.TaskModel{}
+			for i := int64(1); i <= int64(len(tasks)); i++ {
+				if task, ok := tasks[i]; ok && task.ParentID != nil && *task.ParentID == id {
+					subtasks = append(subtasks, *task)
+				}
+			}
+			return subtasks, nil
+		},
+		SetDoneFunc: func(ctx context.Context, id int64, done bool, record model.Record) error {
+			tasks[id].IsDone = done
+			return nil
+		},
+		CreateFunc: func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+			task.ID = int64(len(tasks) + 1)
+			tasks[task.ID] = &task
+			return task, nil
+		},
+	}
+}
+
+func TestUseCase_SetTaskDone(t *testing.T) {
+	ctx := context.Background()
+	parentID := int64(1)
+
+	tests := []struct {
+		name           string
+		block          bool
+		subtasks       []bool
+		parentDone     bool
+		id             int64
+		done           bool
+		wantErr        error
+		wantParentDone bool
+	}{
+		{
+			name:           "case 1 -> completing the last open subtask completes the parent",
+			subtasks:       []bool{true, false},
+			id:             3,
+			done:           true,
+			wantParentDone: true,
+		},
+		{
+			name:           "case 2 -> completing a subtask leaves the parent open while others are open",
+			subtasks:       []bool{false, false},
+			id:             3,
+			done:           true,
+			wantParentDone: false,
+		},
+		{
+			name:           "case 3 -> reopening a subtask reopens the parent",
+			subtasks:       []bool{true, true},
+			parentDone:     true,
+			id:             2,
+			done:           false,
+			wantParentDone: false,
+		},
+		{
+			name:           "case 4 -> parent with open subtasks can be completed by default",
+			subtasks:       []bool{true, false},
+			id:             1,
+			done:           true,
+			wantParentDone: true,
+		},
+		{
+			name:           "case 5 -> parent with open subtasks is blocked when configured",
+			block:          true,
+			subtasks:       []bool{true, false},
+			id:             1,
+			done:           true,
+			wantErr:        apperror.ErrConflict,
+			wantParentDone: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := map[int64]*model.TaskModel{
+				1: {ID: 1, TaskName: "parent", IsDone: tt.parentDone},
+			}
+			for i, done := range tt.subtasks {
+				id := int64(i + 2)
+				tasks[id] = &model.TaskModel{ID: id, TaskName: "step", IsDone: done, ParentID: &parentID, Position: i}
+			}
+
+			u := NewUseCase(subtaskRepository(tasks), WithBlockOpenSubtasks(tt.block))
+			_, err := u.SetTaskDone(ctx, tt.id, tt.done)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantParentDone, tasks[1].IsDone)
+		})
+	}
+}
+
+func TestUseCase_CreateSubtask(t *testing.T) {
+	ctx := context.Background()
+	parentID := int64(1)
+	listID := int64(5)
+
+	t.Run("case 1 -> open subtask joins the list of its parent and reopens it", func(t *testing.T) {
+		tasks := map[int64]*model.TaskModel{
+			1: {ID: 1, TaskName: "parent", IsDone: true, Timezone: "Asia/Jakarta", ListID: &listID},
+		}
+		u := NewUseCase(subtaskRepository(tasks))
+
+		got, err := u.CreateSubtask(ctx, 1, model.TaskModel{TaskName: "step"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.TaskModel{ID: 2, TaskName: "step", Timezone: "Asia/Jakarta", ListID: &listID, ParentID: &parentID}, got)
+		assert.False(t, tasks[1].IsDone)
+	})
+
+	t.Run("case 2 -> subtask of a subtask is rejected", func(t *testing.T) {
+		tasks := map[int64]*model.TaskModel{
+			1: {ID: 1, TaskName: "parent"},
+			2: {ID: 2, TaskName: "step", ParentID: &parentID},
+		}
+		u := NewUseCase(subtaskRepository(tasks))
+
+		_, err := u.CreateSubtask(ctx, 2, model.TaskModel{TaskName: "sub step"})
+
+		assert.ErrorIs(t, err, apperror.ErrValidation)
+	})
+
+	t.Run("case 3 -> unknown parent is not found", func(t *testing.T) {
+		u := NewUseCase(subtaskRepository(map[int64]*model.TaskModel{}))
+
+		_, err := u.CreateSubtask(ctx, 9, model.TaskModel{TaskName: "step"})
+
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+	})
+}
+
+func TestUseCase_MoveTask(t *testing.T) {
+	ctx := context.Background()
+	parentID := int64(1)
+	listID := int64(5)
+
+	tasks := map[int64]*model.TaskModel{
+		1: {ID: 1, TaskName: "parent"},
+		2: {ID: 2, TaskName: "step", ParentID: &parentID},
+	}
+	repo := subtaskRepository(tasks)
+	repo.MoveFunc = func(ctx context.Context, id int64, listID *int64, record model.Record) (model.TaskModel, error) {
+		task := *tasks[id]
+		task.ListID = listID
+		return task, nil
+	}
+	u := NewUseCase(repo)
+
+	got, err := u.MoveTask(ctx, 1, &listID)
+	assert.NoError(t, err)
+	assert.Equal(t, &listID, got.ListID)
+
+	_, err = u.MoveTask(ctx, 2, &listID)
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+}
@@ -2,55 +2,276 @@ package task
 
 import (
 	"context"
-	"errors"
+	"sort"
+	"strings"
+	"time"
+	listmodel "to-do-list/internal/model/list"
 	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
 )
 
 type Usecase struct {
-	taskRepo Repo
+	taskRepo          Repo
+	events            EventRepo
+	sharing           Sharing
+	blockOpenSubtasks bool
+	validate          Validator
 }
 
-func NewUseCase(repo Repo) *Usecase {
-	return &Usecase{
+// Validator checks a task the way the handler checks request bodies. It
+// runs on patched tasks, whose body alone says nothing about the result.
+type Validator func(task model.TaskModel) []model.ErrorField
+
+// Option changes a rule of the Usecase.
+type Option func(*Usecase)
+
+// WithBlockOpenSubtasks refuses to complete a task while some of its
+// subtasks are open, instead of leaving them open.
+func WithBlockOpenSubtasks(block bool) Option {
+	return func(u *Usecase) {
+		u.blockOpenSubtasks = block
+	}
+}
+
+// WithValidator sets the checks PatchTask runs on the patched task.
+func WithValidator(validate Validator) Option {
+	return func(u *Usecase) {
+		u.validate = validate
+	}
+}
+
+// WithEvents records every change made through the Usecase in the audit
+// log of events.
+func WithEvents(events EventRepo) Option {
+	return func(u *Usecase) {
+		u.events = events
+	}
+}
+
+func NewUseCase(repo Repo, opts ...Option) *Usecase {
+	u := &Usecase{
 		taskRepo: repo,
 	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
 type Repo interface {
-	GetAll(ctx context.Context) ([]model.TaskModel, error)
-	Create(ctx context.Context, task model.TaskModel) model.TaskModel
-	Update(ctx context.Context, task model.TaskModel) (bool, model.TaskModel)
-	Delete(ctx context.Context, task model.TaskModel) bool
+	GetAll(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error)
+	GetByID(ctx context.Context, id int64) (model.TaskModel, error)
+	Create(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error)
+	Update(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error)
+	Move(ctx context.Context, id int64, listID *int64, record model.Record) (model.TaskModel, error)
+	Delete(ctx context.Context, task model.TaskModel, record model.Record) error
+	GetSubtasks(ctx context.Context, id int64) ([]model.TaskModel, error)
+	Reorder(ctx context.Context, id int64, ids []int64, record model.Record) error
+	SetDone(ctx context.Context, id int64, done bool, record model.Record) error
+	Batch(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error)
+	GetTrash(ctx context.Context) ([]model.TaskModel, error)
+	Restore(ctx context.Context, id int64, record model.Record) (model.TaskModel, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	Rewind(ctx context.Context, steps []model.RewindStep, record model.Record) ([]model.RewindResult, error)
+	AddDependency(ctx context.Context, dependency model.Dependency, check func([]model.Dependency) error) error
+	RemoveDependency(ctx context.Context, dependency model.Dependency) error
+	GetDependencies(ctx context.Context) ([]model.Dependency, error)
+	GetBlockers(ctx context.Context, id int64) ([]model.TaskModel, error)
+	GetOpenTasks(ctx context.Context) ([]model.TaskModel, error)
+	Reposition(ctx context.Context, id int64, place model.RepositionRequest, record model.Record) (model.TaskModel, error)
+	Rebalance(ctx context.Context) (int64, error)
+}
+
+func (u *Usecase) GetAllTask(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = model.DefaultPageLimit
+	}
+	if filter.Limit > model.MaxPageLimit {
+		filter.Limit = model.MaxPageLimit
+	}
+
+	filter, err := resolveDue(filter)
+	if err != nil {
+		return model.TaskPage{}, err
+	}
+
+	if filter.ListID != nil {
+		if ctx, err = u.forList(ctx, *filter.ListID, listmodel.RoleViewer); err != nil {
+			return model.TaskPage{}, err
+		}
+	}
+
+	page, err := u.taskRepo.GetAll(ctx, filter)
+	if err != nil {
+		return model.TaskPage{}, err
+	}
+	return page, nil
 }
 
-func (u *Usecase) GetAllTask(ctx context.Context) ([]model.TaskModel, error) {
-	tasks, err := u.taskRepo.GetAll(ctx)
+func (u *Usecase) GetTask(ctx context.Context, id int64) (model.TaskModel, error) {
+	ctx, err := u.forTask(ctx, id, listmodel.RoleViewer)
 	if err != nil {
-		return nil, err
+		return model.TaskModel{}, err
 	}
-	return tasks, nil
+	return u.taskRepo.GetByID(ctx, id)
 }
 
 func (u *Usecase) CreateTask(ctx context.Context, r model.TaskModel) (model.TaskModel, error) {
-	task_create := u.taskRepo.Create(ctx, r)
-	if task_create.ID == 0 {
-		return task_create, errors.New("error, create task failed")
+	if r.Timezone == "" {
+		r.Timezone = model.DefaultTimezone
+	}
+	r.Tags = NormalizeTags(r.Tags)
+	r.ParentID = nil
+	r = withRepeatDefaults(r)
+	if r.ListID != nil {
+		var err error
+		if ctx, err = u.forList(ctx, *r.ListID, listmodel.RoleEditor); err != nil {
+			return r, err
+		}
+	}
+	task_create, err := u.taskRepo.Create(ctx, r, u.record(ctx, as(model.EventCreate)))
+	if err != nil {
+		return task_create, err
 	}
 	return task_create, nil
 }
 
+// UpdateTask replaces the task. A non-zero Version makes it fail with
+// apperror.ErrPreconditionFailed unless the task is still at that version.
+// Completing a recurring task creates its next occurrence.
 func (u *Usecase) UpdateTask(ctx context.Context, r model.TaskModel) (model.TaskModel, error) {
-	success, task_update := u.taskRepo.Update(ctx, r)
-	if !success {
-		return task_update, errors.New("error, update task failed")
+	if r.Timezone == "" {
+		r.Timezone = model.DefaultTimezone
+	}
+	r.Tags = NormalizeTags(r.Tags)
+	r = withRepeatDefaults(r)
+
+	ctx, err := u.forTask(ctx, r.ID, listmodel.RoleEditor)
+	if err != nil {
+		return r, err
+	}
+	current, err := u.taskRepo.GetByID(ctx, r.ID)
+	if err != nil {
+		return r, err
+	}
+	if r.Version != 0 && r.Version != current.Version {
+		return r, apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+	}
+	if err := u.checkListChange(ctx, current.ListID, r.ListID); err != nil {
+		return r, err
+	}
+	if err := u.checkDone(ctx, current, r.IsDone); err != nil {
+		return r, err
+	}
+	if current.ParentID != nil && !sameID(current.ListID, r.ListID) {
+		return r, apperror.New(apperror.ErrValidation, "a subtask stays in the list of its parent")
+	}
+	if current.ParentID != nil && r.RRule != "" {
+		return r, apperror.New(apperror.ErrValidation, "a subtask cannot repeat")
+	}
+	r.ParentID = current.ParentID
+	r.Position = current.Position
+	r.Rank = current.Rank
+
+	var next *model.TaskModel
+	if r.IsDone && !current.IsDone && r.RRule != "" {
+		occurrence, ok, err := nextOccurrence(r, now())
+		if err != nil {
+			return r, err
+		}
+		if ok {
+			next = &occurrence
+		}
+		r.RRule, r.RepeatFrom = "", ""
+	}
+
+	task_update, err := u.taskRepo.Update(ctx, r, u.record(ctx, as(model.EventUpdate)))
+	if err != nil {
+		return task_update, err
+	}
+	if next != nil {
+		if _, err := u.CreateTask(ctx, *next); err != nil {
+			return task_update, err
+		}
+	}
+	if current.IsDone != r.IsDone {
+		if err := u.rollUp(ctx, current.ParentID); err != nil {
+			return task_update, err
+		}
 	}
 	return task_update, nil
 }
 
+// MoveTask moves a task and its subtasks. Subtasks stay in the list of
+// their parent.
+func (u *Usecase) MoveTask(ctx context.Context, id int64, listID *int64) (model.TaskModel, error) {
+	ctx, err := u.forTask(ctx, id, listmodel.RoleEditor)
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+	current, err := u.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return current, err
+	}
+	if current.ParentID != nil {
+		return current, apperror.New(apperror.ErrValidation, "a subtask stays in the list of its parent")
+	}
+	if err := u.checkListChange(ctx, current.ListID, listID); err != nil {
+		return current, err
+	}
+	return u.taskRepo.Move(ctx, id, listID, u.record(ctx, as(model.EventUpdate)))
+}
+
+// DeleteTask moves the task and its subtasks to the trash, RestoreTask
+// brings them back.
 func (u *Usecase) DeleteTask(ctx context.Context, r model.TaskModel) error {
-	task_delete := u.taskRepo.Delete(ctx, r)
-	if !task_delete {
-		return errors.New("error, delete task failed")
+	ctx, err := u.forTask(ctx, r.ID, listmodel.RoleEditor)
+	if err != nil {
+		return err
+	}
+	current, err := u.taskRepo.GetByID(ctx, r.ID)
+	if err != nil {
+		return err
+	}
+	if r.Version != 0 && r.Version != current.Version {
+		return apperror.New(apperror.ErrPreconditionFailed, "task has been changed")
+	}
+	if err := u.taskRepo.Delete(ctx, r, u.record(ctx, as(model.EventDelete))); err != nil {
+		return err
+	}
+	return u.rollUp(ctx, current.ParentID)
+}
+
+// NormalizeTags lower cases and trims tag names, drops duplicates and sorts
+// them the way the repo returns them.
+func NormalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	var (
+		seen       = map[string]bool{}
+		normalized = []string{}
+	)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	sort.Strings(normalized)
+
+	return normalized
+}
+
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
-	return nil
+	return *a == *b
 }
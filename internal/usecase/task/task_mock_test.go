@@ -2,30 +2,152 @@ package task
 
 import (
 	"context"
+	"time"
+	listmodel "to-do-list/internal/model/list"
 	model "to-do-list/internal/model/task"
 )
 
 var TaskRepository = &TaskRepositoryMock{}
 
 type TaskRepositoryMock struct {
-	GetAllFunc func(ctx context.Context) ([]model.TaskModel, error)
-	CreateFunc func(ctx context.Context, task model.TaskModel) model.TaskModel
-	UpdateFunc func(ctx context.Context, task model.TaskModel) (bool, model.TaskModel)
-	DeleteFunc func(ctx context.Context, task model.TaskModel) bool
+	GetAllFunc  func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error)
+	GetByIDFunc func(ctx context.Context, id int64) (model.TaskModel, error)
+	CreateFunc  func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error)
+	UpdateFunc  func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error)
+	MoveFunc    func(ctx context.Context, id int64, listID *int64, record model.Record) (model.TaskModel, error)
+	DeleteFunc  func(ctx context.Context, task model.TaskModel, record model.Record) error
+
+	GetSubtasksFunc func(ctx context.Context, id int64) ([]model.TaskModel, error)
+	ReorderFunc     func(ctx context.Context, id int64, ids []int64, record model.Record) error
+	SetDoneFunc     func(ctx context.Context, id int64, done bool, record model.Record) error
+	BatchFunc       func(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error)
+
+	GetTrashFunc func(ctx context.Context) ([]model.TaskModel, error)
+	RestoreFunc  func(ctx context.Context, id int64, record model.Record) (model.TaskModel, error)
+	PurgeFunc    func(ctx context.Context, before time.Time) (int64, error)
+	RewindFunc   func(ctx context.Context, steps []model.RewindStep, record model.Record) ([]model.RewindResult, error)
+
+	AddDependencyFunc    func(ctx context.Context, dependency model.Dependency, check func([]model.Dependency) error) error
+	RemoveDependencyFunc func(ctx context.Context, dependency model.Dependency) error
+	GetDependenciesFunc  func(ctx context.Context) ([]model.Dependency, error)
+	GetBlockersFunc      func(ctx context.Context, id int64) ([]model.TaskModel, error)
+	GetOpenTasksFunc     func(ctx context.Context) ([]model.TaskModel, error)
+
+	RepositionFunc func(ctx context.Context, id int64, place model.RepositionRequest, record model.Record) (model.TaskModel, error)
+	RebalanceFunc  func(ctx context.Context) (int64, error)
+}
+
+func (repository *TaskRepositoryMock) GetAll(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+	return repository.GetAllFunc(ctx, filter)
+}
+
+func (repository *TaskRepositoryMock) GetByID(ctx context.Context, id int64) (model.TaskModel, error) {
+	return repository.GetByIDFunc(ctx, id)
+}
+
+func (repository *TaskRepositoryMock) Create(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+	return repository.CreateFunc(ctx, task, record)
+}
+
+func (repository *TaskRepositoryMock) Update(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+	return repository.UpdateFunc(ctx, task, record)
+}
+
+func (repository *TaskRepositoryMock) Move(ctx context.Context, id int64, listID *int64, record model.Record) (model.TaskModel, error) {
+	return repository.MoveFunc(ctx, id, listID, record)
+}
+
+func (repository *TaskRepositoryMock) Delete(ctx context.Context, task model.TaskModel, record model.Record) error {
+	return repository.DeleteFunc(ctx, task, record)
+}
+
+func (repository *TaskRepositoryMock) GetSubtasks(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	return repository.GetSubtasksFunc(ctx, id)
+}
+
+func (repository *TaskRepositoryMock) Reorder(ctx context.Context, id int64, ids []int64, record model.Record) error {
+	return repository.ReorderFunc(ctx, id, ids, record)
+}
+
+func (repository *TaskRepositoryMock) SetDone(ctx context.Context, id int64, done bool, record model.Record) error {
+	return repository.SetDoneFunc(ctx, id, done, record)
+}
+
+func (repository *TaskRepositoryMock) Batch(ctx context.Context, ops []model.BatchOperation, opts model.BatchOptions, record model.Record) ([]model.BatchResult, error) {
+	return repository.BatchFunc(ctx, ops, opts, record)
+}
+
+func (repository *TaskRepositoryMock) GetTrash(ctx context.Context) ([]model.TaskModel, error) {
+	return repository.GetTrashFunc(ctx)
+}
+
+func (repository *TaskRepositoryMock) Restore(ctx context.Context, id int64, record model.Record) (model.TaskModel, error) {
+	return repository.RestoreFunc(ctx, id, record)
+}
+
+func (repository *TaskRepositoryMock) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return repository.PurgeFunc(ctx, before)
+}
+
+func (repository *TaskRepositoryMock) Rewind(ctx context.Context, steps []model.RewindStep, record model.Record) ([]model.RewindResult, error) {
+	return repository.RewindFunc(ctx, steps, record)
+}
+
+func (repository *TaskRepositoryMock) AddDependency(ctx context.Context, dependency model.Dependency, check func([]model.Dependency) error) error {
+	return repository.AddDependencyFunc(ctx, dependency, check)
+}
+
+func (repository *TaskRepositoryMock) RemoveDependency(ctx context.Context, dependency model.Dependency) error {
+	return repository.RemoveDependencyFunc(ctx, dependency)
+}
+
+func (repository *TaskRepositoryMock) GetDependencies(ctx context.Context) ([]model.Dependency, error) {
+	return repository.GetDependenciesFunc(ctx)
+}
+
+func (repository *TaskRepositoryMock) GetBlockers(ctx context.Context, id int64) ([]model.TaskModel, error) {
+	return repository.GetBlockersFunc(ctx, id)
+}
+
+func (repository *TaskRepositoryMock) GetOpenTasks(ctx context.Context) ([]model.TaskModel, error) {
+	return repository.GetOpenTasksFunc(ctx)
+}
+
+func (repository *TaskRepositoryMock) Reposition(ctx context.Context, id int64, place model.RepositionRequest, record model.Record) (model.TaskModel, error) {
+	return repository.RepositionFunc(ctx, id, place, record)
+}
+
+func (repository *TaskRepositoryMock) Rebalance(ctx context.Context) (int64, error) {
+	return repository.RebalanceFunc(ctx)
+}
+
+type EventRepositoryMock struct {
+	GetEventsFunc         func(ctx context.Context, filter model.EventFilter) (model.EventPage, error)
+	GetUndoableEventsFunc func(ctx context.Context, user int64) ([]model.TaskEvent, error)
+	GetSnapshotFunc       func(ctx context.Context, id, version int64) (model.TaskModel, error)
+}
+
+func (repository *EventRepositoryMock) GetEvents(ctx context.Context, filter model.EventFilter) (model.EventPage, error) {
+	return repository.GetEventsFunc(ctx, filter)
+}
+
+func (repository *EventRepositoryMock) GetUndoableEvents(ctx context.Context, user int64) ([]model.TaskEvent, error) {
+	return repository.GetUndoableEventsFunc(ctx, user)
 }
 
-func (repository *TaskRepositoryMock) GetAll(ctx context.Context) ([]model.TaskModel, error) {
-	return repository.GetAllFunc(ctx)
+func (repository *EventRepositoryMock) GetSnapshot(ctx context.Context, id, version int64) (model.TaskModel, error) {
+	return repository.GetSnapshotFunc(ctx, id, version)
 }
 
-func (repository *TaskRepositoryMock) Create(ctx context.Context, task model.TaskModel) model.TaskModel {
-	return repository.CreateFunc(ctx, task)
+type SharingMock struct {
+	ListAccessFunc func(ctx context.Context, id int64) (listmodel.Access, error)
+	TaskAccessFunc func(ctx context.Context, id int64) (listmodel.Access, error)
 }
 
-func (repository *TaskRepositoryMock) Update(ctx context.Context, task model.TaskModel) (bool, model.TaskModel) {
-	return repository.UpdateFunc(ctx, task)
+func (sharing *SharingMock) ListAccess(ctx context.Context, id int64) (listmodel.Access, error) {
+	return sharing.ListAccessFunc(ctx, id)
 }
 
-func (repository *TaskRepositoryMock) Delete(ctx context.Context, task model.TaskModel) bool {
-	return repository.DeleteFunc(ctx, task)
+func (sharing *SharingMock) TaskAccess(ctx context.Context, id int64) (listmodel.Access, error) {
+	return sharing.TaskAccessFunc(ctx, id)
 }
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -48,59 +49,94 @@ func TestUseCase_GetAllTask(t *testing.T) {
 	ctx := context.Background()
 
 	type arg struct {
-		ctx context.Context
+		ctx    context.Context
+		filter model.TaskFilter
 	}
 	tests := []struct {
 		name    string
 		arg     arg
 		fields  fields
-		want    []model.TaskModel
+		want    model.TaskPage
 		wantErr error
 	}{
 		{
 			name: "case 1 -> success return data with task model struct",
-			arg:  arg{ctx: ctx},
+			arg:  arg{ctx: ctx, filter: model.TaskFilter{Limit: 2}},
 			fields: fields{taskRepository: &TaskRepositoryMock{
-				GetAllFunc: func(ctx context.Context) ([]model.TaskModel, error) {
-					return []model.TaskModel{
-						{
-							ID:       1,
-							TaskName: "task 1",
-							IsDone:   true,
-						},
-						{
-							ID:       2,
-							TaskName: "task 2",
-							IsDone:   false,
+				GetAllFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					return model.TaskPage{
+						Tasks: []model.TaskModel{
+							{
+								ID:       1,
+								TaskName: "task 1",
+								IsDone:   true,
+							},
+							{
+								ID:       2,
+								TaskName: "task 2",
+								IsDone:   false,
+							},
 						},
+						NextCursor: "next",
 					}, nil
 				},
 			}},
-			want: []model.TaskModel{
-				{
-					ID:       1,
-					TaskName: "task 1",
-					IsDone:   true,
-				},
-				{
-					ID:       2,
-					TaskName: "task 2",
-					IsDone:   false,
+			want: model.TaskPage{
+				Tasks: []model.TaskModel{
+					{
+						ID:       1,
+						TaskName: "task 1",
+						IsDone:   true,
+					},
+					{
+						ID:       2,
+						TaskName: "task 2",
+						IsDone:   false,
+					},
 				},
+				NextCursor: "next",
 			},
 			wantErr: nil,
 		},
 		{
-			name: "case 1 -> failed return data",
+			name: "case 2 -> failed return data",
 			arg:  arg{ctx: ctx},
 			fields: fields{taskRepository: &TaskRepositoryMock{
-				GetAllFunc: func(ctx context.Context) ([]model.TaskModel, error) {
-					return nil, errors.New("database error")
+				GetAllFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					return model.TaskPage{}, errors.New("database error")
 				},
 			}},
-			want:    nil,
+			want:    model.TaskPage{},
 			wantErr: errors.New("database error"),
 		},
+		{
+			name: "case 3 -> default limit when not given",
+			arg:  arg{ctx: ctx},
+			fields: fields{taskRepository: &TaskRepositoryMock{
+				GetAllFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					if filter.Limit != model.DefaultPageLimit {
+						return model.TaskPage{}, errors.New("unexpected limit")
+					}
+					return model.TaskPage{Tasks: []model.TaskModel{}}, nil
+				},
+			}},
+			want:    model.TaskPage{Tasks: []model.TaskModel{}},
+			wantErr: nil,
+		},
+		{
+			name: "case 4 -> limit capped to max page limit",
+			arg:  arg{ctx: ctx, filter: model.TaskFilter{Limit: 5000}},
+			fields: fields{taskRepository: &TaskRepositoryMock{
+				GetAllFunc: func(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+					if filter.Limit != model.MaxPageLimit {
+						return model.TaskPage{}, errors.New("unexpected limit")
+					}
+					return model.TaskPage{Tasks: []model.TaskModel{}}, nil
+				},
+			}},
+			want:    model.TaskPage{Tasks: []model.TaskModel{}},
+			wantErr: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -109,7 +145,7 @@ func TestUseCase_GetAllTask(t *testing.T) {
 				taskRepo: tt.fields.taskRepository,
 			}
 
-			got, err := u.GetAllTask(tt.arg.ctx)
+			got, err := u.GetAllTask(tt.arg.ctx, tt.arg.filter)
 
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
@@ -139,9 +175,9 @@ func TestUseCase_CreateTask(t *testing.T) {
 		{
 			name: "case 1 -> success create task",
 			fields: fields{taskRepository: &TaskRepositoryMock{
-				CreateFunc: func(ctx context.Context, task model.TaskModel) model.TaskModel {
+				CreateFunc: func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
 					task.ID = 1
-					return task
+					return task, nil
 				},
 			}},
 			args: args{
@@ -155,15 +191,15 @@ func TestUseCase_CreateTask(t *testing.T) {
 				ID:       1,
 				TaskName: "task 1",
 				IsDone:   true,
+				Timezone: "UTC",
 			},
 			want2: nil,
 		},
 		{
 			name: "case 2 -> fail create task",
 			fields: fields{taskRepository: &TaskRepositoryMock{
-				CreateFunc: func(ctx context.Context, task model.TaskModel) model.TaskModel {
-					task.ID = 0
-					return task
+				CreateFunc: func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+					return task, apperror.New(apperror.ErrConflict, "task already exists")
 				},
 			}},
 			args: args{
@@ -177,8 +213,9 @@ func TestUseCase_CreateTask(t *testing.T) {
 				ID:       0,
 				TaskName: "task 1",
 				IsDone:   true,
+				Timezone: "UTC",
 			},
-			want2: errors.New("error, create task failed"),
+			want2: apperror.New(apperror.ErrConflict, "task already exists"),
 		},
 	}
 
@@ -217,8 +254,11 @@ func TestUseCase_UpdateTask(t *testing.T) {
 		{
 			name: "case 1 -> success update task",
 			fields: fields{taskRepository: &TaskRepositoryMock{
-				UpdateFunc: func(ctx context.Context, task model.TaskModel) (bool, model.TaskModel) {
-					return true, task
+				GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1"}, nil
+				},
+				UpdateFunc: func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+					return task, nil
 				},
 			}},
 			args: args{ctx: ctx, request: model.TaskModel{
@@ -230,14 +270,18 @@ func TestUseCase_UpdateTask(t *testing.T) {
 				ID:       1,
 				TaskName: "task 1",
 				IsDone:   true,
+				Timezone: "UTC",
 			},
 			want2: nil,
 		},
 		{
 			name: "case 1 -> fail update task",
 			fields: fields{taskRepository: &TaskRepositoryMock{
-				UpdateFunc: func(ctx context.Context, task model.TaskModel) (bool, model.TaskModel) {
-					return false, task
+				GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1"}, nil
+				},
+				UpdateFunc: func(ctx context.Context, task model.TaskModel, record model.Record) (model.TaskModel, error) {
+					return task, apperror.New(apperror.ErrNotFound, "task not found")
 				},
 			}},
 			args: args{ctx: ctx, request: model.TaskModel{
@@ -249,8 +293,29 @@ func TestUseCase_UpdateTask(t *testing.T) {
 				ID:       1,
 				TaskName: "task 1",
 				IsDone:   true,
+				Timezone: "UTC",
 			},
-			want2: errors.New("error, update task failed"),
+			want2: apperror.New(apperror.ErrNotFound, "task not found"),
+		},
+		{
+			name: "case 3 -> task changed since the expected version",
+			fields: fields{taskRepository: &TaskRepositoryMock{
+				GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1", Version: 4}, nil
+				},
+			}},
+			args: args{ctx: ctx, request: model.TaskModel{
+				ID:       1,
+				TaskName: "task 1",
+				Version:  3,
+			}},
+			want1: model.TaskModel{
+				ID:       1,
+				TaskName: "task 1",
+				Timezone: "UTC",
+				Version:  3,
+			},
+			want2: apperror.New(apperror.ErrPreconditionFailed, "task has been changed"),
 		},
 	}
 
@@ -289,8 +354,11 @@ func TestUseCase_Delete(t *testing.T) {
 		{
 			name: "case 1 -> success update task",
 			fields: fields{taskRepository: &TaskRepositoryMock{
-				DeleteFunc: func(ctx context.Context, task model.TaskModel) bool {
-					return true
+				GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1"}, nil
+				},
+				DeleteFunc: func(ctx context.Context, task model.TaskModel, record model.Record) error {
+					return nil
 				},
 			}},
 			args: args{ctx: ctx, request: model.TaskModel{
@@ -303,8 +371,11 @@ func TestUseCase_Delete(t *testing.T) {
 		{
 			name: "case 1 -> fail update task",
 			fields: fields{taskRepository: &TaskRepositoryMock{
-				DeleteFunc: func(ctx context.Context, task model.TaskModel) bool {
-					return false
+				GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1"}, nil
+				},
+				DeleteFunc: func(ctx context.Context, task model.TaskModel, record model.Record) error {
+					return apperror.New(apperror.ErrNotFound, "task not found")
 				},
 			}},
 			args: args{ctx: ctx, request: model.TaskModel{
@@ -312,7 +383,17 @@ func TestUseCase_Delete(t *testing.T) {
 				TaskName: "task 1",
 				IsDone:   true,
 			}},
-			want: errors.New("error, delete task failed"),
+			want: apperror.New(apperror.ErrNotFound, "task not found"),
+		},
+		{
+			name: "case 3 -> task changed since the expected version",
+			fields: fields{taskRepository: &TaskRepositoryMock{
+				GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1", Version: 4}, nil
+				},
+			}},
+			args: args{ctx: ctx, request: model.TaskModel{ID: 1, Version: 3}},
+			want: apperror.New(apperror.ErrPreconditionFailed, "task has been changed"),
 		},
 	}
 
@@ -328,3 +409,90 @@ func TestUseCase_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestUseCase_GetTask(t *testing.T) {
+	ctx := context.Background()
+
+	type fields struct {
+		taskRepository *TaskRepositoryMock
+	}
+
+	type args struct {
+		ctx context.Context
+		id  int64
+	}
+
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    model.TaskModel
+		wantErr error
+	}{
+		{
+			name: "case 1 -> success get task",
+			fields: fields{taskRepository: &TaskRepositoryMock{
+				GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{ID: id, TaskName: "task 1"}, nil
+				},
+			}},
+			args:    args{ctx: ctx, id: 1},
+			want:    model.TaskModel{ID: 1, TaskName: "task 1"},
+			wantErr: nil,
+		},
+		{
+			name: "case 2 -> task not found",
+			fields: fields{taskRepository: &TaskRepositoryMock{
+				GetByIDFunc: func(ctx context.Context, id int64) (model.TaskModel, error) {
+					return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "task not found")
+				},
+			}},
+			args:    args{ctx: ctx, id: 1},
+			want:    model.TaskModel{},
+			wantErr: apperror.New(apperror.ErrNotFound, "task not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &Usecase{
+				taskRepo: tt.fields.taskRepository,
+			}
+
+			got, err := u.GetTask(tt.args.ctx, tt.args.id)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  []string
+		want []string
+	}{
+		{
+			name: "case 1 -> no tags",
+			arg:  nil,
+			want: nil,
+		},
+		{
+			name: "case 2 -> lower case, trimmed, unique and sorted",
+			arg:  []string{" Work", "home", "WORK", ""},
+			want: []string{"home", "work"},
+		},
+		{
+			name: "case 3 -> only blank tags",
+			arg:  []string{" "},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeTags(tt.arg))
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package task
+
+import (
+	"context"
+	"time"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+)
+
+func (u *Usecase) GetTrash(ctx context.Context) ([]model.TaskModel, error) {
+	return u.taskRepo.GetTrash(ctx)
+}
+
+// RestoreTask takes a task out of the trash and rolls its status up to its
+// parent, which may have been completed while it was gone.
+func (u *Usecase) RestoreTask(ctx context.Context, id int64) (model.TaskModel, error) {
+	ctx, err := u.forTask(ctx, id, listmodel.RoleEditor)
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+	task, err := u.taskRepo.Restore(ctx, id, u.record(ctx, as(model.EventRestore)))
+	if err != nil {
+		return task, err
+	}
+	return task, u.rollUp(ctx, task.ParentID)
+}
+
+// PurgeTrash removes the tasks that have been in the trash for longer than
+// retention and returns how many it removed.
+func (u *Usecase) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention <= 0 {
+		return 0, apperror.New(apperror.ErrValidation, "retention must be positive")
+	}
+	return u.taskRepo.Purge(ctx, time.Now().Add(-retention))
+}
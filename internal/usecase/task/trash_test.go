@@ -0,0 +1,108 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseCase_RestoreTask(t *testing.T) {
+	ctx := context.Background()
+	parentID := int64(1)
+
+	tests := []struct {
+		name           string
+		restored       model.TaskModel
+		restoreErr     error
+		wantErr        error
+		wantParentDone bool
+	}{
+		{
+			name:           "case 1 -> restoring an open subtask reopens its parent",
+			restored:       model.TaskModel{ID: 3, TaskName: "step 2", ParentID: &parentID},
+			wantParentDone: false,
+		},
+		{
+			name:           "case 2 -> restoring a done subtask keeps the parent done",
+			restored:       model.TaskModel{ID: 3, TaskName: "step 2", IsDone: true, ParentID: &parentID},
+			wantParentDone: true,
+		},
+		{
+			name:           "case 3 -> task not in the trash",
+			restoreErr:     apperror.New(apperror.ErrNotFound, "task not found in trash"),
+			wantErr:        apperror.ErrNotFound,
+			wantParentDone: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := map[int64]*model.TaskModel{
+				1: {ID: 1, TaskName: "task 1", IsDone: true},
+				2: {ID: 2, TaskName: "step 1", IsDone: true, ParentID: &parentID},
+			}
+			repo := subtaskRepository(tasks)
+			repo.RestoreFunc = func(ctx context.Context, id int64, record model.Record) (model.TaskModel, error) {
+				if tt.restoreErr != nil {
+					return model.TaskModel{}, tt.restoreErr
+				}
+				tasks[id] = &tt.restored
+				return tt.restored, nil
+			}
+
+			result, err := NewUseCase(repo).RestoreTask(ctx, 3)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.restored, result)
+			}
+			assert.Equal(t, tt.wantParentDone, tasks[1].IsDone)
+		})
+	}
+}
+
+func TestUseCase_PurgeTrash(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		want      int64
+		wantErr   error
+	}{
+		{
+			name:      "case 1 -> purge trash older than the retention",
+			retention: 24 * time.Hour,
+			want:      2,
+		},
+		{
+			name:      "case 2 -> no retention",
+			retention: 0,
+			wantErr:   apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before time.Time
+			u := NewUseCase(&TaskRepositoryMock{PurgeFunc: func(ctx context.Context, t time.Time) (int64, error) {
+				before = t
+				return 2, nil
+			}})
+
+			purged, err := u.PurgeTrash(ctx, tt.retention)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, purged)
+			assert.WithinDuration(t, time.Now().Add(-tt.retention), before, time.Minute)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package task
+
+import (
+	"context"
+	"errors"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// Undo reverses the last change of the signed in user that has not been
+// undone yet, with every event of the request that made it, and returns the
+// tasks as they are now. Calling it again goes further back. It fails with
+// apperror.ErrConflict when one of the tasks changed since, and changes
+// nothing then.
+func (u *Usecase) Undo(ctx context.Context) ([]model.TaskModel, error) {
+	if u.events == nil {
+		return nil, apperror.New(apperror.ErrNotFound, "nothing to undo")
+	}
+
+	events, err := u.events.GetUndoableEvents(ctx, requestinfo.UserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, apperror.New(apperror.ErrNotFound, "nothing to undo")
+	}
+
+	// a member changed the tasks of a shared list, which they have to
+	// still edit to undo it
+	if owner := events[0].OwnerID; owner != requestinfo.OwnerID(ctx) {
+		ctx, err = u.forTask(ctx, events[0].TaskID, listmodel.RoleEditor)
+		if err != nil {
+			return nil, err
+		}
+		if requestinfo.OwnerID(ctx) != owner {
+			return nil, apperror.New(apperror.ErrNotFound, "nothing to undo")
+		}
+	}
+
+	steps := make([]model.RewindStep, len(events))
+	for i, event := range events {
+		steps[i] = undoStep(event)
+	}
+
+	record := u.record(ctx, func(changes []model.Change) []model.TaskEvent {
+		undone := rewindEvents(changes)
+		for i := range undone {
+			undone[i].Reverts = events[i].ID
+		}
+		return undone
+	})
+	results, err := u.taskRepo.Rewind(ctx, steps, record)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]model.TaskModel, len(results))
+	for i, result := range results {
+		tasks[i] = result.After
+	}
+
+	return tasks, nil
+}
+
+// RevertTask puts a task back to how it was at version to, from the trash or
+// from nothing if needs be. A non-zero version makes it fail with
+// apperror.ErrConflict unless the task is still at that version.
+func (u *Usecase) RevertTask(ctx context.Context, id, to, version int64) (model.TaskModel, error) {
+	if u.events == nil {
+		return model.TaskModel{}, apperror.New(apperror.ErrNotFound, "version not found in the history of the task")
+	}
+
+	// once the task is gone only its owner brings it back
+	ctx, err := u.forTask(ctx, id, listmodel.RoleEditor)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return model.TaskModel{}, err
+	}
+
+	state, err := u.events.GetSnapshot(ctx, id, to)
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+
+	results, err := u.taskRepo.Rewind(ctx, []model.RewindStep{{TaskID: id, Expected: version, State: &state}}, u.record(ctx, rewindEvents))
+	if err != nil {
+		return model.TaskModel{}, err
+	}
+
+	return results[0].After, nil
+}
+
+// undoStep reverses event, on the condition that its task is still where
+// the event left it. A deletion left it in the trash one version later.
+func undoStep(event model.TaskEvent) model.RewindStep {
+	step := model.RewindStep{TaskID: event.TaskID}
+	switch event.Action {
+	case model.EventCreate, model.EventRestore:
+		step.Expected = event.After.Version
+	case model.EventUpdate:
+		step.Expected = event.After.Version
+		step.State = event.Before
+	case model.EventDelete:
+		step.Expected = event.Before.Version + 1
+		step.State = event.Before
+	}
+	return step
+}
+
+// rewindEvents describes what the steps of a rewind did to their tasks. A
+// task back from the trash or from nothing is restored.
+func rewindEvents(changes []model.Change) []model.TaskEvent {
+	return describeChanges(model.EventRestore, changes)
+}
@@ -0,0 +1,233 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+	listmodel "to-do-list/internal/model/list"
+	model "to-do-list/internal/model/task"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseCase_Undo(t *testing.T) {
+	ctx := requestinfo.WithRequestID(requestinfo.WithActor(requestinfo.WithUserID(context.Background(), 1), "alice"), "req-9")
+	deletedAt := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		undoable   []model.TaskEvent
+		results    []model.RewindResult
+		rewindErr  error
+		wantSteps  []model.RewindStep
+		want       []model.TaskModel
+		wantEvents []model.TaskEvent
+		wantErr    error
+	}{
+		{
+			name: "case 1 -> undo a request that updated one task and deleted another",
+			undoable: []model.TaskEvent{
+				{ID: 7, TaskID: 2, Action: model.EventDelete, Before: &model.TaskModel{ID: 2, TaskName: "task 2", Version: 3}, OwnerID: 1},
+				{ID: 6, TaskID: 1, Action: model.EventUpdate, Before: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}, After: &model.TaskModel{ID: 1, TaskName: "task one", Version: 2}, OwnerID: 1},
+			},
+			results: []model.RewindResult{
+				{Before: &model.TaskModel{ID: 2, TaskName: "task 2", Version: 4, DeletedAt: &deletedAt}, After: model.TaskModel{ID: 2, TaskName: "task 2", Version: 6}},
+				{Before: &model.TaskModel{ID: 1, TaskName: "task one", Version: 2}, After: model.TaskModel{ID: 1, TaskName: "task 1", Version: 3}},
+			},
+			wantSteps: []model.RewindStep{
+				{TaskID: 2, Expected: 4, State: &model.TaskModel{ID: 2, TaskName: "task 2", Version: 3}},
+				{TaskID: 1, Expected: 2, State: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 1}},
+			},
+			want: []model.TaskModel{{ID: 2, TaskName: "task 2", Version: 6}, {ID: 1, TaskName: "task 1", Version: 3}},
+			wantEvents: []model.TaskEvent{
+				{TaskID: 2, Action: model.EventRestore, After: &model.TaskModel{ID: 2, TaskName: "task 2", Version: 6}, Actor: "alice", RequestID: "req-9", Reverts: 7},
+				{TaskID: 1, Action: model.EventUpdate, Before: &model.TaskModel{ID: 1, TaskName: "task one", Version: 2}, After: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 3}, Actor: "alice", RequestID: "req-9", Reverts: 6},
+			},
+		},
+		{
+			name: "case 2 -> undo a creation",
+			undoable: []model.TaskEvent{
+				{ID: 5, TaskID: 3, Action: model.EventCreate, After: &model.TaskModel{ID: 3, TaskName: "task 3", Version: 1}, OwnerID: 1},
+			},
+			results: []model.RewindResult{
+				{Before: &model.TaskModel{ID: 3, TaskName: "task 3", Version: 1}, After: model.TaskModel{ID: 3, TaskName: "task 3", Version: 2, DeletedAt: &deletedAt}},
+			},
+			wantSteps: []model.RewindStep{{TaskID: 3, Expected: 1}},
+			want:      []model.TaskModel{{ID: 3, TaskName: "task 3", Version: 2, DeletedAt: &deletedAt}},
+			wantEvents: []model.TaskEvent{
+				{TaskID: 3, Action: model.EventDelete, Before: &model.TaskModel{ID: 3, TaskName: "task 3", Version: 1}, Actor: "alice", RequestID: "req-9", Reverts: 5},
+			},
+		},
+		{
+			name:    "case 3 -> nothing to undo",
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name: "case 4 -> task changed since",
+			undoable: []model.TaskEvent{
+				{ID: 5, TaskID: 3, Action: model.EventCreate, After: &model.TaskModel{ID: 3, TaskName: "task 3", Version: 1}, OwnerID: 1},
+			},
+			rewindErr: apperror.New(apperror.ErrConflict, "task 3 has changed since"),
+			wantSteps: []model.RewindStep{{TaskID: 3, Expected: 1}},
+			wantErr:   apperror.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotSteps  []model.RewindStep
+				gotEvents []model.TaskEvent
+			)
+			repo := &TaskRepositoryMock{RewindFunc: func(ctx context.Context, steps []model.RewindStep, record model.Record) ([]model.RewindResult, error) {
+				gotSteps = steps
+				if tt.rewindErr == nil {
+					gotEvents = record(model.RewindChanges(tt.results))
+				}
+				return tt.results, tt.rewindErr
+			}}
+			events := &EventRepositoryMock{
+				GetUndoableEventsFunc: func(ctx context.Context, user int64) ([]model.TaskEvent, error) {
+					assert.Equal(t, int64(1), user)
+					return tt.undoable, nil
+				},
+			}
+
+			tasks, err := NewUseCase(repo, WithEvents(events)).Undo(ctx)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, tasks)
+			assert.Equal(t, tt.wantSteps, gotSteps)
+			assert.Equal(t, tt.wantEvents, gotEvents)
+		})
+	}
+}
+
+func TestUseCase_UndoSharedTask(t *testing.T) {
+	// user 2 edited a task of user 1 in a shared list, and made an older
+	// change to a task of their own
+	ctx := requestinfo.WithActor(requestinfo.WithUserID(context.Background(), 2), "bob")
+	undoable := []model.TaskEvent{
+		{ID: 9, TaskID: 1, Action: model.EventUpdate, Before: &model.TaskModel{ID: 1, TaskName: "shared", Version: 1}, After: &model.TaskModel{ID: 1, TaskName: "shared task", Version: 2}, Actor: "bob", OwnerID: 1},
+	}
+
+	tests := []struct {
+		name       string
+		role       listmodel.Role
+		wantSteps  []model.RewindStep
+		wantEvents []model.TaskEvent
+		wantErr    error
+	}{
+		{
+			name:      "case 1 -> the editor undoes their change to the task of the owner",
+			role:      listmodel.RoleEditor,
+			wantSteps: []model.RewindStep{{TaskID: 1, Expected: 2, State: &model.TaskModel{ID: 1, TaskName: "shared", Version: 1}}},
+			wantEvents: []model.TaskEvent{
+				{TaskID: 1, Action: model.EventUpdate, Before: &model.TaskModel{ID: 1, TaskName: "shared task", Version: 2}, After: &model.TaskModel{ID: 1, TaskName: "shared", Version: 3}, Actor: "bob", Reverts: 9},
+			},
+		},
+		{
+			name:    "case 2 -> a member who is now a viewer cannot undo it",
+			role:    listmodel.RoleViewer,
+			wantErr: apperror.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotSteps  []model.RewindStep
+				gotEvents []model.TaskEvent
+			)
+			repo := &TaskRepositoryMock{RewindFunc: func(ctx context.Context, steps []model.RewindStep, record model.Record) ([]model.RewindResult, error) {
+				assert.Equal(t, int64(1), requestinfo.OwnerID(ctx), "the task is changed back as its owner")
+				assert.Equal(t, int64(2), requestinfo.UserID(ctx))
+				gotSteps = steps
+				results := []model.RewindResult{{
+					Before: &model.TaskModel{ID: 1, TaskName: "shared task", Version: 2},
+					After:  model.TaskModel{ID: 1, TaskName: "shared", Version: 3},
+				}}
+				gotEvents = record(model.RewindChanges(results))
+				return results, nil
+			}}
+			events := &EventRepositoryMock{
+				GetUndoableEventsFunc: func(ctx context.Context, user int64) ([]model.TaskEvent, error) {
+					assert.Equal(t, int64(2), user)
+					return undoable, nil
+				},
+			}
+
+			_, err := NewUseCase(repo, WithEvents(events), WithSharing(newSharing(tt.role))).Undo(ctx)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantSteps, gotSteps)
+			assert.Equal(t, tt.wantEvents, gotEvents)
+		})
+	}
+}
+
+func TestUseCase_RevertTask(t *testing.T) {
+	snapshot := model.TaskModel{ID: 1, TaskName: "task 1", Version: 2}
+
+	tests := []struct {
+		name       string
+		snapErr    error
+		wantStep   []model.RewindStep
+		want       model.TaskModel
+		wantEvents []model.TaskEvent
+		wantErr    error
+	}{
+		{
+			name:     "case 1 -> revert to a recorded version",
+			wantStep: []model.RewindStep{{TaskID: 1, Expected: 4, State: &snapshot}},
+			want:     model.TaskModel{ID: 1, TaskName: "task 1", Version: 5},
+			wantEvents: []model.TaskEvent{
+				{TaskID: 1, Action: model.EventUpdate, Before: &model.TaskModel{ID: 1, TaskName: "task one", Version: 4}, After: &model.TaskModel{ID: 1, TaskName: "task 1", Version: 5}, Actor: "anonymous"},
+			},
+		},
+		{
+			name:    "case 2 -> version never recorded",
+			snapErr: apperror.New(apperror.ErrNotFound, "version not found in the history of the task"),
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotSteps  []model.RewindStep
+				gotEvents []model.TaskEvent
+			)
+			repo := &TaskRepositoryMock{RewindFunc: func(ctx context.Context, steps []model.RewindStep, record model.Record) ([]model.RewindResult, error) {
+				gotSteps = steps
+				results := []model.RewindResult{{Before: &model.TaskModel{ID: 1, TaskName: "task one", Version: 4}, After: model.TaskModel{ID: 1, TaskName: "task 1", Version: 5}}}
+				gotEvents = record(model.RewindChanges(results))
+				return results, nil
+			}}
+			events := &EventRepositoryMock{
+				GetSnapshotFunc: func(ctx context.Context, id, version int64) (model.TaskModel, error) {
+					assert.Equal(t, int64(2), version)
+					return snapshot, tt.snapErr
+				},
+			}
+
+			task, err := NewUseCase(repo, WithEvents(events)).RevertTask(context.Background(), 1, 2, 4)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, task)
+			assert.Equal(t, tt.wantStep, gotSteps)
+			assert.Equal(t, tt.wantEvents, gotEvents)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package user
+
+import (
+	"context"
+	"errors"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+)
+
+// APIKeyPrefix starts every API key, which tells them apart from access
+// tokens and makes leaked keys easy to search for.
+const APIKeyPrefix = "tdl_"
+
+// prefixLength is how much of a key is kept in clear to tell keys apart.
+const prefixLength = len(APIKeyPrefix) + 8
+
+// CreateAPIKey returns the new key with the key itself, which cannot be
+// read again afterwards.
+func (u *Usecase) CreateAPIKey(ctx context.Context, r model.APIKeyRequest) (model.APIKeyModel, error) {
+	random, err := randomToken()
+	if err != nil {
+		return model.APIKeyModel{}, err
+	}
+	secret := APIKeyPrefix + random
+
+	key, err := u.userRepo.CreateAPIKey(ctx, model.APIKeyModel{
+		Name:     r.Name,
+		Prefix:   secret[:prefixLength],
+		ReadOnly: r.ReadOnly,
+		Hash:     hashToken(secret),
+	})
+	if err != nil {
+		return model.APIKeyModel{}, err
+	}
+
+	key.Key = secret
+	return key, nil
+}
+
+func (u *Usecase) GetAPIKeys(ctx context.Context) ([]model.APIKeyModel, error) {
+	return u.userRepo.GetAPIKeys(ctx)
+}
+
+func (u *Usecase) RevokeAPIKey(ctx context.Context, id int64) error {
+	return u.userRepo.RevokeAPIKey(ctx, id)
+}
+
+func (u *Usecase) authenticateKey(ctx context.Context, key string) (model.Principal, error) {
+	principal, err := u.userRepo.UseAPIKey(ctx, hashToken(key))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return model.Principal{}, apperror.New(apperror.ErrUnauthorized, "invalid API key")
+	}
+	return principal, err
+}
@@ -0,0 +1,78 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"testing"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseCase_CreateAPIKey(t *testing.T) {
+	var stored model.APIKeyModel
+	repo := &UserRepositoryMock{
+		CreateAPIKeyFunc: func(ctx context.Context, key model.APIKeyModel) (model.APIKeyModel, error) {
+			stored = key
+			key.ID = 1
+			return key, nil
+		},
+	}
+
+	got, err := newUseCase(repo).CreateAPIKey(context.Background(), model.APIKeyRequest{Name: "backup", ReadOnly: true})
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got.Key, APIKeyPrefix))
+	assert.Equal(t, got.Key[:prefixLength], got.Prefix)
+	assert.Equal(t, hashToken(got.Key), stored.Hash)
+	assert.Empty(t, stored.Key, "the key itself is never stored")
+	assert.Equal(t, "backup", stored.Name)
+	assert.True(t, stored.ReadOnly)
+}
+
+func TestUseCase_AuthenticateAPIKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		useErr  error
+		want    model.Principal
+		wantErr error
+	}{
+		{
+			name: "case 1 -> valid key",
+			want: model.Principal{UserID: 1, Email: "alice@example.com", ReadOnly: true},
+		},
+		{
+			name:    "case 2 -> unknown or revoked key",
+			useErr:  apperror.New(apperror.ErrNotFound, "api key not found"),
+			wantErr: apperror.ErrUnauthorized,
+		},
+		{
+			name:    "case 3 -> storage error is returned",
+			useErr:  apperror.New(apperror.ErrUnavailable, "storage unavailable"),
+			wantErr: apperror.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &UserRepositoryMock{
+				UseAPIKeyFunc: func(ctx context.Context, hash string) (model.Principal, error) {
+					assert.Equal(t, hashToken("tdl_secret"), hash)
+					if tt.useErr != nil {
+						return model.Principal{}, tt.useErr
+					}
+					return tt.want, nil
+				},
+			}
+
+			got, err := newUseCase(repo).Authenticate(context.Background(), "tdl_secret")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
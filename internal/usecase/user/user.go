@@ -0,0 +1,207 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/password"
+	"to-do-list/pkg/token"
+)
+
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+type Usecase struct {
+	userRepo   Repo
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// Option changes a rule of the Usecase.
+type Option func(*Usecase)
+
+// WithAccessTTL sets how long an access token is accepted.
+func WithAccessTTL(ttl time.Duration) Option {
+	return func(u *Usecase) {
+		u.accessTTL = ttl
+	}
+}
+
+// WithRefreshTTL sets how long a refresh token can be traded for new tokens.
+func WithRefreshTTL(ttl time.Duration) Option {
+	return func(u *Usecase) {
+		u.refreshTTL = ttl
+	}
+}
+
+// NewUseCase signs access tokens with secret, which must be kept private
+// and the same on every instance.
+func NewUseCase(repo Repo, secret []byte, opts ...Option) *Usecase {
+	u := &Usecase{
+		userRepo:   repo,
+		secret:     secret,
+		accessTTL:  DefaultAccessTTL,
+		refreshTTL: DefaultRefreshTTL,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+type Repo interface {
+	Create(ctx context.Context, user model.UserModel) (model.UserModel, error)
+	GetByEmail(ctx context.Context, email string) (model.UserModel, error)
+	GetByID(ctx context.Context, id int64) (model.UserModel, error)
+	SaveRefreshToken(ctx context.Context, token model.RefreshToken) error
+	TakeRefreshToken(ctx context.Context, hash string) (model.RefreshToken, error)
+	CreateAPIKey(ctx context.Context, key model.APIKeyModel) (model.APIKeyModel, error)
+	GetAPIKeys(ctx context.Context) ([]model.APIKeyModel, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+	UseAPIKey(ctx context.Context, hash string) (model.Principal, error)
+}
+
+func (u *Usecase) Register(ctx context.Context, r model.Credentials) (model.UserModel, error) {
+	hash, err := password.Hash(r.Password)
+	if err != nil {
+		return model.UserModel{}, err
+	}
+	return u.userRepo.Create(ctx, model.UserModel{Email: normalize(r.Email), PasswordHash: hash})
+}
+
+// Login answers the same way, and takes as long, for an unknown email and
+// a wrong password, so it does not tell which emails have an account.
+func (u *Usecase) Login(ctx context.Context, r model.Credentials) (model.TokenPair, error) {
+	user, err := u.userRepo.GetByEmail(ctx, normalize(r.Email))
+	if errors.Is(err, apperror.ErrNotFound) {
+		password.VerifyNone(r.Password)
+		return model.TokenPair{}, apperror.New(apperror.ErrUnauthorized, "invalid email or password")
+	}
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	if !password.Verify(r.Password, user.PasswordHash) {
+		return model.TokenPair{}, apperror.New(apperror.ErrUnauthorized, "invalid email or password")
+	}
+	return u.issue(ctx, user)
+}
+
+// Refresh trades a refresh token for a new pair. A refresh token is good
+// for one trade only.
+func (u *Usecase) Refresh(ctx context.Context, refreshToken string) (model.TokenPair, error) {
+	stored, err := u.userRepo.TakeRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return model.TokenPair{}, apperror.New(apperror.ErrUnauthorized, "invalid refresh token")
+	}
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	if !u.now().Before(stored.ExpiresAt) {
+		return model.TokenPair{}, apperror.New(apperror.ErrUnauthorized, "invalid refresh token")
+	}
+
+	user, err := u.userRepo.GetByID(ctx, stored.UserID)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return model.TokenPair{}, apperror.New(apperror.ErrUnauthorized, "invalid refresh token")
+	}
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	return u.issue(ctx, user)
+}
+
+// Logout revokes a refresh token. Access tokens already issued stay valid
+// until they expire.
+func (u *Usecase) Logout(ctx context.Context, refreshToken string) error {
+	_, err := u.userRepo.TakeRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Authenticate returns who a credential was issued to. The credential is
+// either an API key or an access token.
+func (u *Usecase) Authenticate(ctx context.Context, credential string) (model.Principal, error) {
+	if strings.HasPrefix(credential, APIKeyPrefix) {
+		return u.authenticateKey(ctx, credential)
+	}
+
+	claims, err := token.Parse(credential, u.secret, u.now())
+	if err != nil {
+		return model.Principal{}, apperror.Wrap(apperror.ErrUnauthorized, "invalid access token", err)
+	}
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || id <= 0 {
+		return model.Principal{}, apperror.Wrap(apperror.ErrUnauthorized, "invalid access token", err)
+	}
+	return model.Principal{UserID: id, Email: claims.Email}, nil
+}
+
+func (u *Usecase) issue(ctx context.Context, user model.UserModel) (model.TokenPair, error) {
+	now := u.now()
+
+	access, err := token.Sign(token.Claims{
+		Subject:   strconv.FormatInt(user.ID, 10),
+		Email:     user.Email,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(u.accessTTL).Unix(),
+	}, u.secret)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	refresh, err := randomToken()
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	err = u.userRepo.SaveRefreshToken(ctx, model.RefreshToken{
+		Hash:      hashToken(refresh),
+		UserID:    user.ID,
+		ExpiresAt: now.Add(u.refreshTTL),
+	})
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	return model.TokenPair{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(u.accessTTL / time.Second),
+		RefreshToken: refresh,
+	}, nil
+}
+
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// hashToken is what the repo stores instead of a refresh token or an API
+// key, so a leak of the table leaks no usable token. Tokens are random, a plain hash is
+// enough.
+func hashToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalize(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
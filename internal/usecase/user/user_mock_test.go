@@ -0,0 +1,54 @@
+package user
+
+import (
+	"context"
+	model "to-do-list/internal/model/user"
+)
+
+type UserRepositoryMock struct {
+	CreateFunc           func(ctx context.Context, user model.UserModel) (model.UserModel, error)
+	GetByEmailFunc       func(ctx context.Context, email string) (model.UserModel, error)
+	GetByIDFunc          func(ctx context.Context, id int64) (model.UserModel, error)
+	SaveRefreshTokenFunc func(ctx context.Context, token model.RefreshToken) error
+	TakeRefreshTokenFunc func(ctx context.Context, hash string) (model.RefreshToken, error)
+	CreateAPIKeyFunc     func(ctx context.Context, key model.APIKeyModel) (model.APIKeyModel, error)
+	GetAPIKeysFunc       func(ctx context.Context) ([]model.APIKeyModel, error)
+	RevokeAPIKeyFunc     func(ctx context.Context, id int64) error
+	UseAPIKeyFunc        func(ctx context.Context, hash string) (model.Principal, error)
+}
+
+func (repository *UserRepositoryMock) Create(ctx context.Context, user model.UserModel) (model.UserModel, error) {
+	return repository.CreateFunc(ctx, user)
+}
+
+func (repository *UserRepositoryMock) GetByEmail(ctx context.Context, email string) (model.UserModel, error) {
+	return repository.GetByEmailFunc(ctx, email)
+}
+
+func (repository *UserRepositoryMock) GetByID(ctx context.Context, id int64) (model.UserModel, error) {
+	return repository.GetByIDFunc(ctx, id)
+}
+
+func (repository *UserRepositoryMock) SaveRefreshToken(ctx context.Context, token model.RefreshToken) error {
+	return repository.SaveRefreshTokenFunc(ctx, token)
+}
+
+func (repository *UserRepositoryMock) TakeRefreshToken(ctx context.Context, hash string) (model.RefreshToken, error) {
+	return repository.TakeRefreshTokenFunc(ctx, hash)
+}
+
+func (repository *UserRepositoryMock) CreateAPIKey(ctx context.Context, key model.APIKeyModel) (model.APIKeyModel, error) {
+	return repository.CreateAPIKeyFunc(ctx, key)
+}
+
+func (repository *UserRepositoryMock) GetAPIKeys(ctx context.Context) ([]model.APIKeyModel, error) {
+	return repository.GetAPIKeysFunc(ctx)
+}
+
+func (repository *UserRepositoryMock) RevokeAPIKey(ctx context.Context, id int64) error {
+	return repository.RevokeAPIKeyFunc(ctx, id)
+}
+
+func (repository *UserRepositoryMock) UseAPIKey(ctx context.Context, hash string) (model.Principal, error) {
+	return repository.UseAPIKeyFunc(ctx, hash)
+}
@@ -0,0 +1,233 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+	model "to-do-list/internal/model/user"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/password"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	secret = []byte("test-secret")
+	now    = time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+)
+
+func newUseCase(repo Repo) *Usecase {
+	u := NewUseCase(repo, secret)
+	u.now = func() time.Time { return now }
+	return u
+}
+
+func TestUseCase_Register(t *testing.T) {
+	repo := &UserRepositoryMock{
+		CreateFunc: func(ctx context.Context, user model.UserModel) (model.UserModel, error) {
+			user.ID = 1
+			return user, nil
+		},
+	}
+
+	got, err := newUseCase(repo).Register(context.Background(), model.Credentials{Email: " Alice@Example.com ", Password: "correct horse"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), got.ID)
+	assert.Equal(t, "alice@example.com", got.Email)
+	assert.NotEqual(t, "correct horse", got.PasswordHash)
+	assert.True(t, password.Verify("correct horse", got.PasswordHash))
+}
+
+func TestUseCase_Login(t *testing.T) {
+	hash, err := password.Hash("correct horse")
+	assert.NoError(t, err)
+
+	alice := model.UserModel{ID: 1, Email: "alice@example.com", PasswordHash: hash}
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		wantErr  error
+	}{
+		{
+			name:     "case 1 -> valid credentials",
+			email:    "Alice@example.com",
+			password: "correct horse",
+		},
+		{
+			name:     "case 2 -> wrong password",
+			email:    "alice@example.com",
+			password: "wrong horse",
+			wantErr:  apperror.ErrUnauthorized,
+		},
+		{
+			name:     "case 3 -> unknown email",
+			email:    "bob@example.com",
+			password: "correct horse",
+			wantErr:  apperror.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved model.RefreshToken
+			repo := &UserRepositoryMock{
+				GetByEmailFunc: func(ctx context.Context, email string) (model.UserModel, error) {
+					if email != alice.Email {
+						return model.UserModel{}, apperror.New(apperror.ErrNotFound, "user not found")
+					}
+					return alice, nil
+				},
+				SaveRefreshTokenFunc: func(ctx context.Context, token model.RefreshToken) error {
+					saved = token
+					return nil
+				},
+			}
+			u := newUseCase(repo)
+
+			got, err := u.Login(context.Background(), model.Credentials{Email: tt.email, Password: tt.password})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, model.TokenPair{}, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "Bearer", got.TokenType)
+			assert.Equal(t, int64(900), got.ExpiresIn)
+			assert.Equal(t, model.RefreshToken{Hash: hashToken(got.RefreshToken), UserID: 1, ExpiresAt: now.Add(DefaultRefreshTTL)}, saved)
+
+			user, err := u.Authenticate(context.Background(), got.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, model.Principal{UserID: 1, Email: "alice@example.com"}, user)
+		})
+	}
+}
+
+func TestUseCase_Refresh(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  model.RefreshToken
+		takeErr error
+		wantErr error
+	}{
+		{
+			name:   "case 1 -> token is traded for a new pair",
+			stored: model.RefreshToken{UserID: 1, ExpiresAt: now.Add(time.Hour)},
+		},
+		{
+			name:    "case 2 -> unknown or already used token",
+			takeErr: apperror.New(apperror.ErrNotFound, "refresh token not found"),
+			wantErr: apperror.ErrUnauthorized,
+		},
+		{
+			name:    "case 3 -> expired token",
+			stored:  model.RefreshToken{UserID: 1, ExpiresAt: now},
+			wantErr: apperror.ErrUnauthorized,
+		},
+		{
+			name:    "case 4 -> storage error is returned",
+			takeErr: apperror.New(apperror.ErrUnavailable, "storage unavailable"),
+			wantErr: apperror.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &UserRepositoryMock{
+				TakeRefreshTokenFunc: func(ctx context.Context, hash string) (model.RefreshToken, error) {
+					assert.Equal(t, hashToken("old"), hash)
+					return tt.stored, tt.takeErr
+				},
+				GetByIDFunc: func(ctx context.Context, id int64) (model.UserModel, error) {
+					return model.UserModel{ID: id, Email: "alice@example.com"}, nil
+				},
+				SaveRefreshTokenFunc: func(ctx context.Context, token model.RefreshToken) error {
+					return nil
+				},
+			}
+
+			got, err := newUseCase(repo).Refresh(context.Background(), "old")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, got.AccessToken)
+			assert.NotEqual(t, "old", got.RefreshToken)
+		})
+	}
+}
+
+func TestUseCase_Logout(t *testing.T) {
+	repo := &UserRepositoryMock{
+		TakeRefreshTokenFunc: func(ctx context.Context, hash string) (model.RefreshToken, error) {
+			return model.RefreshToken{}, apperror.New(apperror.ErrNotFound, "refresh token not found")
+		},
+	}
+
+	assert.NoError(t, newUseCase(repo).Logout(context.Background(), "unknown"))
+}
+
+func TestUseCase_Authenticate(t *testing.T) {
+	repo := &UserRepositoryMock{
+		SaveRefreshTokenFunc: func(ctx context.Context, token model.RefreshToken) error {
+			return nil
+		},
+	}
+	pair, err := newUseCase(repo).issue(context.Background(), model.UserModel{ID: 1, Email: "alice@example.com"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		at      time.Time
+		secret  []byte
+		wantErr error
+	}{
+		{
+			name:   "case 1 -> valid token",
+			token:  pair.AccessToken,
+			at:     now,
+			secret: secret,
+		},
+		{
+			name:    "case 2 -> expired token",
+			token:   pair.AccessToken,
+			at:      now.Add(DefaultAccessTTL),
+			secret:  secret,
+			wantErr: apperror.ErrUnauthorized,
+		},
+		{
+			name:    "case 3 -> token signed with another secret",
+			token:   pair.AccessToken,
+			at:      now,
+			secret:  []byte("other-secret"),
+			wantErr: apperror.ErrUnauthorized,
+		},
+		{
+			name:    "case 4 -> refresh token used as access token",
+			token:   pair.RefreshToken,
+			at:      now,
+			secret:  secret,
+			wantErr: apperror.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUseCase(repo, tt.secret)
+			u.now = func() time.Time { return tt.at }
+
+			got, err := u.Authenticate(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, model.Principal{UserID: 1, Email: "alice@example.com"}, got)
+		})
+	}
+}
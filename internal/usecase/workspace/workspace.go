@@ -0,0 +1,130 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	model "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+)
+
+// slugPattern keeps slugs usable as a subdomain.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+type Usecase struct {
+	workspaceRepo Repo
+}
+
+func NewUseCase(repo Repo) *Usecase {
+	return &Usecase{
+		workspaceRepo: repo,
+	}
+}
+
+type Repo interface {
+	GetAll(ctx context.Context) ([]model.WorkspaceModel, error)
+	GetByID(ctx context.Context, id int64) (model.WorkspaceModel, error)
+	GetBySlug(ctx context.Context, slug string) (model.WorkspaceModel, error)
+	Create(ctx context.Context, workspace model.WorkspaceModel) (model.WorkspaceModel, error)
+	GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error)
+	AddMember(ctx context.Context, id int64, email string) (model.MemberModel, error)
+	RemoveMember(ctx context.Context, id int64, userID int64) error
+}
+
+// Resolve returns the workspace a request works in: the one named by slug,
+// else the one the API key of the request is limited to, else the default
+// one. Workspaces the user is not a member of are not found, and a key
+// limited to a workspace is refused in the others.
+func (u *Usecase) Resolve(ctx context.Context, slug string) (model.WorkspaceModel, error) {
+	var (
+		pinned    = requestinfo.WorkspaceID(ctx)
+		workspace model.WorkspaceModel
+		err       error
+	)
+
+	switch {
+	case slug != "":
+		workspace, err = u.workspaceRepo.GetBySlug(ctx, strings.ToLower(slug))
+	case pinned != 0:
+		workspace, err = u.workspaceRepo.GetByID(ctx, pinned)
+	default:
+		workspace, err = u.workspaceRepo.GetByID(ctx, model.DefaultID)
+	}
+	if errors.Is(err, apperror.ErrNotFound) {
+		return workspace, apperror.New(apperror.ErrNotFound, "workspace not found")
+	}
+	if err != nil {
+		return workspace, err
+	}
+
+	if pinned != 0 && workspace.ID != pinned {
+		return model.WorkspaceModel{}, apperror.New(apperror.ErrForbidden, "API key is limited to another workspace")
+	}
+	return workspace, nil
+}
+
+// GetWorkspaces returns the workspaces of the signed in user.
+func (u *Usecase) GetWorkspaces(ctx context.Context) ([]model.WorkspaceModel, error) {
+	return u.workspaceRepo.GetAll(ctx)
+}
+
+// CreateWorkspace makes the signed in user the owner of a new workspace.
+func (u *Usecase) CreateWorkspace(ctx context.Context, r model.WorkspaceModel) (model.WorkspaceModel, error) {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Slug = strings.ToLower(strings.TrimSpace(r.Slug))
+	if r.Name == "" {
+		return model.WorkspaceModel{}, apperror.New(apperror.ErrValidation, "name must not be blank")
+	}
+	if !slugPattern.MatchString(r.Slug) {
+		return model.WorkspaceModel{}, apperror.New(apperror.ErrValidation, "slug must be lower case letters, digits and inner hyphens")
+	}
+	return u.workspaceRepo.Create(ctx, r)
+}
+
+// GetMembers returns the members of a workspace of the signed in user.
+func (u *Usecase) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	if _, err := u.workspace(ctx, id, model.RoleMember); err != nil {
+		return nil, err
+	}
+	return u.workspaceRepo.GetMembers(ctx, id)
+}
+
+// AddMember gives a user the tasks of a workspace, only its owner can.
+func (u *Usecase) AddMember(ctx context.Context, id int64, r model.MemberRequest) (model.MemberModel, error) {
+	if _, err := u.workspace(ctx, id, model.RoleOwner); err != nil {
+		return model.MemberModel{}, err
+	}
+	return u.workspaceRepo.AddMember(ctx, id, strings.ToLower(strings.TrimSpace(r.Email)))
+}
+
+// RemoveMember takes a user out of a workspace. Owners remove anyone but
+// themselves, other members only themselves, to leave.
+func (u *Usecase) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	need := model.RoleMember
+	if userID != requestinfo.UserID(ctx) {
+		need = model.RoleOwner
+	}
+	if _, err := u.workspace(ctx, id, need); err != nil {
+		return err
+	}
+	return u.workspaceRepo.RemoveMember(ctx, id, userID)
+}
+
+// workspace returns a workspace of the signed in user when they have the
+// role it needs. The default workspace has no owner and no members to
+// manage.
+func (u *Usecase) workspace(ctx context.Context, id int64, need model.Role) (model.WorkspaceModel, error) {
+	if id == model.DefaultID {
+		return model.WorkspaceModel{}, apperror.New(apperror.ErrForbidden, "the default workspace is open to every user")
+	}
+	workspace, err := u.workspaceRepo.GetByID(ctx, id)
+	if err != nil {
+		return workspace, err
+	}
+	if need == model.RoleOwner && workspace.Role != model.RoleOwner {
+		return workspace, apperror.New(apperror.ErrForbidden, "only the owner of the workspace manages its members")
+	}
+	return workspace, nil
+}
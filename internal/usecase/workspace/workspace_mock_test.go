@@ -0,0 +1,44 @@
+package workspace
+
+import (
+	"context"
+	model "to-do-list/internal/model/workspace"
+)
+
+type WorkspaceRepositoryMock struct {
+	GetAllFunc       func(ctx context.Context) ([]model.WorkspaceModel, error)
+	GetByIDFunc      func(ctx context.Context, id int64) (model.WorkspaceModel, error)
+	GetBySlugFunc    func(ctx context.Context, slug string) (model.WorkspaceModel, error)
+	CreateFunc       func(ctx context.Context, workspace model.WorkspaceModel) (model.WorkspaceModel, error)
+	GetMembersFunc   func(ctx context.Context, id int64) ([]model.MemberModel, error)
+	AddMemberFunc    func(ctx context.Context, id int64, email string) (model.MemberModel, error)
+	RemoveMemberFunc func(ctx context.Context, id int64, userID int64) error
+}
+
+func (repository *WorkspaceRepositoryMock) GetAll(ctx context.Context) ([]model.WorkspaceModel, error) {
+	return repository.GetAllFunc(ctx)
+}
+
+func (repository *WorkspaceRepositoryMock) GetByID(ctx context.Context, id int64) (model.WorkspaceModel, error) {
+	return repository.GetByIDFunc(ctx, id)
+}
+
+func (repository *WorkspaceRepositoryMock) GetBySlug(ctx context.Context, slug string) (model.WorkspaceModel, error) {
+	return repository.GetBySlugFunc(ctx, slug)
+}
+
+func (repository *WorkspaceRepositoryMock) Create(ctx context.Context, workspace model.WorkspaceModel) (model.WorkspaceModel, error) {
+	return repository.CreateFunc(ctx, workspace)
+}
+
+func (repository *WorkspaceRepositoryMock) GetMembers(ctx context.Context, id int64) ([]model.MemberModel, error) {
+	return repository.GetMembersFunc(ctx, id)
+}
+
+func (repository *WorkspaceRepositoryMock) AddMember(ctx context.Context, id int64, email string) (model.MemberModel, error) {
+	return repository.AddMemberFunc(ctx, id, email)
+}
+
+func (repository *WorkspaceRepositoryMock) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	return repository.RemoveMemberFunc(ctx, id, userID)
+}
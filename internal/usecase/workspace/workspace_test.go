@@ -0,0 +1,212 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+	model "to-do-list/internal/model/workspace"
+	"to-do-list/pkg/apperror"
+	"to-do-list/pkg/requestinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// workspaces answers like the repo for a user who is a member of the
+// default workspace and of acme, owned by user 4, but not of globex.
+var workspaces = map[string]model.WorkspaceModel{
+	"default": {ID: model.DefaultID, Name: "Default", Slug: "default", Role: model.RoleMember},
+	"acme":    {ID: 2, Name: "Acme", Slug: "acme", Role: model.RoleMember},
+}
+
+func workspaceRepo() *WorkspaceRepositoryMock {
+	return &WorkspaceRepositoryMock{
+		GetBySlugFunc: func(ctx context.Context, slug string) (model.WorkspaceModel, error) {
+			if workspace, ok := workspaces[slug]; ok {
+				return workspace, nil
+			}
+			return model.WorkspaceModel{}, apperror.New(apperror.ErrNotFound, "workspace not found")
+		},
+		GetByIDFunc: func(ctx context.Context, id int64) (model.WorkspaceModel, error) {
+			for _, workspace := range workspaces {
+				if workspace.ID == id {
+					return workspace, nil
+				}
+			}
+			return model.WorkspaceModel{}, apperror.New(apperror.ErrNotFound, "workspace not found")
+		},
+	}
+}
+
+func TestUseCase_Resolve(t *testing.T) {
+	userCtx := requestinfo.WithUserID(context.Background(), 1)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		slug    string
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "case 1 -> no workspace named",
+			ctx:  userCtx,
+			want: model.DefaultID,
+		},
+		{
+			name: "case 2 -> workspace of the user by slug",
+			ctx:  userCtx,
+			slug: "Acme",
+			want: 2,
+		},
+		{
+			name:    "case 3 -> workspace the user is not a member of",
+			ctx:     userCtx,
+			slug:    "globex",
+			wantErr: apperror.ErrNotFound,
+		},
+		{
+			name: "case 4 -> API key limited to a workspace",
+			ctx:  requestinfo.WithWorkspaceID(userCtx, 2),
+			want: 2,
+		},
+		{
+			name: "case 5 -> API key limited to a workspace names it",
+			ctx:  requestinfo.WithWorkspaceID(userCtx, 2),
+			slug: "acme",
+			want: 2,
+		},
+		{
+			name:    "case 6 -> API key limited to a workspace names another",
+			ctx:     requestinfo.WithWorkspaceID(userCtx, 2),
+			slug:    "default",
+			wantErr: apperror.ErrForbidden,
+		},
+		{
+			name:    "case 7 -> API key of a workspace the user has left",
+			ctx:     requestinfo.WithWorkspaceID(userCtx, 3),
+			wantErr: apperror.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUseCase(workspaceRepo())
+			result, err := u.Resolve(tt.ctx, tt.slug)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result.ID)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUseCase_CreateWorkspace(t *testing.T) {
+	tests := []struct {
+		name    string
+		request model.WorkspaceModel
+		want    model.WorkspaceModel
+		wantErr error
+	}{
+		{
+			name:    "case 1 -> create workspace",
+			request: model.WorkspaceModel{Name: " Acme ", Slug: "Acme-2"},
+			want:    model.WorkspaceModel{ID: 2, Name: "Acme", Slug: "acme-2", Role: model.RoleOwner},
+		},
+		{
+			name:    "case 2 -> slug unusable as a subdomain",
+			request: model.WorkspaceModel{Name: "Acme", Slug: "acme.corp"},
+			wantErr: apperror.ErrValidation,
+		},
+		{
+			name:    "case 3 -> blank name",
+			request: model.WorkspaceModel{Name: "  ", Slug: "acme"},
+			wantErr: apperror.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUseCase(&WorkspaceRepositoryMock{
+				CreateFunc: func(ctx context.Context, workspace model.WorkspaceModel) (model.WorkspaceModel, error) {
+					workspace.ID = 2
+					workspace.Role = model.RoleOwner
+					return workspace, nil
+				},
+			})
+			result, err := u.CreateWorkspace(context.Background(), tt.request)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUseCase_Members(t *testing.T) {
+	ctx := requestinfo.WithUserID(context.Background(), 1)
+
+	tests := []struct {
+		name       string
+		id         int64
+		role       model.Role
+		userID     int64
+		wantAdd    error
+		wantRemove error
+	}{
+		{
+			name:   "case 1 -> owner adds and removes members",
+			id:     2,
+			role:   model.RoleOwner,
+			userID: 4,
+		},
+		{
+			name:       "case 2 -> member neither adds nor removes others",
+			id:         2,
+			role:       model.RoleMember,
+			userID:     4,
+			wantAdd:    apperror.ErrForbidden,
+			wantRemove: apperror.ErrForbidden,
+		},
+		{
+			name:    "case 3 -> member leaves",
+			id:      2,
+			role:    model.RoleMember,
+			userID:  1,
+			wantAdd: apperror.ErrForbidden,
+		},
+		{
+			name:       "case 4 -> default workspace has no members to manage",
+			id:         model.DefaultID,
+			role:       model.RoleMember,
+			userID:     1,
+			wantAdd:    apperror.ErrForbidden,
+			wantRemove: apperror.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUseCase(&WorkspaceRepositoryMock{
+				GetByIDFunc: func(ctx context.Context, id int64) (model.WorkspaceModel, error) {
+					return model.WorkspaceModel{ID: id, Role: tt.role}, nil
+				},
+				AddMemberFunc: func(ctx context.Context, id int64, email string) (model.MemberModel, error) {
+					assert.Equal(t, "bob@example.com", email)
+					return model.MemberModel{WorkspaceID: id, UserID: 4, Email: email, Role: model.RoleMember}, nil
+				},
+				RemoveMemberFunc: func(ctx context.Context, id int64, userID int64) error {
+					return nil
+				},
+			})
+
+			_, err := u.AddMember(ctx, tt.id, model.MemberRequest{Email: " Bob@Example.com"})
+			assert.ErrorIs(t, err, tt.wantAdd)
+
+			err = u.RemoveMember(ctx, tt.id, tt.userID)
+			assert.ErrorIs(t, err, tt.wantRemove)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package apperror
+
+import "errors"
+
+// Kinds of failure every layer can report. Compare with errors.Is.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrUnavailable = errors.New("storage unavailable")
+	// ErrPreconditionFailed reports a write made against an outdated
+	// version of the data
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrUnauthorized reports a request without valid credentials
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden reports a request of a known user that is not allowed
+	// to do what it asks
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Error carries a kind, a message that is safe to show to clients and the
+// underlying cause, which is never shown to clients.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func New(kind error, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func Wrap(kind error, message string, err error) error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
@@ -0,0 +1,156 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "case 1 -> replace a member",
+			doc:   `{"a":"b"}`,
+			patch: `{"a":"c"}`,
+			want:  `{"a":"c"}`,
+		},
+		{
+			name:  "case 2 -> null removes a member",
+			doc:   `{"a":"b","b":"c"}`,
+			patch: `{"a":null}`,
+			want:  `{"b":"c"}`,
+		},
+		{
+			name:  "case 3 -> objects merge recursively",
+			doc:   `{"a":{"b":"c","d":"e"}}`,
+			patch: `{"a":{"b":"x","d":null}}`,
+			want:  `{"a":{"b":"x"}}`,
+		},
+		{
+			name:  "case 4 -> arrays are replaced",
+			doc:   `{"a":[1,2]}`,
+			patch: `{"a":[3]}`,
+			want:  `{"a":[3]}`,
+		},
+		{
+			name:  "case 5 -> members not in the patch are kept",
+			doc:   `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`,
+			patch: `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`,
+			want:  `{"author":{"givenName":"John"},"content":"This will be unchanged","phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge([]byte(tt.doc), []byte(tt.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "case 1 -> add a member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:  "case 2 -> add in the middle of an array",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:  "case 3 -> append to an array",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":"qux"}]`,
+			want:  `{"foo":["bar","qux"]}`,
+		},
+		{
+			name:  "case 4 -> remove from an array",
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:  "case 5 -> replace, move and copy",
+			doc:   `{"a":1,"b":{"c":2}}`,
+			patch: `[{"op":"replace","path":"/a","value":3},{"op":"move","from":"/b/c","path":"/d"},{"op":"copy","from":"/a","path":"/b/e"}]`,
+			want:  `{"a":3,"b":{"e":3},"d":2}`,
+		},
+		{
+			name:  "case 6 -> escaped pointer",
+			doc:   `{"a/b":1,"m~n":2}`,
+			patch: `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`,
+			want:  `{"a/b":3}`,
+		},
+		{
+			name:    "case 7 -> replace a missing member",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"replace","path":"/b","value":3}]`,
+			wantErr: true,
+		},
+		{
+			name:    "case 8 -> add without value",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"add","path":"/b"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "case 9 -> unknown op",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"swap","path":"/a"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "case 10 -> index out of range",
+			doc:     `{"a":[1]}`,
+			patch:   `[{"op":"add","path":"/a/3","value":2}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ops []Operation
+			assert.NoError(t, json.Unmarshal([]byte(tt.patch), &ops))
+
+			got, err := Apply([]byte(tt.doc), ops)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestApply_Test(t *testing.T) {
+	doc := []byte(`{"is_done":false,"tags":["a"]}`)
+
+	var ops []Operation
+	_ = json.Unmarshal([]byte(`[{"op":"test","path":"/is_done","value":false},{"op":"replace","path":"/is_done","value":true}]`), &ops)
+	got, err := Apply(doc, ops)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"is_done":true,"tags":["a"]}`, string(got))
+
+	_ = json.Unmarshal([]byte(`[{"op":"test","path":"/is_done","value":true}]`), &ops)
+	_, err = Apply(doc, ops)
+	assert.ErrorIs(t, err, ErrTestFailed)
+}
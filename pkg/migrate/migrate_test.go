@@ -0,0 +1,288 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+	"to-do-list/schema"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var testFS = fstest.MapFS{
+	"01_tasks.up.sql":     {Data: []byte("CREATE TABLE tasks()")},
+	"01_tasks.down.sql":   {Data: []byte("DROP TABLE tasks")},
+	"02_tags.up.sql":      {Data: []byte("CREATE TABLE tags()")},
+	"02_tags.down.sql":    {Data: []byte("DROP TABLE tags")},
+	"03_lists.up.sql":     {Data: []byte("CREATE TABLE lists()")},
+	"tasks.sql":           {Data: []byte("CREATE DATABASE x")},
+	"schema.go":           {Data: []byte("package schema")},
+	"notes/04_x.up.sql":   {Data: []byte("SELECT 1")},
+	"04_lists.down.sql.1": {Data: []byte("SELECT 1")},
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "case 1 -> in the order of the version, other files left out",
+			fsys: testFS,
+			want: []Migration{
+				{Version: 1, Name: "tasks", Up: "CREATE TABLE tasks()", Down: "DROP TABLE tasks"},
+				{Version: 2, Name: "tags", Up: "CREATE TABLE tags()", Down: "DROP TABLE tags"},
+				{Version: 3, Name: "lists", Up: "CREATE TABLE lists()"},
+			},
+		},
+		{
+			name: "case 2 -> down without up",
+			fsys: fstest.MapFS{
+				"01_tasks.down.sql": {Data: []byte("DROP TABLE tasks")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "case 3 -> one version with two names",
+			fsys: fstest.MapFS{
+				"01_tasks.up.sql":   {Data: []byte("CREATE TABLE tasks()")},
+				"01_todos.down.sql": {Data: []byte("DROP TABLE todos")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "case 4 -> no migration",
+			fsys: fstest.MapFS{},
+			want: []Migration{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// the migrations shipped with the app follow each other and can all be
+// undone
+func TestLoad_Schema(t *testing.T) {
+	migrations, err := Load(schema.FS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version, m.Name)
+		assert.NotEmpty(t, m.Down, m.Name)
+	}
+}
+
+func expectLocked(mock sqlmock.Sqlmock, applied ...int) {
+	mock.ExpectExec(regexp.QuoteMeta(lock)).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range applied {
+		rows.AddRow(version, time.Date(2026, 1, version, 0, 0, 0, 0, time.UTC))
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(fetchApplied)).WillReturnRows(rows)
+}
+
+func expectUnlocked(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(unlock)).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    func(mock sqlmock.Sqlmock)
+		want    []int
+		wantErr bool
+	}{
+		{
+			name: "case 1 -> pending migrations applied in order",
+			mock: func(mock sqlmock.Sqlmock) {
+				expectLocked(mock, 1)
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE tags()")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(insertVersion)).WithArgs(2, "tags").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE lists()")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(insertVersion)).WithArgs(3, "lists").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectUnlocked(mock)
+			},
+			want: []int{2, 3},
+		},
+		{
+			name: "case 2 -> nothing pending",
+			mock: func(mock sqlmock.Sqlmock) {
+				expectLocked(mock, 1, 2, 3)
+				expectUnlocked(mock)
+			},
+		},
+		{
+			name: "case 3 -> failed migration rolled back, the ones before it kept",
+			mock: func(mock sqlmock.Sqlmock) {
+				expectLocked(mock)
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE tasks()")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(insertVersion)).WithArgs(1, "tasks").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE tags()")).WillReturnError(errors.New("syntax error"))
+				mock.ExpectRollback()
+				expectUnlocked(mock)
+			},
+			want:    []int{1},
+			wantErr: true,
+		},
+		{
+			name: "case 4 -> lock not taken",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(lock)).WithArgs(lockKey).WillReturnError(errors.New("canceled"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			tt.mock(mock)
+
+			m, err := New(db, testFS)
+			assert.NoError(t, err)
+
+			got, err := m.Up(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, versions(got))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		mock    func(mock sqlmock.Sqlmock)
+		want    []int
+		wantErr bool
+	}{
+		{
+			name: "case 1 -> newest applied migration undone",
+			n:    1,
+			mock: func(mock sqlmock.Sqlmock) {
+				expectLocked(mock, 1, 2)
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DROP TABLE tags")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(deleteVersion)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectUnlocked(mock)
+			},
+			want: []int{2},
+		},
+		{
+			name: "case 2 -> more than applied stops at the first",
+			n:    5,
+			mock: func(mock sqlmock.Sqlmock) {
+				expectLocked(mock, 1)
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DROP TABLE tasks")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(deleteVersion)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectUnlocked(mock)
+			},
+			want: []int{1},
+		},
+		{
+			name: "case 3 -> migration without down file",
+			n:    1,
+			mock: func(mock sqlmock.Sqlmock) {
+				expectLocked(mock, 1, 2, 3)
+				expectUnlocked(mock)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			tt.mock(mock)
+
+			m, err := New(db, testFS)
+			assert.NoError(t, err)
+
+			got, err := m.Down(context.Background(), tt.n)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, versions(got))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Baseline(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectLocked(mock, 1)
+	mock.ExpectExec(regexp.QuoteMeta(insertVersion)).WithArgs(2, "tags").WillReturnResult(sqlmock.NewResult(0, 1))
+	expectUnlocked(mock)
+
+	m, err := New(db, testFS)
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.Baseline(context.Background(), 2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectLocked(mock, 1)
+	expectUnlocked(mock)
+
+	m, err := New(db, testFS)
+	assert.NoError(t, err)
+
+	states, err := m.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, states, 3)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), *states[0].AppliedAt)
+	assert.Nil(t, states[1].AppliedAt)
+	assert.Nil(t, states[2].AppliedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func versions(migrations []Migration) []int {
+	var got []int
+	for _, m := range migrations {
+		got = append(got, m.Version)
+	}
+	return got
+}
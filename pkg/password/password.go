@@ -0,0 +1,120 @@
+// Package password hashes passwords for storage with Argon2id and a random
+// salt per password.
+//
+// A hash is stored in the PHC string format,
+// "$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>", salt and
+// key in unpadded base64, so the cost can be raised later without breaking
+// the hashes already stored.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	scheme = "argon2id"
+
+	// Memory (in KiB), Time and Threads follow the second setting the
+	// OWASP recommends for Argon2id.
+	Memory  = 19 * 1024
+	Time    = 2
+	Threads = 1
+
+	saltLen = 16
+	keyLen  = 32
+)
+
+// params is the cost a hash was made with.
+type params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+var (
+	encoding = base64.RawStdEncoding
+
+	defaults = params{memory: Memory, time: Time, threads: Threads}
+)
+
+// Hash returns the stored form of password.
+func Hash(password string) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("password: read salt: %w", err)
+	}
+	return encode(defaults, salt, derive([]byte(password), salt, defaults, keyLen)), nil
+}
+
+// Verify tells whether password matches a hash made by Hash. A hash it
+// cannot read matches nothing.
+func Verify(password, hash string) bool {
+	p, salt, key, err := decode(hash)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(key, derive([]byte(password), salt, p, uint32(len(key)))) == 1
+}
+
+// dummy is checked against when there is no hash to check, so that it takes
+// as long as checking a real one.
+var dummy = mustHash("")
+
+// VerifyNone spends the time Verify would on a hash, and matches nothing.
+// It stands in for Verify when there is no account to check against.
+func VerifyNone(password string) bool {
+	Verify(password, dummy)
+	return false
+}
+
+func mustHash(password string) string {
+	hash, err := Hash(password)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+}
+
+func derive(password, salt []byte, p params, keyLen uint32) []byte {
+	return argon2.IDKey(password, salt, p.time, p.memory, p.threads, keyLen)
+}
+
+func encode(p params, salt, key []byte) string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", scheme, argon2.Version, p.memory, p.time, p.threads, encoding.EncodeToString(salt), encoding.EncodeToString(key))
+}
+
+var errMalformed = errors.New("password: malformed hash")
+
+func decode(hash string) (params, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != scheme {
+		return params{}, nil, nil, errMalformed
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params{}, nil, nil, errMalformed
+	}
+
+	var p params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil || p.memory == 0 || p.time == 0 || p.threads == 0 {
+		return params{}, nil, nil, errMalformed
+	}
+
+	salt, err := encoding.DecodeString(parts[4])
+	if err != nil {
+		return params{}, nil, nil, errMalformed
+	}
+	key, err := encoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params{}, nil, nil, errMalformed
+	}
+	return p, salt, key, nil
+}
@@ -0,0 +1,51 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	hash, err := Hash("correct horse")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"))
+
+	other, err := Hash("correct horse")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other, "every hash has its own salt")
+}
+
+func TestVerify(t *testing.T) {
+	hash, err := Hash("correct horse")
+	assert.NoError(t, err)
+
+	cheap := encode(params{memory: 8, time: 1, threads: 1}, []byte("somesalt"), derive([]byte("password"), []byte("somesalt"), params{memory: 8, time: 1, threads: 1}, 16))
+
+	tests := []struct {
+		name     string
+		password string
+		hash     string
+		want     bool
+	}{
+		{name: "case 1 -> right password", password: "correct horse", hash: hash, want: true},
+		{name: "case 2 -> wrong password", password: "correct horse!", hash: hash, want: false},
+		{name: "case 3 -> cost and key length read from the hash", password: "password", hash: cheap, want: true},
+		{name: "case 4 -> other scheme", password: "password", hash: "$argon2i$v=19$m=8,t=1,p=1$c29tZXNhbHQ$abc", want: false},
+		{name: "case 5 -> other version", password: "password", hash: strings.Replace(cheap, "v=19", "v=16", 1), want: false},
+		{name: "case 6 -> malformed cost", password: "password", hash: "$argon2id$v=19$m=x,t=1,p=1$c29tZXNhbHQ$abc", want: false},
+		{name: "case 7 -> empty hash", password: "", hash: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Verify(tt.password, tt.hash))
+		})
+	}
+}
+
+func TestVerifyNone(t *testing.T) {
+	assert.False(t, VerifyNone(""))
+	assert.False(t, VerifyNone("correct horse"))
+}
@@ -0,0 +1,116 @@
+// Package rank makes sort keys that order items by plain byte comparison,
+// so moving an item between two others only changes its own key.
+//
+// Keys are strings of base 62 digits that never end with the lowest digit,
+// which leaves room for a key before any other key.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits are in byte order, so keys compare like the numbers they spell.
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// MaxLen is the key length past which keys should be spread again.
+const MaxLen = 32
+
+// ErrNoRoom is returned when no key fits between the bounds, because they
+// are equal, out of order or not keys. Spreading the keys again makes room.
+var ErrNoRoom = errors.New("rank: no key between the bounds")
+
+// Between returns a key that sorts after a and before b. An empty a is
+// before every key and an empty b after every key. The key is kept as
+// short as the bounds allow.
+func Between(a, b string) (string, error) {
+	if b != "" && a >= b {
+		return "", ErrNoRoom
+	}
+
+	var (
+		key     = []byte{}
+		bounded = b != ""
+	)
+	for i := 0; ; i++ {
+		lo := 0
+		if i < len(a) {
+			lo = strings.IndexByte(digits, a[i])
+		}
+
+		hi := len(digits)
+		if bounded {
+			// the key so far is b, nothing fits before it
+			if i >= len(b) {
+				return "", ErrNoRoom
+			}
+			hi = strings.IndexByte(digits, b[i])
+		}
+
+		if lo < 0 || hi < 0 {
+			return "", ErrNoRoom
+		}
+
+		if hi-lo > 1 {
+			return string(append(key, digits[(lo+hi)/2])), nil
+		}
+
+		// once a digit is below the one of b, the rest of the key only
+		// has to stay above a
+		key = append(key, digits[lo])
+		if hi > lo {
+			bounded = false
+		}
+	}
+}
+
+// After returns a key that sorts after a. Raising the first digit that can
+// be raised keeps keys short when items are appended one after another.
+func After(a string) (string, error) {
+	for i := 0; i < len(a); i++ {
+		d := strings.IndexByte(digits, a[i])
+		if d < 0 {
+			return "", ErrNoRoom
+		}
+		if d < len(digits)-1 {
+			return a[:i] + string(digits[d+1]), nil
+		}
+	}
+	return Between(a, "")
+}
+
+// Spread returns n keys in order, evenly apart, so every gap has room for
+// many moves.
+func Spread(n int) []string {
+	if n <= 0 {
+		return []string{}
+	}
+
+	// one more digit than n needs leaves at least len(digits) values
+	// between neighbours
+	length, space := 1, int64(len(digits))
+	for space/int64(n+1) < int64(len(digits)) {
+		length++
+		space *= int64(len(digits))
+	}
+
+	step := space / int64(n+1)
+	keys := make([]string, n)
+	for i := range keys {
+		v := int64(i+1) * step
+		if v%int64(len(digits)) == 0 {
+			v++
+		}
+		keys[i] = encode(v, length)
+	}
+	return keys
+}
+
+func encode(v int64, length int) string {
+	key := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		key[i] = digits[v%int64(len(digits))]
+		v /= int64(len(digits))
+	}
+	return string(key)
+}
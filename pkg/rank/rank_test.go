@@ -0,0 +1,94 @@
+package rank
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		want    string
+		wantErr error
+	}{
+		{name: "case 1 -> no bounds", a: "", b: "", want: "V"},
+		{name: "case 2 -> after a key", a: "V", b: "", want: "k"},
+		{name: "case 3 -> before a key", a: "", b: "V", want: "F"},
+		{name: "case 4 -> between neighbour digits", a: "V", b: "W", want: "VV"},
+		{name: "case 5 -> between a key and its extension", a: "V", b: "VV", want: "VF"},
+		{name: "case 6 -> after the last digit", a: "z", b: "", want: "zV"},
+		{name: "case 7 -> before a key of low digits", a: "", b: "01", want: "00V"},
+		{name: "case 8 -> equal bounds", a: "V", b: "V", wantErr: ErrNoRoom},
+		{name: "case 9 -> bounds out of order", a: "W", b: "V", wantErr: ErrNoRoom},
+		{name: "case 10 -> nothing before the lowest digit", a: "", b: "0", wantErr: ErrNoRoom},
+		{name: "case 11 -> not a key", a: "V-", b: "W", wantErr: ErrNoRoom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Less(t, tt.a, got)
+			if tt.b != "" {
+				assert.Less(t, got, tt.b)
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		want string
+	}{
+		{name: "case 1 -> first key", a: "", want: "V"},
+		{name: "case 2 -> raise the first digit", a: "V3x", want: "W"},
+		{name: "case 3 -> raise a later digit", a: "zV", want: "zW"},
+		{name: "case 4 -> only highest digits", a: "zz", want: "zzV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := After(tt.a)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, n := range []int{0, 1, 61, 62, 5000} {
+		keys := Spread(n)
+		assert.Len(t, keys, n)
+		assert.True(t, sort.StringsAreSorted(keys))
+		for i, key := range keys {
+			assert.NotEqual(t, byte('0'), key[len(key)-1])
+			if i > 0 {
+				_, err := Between(keys[i-1], key)
+				assert.NoError(t, err)
+			}
+		}
+	}
+}
+
+// Moving items to the same place over and over makes keys grow slowly
+// and keeps them in order.
+func TestRepeatedMoves(t *testing.T) {
+	keys := Spread(2)
+	for i := 0; i < 100; i++ {
+		key, err := Between(keys[0], keys[1])
+		assert.NoError(t, err)
+		keys[1] = key
+	}
+	assert.Less(t, keys[0], keys[1])
+	assert.LessOrEqual(t, len(keys[1]), MaxLen)
+}
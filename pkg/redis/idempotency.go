@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"to-do-list/pkg/apperror"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	redisIdempotencyKey = "idempotency:"
+
+	// DefaultIdempotencyWindow is how long a response is replayed when no
+	// window is configured
+	DefaultIdempotencyWindow = 24 * time.Hour
+
+	// a request holding a key longer than this is taken as dead, and its
+	// duplicates stop waiting for it
+	idempotencyLockTTL  = 30 * time.Second
+	idempotencyPollWait = 50 * time.Millisecond
+)
+
+var (
+	ErrIdempotencyMismatch   = apperror.New(apperror.ErrValidation, "Idempotency-Key was already used for another request")
+	ErrIdempotencyInProgress = apperror.New(apperror.ErrConflict, "a request with this Idempotency-Key is still in progress")
+)
+
+// IdempotentResponse is the first response given to a key, replayed to the
+// retries of the request.
+type IdempotentResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   []byte            `json:"body"`
+}
+
+// idempotencyRecord has no Response while the first request runs.
+type idempotencyRecord struct {
+	Fingerprint string              `json:"fingerprint"`
+	Response    *IdempotentResponse `json:"response,omitempty"`
+}
+
+// IdempotencyStore remembers the response given to each idempotency key.
+// The fingerprint identifies the request a key was first used for.
+type IdempotencyStore struct {
+	client   *redis.Client
+	window   time.Duration
+	lockTTL  time.Duration
+	pollWait time.Duration
+}
+
+func NewIdempotencyStore(client *redis.Client, window time.Duration) *IdempotencyStore {
+	if window <= 0 {
+		window = DefaultIdempotencyWindow
+	}
+	return &IdempotencyStore{
+		client:   client,
+		window:   window,
+		lockTTL:  idempotencyLockTTL,
+		pollWait: idempotencyPollWait,
+	}
+}
+
+// Begin claims the key for a request. It returns nil when the caller has
+// to handle the request and then Complete or Release the key, or the stored
+// response when the request was already handled. A duplicate arriving while
+// the first request runs waits for its response.
+func (s *IdempotencyStore) Begin(ctx context.Context, key string, fingerprint string) (*IdempotentResponse, error) {
+	pending, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(s.lockTTL)
+	for {
+		claimed, err := s.client.SetNX(ctx, redisIdempotencyKey+key, pending, s.lockTTL).Result()
+		if err != nil {
+			return nil, apperror.Wrap(apperror.ErrUnavailable, "idempotency store unavailable", err)
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		data, err := s.client.Get(ctx, redisIdempotencyKey+key).Bytes()
+		if err == redis.Nil {
+			// released or expired since SetNX, claim it again
+			continue
+		}
+		if err != nil {
+			return nil, apperror.Wrap(apperror.ErrUnavailable, "idempotency store unavailable", err)
+		}
+
+		var record idempotencyRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+		if record.Fingerprint != fingerprint {
+			return nil, ErrIdempotencyMismatch
+		}
+		if record.Response != nil {
+			return record.Response, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrIdempotencyInProgress
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.pollWait):
+		}
+	}
+}
+
+// Complete stores the response of a key claimed with Begin for the window
+// of the store.
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, fingerprint string, response IdempotentResponse) error {
+	data, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint, Response: &response})
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, redisIdempotencyKey+key, data, s.window).Err()
+}
+
+// Release frees a key claimed with Begin without a response, so a retry
+// handles the request again.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, redisIdempotencyKey+key).Err()
+}
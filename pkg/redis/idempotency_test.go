@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+	"to-do-list/pkg/apperror"
+
+	"github.com/alicebob/miniredis"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T) (*IdempotencyStore, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub redis connection", err)
+	}
+	t.Cleanup(mr.Close)
+
+	store := NewIdempotencyStore(NewRedisClient(mr.Addr(), ""), time.Hour)
+	store.lockTTL = 500 * time.Millisecond
+	store.pollWait = 10 * time.Millisecond
+	return store, mr
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	response := IdempotentResponse{Status: 201, Header: map[string]string{"ETag": `"1"`}, Body: []byte(`{"id":1}`)}
+
+	t.Run("case 1 -> first request claims the key, retries get its response", func(t *testing.T) {
+		store, mr := newTestStore(t)
+
+		got, err := store.Begin(ctx, "a", "body")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		assert.NoError(t, store.Complete(ctx, "a", "body", response))
+		assert.Equal(t, time.Hour, mr.TTL("idempotency:a"))
+
+		got, err = store.Begin(ctx, "a", "body")
+		assert.NoError(t, err)
+		assert.Equal(t, &response, got)
+	})
+
+	t.Run("case 2 -> key reused with another body", func(t *testing.T) {
+		store, _ := newTestStore(t)
+
+		_, _ = store.Begin(ctx, "a", "body")
+		assert.NoError(t, store.Complete(ctx, "a", "body", response))
+
+		_, err := store.Begin(ctx, "a", "other body")
+		assert.ErrorIs(t, err, apperror.ErrValidation)
+	})
+
+	t.Run("case 3 -> duplicate waits for the running request", func(t *testing.T) {
+		store, _ := newTestStore(t)
+
+		_, _ = store.Begin(ctx, "a", "body")
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = store.Complete(ctx, "a", "body", response)
+		}()
+
+		got, err := store.Begin(ctx, "a", "body")
+		assert.NoError(t, err)
+		assert.Equal(t, &response, got)
+	})
+
+	t.Run("case 4 -> duplicate gives up on a request that does not finish", func(t *testing.T) {
+		store, _ := newTestStore(t)
+		store.lockTTL = 5 * time.Second
+
+		_, _ = store.Begin(ctx, "a", "body")
+		store.lockTTL = 50 * time.Millisecond
+
+		_, err := store.Begin(ctx, "a", "body")
+		assert.ErrorIs(t, err, apperror.ErrConflict)
+	})
+
+	t.Run("case 5 -> released key is claimed again", func(t *testing.T) {
+		store, _ := newTestStore(t)
+
+		_, _ = store.Begin(ctx, "a", "body")
+		assert.NoError(t, store.Release(ctx, "a"))
+
+		got, err := store.Begin(ctx, "a", "body")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,166 @@
+// Package requestinfo carries who made a request and its id through the
+// context, down to the audit log and the queries scoped to a user and a
+// workspace.
+package requestinfo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	ActorHeader     = "X-Actor"
+
+	// Anonymous is the actor of requests that do not name one.
+	Anonymous = "anonymous"
+
+	maxLength = 100
+)
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+	userIDKey
+	ownerIDKey
+	workspaceIDKey
+	readOnlyKey
+)
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the actor of the request, Anonymous when there is none.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return Anonymous
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the id of the request, empty outside of one.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+func WithUserID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, userIDKey, id)
+}
+
+// UserID returns the id of the signed in user, 0 when there is none. No
+// row belongs to user 0, so queries scoped to it find nothing.
+func UserID(ctx context.Context) int64 {
+	id, _ := ctx.Value(userIDKey).(int64)
+	return id
+}
+
+// WithOwnerID makes the request reach the data of another user, like
+// members of a list shared with them do once their role is checked.
+func WithOwnerID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, ownerIDKey, id)
+}
+
+// OwnerID returns the user whose data the request reaches, the signed in
+// user unless WithOwnerID said otherwise. Queries are scoped to it.
+func OwnerID(ctx context.Context) int64 {
+	if id, ok := ctx.Value(ownerIDKey).(int64); ok {
+		return id
+	}
+	return UserID(ctx)
+}
+
+func WithWorkspaceID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, workspaceIDKey, id)
+}
+
+// WorkspaceID returns the workspace the request works in, 0 when there is
+// none. No task is in workspace 0.
+func WorkspaceID(ctx context.Context) int64 {
+	id, _ := ctx.Value(workspaceIDKey).(int64)
+	return id
+}
+
+// WithReadOnly marks the request as limited to reading, like requests made
+// with a read only API key.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey, true)
+}
+
+// ReadOnly reports whether the request may only read.
+func ReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey).(bool)
+	return readOnly
+}
+
+// Detach returns a context with the values of ctx that is never cancelled,
+// for work that has to finish after the client hung up.
+func Detach(ctx context.Context) context.Context {
+	return detached{parent: ctx}
+}
+
+type detached struct {
+	parent context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detached) Done() <-chan struct{}       { return nil }
+func (detached) Err() error                  { return nil }
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+// Middleware puts the request id and the actor in the request context. The
+// id is taken from X-Request-ID when the client sent a usable one, otherwise
+// generated, and is echoed in the response. The actor is read from X-Actor,
+// authenticated requests replace it with the signed in user.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !usable(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := WithRequestID(r.Context(), id)
+		if actor := strings.TrimSpace(r.Header.Get(ActorHeader)); usable(actor) {
+			ctx = WithActor(ctx, actor)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// usable accepts short printable ASCII values, which are safe to log and to
+// send back in a header.
+func usable(value string) bool {
+	if value == "" || len(value) > maxLength {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < ' ' || value[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
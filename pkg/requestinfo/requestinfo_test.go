@@ -0,0 +1,92 @@
+package requestinfo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		headers       map[string]string
+		wantRequestID string
+		wantActor     string
+	}{
+		{
+			name:      "case 1 -> generated request id and anonymous actor",
+			wantActor: Anonymous,
+		},
+		{
+			name:          "case 2 -> request id and actor from the client",
+			headers:       map[string]string{RequestIDHeader: "req-1", ActorHeader: " alice "},
+			wantRequestID: "req-1",
+			wantActor:     "alice",
+		},
+		{
+			name:      "case 3 -> unusable request id is replaced",
+			headers:   map[string]string{RequestIDHeader: strings.Repeat("a", 101)},
+			wantActor: Anonymous,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requestID, actor string
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestID = RequestID(r.Context())
+				actor = Actor(r.Context())
+			}))
+
+			request, _ := http.NewRequest("GET", "/api/tasks", nil)
+			for key, value := range tt.headers {
+				request.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if tt.wantRequestID == "" {
+				assert.Len(t, requestID, 32)
+			} else {
+				assert.Equal(t, tt.wantRequestID, requestID)
+			}
+			assert.Equal(t, requestID, recorder.Header().Get(RequestIDHeader))
+			assert.Equal(t, tt.wantActor, actor)
+		})
+	}
+}
+
+func TestDetach(t *testing.T) {
+	ctx, cancel := context.WithCancel(WithUserID(WithActor(context.Background(), "alice"), 7))
+	cancel()
+
+	detached := Detach(ctx)
+
+	assert.Error(t, ctx.Err())
+	assert.NoError(t, detached.Err())
+	assert.Nil(t, detached.Done())
+	assert.Equal(t, "alice", Actor(detached))
+	assert.Equal(t, int64(7), UserID(detached))
+	assert.Equal(t, int64(0), UserID(context.Background()))
+}
+
+func TestOwnerID(t *testing.T) {
+	ctx := WithUserID(context.Background(), 7)
+
+	assert.Equal(t, int64(7), OwnerID(ctx))
+	assert.Equal(t, int64(3), OwnerID(WithOwnerID(ctx, 3)))
+	assert.Equal(t, int64(7), UserID(WithOwnerID(ctx, 3)))
+	assert.Equal(t, int64(0), OwnerID(context.Background()))
+}
+
+func TestWorkspaceID(t *testing.T) {
+	ctx := WithWorkspaceID(WithUserID(context.Background(), 7), 2)
+
+	assert.Equal(t, int64(2), WorkspaceID(ctx))
+	assert.Equal(t, int64(2), WorkspaceID(WithOwnerID(ctx, 3)), "reaching the data of another user stays in the workspace")
+	assert.Equal(t, int64(0), WorkspaceID(context.Background()))
+}
@@ -6,6 +6,15 @@ import (
 	"net/http"
 )
 
+type ResponseStandard struct {
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+type StatusRespose struct {
+	Success bool `json:"status"`
+}
+
 type ErrorResponse struct {
 	Message string
 	Error   interface{}
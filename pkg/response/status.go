@@ -0,0 +1,39 @@
+package util
+
+import (
+	"errors"
+	"net/http"
+	"to-do-list/pkg/apperror"
+)
+
+// StatusFromError maps the apperror kinds to HTTP status codes. Anything
+// outside the taxonomy is an internal server error.
+func StatusFromError(err error) int {
+	switch {
+	case errors.Is(err, apperror.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperror.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperror.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, apperror.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, apperror.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, apperror.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apperror.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// MessageFromError returns a message that is safe to show to clients.
+func MessageFromError(err error) string {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return "Internal Server Error"
+}
@@ -0,0 +1,483 @@
+// Package rrule reads RFC 5545 recurrence rules and finds their
+// occurrences. It covers the rules a to-do list needs: DAILY, WEEKLY,
+// MONTHLY and YEARLY frequencies with INTERVAL, COUNT, UNTIL, BYDAY,
+// BYMONTHDAY, BYMONTH and WKST.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequencies of a Rule.
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// searchYears bounds the search for the next occurrence, so rules that
+// never match, like the 30th of February, end.
+const searchYears = 400
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Day is a BYDAY value. N picks the nth such weekday of the month, counted
+// from the end when negative, and 0 every one of them.
+type Day struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// Rule is a parsed RRULE.
+type Rule struct {
+	Freq     string
+	Interval int
+	// Count is the number of occurrences, the start included, 0 for no
+	// limit.
+	Count int
+	// Until is the last time an occurrence can fall on, zero for no limit.
+	// A Floating Until is a wall clock time in the location of the series,
+	// kept in UTC.
+	Until      time.Time
+	Floating   bool
+	ByDay      []Day
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+// Parse reads a rule like "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", with or
+// without the "RRULE:" prefix.
+func Parse(s string) (Rule, error) {
+	rule := Rule{Interval: 1, WeekStart: time.Monday}
+
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	if s == "" {
+		return rule, errors.New("empty rule")
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return rule, fmt.Errorf("invalid part %q", part)
+		}
+		if seen[name] {
+			return rule, fmt.Errorf("%s is given twice", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			switch value {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = value
+			case "SECONDLY", "MINUTELY", "HOURLY":
+				err = fmt.Errorf("FREQ=%s is not supported", value)
+			default:
+				err = fmt.Errorf("unknown FREQ %q", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseNumber(value, 1, 1000)
+		case "COUNT":
+			rule.Count, err = parseNumber(value, 1, 1000)
+		case "UNTIL":
+			rule.Until, rule.Floating, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseDays(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseMonthDays(value)
+		case "BYMONTH":
+			rule.ByMonth, err = parseMonths(value)
+		case "WKST":
+			weekday, ok := weekdays[value]
+			if !ok {
+				err = fmt.Errorf("unknown weekday %q", value)
+			}
+			rule.WeekStart = weekday
+		case "BYSETPOS", "BYYEARDAY", "BYWEEKNO", "BYHOUR", "BYMINUTE", "BYSECOND":
+			err = fmt.Errorf("%s is not supported", name)
+		default:
+			err = fmt.Errorf("unknown part %s", name)
+		}
+		if err != nil {
+			return rule, err
+		}
+	}
+
+	return rule, rule.check()
+}
+
+func (r Rule) check() error {
+	if r.Freq == "" {
+		return errors.New("FREQ is required")
+	}
+	if r.Count != 0 && !r.Until.IsZero() {
+		return errors.New("COUNT and UNTIL cannot be used together")
+	}
+	for _, day := range r.ByDay {
+		if day.N == 0 {
+			continue
+		}
+		if r.Freq == Daily || r.Freq == Weekly {
+			return fmt.Errorf("BYDAY cannot number weekdays in a %s rule", r.Freq)
+		}
+	}
+	if r.Freq == Yearly && len(r.ByDay) > 0 && len(r.ByMonth) == 0 {
+		return errors.New("BYDAY in a YEARLY rule needs BYMONTH")
+	}
+	return nil
+}
+
+func parseNumber(value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%q is not a number from %d to %d", value, min, max)
+	}
+	return n, nil
+}
+
+// parseUntil takes a UTC time, a floating time or a date, which includes
+// the whole day.
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t.Add(24*time.Hour - time.Second), true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+func parseDays(value string) ([]Day, error) {
+	days := []Day{}
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+		weekday, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+		day := Day{Weekday: weekday}
+		if ordinal := item[:len(item)-2]; ordinal != "" {
+			n, err := strconv.Atoi(ordinal)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid BYDAY %q, the number goes from -5 to 5", item)
+			}
+			day.N = n
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func parseMonthDays(value string) ([]int, error) {
+	days := []int{}
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n < -31 || n > 31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY %q", item)
+		}
+		days = append(days, n)
+	}
+	return days, nil
+}
+
+func parseMonths(value string) ([]time.Month, error) {
+	months := []time.Month{}
+	for _, item := range strings.Split(value, ",") {
+		n, err := parseNumber(item, 1, 12)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BYMONTH %q", item)
+		}
+		months = append(months, time.Month(n))
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i] < months[j] })
+	return months, nil
+}
+
+// String writes the rule back in RFC 5545 form.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.Floating {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, month := range r.ByMonth {
+			months[i] = strconv.Itoa(int(month))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = weekdayName(day.Weekday)
+			if day.N != 0 {
+				days[i] = strconv.Itoa(day.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayName(r.WeekStart))
+	}
+	return strings.Join(parts, ";")
+}
+
+func weekdayName(weekday time.Weekday) string {
+	return strings.ToUpper(weekday.String()[:2])
+}
+
+// Next returns the first occurrence after after of the series starting at
+// start, false when the series ends before. start is the first occurrence
+// and counts towards Count. Occurrences keep the wall clock time of start
+// in its location, so a 09:00 task stays at 09:00 across DST changes.
+func (r Rule) Next(start, after time.Time) (time.Time, bool) {
+	var (
+		loc   = start.Location()
+		until = r.Until
+		count = 1
+	)
+	if r.Floating {
+		until = wallClock(until, loc)
+	}
+	if !until.IsZero() && start.After(until) {
+		return time.Time{}, false
+	}
+	if start.After(after) {
+		return start, true
+	}
+
+	limit := start.AddDate(searchYears, 0, 0)
+	for period := 0; ; period++ {
+		candidates, end := r.expand(start, period)
+		for _, t := range candidates {
+			if !t.After(start) {
+				continue
+			}
+			if !until.IsZero() && t.After(until) {
+				return time.Time{}, false
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return time.Time{}, false
+			}
+			if t.After(after) {
+				return t, true
+			}
+		}
+		if end.After(limit) {
+			return time.Time{}, false
+		}
+	}
+}
+
+// expand returns the occurrences of a period of the series in order,
+// before the filters of start, with the first day of the period.
+func (r Rule) expand(start time.Time, period int) ([]time.Time, time.Time) {
+	var (
+		interval  = r.interval()
+		y, mo, d  = start.Date()
+		h, mi, s  = start.Clock()
+		loc       = start.Location()
+		dates     []time.Time
+		firstDate time.Time
+	)
+
+	switch r.Freq {
+	case Daily:
+		firstDate = date(y, mo, d+period*interval)
+		dates = []time.Time{firstDate}
+	case Weekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		firstDate = date(y, mo, d-offset+7*period*interval)
+		for i := 0; i < 7; i++ {
+			day := firstDate.AddDate(0, 0, i)
+			if r.weeklyDay(day.Weekday(), start.Weekday()) {
+				dates = append(dates, day)
+			}
+		}
+	case Monthly:
+		firstDate = date(y, mo+time.Month(period*interval), 1)
+		dates = r.monthDates(firstDate.Year(), firstDate.Month(), d)
+	case Yearly:
+		firstDate = date(y+period*interval, 1, 1)
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{mo}
+		}
+		for _, month := range months {
+			dates = append(dates, r.monthDates(firstDate.Year(), month, d)...)
+		}
+	}
+
+	occurrences := []time.Time{}
+	for _, day := range dates {
+		if r.keep(day) {
+			occurrences = append(occurrences, time.Date(day.Year(), day.Month(), day.Day(), h, mi, s, 0, loc))
+		}
+	}
+	return occurrences, time.Date(firstDate.Year(), firstDate.Month(), firstDate.Day(), h, mi, s, 0, loc)
+}
+
+func (r Rule) interval() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+func (r Rule) weeklyDay(weekday, startWeekday time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return weekday == startWeekday
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// monthDates are the days of a month the rule picks, the day of the start
+// when it picks none itself. Months too short for that day are skipped.
+func (r Rule) monthDates(y int, mo time.Month, startDay int) []time.Time {
+	last := daysIn(y, mo)
+
+	days := []int{}
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, day := range r.ByMonthDay {
+			if day < 0 {
+				day = last + day + 1
+			}
+			if day >= 1 && day <= last {
+				days = append(days, day)
+			}
+		}
+	case len(r.ByDay) > 0:
+		for day := 1; day <= last; day++ {
+			days = append(days, day)
+		}
+	case startDay <= last:
+		days = append(days, startDay)
+	}
+	sort.Ints(days)
+
+	dates := []time.Time{}
+	for i, day := range days {
+		if i > 0 && day == days[i-1] {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchDay(y, mo, day, last) {
+			continue
+		}
+		dates = append(dates, date(y, mo, day))
+	}
+	return dates
+}
+
+// matchDay tells whether day is one of the numbered or plain weekdays of
+// BYDAY.
+func (r Rule) matchDay(y int, mo time.Month, day, last int) bool {
+	weekday := date(y, mo, day).Weekday()
+	for _, d := range r.ByDay {
+		if d.Weekday != weekday {
+			continue
+		}
+		switch {
+		case d.N == 0:
+			return true
+		case d.N > 0 && (day-1)/7+1 == d.N:
+			return true
+		case d.N < 0 && (last-day)/7+1 == -d.N:
+			return true
+		}
+	}
+	return false
+}
+
+// keep applies the BY parts that only filter at this frequency.
+func (r Rule) keep(day time.Time) bool {
+	if len(r.ByMonth) > 0 && r.Freq != Yearly {
+		found := false
+		for _, month := range r.ByMonth {
+			found = found || month == day.Month()
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Freq == Daily || r.Freq == Weekly {
+		if len(r.ByMonthDay) > 0 {
+			last := daysIn(day.Year(), day.Month())
+			found := false
+			for _, d := range r.ByMonthDay {
+				found = found || d == day.Day() || last+d+1 == day.Day()
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	if r.Freq == Daily && len(r.ByDay) > 0 {
+		found := false
+		for _, d := range r.ByDay {
+			found = found || d.Weekday == day.Weekday()
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// date is a calendar day, computed in UTC so DST does not shift it.
+func date(y int, mo time.Month, d int) time.Time {
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+}
+
+func daysIn(y int, mo time.Month) int {
+	return date(y, mo+1, 0).Day()
+}
+
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
@@ -0,0 +1,240 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "case 1 -> every 3 days",
+			rule: "FREQ=DAILY;INTERVAL=3",
+			want: "FREQ=DAILY;INTERVAL=3",
+		},
+		{
+			name: "case 2 -> prefix, lower case and every part",
+			rule: "RRULE:freq=monthly;bymonth=12,6;bymonthday=1,-1;count=5;wkst=su",
+			want: "FREQ=MONTHLY;COUNT=5;BYMONTH=6,12;BYMONTHDAY=1,-1;WKST=SU",
+		},
+		{
+			name: "case 3 -> last friday of the month until a date",
+			rule: "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231",
+			want: "FREQ=MONTHLY;UNTIL=20261231T235959;BYDAY=-1FR",
+		},
+		{
+			name: "case 4 -> until in UTC",
+			rule: "FREQ=WEEKLY;BYDAY=MO,TH;UNTIL=20260401T120000Z",
+			want: "FREQ=WEEKLY;UNTIL=20260401T120000Z;BYDAY=MO,TH",
+		},
+		{
+			name:    "case 5 -> missing FREQ",
+			rule:    "INTERVAL=2",
+			wantErr: true,
+		},
+		{
+			name:    "case 6 -> hourly is not supported",
+			rule:    "FREQ=HOURLY",
+			wantErr: true,
+		},
+		{
+			name:    "case 7 -> COUNT with UNTIL",
+			rule:    "FREQ=DAILY;COUNT=2;UNTIL=20260401",
+			wantErr: true,
+		},
+		{
+			name:    "case 8 -> numbered weekday in a weekly rule",
+			rule:    "FREQ=WEEKLY;BYDAY=2MO",
+			wantErr: true,
+		},
+		{
+			name:    "case 9 -> invalid month day",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=32",
+			wantErr: true,
+		},
+		{
+			name:    "case 10 -> part given twice",
+			rule:    "FREQ=DAILY;FREQ=WEEKLY",
+			wantErr: true,
+		},
+		{
+			name:    "case 11 -> unsupported part",
+			rule:    "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1",
+			wantErr: true,
+		},
+		{
+			name:    "case 12 -> empty",
+			rule:    " ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, rule.String())
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		rule   string
+		start  time.Time
+		after  time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "case 1 -> every 3 days",
+			rule:   "FREQ=DAILY;INTERVAL=3",
+			start:  time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+			after:  time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "case 2 -> keeps 09:00 across the spring DST change",
+			rule:   "FREQ=DAILY",
+			start:  time.Date(2026, 3, 7, 9, 0, 0, 0, newYork),
+			after:  time.Date(2026, 3, 7, 9, 0, 0, 0, newYork),
+			want:   time.Date(2026, 3, 8, 9, 0, 0, 0, newYork),
+			wantOK: true,
+		},
+		{
+			name:   "case 3 -> keeps 09:00 across the autumn DST change",
+			rule:   "FREQ=WEEKLY",
+			start:  time.Date(2026, 10, 24, 9, 0, 0, 0, berlin),
+			after:  time.Date(2026, 10, 24, 9, 0, 0, 0, berlin),
+			want:   time.Date(2026, 10, 31, 9, 0, 0, 0, berlin),
+			wantOK: true,
+		},
+		{
+			name:   "case 4 -> rent on the 1st",
+			rule:   "FREQ=MONTHLY;BYMONTHDAY=1",
+			start:  time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC),
+			after:  time.Date(2026, 1, 3, 10, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "case 5 -> monthly on the 31st skips short months",
+			rule:   "FREQ=MONTHLY",
+			start:  time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC),
+			after:  time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, 3, 31, 10, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "case 6 -> last day of the month",
+			rule:   "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start:  time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC),
+			after:  time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, 2, 28, 10, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "case 7 -> last friday of the month",
+			rule:   "FREQ=MONTHLY;BYDAY=-1FR",
+			start:  time.Date(2026, 3, 27, 17, 0, 0, 0, time.UTC),
+			after:  time.Date(2026, 3, 27, 17, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, 4, 24, 17, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "case 8 -> every other week on monday and thursday",
+			rule:   "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start:  time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC),
+			after:  time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "case 9 -> leap day every 4 years",
+			rule:   "FREQ=YEARLY",
+			start:  time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			after:  time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			want:   time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "case 10 -> second sunday of may",
+			rule:   "FREQ=YEARLY;BYMONTH=5;BYDAY=2SU",
+			start:  time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC),
+			after:  time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC),
+			want:   time.Date(2027, 5, 9, 12, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "case 11 -> weekdays only",
+			rule:   "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			start:  time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC),
+			after:  time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "case 12 -> late completion on a fixed schedule",
+			rule:   "FREQ=WEEKLY",
+			start:  time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+			after:  time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, 3, 23, 9, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:  "case 13 -> count used up",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+			after: time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "case 14 -> until as a date includes the whole day",
+			rule:   "FREQ=DAILY;UNTIL=20260302",
+			start:  time.Date(2026, 3, 1, 22, 0, 0, 0, newYork),
+			after:  time.Date(2026, 3, 1, 22, 0, 0, 0, newYork),
+			want:   time.Date(2026, 3, 2, 22, 0, 0, 0, newYork),
+			wantOK: true,
+		},
+		{
+			name:  "case 15 -> past until in UTC",
+			rule:  "FREQ=DAILY;UNTIL=20260302T120000Z",
+			start: time.Date(2026, 3, 1, 22, 0, 0, 0, newYork),
+			after: time.Date(2026, 3, 1, 22, 0, 0, 0, newYork),
+		},
+		{
+			name:  "case 16 -> never matching rule ends",
+			rule:  "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			start: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+			after: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			assert.NoError(t, err)
+
+			got, ok := rule.Next(tt.start, tt.after)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package token signs and reads JSON Web Tokens (RFC 7519) with HMAC
+// SHA-256, the only algorithm it accepts.
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalid is returned for a token that is malformed, signed with
+	// another key or another algorithm.
+	ErrInvalid = errors.New("token: invalid")
+	ErrExpired = errors.New("token: expired")
+)
+
+// Claims are the registered claims the service uses, the subject is the id
+// of a user. Times are in seconds since the epoch.
+type Claims struct {
+	Subject   string `json:"sub"`
+	Email     string `json:"email,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// the header never changes, so it is encoded once
+var signedHeader = mustEncode(header{Alg: "HS256", Typ: "JWT"})
+
+// Sign returns the compact form of a token carrying claims.
+func Sign(claims Claims, secret []byte) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := signedHeader + "." + encoding.EncodeToString(payload)
+	return unsigned + "." + encoding.EncodeToString(signature(unsigned, secret)), nil
+}
+
+// Parse checks the signature and the expiry of a token at now and returns
+// its claims.
+func Parse(token string, secret []byte, now time.Time) (Claims, error) {
+	var claims Claims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != signedHeader {
+		return claims, ErrInvalid
+	}
+
+	sig, err := encoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, signature(parts[0]+"."+parts[1], secret)) {
+		return claims, ErrInvalid
+	}
+
+	payload, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, ErrInvalid
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return claims, ErrInvalid
+	}
+
+	if now.Unix() >= claims.ExpiresAt {
+		return claims, ErrExpired
+	}
+
+	return claims, nil
+}
+
+func signature(unsigned string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+func mustEncode(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return encoding.EncodeToString(data)
+}
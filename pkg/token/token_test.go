@@ -0,0 +1,50 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	claims := Claims{Subject: "7", Email: "alice@example.com", IssuedAt: now.Unix(), ExpiresAt: now.Add(15 * time.Minute).Unix()}
+
+	signed, err := Sign(claims, secret)
+	assert.NoError(t, err)
+
+	parts := strings.Split(signed, ".")
+	none := mustEncode(header{Alg: "none", Typ: "JWT"}) + "." + parts[1] + "."
+	tampered := parts[0] + "." + mustEncode(Claims{Subject: "8", ExpiresAt: claims.ExpiresAt}) + "." + parts[2]
+
+	tests := []struct {
+		name    string
+		token   string
+		secret  []byte
+		now     time.Time
+		want    Claims
+		wantErr error
+	}{
+		{name: "case 1 -> valid token", token: signed, secret: secret, now: now, want: claims},
+		{name: "case 2 -> expired token", token: signed, secret: secret, now: now.Add(15 * time.Minute), wantErr: ErrExpired},
+		{name: "case 3 -> other secret", token: signed, secret: []byte("other"), now: now, wantErr: ErrInvalid},
+		{name: "case 4 -> tampered claims", token: tampered, secret: secret, now: now, wantErr: ErrInvalid},
+		{name: "case 5 -> unsigned token", token: none, secret: secret, now: now, wantErr: ErrInvalid},
+		{name: "case 6 -> not a token", token: "abc", secret: secret, now: now, wantErr: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.token, tt.secret, tt.now)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
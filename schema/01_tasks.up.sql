@@ -1,5 +1,5 @@
-CREATE TABLE IF NOT EXISTS tasks AUTHORIZATION pg_database_owner(
-	id bigint NOT NULL,
+CREATE TABLE IF NOT EXISTS tasks(
+	id serial,
 	task_name varchar NOT NULL,
 	is_done bool NOT NULL,
 	CONSTRAINT tasks_pk PRIMARY KEY (id)
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS tasks_due_at_idx;
+
+ALTER TABLE tasks
+	DROP CONSTRAINT IF EXISTS tasks_remind_before_due,
+	DROP COLUMN IF EXISTS remind_at,
+	DROP COLUMN IF EXISTS timezone,
+	DROP COLUMN IF EXISTS due_at;
@@ -0,0 +1,7 @@
+ALTER TABLE tasks
+	ADD COLUMN due_at timestamptz,
+	ADD COLUMN timezone varchar NOT NULL DEFAULT 'UTC',
+	ADD COLUMN remind_at timestamptz,
+	ADD CONSTRAINT tasks_remind_before_due CHECK (remind_at IS NULL OR due_at IS NULL OR remind_at <= due_at);
+
+CREATE INDEX IF NOT EXISTS tasks_due_at_idx ON tasks (due_at);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS task_tags;
+
+DROP TABLE IF EXISTS tags;
+
+ALTER TABLE tasks
+	DROP CONSTRAINT IF EXISTS tasks_priority_range,
+	DROP COLUMN IF EXISTS priority;
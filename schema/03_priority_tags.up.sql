@@ -0,0 +1,18 @@
+ALTER TABLE tasks
+	ADD COLUMN priority smallint NOT NULL DEFAULT 0,
+	ADD CONSTRAINT tasks_priority_range CHECK (priority BETWEEN 0 AND 3);
+
+CREATE TABLE IF NOT EXISTS tags(
+	id serial,
+	name varchar(50) NOT NULL,
+	CONSTRAINT tags_pk PRIMARY KEY (id),
+	CONSTRAINT tags_name_key UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS task_tags(
+	task_id integer NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	tag_id integer NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+	CONSTRAINT task_tags_pk PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_tags_tag_id_idx ON task_tags (tag_id);
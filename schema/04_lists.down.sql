@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+	DROP CONSTRAINT IF EXISTS tasks_list_id_fkey,
+	DROP COLUMN IF EXISTS list_id;
+
+DROP TABLE IF EXISTS lists;
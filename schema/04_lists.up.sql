@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS lists(
+	id serial,
+	name varchar(100) NOT NULL,
+	CONSTRAINT lists_pk PRIMARY KEY (id)
+);
+
+ALTER TABLE tasks
+	ADD COLUMN list_id integer,
+	ADD CONSTRAINT tasks_list_id_fkey FOREIGN KEY (list_id) REFERENCES lists (id);
+
+CREATE INDEX IF NOT EXISTS tasks_list_id_idx ON tasks (list_id);
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+	DROP CONSTRAINT IF EXISTS tasks_parent_id_fkey,
+	DROP COLUMN IF EXISTS position,
+	DROP COLUMN IF EXISTS parent_id;
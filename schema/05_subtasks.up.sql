@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+	ADD COLUMN parent_id integer,
+	ADD COLUMN position integer NOT NULL DEFAULT 0,
+	ADD CONSTRAINT tasks_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES tasks (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS tasks_parent_id_idx ON tasks (parent_id, position);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS task_events(
+	id bigserial,
+	task_id integer NOT NULL,
+	action varchar(20) NOT NULL,
+	before jsonb,
+	after jsonb,
+	actor varchar(100) NOT NULL,
+	request_id varchar(100) NOT NULL DEFAULT '',
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT task_events_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS task_events_task_id_idx ON task_events (task_id, id);
+
+CREATE INDEX IF NOT EXISTS task_events_created_at_idx ON task_events (created_at);
+
+CREATE OR REPLACE FUNCTION task_events_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'task_events is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_events_append_only
+	BEFORE UPDATE OR DELETE ON task_events
+	FOR EACH STATEMENT EXECUTE FUNCTION task_events_append_only();
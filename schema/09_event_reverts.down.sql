@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS task_events_actor_idx;
+
+DROP INDEX IF EXISTS task_events_reverts_idx;
+
+ALTER TABLE task_events
+	DROP COLUMN IF EXISTS reverts;
@@ -0,0 +1,6 @@
+ALTER TABLE task_events
+	ADD COLUMN reverts bigint REFERENCES task_events (id);
+
+CREATE INDEX IF NOT EXISTS task_events_reverts_idx ON task_events (reverts) WHERE reverts IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS task_events_actor_idx ON task_events (actor, id);
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+	DROP CONSTRAINT IF EXISTS tasks_repeat_from_check,
+	DROP COLUMN IF EXISTS repeat_from,
+	DROP COLUMN IF EXISTS rrule;
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+	ADD COLUMN rrule varchar(500) NOT NULL DEFAULT '',
+	ADD COLUMN repeat_from varchar(20) NOT NULL DEFAULT '',
+	ADD CONSTRAINT tasks_repeat_from_check CHECK (repeat_from IN ('', 'due', 'completion'));
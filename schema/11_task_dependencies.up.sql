@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS task_dependencies(
+	task_id integer NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	blocked_by integer NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT task_dependencies_pk PRIMARY KEY (task_id, blocked_by),
+	CONSTRAINT task_dependencies_not_self CHECK (task_id <> blocked_by)
+);
+
+CREATE INDEX IF NOT EXISTS task_dependencies_blocked_by_idx ON task_dependencies (blocked_by);
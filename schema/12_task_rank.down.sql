@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tasks_rank_idx;
+
+ALTER TABLE tasks
+	DROP COLUMN IF EXISTS rank;
@@ -0,0 +1,7 @@
+ALTER TABLE tasks
+	ADD COLUMN rank varchar(255) COLLATE "C" NOT NULL DEFAULT '';
+
+-- existing tasks keep the order of their ids, hex digits are rank digits
+UPDATE tasks SET rank = lpad(to_hex(id), 8, '0');
+
+CREATE INDEX IF NOT EXISTS tasks_rank_idx ON tasks (rank, id);
@@ -0,0 +1,42 @@
+DROP INDEX IF EXISTS task_events_owner_id_idx;
+
+DROP INDEX IF EXISTS lists_owner_id_idx;
+
+DROP INDEX IF EXISTS tasks_owner_rank_idx;
+
+CREATE INDEX IF NOT EXISTS tasks_rank_idx ON tasks (rank, id);
+
+ALTER TABLE task_events
+	DROP COLUMN IF EXISTS owner_id;
+
+ALTER TABLE tags
+	DROP CONSTRAINT IF EXISTS tags_owner_name_key,
+	DROP COLUMN IF EXISTS owner_id;
+
+-- tags of different owners may share a name, they are merged into the
+-- oldest one
+INSERT INTO task_tags (task_id, tag_id)
+	SELECT task_tags.task_id, keep.id FROM task_tags
+	JOIN tags ON tags.id = task_tags.tag_id
+	JOIN (SELECT name, MIN(id) AS id FROM tags GROUP BY name) keep ON keep.name = tags.name
+	WHERE tags.id <> keep.id
+	ON CONFLICT DO NOTHING;
+
+DELETE FROM tags WHERE id NOT IN (SELECT MIN(id) FROM tags GROUP BY name);
+
+ALTER TABLE tags
+	ADD CONSTRAINT tags_name_key UNIQUE (name);
+
+ALTER TABLE tasks
+	DROP CONSTRAINT IF EXISTS tasks_list_owner_fkey;
+
+ALTER TABLE lists
+	DROP CONSTRAINT IF EXISTS lists_id_owner_key,
+	DROP COLUMN IF EXISTS owner_id;
+
+ALTER TABLE tasks
+	DROP COLUMN IF EXISTS owner_id;
+
+DROP TABLE IF EXISTS refresh_tokens;
+
+DROP TABLE IF EXISTS users;
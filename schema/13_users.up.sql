@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS users(
+	id serial,
+	email varchar(255) NOT NULL,
+	password_hash varchar(255) NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT users_pk PRIMARY KEY (id),
+	CONSTRAINT users_email_key UNIQUE (email)
+);
+
+-- only a hash of a refresh token is kept, the token itself is a secret
+CREATE TABLE IF NOT EXISTS refresh_tokens(
+	token_hash varchar(64) NOT NULL,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT refresh_tokens_pk PRIMARY KEY (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+
+-- rows created before users existed have no owner and are seen by no one
+ALTER TABLE tasks
+	ADD COLUMN owner_id integer REFERENCES users (id);
+
+ALTER TABLE lists
+	ADD COLUMN owner_id integer REFERENCES users (id),
+	ADD CONSTRAINT lists_id_owner_key UNIQUE (id, owner_id);
+
+-- a task only goes in a list of its owner
+ALTER TABLE tasks
+	ADD CONSTRAINT tasks_list_owner_fkey FOREIGN KEY (list_id, owner_id) REFERENCES lists (id, owner_id);
+
+ALTER TABLE tags
+	ADD COLUMN owner_id integer REFERENCES users (id),
+	DROP CONSTRAINT IF EXISTS tags_name_key,
+	ADD CONSTRAINT tags_owner_name_key UNIQUE (owner_id, name);
+
+ALTER TABLE task_events
+	ADD COLUMN owner_id integer REFERENCES users (id);
+
+-- the manual order is kept per owner
+DROP INDEX IF EXISTS tasks_rank_idx;
+
+CREATE INDEX IF NOT EXISTS tasks_owner_rank_idx ON tasks (owner_id, rank, id);
+
+CREATE INDEX IF NOT EXISTS lists_owner_id_idx ON lists (owner_id);
+
+CREATE INDEX IF NOT EXISTS task_events_owner_id_idx ON task_events (owner_id, id);
@@ -0,0 +1,15 @@
+-- only a hash of a key is kept, the prefix tells keys apart in lists
+CREATE TABLE IF NOT EXISTS api_keys(
+	id serial,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	name varchar(100) NOT NULL,
+	prefix varchar(16) NOT NULL,
+	key_hash varchar(64) NOT NULL,
+	read_only boolean NOT NULL DEFAULT false,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	last_used_at timestamptz,
+	CONSTRAINT api_keys_pk PRIMARY KEY (id),
+	CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash)
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id);
@@ -0,0 +1,12 @@
+-- members of a shared list, the owner of the list is never one of them.
+-- accepted_at stays null while the invitation is pending
+CREATE TABLE IF NOT EXISTS list_members(
+	list_id integer NOT NULL REFERENCES lists (id) ON DELETE CASCADE,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	role varchar(10) NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+	created_at timestamptz NOT NULL DEFAULT now(),
+	accepted_at timestamptz,
+	CONSTRAINT list_members_pk PRIMARY KEY (list_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS list_members_user_id_idx ON list_members (user_id);
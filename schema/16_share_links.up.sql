@@ -0,0 +1,14 @@
+-- public links to a list or to a task, read as the user who created them.
+-- the token of a link is signed from its row, nothing secret is stored
+CREATE TABLE IF NOT EXISTS share_links(
+	id serial,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	list_id integer REFERENCES lists (id) ON DELETE CASCADE,
+	task_id integer REFERENCES tasks (id) ON DELETE CASCADE,
+	expires_at timestamptz,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT share_links_pk PRIMARY KEY (id),
+	CONSTRAINT share_links_target_check CHECK ((list_id IS NULL) <> (task_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS share_links_user_id_idx ON share_links (user_id);
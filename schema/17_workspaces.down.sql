@@ -0,0 +1,19 @@
+ALTER TABLE share_links
+	DROP COLUMN IF EXISTS workspace_id;
+
+ALTER TABLE api_keys
+	DROP COLUMN IF EXISTS workspace_id;
+
+DROP INDEX IF EXISTS task_events_workspace_owner_idx;
+
+ALTER TABLE task_events
+	DROP COLUMN IF EXISTS workspace_id;
+
+DROP INDEX IF EXISTS tasks_workspace_owner_idx;
+
+ALTER TABLE tasks
+	DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS workspace_members;
+
+DROP TABLE IF EXISTS workspaces;
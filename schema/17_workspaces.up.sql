@@ -0,0 +1,52 @@
+-- a workspace holds the tasks of a team, apart from every other workspace.
+-- the default workspace is open to every user and gets the tasks of before
+CREATE TABLE IF NOT EXISTS workspaces(
+	id serial,
+	name varchar(100) NOT NULL,
+	slug varchar(50) NOT NULL,
+	owner_id integer REFERENCES users (id) ON DELETE SET NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT workspaces_pk PRIMARY KEY (id),
+	CONSTRAINT workspaces_slug_key UNIQUE (slug)
+);
+
+INSERT INTO workspaces (id, name, slug) VALUES (1, 'Default', 'default') ON CONFLICT DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('workspaces', 'id'), (SELECT MAX(id) FROM workspaces));
+
+CREATE TABLE IF NOT EXISTS workspace_members(
+	workspace_id integer NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT workspace_members_pk PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS workspace_members_user_id_idx ON workspace_members (user_id);
+
+ALTER TABLE tasks
+	ADD COLUMN workspace_id integer NOT NULL DEFAULT 1 REFERENCES workspaces (id) ON DELETE CASCADE;
+
+ALTER TABLE tasks
+	ALTER COLUMN workspace_id DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS tasks_workspace_owner_idx ON tasks (workspace_id, owner_id);
+
+-- events outlive their tasks, so they keep the workspace without a key
+ALTER TABLE task_events
+	ADD COLUMN workspace_id integer NOT NULL DEFAULT 1;
+
+ALTER TABLE task_events
+	ALTER COLUMN workspace_id DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS task_events_workspace_owner_idx ON task_events (workspace_id, owner_id, id);
+
+-- an API key works in the workspace it was created in, keys of before in
+-- any workspace of their user
+ALTER TABLE api_keys
+	ADD COLUMN workspace_id integer REFERENCES workspaces (id) ON DELETE CASCADE;
+
+ALTER TABLE share_links
+	ADD COLUMN workspace_id integer NOT NULL DEFAULT 1 REFERENCES workspaces (id) ON DELETE CASCADE;
+
+ALTER TABLE share_links
+	ALTER COLUMN workspace_id DROP DEFAULT;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS task_events_workspace_user_idx;
+
+ALTER TABLE task_events
+	DROP COLUMN IF EXISTS user_id;
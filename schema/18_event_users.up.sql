@@ -0,0 +1,16 @@
+-- the user who made a change, whichever owner the task is of, so undo finds
+-- the changes of members to the tasks of shared lists. Like the workspace,
+-- it is kept without a key since events outlive what they name
+ALTER TABLE task_events
+	ADD COLUMN user_id integer;
+
+-- the actor of the changes of before is the email of their user
+ALTER TABLE task_events
+	DISABLE TRIGGER task_events_append_only;
+
+UPDATE task_events SET user_id = users.id FROM users WHERE users.email = task_events.actor;
+
+ALTER TABLE task_events
+	ENABLE TRIGGER task_events_append_only;
+
+CREATE INDEX IF NOT EXISTS task_events_workspace_user_idx ON task_events (workspace_id, user_id, id);
@@ -0,0 +1,9 @@
+// Package schema holds the migrations of the database, applied in the order
+// of their version by pkg/migrate. tasks.sql is a snapshot of the whole
+// schema for reading, it is not embedded and never applied.
+package schema
+
+import "embed"
+
+//go:embed *.up.sql *.down.sql
+var FS embed.FS
@@ -1,8 +1,206 @@
+-- snapshot of the whole schema after the last migration, for reading and
+-- for psql. The migrations next to it are what gets applied, keep this file
+-- in step with them.
 CREATE DATABASE "to-do-list";
 \c "to-do-list"
+CREATE TABLE IF NOT EXISTS users(
+	id serial,
+	email varchar(255) NOT NULL,
+	password_hash varchar(255) NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT users_pk PRIMARY KEY (id),
+	CONSTRAINT users_email_key UNIQUE (email)
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens(
+	token_hash varchar(64) NOT NULL,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT refresh_tokens_pk PRIMARY KEY (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS workspaces(
+	id serial,
+	name varchar(100) NOT NULL,
+	slug varchar(50) NOT NULL,
+	owner_id integer REFERENCES users (id) ON DELETE SET NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT workspaces_pk PRIMARY KEY (id),
+	CONSTRAINT workspaces_slug_key UNIQUE (slug)
+);
+
+-- the default workspace is open to every user
+INSERT INTO workspaces (id, name, slug) VALUES (1, 'Default', 'default') ON CONFLICT DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('workspaces', 'id'), (SELECT MAX(id) FROM workspaces));
+
+CREATE TABLE IF NOT EXISTS workspace_members(
+	workspace_id integer NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT workspace_members_pk PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS workspace_members_user_id_idx ON workspace_members (user_id);
+
+-- only a hash of a key is kept, the prefix tells keys apart in lists
+CREATE TABLE IF NOT EXISTS api_keys(
+	id serial,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	name varchar(100) NOT NULL,
+	prefix varchar(16) NOT NULL,
+	key_hash varchar(64) NOT NULL,
+	read_only boolean NOT NULL DEFAULT false,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	last_used_at timestamptz,
+	workspace_id integer REFERENCES workspaces (id) ON DELETE CASCADE,
+	CONSTRAINT api_keys_pk PRIMARY KEY (id),
+	CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash)
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id);
+
+CREATE TABLE IF NOT EXISTS lists(
+	id serial,
+	name varchar(100) NOT NULL,
+	owner_id integer REFERENCES users (id),
+	CONSTRAINT lists_pk PRIMARY KEY (id),
+	CONSTRAINT lists_id_owner_key UNIQUE (id, owner_id)
+);
+
+CREATE INDEX IF NOT EXISTS lists_owner_id_idx ON lists (owner_id);
+
+-- members of a shared list, the owner of the list is never one of them.
+-- accepted_at stays null while the invitation is pending
+CREATE TABLE IF NOT EXISTS list_members(
+	list_id integer NOT NULL REFERENCES lists (id) ON DELETE CASCADE,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	role varchar(10) NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+	created_at timestamptz NOT NULL DEFAULT now(),
+	accepted_at timestamptz,
+	CONSTRAINT list_members_pk PRIMARY KEY (list_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS list_members_user_id_idx ON list_members (user_id);
+
 CREATE TABLE IF NOT EXISTS tasks(
 	id serial,
 	task_name varchar NOT NULL,
 	is_done bool NOT NULL,
-	CONSTRAINT tasks_pk PRIMARY KEY (id)
-);
\ No newline at end of file
+	due_at timestamptz,
+	timezone varchar NOT NULL DEFAULT 'UTC',
+	remind_at timestamptz,
+	priority smallint NOT NULL DEFAULT 0,
+	list_id integer,
+	parent_id integer,
+	position integer NOT NULL DEFAULT 0,
+	version integer NOT NULL DEFAULT 1,
+	deleted_at timestamptz,
+	rrule varchar(500) NOT NULL DEFAULT '',
+	repeat_from varchar(20) NOT NULL DEFAULT '',
+	rank varchar(255) COLLATE "C" NOT NULL DEFAULT '',
+	owner_id integer REFERENCES users (id),
+	workspace_id integer NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	CONSTRAINT tasks_pk PRIMARY KEY (id),
+	CONSTRAINT tasks_remind_before_due CHECK (remind_at IS NULL OR due_at IS NULL OR remind_at <= due_at),
+	CONSTRAINT tasks_priority_range CHECK (priority BETWEEN 0 AND 3),
+	CONSTRAINT tasks_repeat_from_check CHECK (repeat_from IN ('', 'due', 'completion')),
+	CONSTRAINT tasks_list_id_fkey FOREIGN KEY (list_id) REFERENCES lists (id),
+	CONSTRAINT tasks_list_owner_fkey FOREIGN KEY (list_id, owner_id) REFERENCES lists (id, owner_id),
+	CONSTRAINT tasks_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS tasks_due_at_idx ON tasks (due_at);
+
+CREATE INDEX IF NOT EXISTS tasks_list_id_idx ON tasks (list_id);
+
+CREATE INDEX IF NOT EXISTS tasks_parent_id_idx ON tasks (parent_id, position);
+
+CREATE INDEX IF NOT EXISTS tasks_owner_rank_idx ON tasks (owner_id, rank, id);
+
+CREATE INDEX IF NOT EXISTS tasks_workspace_owner_idx ON tasks (workspace_id, owner_id);
+
+CREATE INDEX IF NOT EXISTS tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS tags(
+	id serial,
+	name varchar(50) NOT NULL,
+	owner_id integer REFERENCES users (id),
+	CONSTRAINT tags_pk PRIMARY KEY (id),
+	CONSTRAINT tags_owner_name_key UNIQUE (owner_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS task_tags(
+	task_id integer NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	tag_id integer NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+	CONSTRAINT task_tags_pk PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_tags_tag_id_idx ON task_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS task_dependencies(
+	task_id integer NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	blocked_by integer NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT task_dependencies_pk PRIMARY KEY (task_id, blocked_by),
+	CONSTRAINT task_dependencies_not_self CHECK (task_id <> blocked_by)
+);
+
+CREATE INDEX IF NOT EXISTS task_dependencies_blocked_by_idx ON task_dependencies (blocked_by);
+
+CREATE TABLE IF NOT EXISTS share_links(
+	id serial,
+	user_id integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	list_id integer REFERENCES lists (id) ON DELETE CASCADE,
+	task_id integer REFERENCES tasks (id) ON DELETE CASCADE,
+	expires_at timestamptz,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	workspace_id integer NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	CONSTRAINT share_links_pk PRIMARY KEY (id),
+	CONSTRAINT share_links_target_check CHECK ((list_id IS NULL) <> (task_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS share_links_user_id_idx ON share_links (user_id);
+
+CREATE TABLE IF NOT EXISTS task_events(
+	id bigserial,
+	task_id integer NOT NULL,
+	action varchar(20) NOT NULL,
+	before jsonb,
+	after jsonb,
+	actor varchar(100) NOT NULL,
+	request_id varchar(100) NOT NULL DEFAULT '',
+	created_at timestamptz NOT NULL DEFAULT now(),
+	reverts bigint REFERENCES task_events (id),
+	owner_id integer REFERENCES users (id),
+	workspace_id integer NOT NULL,
+	user_id integer,
+	CONSTRAINT task_events_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS task_events_task_id_idx ON task_events (task_id, id);
+
+CREATE INDEX IF NOT EXISTS task_events_created_at_idx ON task_events (created_at);
+
+CREATE INDEX IF NOT EXISTS task_events_reverts_idx ON task_events (reverts) WHERE reverts IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS task_events_actor_idx ON task_events (actor, id);
+
+CREATE INDEX IF NOT EXISTS task_events_owner_id_idx ON task_events (owner_id, id);
+
+CREATE INDEX IF NOT EXISTS task_events_workspace_owner_idx ON task_events (workspace_id, owner_id, id);
+
+CREATE INDEX IF NOT EXISTS task_events_workspace_user_idx ON task_events (workspace_id, user_id, id);
+
+CREATE OR REPLACE FUNCTION task_events_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'task_events is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_events_append_only
+	BEFORE UPDATE OR DELETE ON task_events
+	FOR EACH STATEMENT EXECUTE FUNCTION task_events_append_only();